@@ -120,12 +120,20 @@ func (c *DiskCache) GetMedia(systemID, gameID, mediaType, region string) ([]byte
 	dir := filepath.Join(c.baseDir, "media", systemID, gameID)
 	baseName := hashKey(key)
 
-	// Try common extensions (including .nomedia for cached "not available")
-	for _, ext := range []string{".nomedia", ".png", ".jpg", ".mp4"} {
-		path := filepath.Join(dir, baseName+ext)
-		data, err := c.readIfValid(path)
+	// Media is stored under whatever extension SetMedia was given (including
+	// .nomedia for cached "not available"), so look for any of them
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, "", false
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, baseName+".") || strings.HasSuffix(name, ".meta") {
+			continue
+		}
+		data, err := c.readIfValid(filepath.Join(dir, name))
 		if err == nil {
-			return data, ext[1:], true // Strip the leading dot
+			return data, strings.TrimPrefix(name, baseName+"."), true
 		}
 	}
 
@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiskCache_MediaExtensions(t *testing.T) {
+	c, err := New(t.TempDir(), time.Hour, ModeNormal)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, ext := range []string{"png", "gif", "webp", "nomedia"} {
+		t.Run(ext, func(t *testing.T) {
+			if err := c.SetMedia("1", "42", ext, "us", []byte(ext), ext); err != nil {
+				t.Fatalf("SetMedia() error = %v", err)
+			}
+			data, gotExt, ok := c.GetMedia("1", "42", ext, "us")
+			if !ok {
+				t.Fatal("GetMedia() = not found")
+			}
+			if gotExt != ext || string(data) != ext {
+				t.Errorf("GetMedia() = %q, %q, want %q, %q", data, gotExt, ext, ext)
+			}
+		})
+	}
+
+	if _, _, ok := c.GetMedia("1", "42", "box-2D", "us"); ok {
+		t.Error("GetMedia() found media that was never stored")
+	}
+}
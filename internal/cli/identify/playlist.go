@@ -0,0 +1,81 @@
+package identify
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sargunv/rom-tools/lib/core"
+	romident "github.com/sargunv/rom-tools/lib/identify"
+	"github.com/sargunv/rom-tools/lib/retroarch"
+)
+
+// playlistItems returns the RetroArch playlist items of an identify result:
+// one per file in a folder, and one for a file or archive, described by the
+// item matched in the libretro databases if any.
+func playlistItems(result *romident.Result) []retroarch.Item {
+	if info, err := os.Stat(result.Path); err == nil && info.IsDir() {
+		var items []retroarch.Item
+		for _, item := range result.Items {
+			items = append(items, playlistItem(filepath.Join(result.Path, item.Name), item))
+		}
+		return items
+	}
+
+	if len(result.Items) == 0 {
+		return nil
+	}
+	best := result.Items[0]
+	for _, item := range result.Items {
+		if item.Lookup != nil {
+			best = item
+			break
+		}
+	}
+	return []retroarch.Item{playlistItem(result.Path, best)}
+}
+
+// playlistItem converts an identified item to a playlist item for path
+func playlistItem(path string, item romident.Item) retroarch.Item {
+	label := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	var dbName string
+	if item.Lookup != nil {
+		label = item.Lookup.Name
+		dbName = retroarch.PlaylistName(item.Lookup.Database)
+	}
+
+	crc := item.Hashes[core.HashCRC32]
+	if crc == "" {
+		crc = item.Hashes[core.HashZipCRC32]
+	}
+
+	return retroarch.Item{
+		Path:     path,
+		Label:    label,
+		CorePath: retroarch.Detect,
+		CoreName: retroarch.Detect,
+		CRC32:    retroarch.FormatCRC32(crc),
+		DBName:   dbName,
+	}
+}
+
+// writePlaylist merges identify results into a RetroArch playlist. Items
+// already in the playlist keep their labels unless overwrite is set.
+func writePlaylist(path string, results []*romident.Result, overwrite bool) error {
+	playlist, err := retroarch.ReadPlaylist(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		playlist = retroarch.NewPlaylist() // Start fresh
+	} else if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		for _, item := range playlistItems(result) {
+			playlist.Merge(item, overwrite)
+		}
+	}
+
+	return retroarch.WritePlaylist(path, playlist)
+}
@@ -4,6 +4,8 @@ import (
 	"cmp"
 	"encoding/json"
 	"fmt"
+	"image"
+	"image/png"
 	"os"
 	"path/filepath"
 	"slices"
@@ -12,13 +14,21 @@ import (
 	"github.com/sargunv/rom-tools/internal/format"
 	"github.com/sargunv/rom-tools/lib/core"
 	romident "github.com/sargunv/rom-tools/lib/identify"
+	"github.com/sargunv/rom-tools/lib/mame"
+	"github.com/sargunv/rom-tools/lib/rdb"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	jsonOutput  bool
-	maxHashSize int64
+	jsonOutput         bool
+	maxHashSize        int64
+	iconDir            string
+	mameXML            string
+	softlists          []string
+	lookupRDBs         []string
+	retroarchPlaylist  string
+	retroarchOverwrite bool
 )
 
 var Cmd = &cobra.Command{
@@ -29,28 +39,59 @@ var Cmd = &cobra.Command{
 Supports:
 - Platform specific ROMs: identifies game information from the ROM header. Supported formats:
   - Famicom (NES): .nes
+  - Famicom Disk System: .fds, .qd
   - Super Famicom (SNES): .sfc, .smc
   - Nintendo 64: .z64, .v64, .n64
-  - Nintendo GameCube / Wii: .gcm, .iso, .rvz, .wia
+  - Nintendo 64DD: .ndd, .d64
+  - Nintendo GameCube / Wii: .gcm, .iso, .rvz, .wia, .gcz, .ciso, .wbfs
   - Nintendo Game Boy / Color: .gb, .gbc
   - Nintendo Game Boy Advance: .gba
+  - Nintendo Virtual Boy: .vb
+  - Nintendo Pokémon Mini: .min
   - Nintendo DS: .nds, .dsi, .ids
-  - Nintendo 3DS: .3ds, .cci
+  - Nintendo 3DS: .3ds, .cci, .cia, .cxi, .3dsx
+  - Nintendo Switch: .nsp, .nsz, .xci, .xcz
   - Sega Master System / Game Gear: .sms, .gg
   - Sega Mega Drive (Genesis): .md, .gen, .smd, .32x
   - Sega CD: .bin, .chd
   - Sega Saturn: .bin, .chd
-  - Sega Dreamcast: .bin, .chd
+  - Sega Dreamcast: .bin, .chd (including GD-ROM), .cdi
   - Sony PlayStation 1: .bin, .chd
   - Sony PlayStation 2: .iso, .bin, .chd
   - Sony PlayStation 3: .pkg
   - Sony PlayStation Portable: .iso, .chd
   - Sony PlayStation Vita: .pkg
+  - SNK Neo Geo Pocket / Color: .ngp, .ngc
+  - Bandai WonderSwan / Color: .ws, .wsc
   - Microsoft Xbox: .iso, .chd, .xbe
+  - Microsoft Xbox 360: .iso, .xex, STFS packages (XBLA, Games on Demand)
+- Headerless systems: platform only (games are identified by hash), confirmed by size or magic bytes:
+  - Atari 2600 / 5200 / 7800: .a26, .a52, .a78
+  - Atari Lynx / Jaguar: .lnx, .lyx, .j64
+  - NEC PC Engine / SuperGrafx: .pce, .sgx
+  - Sega SG-1000 / SC-3000: .sg, .sc
+  - ColecoVision: .col
+  - Mattel Intellivision: .int, .rom
+  - GCE Vectrex: .vec, .gam
+  - Fairchild Channel F: .chf
+  - Watara Supervision: .sv
+  - MSX / MSX2: .mx1, .mx2, .rom
 - .chd discs: extracts SHA1 hashes from header (no decompression needed)
+- .cdi discs: reads DiscJuggler session and track descriptors
+- .rvz/.wia GameCube discs: decompresses and hashes the original disc (matches Redump DATs)
 - .zip archives: extracts CRC32 hashes from metadata (no decompression needed)
 - All files: calculates SHA1, MD5, CRC32 for uncompressed files under --max-hash-size
-- All folders: identifies files within`,
+- All folders: identifies files within
+- Embedded icons (NDS banners, 3DS SMDH, GameCube opening.bnr): saved as PNG with --icon-dir
+- Arcade ROM sets (.zip archives or folders): matched against MAME -listxml output with --mame-xml,
+  reporting the machine and set completeness (split, merged and non-merged sets)
+- Other systems: files no parser recognizes are matched by hash against MAME software lists
+  (hash/*.xml) with --mame-softlist
+- Offline lookup: items are matched by hash, or by serial, against RetroArch libretro databases
+  (.rdb files) with --lookup
+- RetroArch playlists: identified files are added to a playlist (.lpl) with --retroarch-playlist,
+  labelled and tagged with their database when matched with --lookup. Items already in the playlist
+  keep their labels unless --retroarch-overwrite is set`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runIdentify,
 }
@@ -61,6 +102,15 @@ func init() {
 	Cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output results as JSON Lines (one JSON object per line)")
 	Cmd.Flags().Int64Var(&maxHashSize, "max-hash-size", defaults.MaxHashSize,
 		"Max file size in bytes for hash calculation (-1 = no limit)")
+	Cmd.Flags().StringVar(&iconDir, "icon-dir", "", "Directory to save embedded game icons as <rom name>.png")
+	Cmd.Flags().StringVar(&mameXML, "mame-xml", "", "MAME -listxml output to identify arcade ROM sets against")
+	Cmd.Flags().StringSliceVar(&softlists, "mame-softlist", nil,
+		"MAME software list files or directories (hash/*.xml) to identify unrecognized files against")
+	Cmd.Flags().StringSliceVar(&lookupRDBs, "lookup", nil,
+		"RetroArch database files or directories (*.rdb) to look up items in by hash or serial")
+	Cmd.Flags().StringVar(&retroarchPlaylist, "retroarch-playlist", "", "RetroArch playlist (.lpl) to add identified files to")
+	Cmd.Flags().BoolVar(&retroarchOverwrite, "retroarch-overwrite", false,
+		"Replace the labels, CRCs and databases of items already in the RetroArch playlist")
 }
 
 func runIdentify(cmd *cobra.Command, args []string) error {
@@ -68,7 +118,32 @@ func runIdentify(cmd *cobra.Command, args []string) error {
 		MaxHashSize: maxHashSize,
 	}
 
+	if mameXML != "" {
+		list, err := mame.Parse(mameXML)
+		if err != nil {
+			return err
+		}
+		opts.MAME = mame.NewIndex(list)
+	}
+
+	if len(softlists) > 0 {
+		index, err := loadSoftwareLists(softlists)
+		if err != nil {
+			return err
+		}
+		opts.SoftwareLists = index
+	}
+
+	if len(lookupRDBs) > 0 {
+		index, err := loadRDBs(lookupRDBs)
+		if err != nil {
+			return err
+		}
+		opts.Lookup = index
+	}
+
 	first := true
+	var results []*romident.Result
 
 	for _, path := range args {
 		result, err := romident.Identify(path, opts)
@@ -76,6 +151,13 @@ func runIdentify(cmd *cobra.Command, args []string) error {
 			fmt.Fprintf(os.Stderr, "Error: failed to identify %s: %v\n", path, err)
 			continue
 		}
+		results = append(results, result)
+
+		if iconDir != "" {
+			if err := saveIcons(result); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to save icons for %s: %v\n", path, err)
+			}
+		}
 
 		if jsonOutput {
 			outputJSONLine(result)
@@ -88,9 +170,61 @@ func runIdentify(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if retroarchPlaylist != "" {
+		if err := writePlaylist(retroarchPlaylist, results, retroarchOverwrite); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// loadSoftwareLists parses software list files, and every .xml file in
+// software list directories, into a single index.
+func loadSoftwareLists(paths []string) (*mame.SoftwareIndex, error) {
+	var lists []*mame.SoftwareList
+	for _, path := range paths {
+		files := []string{path}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			files, err = filepath.Glob(filepath.Join(path, "*.xml"))
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, file := range files {
+			list, err := mame.ParseSoftwareList(file)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			lists = append(lists, list)
+		}
+	}
+	return mame.NewSoftwareIndex(lists...), nil
+}
+
+// loadRDBs parses RetroArch database files, and every .rdb file in database
+// directories, into a single index.
+func loadRDBs(paths []string) (*rdb.Index, error) {
+	var dbs []*rdb.Database
+	for _, path := range paths {
+		files := []string{path}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			files, err = filepath.Glob(filepath.Join(path, "*.rdb"))
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, file := range files {
+			db, err := rdb.Parse(file)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			dbs = append(dbs, db)
+		}
+	}
+	return rdb.NewIndex(dbs...), nil
+}
+
 func outputJSONLine(result *romident.Result) {
 	output, err := json.Marshal(result)
 	if err != nil {
@@ -157,9 +291,102 @@ func outputText(result *romident.Result) {
 				if regions := item.Game.GameRegions(); len(regions) > 0 {
 					fmt.Printf("      Region: %s\n", formatRegions(regions))
 				}
+				if sw, ok := item.Game.(*mame.SoftwareMatch); ok {
+					fmt.Printf("      Software: %s:%s (%s)\n", sw.List, sw.Name, sw.File)
+				}
+				if icon := gameIcon(item.Game); icon != nil {
+					fmt.Printf("      Icon: %dx%d\n", icon.Bounds().Dx(), icon.Bounds().Dy())
+				}
 			}
+
+			if entry := item.Lookup; entry != nil {
+				fmt.Println("    Lookup:")
+				fmt.Printf("      Name: %s\n", entry.Name)
+				fmt.Printf("      Database: %s\n", entry.Database)
+				if entry.Serial != "" {
+					fmt.Printf("      Serial: %s\n", entry.Serial)
+				}
+				if entry.Developer != "" {
+					fmt.Printf("      Developer: %s\n", entry.Developer)
+				}
+				if entry.Publisher != "" {
+					fmt.Printf("      Publisher: %s\n", entry.Publisher)
+				}
+				if date := entry.Date(); date != "" {
+					fmt.Printf("      Released: %s\n", date)
+				}
+			}
+		}
+	}
+
+	if arcade := result.Arcade; arcade != nil {
+		fmt.Println(format.HeaderStyle.Render("Arcade:"))
+		fmt.Printf("  Machine: %s\n", arcade.Name)
+		fmt.Printf("  Title: %s\n", arcade.Description)
+		if arcade.Year != "" {
+			fmt.Printf("  Year: %s\n", arcade.Year)
+		}
+		if arcade.Manufacturer != "" {
+			fmt.Printf("  Manufacturer: %s\n", arcade.Manufacturer)
+		}
+		if arcade.CloneOf != "" {
+			fmt.Printf("  Clone of: %s\n", arcade.CloneOf)
+		}
+		fmt.Printf("  Set type: %s\n", arcade.SetType)
+		if arcade.Complete() {
+			fmt.Println("  Status: complete")
+		} else {
+			fmt.Println("  Status: incomplete")
+		}
+		for _, name := range arcade.Missing {
+			fmt.Printf("    %s %s\n", format.LabelStyle.Render("missing:"), name)
+		}
+		for _, bad := range arcade.Bad {
+			fmt.Printf("    %s %s (crc32 %s, expected %s)\n", format.LabelStyle.Render("bad:"), bad.Name, bad.ActualCRC, bad.ExpectedCRC)
+		}
+		for _, name := range arcade.Extra {
+			fmt.Printf("    %s %s\n", format.LabelStyle.Render("extra:"), name)
+		}
+	}
+}
+
+// gameIcon returns the embedded icon of a game, or nil if it has none.
+func gameIcon(game core.GameInfo) image.Image {
+	if p, ok := game.(core.IconProvider); ok {
+		return p.GameIcon()
+	}
+	return nil
+}
+
+// saveIcons writes the embedded icon of each identified item to iconDir.
+func saveIcons(result *romident.Result) error {
+	for _, item := range result.Items {
+		if item.Game == nil {
+			continue
+		}
+		icon := gameIcon(item.Game)
+		if icon == nil {
+			continue
+		}
+
+		if err := os.MkdirAll(iconDir, 0755); err != nil {
+			return err
+		}
+		name := filepath.Base(item.Name)
+		iconPath := filepath.Join(iconDir, strings.TrimSuffix(name, filepath.Ext(name))+".png")
+		f, err := os.Create(iconPath)
+		if err != nil {
+			return err
+		}
+		if err := png.Encode(f, icon); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 func formatRegions(regions []core.Region) string {
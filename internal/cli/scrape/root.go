@@ -1,11 +1,15 @@
 package scrape
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
@@ -17,8 +21,21 @@ import (
 	"github.com/sargunv/rom-tools/internal/cache"
 	"github.com/sargunv/rom-tools/internal/cli/screenscraper/shared"
 	"github.com/sargunv/rom-tools/internal/scraper"
+	"github.com/sargunv/rom-tools/internal/scraper/output/attractmode"
+	"github.com/sargunv/rom-tools/internal/scraper/output/batocera"
 	"github.com/sargunv/rom-tools/internal/scraper/output/esde"
+	"github.com/sargunv/rom-tools/internal/scraper/output/launchbox"
+	"github.com/sargunv/rom-tools/internal/scraper/output/minui"
+	"github.com/sargunv/rom-tools/internal/scraper/output/muos"
+	"github.com/sargunv/rom-tools/internal/scraper/output/pegasus"
+	"github.com/sargunv/rom-tools/internal/scraper/output/retroarch"
 	"github.com/sargunv/rom-tools/lib/datfile"
+	"github.com/sargunv/rom-tools/lib/hasheous"
+	liblaunchbox "github.com/sargunv/rom-tools/lib/launchbox"
+	libminui "github.com/sargunv/rom-tools/lib/minui"
+	"github.com/sargunv/rom-tools/lib/rdb"
+	libretroarch "github.com/sargunv/rom-tools/lib/retroarch"
+	"github.com/sargunv/rom-tools/lib/screenscraper"
 )
 
 var (
@@ -31,6 +48,37 @@ var (
 	esdeGamelist string
 	esdeMedia    string
 
+	// Output - Batocera/RetroBat
+	batoceraGamelist string
+
+	// Output - MuOS
+	muosCatalogue string
+
+	// Output - MinUI/NextUI
+	minuiRoms       string
+	minuiBoxArtSize string
+
+	// Output - Pegasus
+	pegasusMetadata string
+
+	// Output - RetroArch
+	retroarchPlaylist   string
+	retroarchROMDir     string
+	retroarchThumbnails string
+
+	// Output - LaunchBox
+	launchBoxDir    string
+	launchBoxROMDir string
+
+	// Output - AttractMode
+	attractModeDir      string
+	attractModeEmulator string
+
+	// Metadata sources
+	sources           []string
+	launchBoxMetadata string
+	libretroRDB       string
+
 	// Media
 	mediaTypes []string
 
@@ -67,8 +115,8 @@ var Cmd = &cobra.Command{
 	Long: `Batch scrape metadata and media for ROM files.
 
 Scans the input (DAT file or ROM directory), identifies games using hashes,
-fetches metadata from Screenscraper, downloads media files, and generates
-output in the specified format(s).
+fetches metadata from Screenscraper (or other sources, see --source),
+downloads media files, and generates output in the specified format(s).
 
 Example:
   # Scrape from DAT file to ES-DE format
@@ -83,6 +131,52 @@ Example:
       --media screenshots,covers,3dboxes,marquees,videos \
       --regions jp,us,eu
 
+  # Try Screenscraper first, then fall back to Hasheous
+  rom-tools scrape --system nes --dat nes.dat \
+      --esde-gamelist ./nes/gamelist.xml \
+      --source screenscraper,hasheous
+
+  # Scrape offline from the LaunchBox games database
+  rom-tools scrape --system snes --dat snes.dat \
+      --esde-gamelist ./snes/gamelist.xml \
+      --source launchbox --launchbox-metadata ./Metadata.xml
+
+  # Scrape to a Batocera/RetroBat gamelist with extended media and hashes
+  rom-tools scrape --system snes --dat snes.dat \
+      --batocera-gamelist /userdata/roms/snes/gamelist.xml
+
+  # Scrape to the MuOS catalogue on an SD card
+  rom-tools scrape --system gba --dat gba.dat \
+      --muos-catalogue /mnt/sdcard/MUOS/info/catalogue
+
+  # Scrape into a MinUI/NextUI ROM folder
+  rom-tools scrape --system gb --dat gb.dat \
+      --minui-roms "/mnt/SDCARD/Roms/Game Boy (GB)"
+
+  # Scrape into a Pegasus metadata file, keeping hand edits
+  rom-tools scrape --system snes --dat snes.dat \
+      --pegasus-metadata ~/roms/snes/metadata.pegasus.txt
+
+  # Scrape to a RetroArch playlist with thumbnails
+  rom-tools scrape --system gba --dat gba.dat \
+      --retroarch-playlist ~/.config/retroarch/playlists \
+      --retroarch-rom-dir ~/roms/gba \
+      --retroarch-thumbnails ~/.config/retroarch/thumbnails
+
+  # Scrape into a LaunchBox install on a network share
+  rom-tools scrape --system snes --dat snes.dat \
+      --launchbox-dir /mnt/share/LaunchBox \
+      --launchbox-rom-dir '\\nas\roms\snes'
+
+  # Scrape an arcade DAT into an AttractMode romlist and artwork
+  rom-tools scrape --system mame --dat mame.dat \
+      --attractmode-dir ~/.attract --attractmode-emulator mame
+
+  # Fall back to RetroArch's databases for text metadata
+  rom-tools scrape --system gba --dat gba.dat \
+      --esde-gamelist ./gba/gamelist.xml \
+      --source screenscraper,libretro --libretro-rdb ~/.config/retroarch/database/rdb
+
   # Dry run to see what would be scraped
   rom-tools scrape --system snes --dat snes.dat --dry-run
 
@@ -102,6 +196,46 @@ func init() {
 	Cmd.Flags().StringVar(&esdeGamelist, "esde-gamelist", "", "Path for ES-DE gamelist.xml")
 	Cmd.Flags().StringVar(&esdeMedia, "esde-media", "", "Path for ES-DE media folder")
 
+	// Output flags - Batocera/RetroBat
+	Cmd.Flags().StringVar(&batoceraGamelist, "batocera-gamelist", "", "Path for Batocera/RetroBat gamelist.xml (media is saved next to it)")
+
+	// Output flags - MuOS
+	Cmd.Flags().StringVar(&muosCatalogue, "muos-catalogue", "", "Path for MuOS catalogue folder (MUOS/info/catalogue)")
+
+	// Output flags - MinUI/NextUI
+	Cmd.Flags().StringVar(&minuiRoms, "minui-roms", "", "Path for MinUI/NextUI ROM folder (writes map.txt and .media box art)")
+	Cmd.Flags().StringVar(&minuiBoxArtSize, "minui-boxart-size",
+		fmt.Sprintf("%dx%d", libminui.DefaultBoxArtWidth, libminui.DefaultBoxArtHeight),
+		"Max MinUI/NextUI box art size as WIDTHxHEIGHT (0 = original size)")
+
+	// Output flags - Pegasus
+	Cmd.Flags().StringVar(&pegasusMetadata, "pegasus-metadata", "", "Path for Pegasus metadata.pegasus.txt (media is saved next to it)")
+
+	// Output flags - RetroArch
+	Cmd.Flags().StringVar(&retroarchPlaylist, "retroarch-playlist", "",
+		"Path for RetroArch playlist (.lpl), or the playlists folder to write <database>.lpl in")
+	Cmd.Flags().StringVar(&retroarchROMDir, "retroarch-rom-dir", "", "ROM folder the RetroArch playlist points to (for --retroarch-playlist)")
+	Cmd.Flags().StringVar(&retroarchThumbnails, "retroarch-thumbnails", "", "Path for RetroArch thumbnails folder (for --retroarch-playlist)")
+
+	// Output flags - LaunchBox
+	Cmd.Flags().StringVar(&launchBoxDir, "launchbox-dir", "",
+		"Path for LaunchBox folder (writes Data/Platforms/<platform>.xml and Images)")
+	Cmd.Flags().StringVar(&launchBoxROMDir, "launchbox-rom-dir", "",
+		"ROM folder as LaunchBox sees it, e.g. \\\\nas\\roms\\snes (for --launchbox-dir)")
+
+	// Output flags - AttractMode
+	Cmd.Flags().StringVar(&attractModeDir, "attractmode-dir", "",
+		"Path for AttractMode config folder (writes romlists/<emulator>.txt and scraper/<emulator> artwork)")
+	Cmd.Flags().StringVar(&attractModeEmulator, "attractmode-emulator", "",
+		"AttractMode emulator name for the romlist (default: the --system value)")
+
+	// Source flags
+	Cmd.Flags().StringSliceVar(&sources, "source", []string{"screenscraper"},
+		"Metadata sources to try in order: "+strings.Join(availableSources, ","))
+	Cmd.Flags().StringVar(&launchBoxMetadata, "launchbox-metadata", "", "Path to LaunchBox Metadata.xml (for --source launchbox)")
+	Cmd.Flags().StringVar(&libretroRDB, "libretro-rdb", "",
+		"Path to a RetroArch .rdb file, or a directory of them (for --source libretro)")
+
 	// Media flags
 	Cmd.Flags().StringSliceVarP(&mediaTypes, "media", "m", scraper.DefaultMediaTypes(),
 		"Media types to download: screenshots,titlescreens,covers,3dboxes,marquees,fanart,videos,physicalmedia,backcovers")
@@ -170,15 +304,68 @@ func runScrape(cmd *cobra.Command, args []string) error {
 	}
 
 	// Validate output
-	if esdeGamelist == "" && esdeMedia == "" {
-		return fmt.Errorf("at least one output target is required (--esde-gamelist, --esde-media)")
+	if esdeGamelist == "" && esdeMedia == "" && batoceraGamelist == "" && muosCatalogue == "" && minuiRoms == "" && pegasusMetadata == "" && retroarchPlaylist == "" && launchBoxDir == "" && attractModeDir == "" {
+		return fmt.Errorf("at least one output target is required (--esde-gamelist, --esde-media, --batocera-gamelist, --muos-catalogue, --minui-roms, --pegasus-metadata, --retroarch-playlist, --launchbox-dir, --attractmode-dir)")
 	}
 
 	// Normalize gamelist path
 	esdeGamelist = normalizeGamelistPath(esdeGamelist)
+	batoceraGamelist = normalizeGamelistPath(batoceraGamelist)
+	pegasusMetadata = normalizePegasusPath(pegasusMetadata)
+
+	// Validate sources
+	if len(sources) == 0 {
+		return fmt.Errorf("at least one metadata source is required (--source)")
+	}
+	for _, name := range sources {
+		if !slices.Contains(availableSources, name) {
+			return fmt.Errorf("unknown source: %q (available: %s)", name, strings.Join(availableSources, ", "))
+		}
+	}
+	if slices.Contains(sources, "launchbox") && launchBoxMetadata == "" {
+		return fmt.Errorf("--launchbox-metadata is required for --source launchbox")
+	}
+	if slices.Contains(sources, "libretro") && libretroRDB == "" {
+		return fmt.Errorf("--libretro-rdb is required for --source libretro")
+	}
 
-	// Initialize client from environment variables
-	client, err := shared.NewClientFromEnv("rom-tools")
+	var muosSystem string
+	if muosCatalogue != "" {
+		if muosSystem, err = scraper.LookupMuOSSystem(systemID); err != nil {
+			return err
+		}
+	}
+
+	var retroarchDatabase string
+	if retroarchPlaylist != "" {
+		if retroarchROMDir == "" {
+			return fmt.Errorf("--retroarch-rom-dir is required for --retroarch-playlist")
+		}
+		// RetroArch resolves item paths from its own working directory
+		if retroarchROMDir, err = filepath.Abs(retroarchROMDir); err != nil {
+			return err
+		}
+		if retroarchDatabase, err = scraper.LookupLibretroDatabase(systemID); err != nil {
+			return err
+		}
+		retroarchPlaylist = normalizePlaylistPath(retroarchPlaylist, retroarchDatabase)
+	}
+
+	var launchBoxPlatform string
+	if launchBoxDir != "" {
+		if launchBoxROMDir == "" {
+			return fmt.Errorf("--launchbox-rom-dir is required for --launchbox-dir")
+		}
+		if launchBoxPlatform, err = scraper.LookupLaunchBoxPlatform(systemID); err != nil {
+			return err
+		}
+	}
+
+	if attractModeDir != "" && attractModeEmulator == "" {
+		attractModeEmulator = systemName
+	}
+
+	boxArtSize, err := libminui.ParseBoxArtSize(minuiBoxArtSize)
 	if err != nil {
 		return err
 	}
@@ -192,29 +379,10 @@ func runScrape(cmd *cobra.Command, args []string) error {
 	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
-	// Get user info for rate limits
-	fmt.Print("Connecting to Screenscraper...")
-	userInfoResp, err := client.GetUserInfoWithResponse(ctx)
-	fmt.Print("\r\033[K") // Clear the line
+	// Build metadata sources (and rate limits) in the requested order
+	source, maxThreads, maxReqPerMin, err := buildSource(ctx, systemID)
 	if err != nil {
-		return fmt.Errorf("failed to get user info: %w", err)
-	}
-
-	if userInfoResp.JSON200 == nil || userInfoResp.JSON200.Response.User.Id == "" {
-		return fmt.Errorf("failed to get user info: invalid response")
-	}
-
-	userInfo := userInfoResp.JSON200.Response.User
-	maxThreadsStr := userInfo.MaxThreads
-	maxReqPerMinStr := userInfo.MaxRequestsPerMin
-	maxThreads, _ := strconv.Atoi(maxThreadsStr)
-	maxReqPerMin, _ := strconv.Atoi(maxReqPerMinStr)
-
-	if maxThreads == 0 {
-		maxThreads = 1
-	}
-	if maxReqPerMin == 0 {
-		maxReqPerMin = 60
+		return err
 	}
 
 	// Apply user-specified thread limit
@@ -240,12 +408,23 @@ func runScrape(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to initialize cache: %w", err)
 	}
 
+	// Media is downloaded to the ES-DE media folder, or to a staging folder
+	// when only other outputs need it
+	mediaOutputDir := esdeMedia
+	if mediaOutputDir == "" && (batoceraGamelist != "" || muosCatalogue != "" || minuiRoms != "" || pegasusMetadata != "" || retroarchThumbnails != "" || launchBoxDir != "" || attractModeDir != "") {
+		mediaOutputDir, err = os.MkdirTemp("", "rom-tools-media-")
+		if err != nil {
+			return fmt.Errorf("failed to create media staging directory: %w", err)
+		}
+		defer os.RemoveAll(mediaOutputDir)
+	}
+
 	// Build config
 	config := &scraper.Config{
 		SystemID:          systemID,
 		MediaTypes:        mediaTypes,
 		PreferredRegions:  regions,
-		MediaOutputDir:    esdeMedia,
+		MediaOutputDir:    mediaOutputDir,
 		SkipCacheRead:     noCache,
 		SkipCacheWrite:    cacheOnly,
 		Overwrite:         overwrite,
@@ -256,7 +435,7 @@ func runScrape(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create scraper
-	s := scraper.New(client, diskCache, config)
+	s := scraper.New(source, diskCache, config)
 
 	// Parse DAT to get total count for progress
 	dat, err := datfile.Parse(datPath)
@@ -287,7 +466,11 @@ func runScrape(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Filter: %s\n", filterExpr)
 		fmt.Printf("To scrape: %d (filtered out: %d)\n", toScrape, totalInDat-toScrape)
 	}
-	fmt.Printf("Using %d threads, %d req/min\n\n", maxThreads, maxReqPerMin)
+	if maxReqPerMin > 0 {
+		fmt.Printf("Using %d threads, %d req/min\n\n", maxThreads, maxReqPerMin)
+	} else {
+		fmt.Printf("Using %d threads, no request limit\n\n", maxThreads)
+	}
 
 	// Use filtered count for progress tracking
 	total := toScrape
@@ -341,6 +524,55 @@ func runScrape(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if results != nil && batoceraGamelist != "" {
+		generator := batocera.NewGenerator(batoceraGamelist, mediaOutputDir, overwrite, regions)
+		if err := generator.Generate(results); err != nil {
+			return fmt.Errorf("failed to generate Batocera output: %w", err)
+		}
+	}
+
+	if results != nil && muosCatalogue != "" {
+		generator := muos.NewGenerator(muosCatalogue, muosSystem, mediaOutputDir, overwrite, regions)
+		if err := generator.Generate(results); err != nil {
+			return fmt.Errorf("failed to generate MuOS output: %w", err)
+		}
+	}
+
+	if results != nil && minuiRoms != "" {
+		generator := minui.NewGenerator(minuiRoms, mediaOutputDir, boxArtSize, overwrite, regions)
+		if err := generator.Generate(results); err != nil {
+			return fmt.Errorf("failed to generate MinUI output: %w", err)
+		}
+	}
+
+	if results != nil && pegasusMetadata != "" {
+		generator := pegasus.NewGenerator(pegasusMetadata, mediaOutputDir, systemName, overwrite, regions)
+		if err := generator.Generate(results); err != nil {
+			return fmt.Errorf("failed to generate Pegasus output: %w", err)
+		}
+	}
+
+	if results != nil && retroarchPlaylist != "" {
+		generator := retroarch.NewGenerator(retroarchPlaylist, retroarchThumbnails, retroarchROMDir, retroarchDatabase, mediaOutputDir, overwrite, regions)
+		if err := generator.Generate(results); err != nil {
+			return fmt.Errorf("failed to generate RetroArch output: %w", err)
+		}
+	}
+
+	if results != nil && launchBoxDir != "" {
+		generator := launchbox.NewGenerator(launchBoxDir, launchBoxPlatform, launchBoxROMDir, mediaOutputDir, overwrite, regions)
+		if err := generator.Generate(results); err != nil {
+			return fmt.Errorf("failed to generate LaunchBox output: %w", err)
+		}
+	}
+
+	if results != nil && attractModeDir != "" {
+		generator := attractmode.NewGenerator(attractModeDir, attractModeEmulator, mediaOutputDir, overwrite, regions)
+		if err := generator.Generate(results); err != nil {
+			return fmt.Errorf("failed to generate AttractMode output: %w", err)
+		}
+	}
+
 	// Get final stats
 	stats := s.RateLimiterStats()
 
@@ -455,6 +687,126 @@ func runDryRun(filter *scraper.Filter, filterConfig *scraper.FilterConfig) error
 	return nil
 }
 
+// availableSources lists the metadata sources accepted by --source.
+var availableSources = []string{"screenscraper", "hasheous", "launchbox", "libretro"}
+
+// Rate limits for online sources that don't report per-account limits.
+const (
+	defaultThreads      = 2
+	defaultReqPerMinute = 60
+)
+
+// offlineSources are the sources that look games up in local databases, and
+// so need no request limits. LaunchBox media is still downloaded, so that
+// source limits its downloads itself.
+var offlineSources = map[string]bool{
+	"launchbox": true,
+	"libretro":  true,
+}
+
+// buildSource creates the metadata sources named by --source, chained in
+// order, and returns the thread and request limits to scrape with. A request
+// limit of 0 means unlimited.
+func buildSource(ctx context.Context, systemID string) (scraper.MetadataSource, int, int, error) {
+	// Offline sources are only limited by the CPU
+	maxThreads, maxReqPerMin := runtime.NumCPU(), 0
+	if slices.ContainsFunc(sources, func(name string) bool { return !offlineSources[name] }) {
+		maxThreads, maxReqPerMin = defaultThreads, defaultReqPerMinute
+	}
+
+	var chain []scraper.MetadataSource
+	for _, name := range sources {
+		switch name {
+		case "screenscraper":
+			// Initialize client from environment variables
+			client, err := shared.NewClientFromEnv("rom-tools")
+			if err != nil {
+				return nil, 0, 0, err
+			}
+
+			// Screenscraper limits apply to the whole chain
+			maxThreads, maxReqPerMin, err = screenscraperLimits(ctx, client)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			chain = append(chain, scraper.NewScreenScraperSource(client, systemID))
+
+		case "hasheous":
+			client := hasheous.NewClient(
+				hasheous.WithHTTPClient(&http.Client{Timeout: httpTimeout}),
+				hasheous.WithUserAgent("rom-tools"),
+			)
+			chain = append(chain, scraper.NewHasheousSource(client))
+
+		case "launchbox":
+			platform, err := scraper.LookupLaunchBoxPlatform(systemID)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+
+			fmt.Print("Loading LaunchBox metadata...")
+			db, err := liblaunchbox.Parse(launchBoxMetadata)
+			fmt.Print("\r\033[K") // Clear the line
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			// Lookups are local, but images come from the LaunchBox server
+			mediaLimiter := scraper.NewRateLimiter(defaultThreads, defaultReqPerMinute)
+			chain = append(chain, scraper.NewLaunchBoxSource(db, platform, &http.Client{Timeout: httpTimeout}, mediaLimiter))
+
+		case "libretro":
+			// A directory is RetroArch's database folder; pick the system's file
+			path := libretroRDB
+			if info, err := os.Stat(path); err == nil && info.IsDir() {
+				name, err := scraper.LookupLibretroDatabase(systemID)
+				if err != nil {
+					return nil, 0, 0, err
+				}
+				path = filepath.Join(path, name+".rdb")
+			}
+
+			db, err := rdb.Parse(path)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			chain = append(chain, scraper.NewLibretroSource(rdb.NewIndex(db)))
+		}
+	}
+
+	if len(chain) == 1 {
+		return chain[0], maxThreads, maxReqPerMin, nil
+	}
+	return scraper.NewChain(chain...), maxThreads, maxReqPerMin, nil
+}
+
+// screenscraperLimits fetches the thread and request limits of the
+// Screenscraper account.
+func screenscraperLimits(ctx context.Context, client *screenscraper.ScreenscraperClient) (int, int, error) {
+	fmt.Print("Connecting to Screenscraper...")
+	userInfoResp, err := client.GetUserInfoWithResponse(ctx)
+	fmt.Print("\r\033[K") // Clear the line
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get user info: %w", err)
+	}
+
+	if userInfoResp.JSON200 == nil || userInfoResp.JSON200.Response.User.Id == "" {
+		return 0, 0, fmt.Errorf("failed to get user info: invalid response")
+	}
+
+	userInfo := userInfoResp.JSON200.Response.User
+	maxThreads, _ := strconv.Atoi(userInfo.MaxThreads)
+	maxReqPerMin, _ := strconv.Atoi(userInfo.MaxRequestsPerMin)
+
+	if maxThreads == 0 {
+		maxThreads = 1
+	}
+	if maxReqPerMin == 0 {
+		maxReqPerMin = 60
+	}
+
+	return maxThreads, maxReqPerMin, nil
+}
+
 func isTerminal() bool {
 	fileInfo, _ := os.Stdout.Stat()
 	return (fileInfo.Mode() & os.ModeCharDevice) != 0
@@ -473,6 +825,31 @@ func normalizeGamelistPath(path string) string {
 	return path
 }
 
+func normalizePegasusPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return filepath.Join(path, "metadata.pegasus.txt")
+	}
+	if !strings.HasSuffix(path, ".txt") {
+		return filepath.Join(path, "metadata.pegasus.txt")
+	}
+	return path
+}
+
+// normalizePlaylistPath returns the path of a database's playlist if path
+// is a playlists folder
+func normalizePlaylistPath(path, database string) string {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return filepath.Join(path, libretroarch.PlaylistName(database))
+	}
+	if !strings.HasSuffix(path, libretroarch.PlaylistExt) {
+		return filepath.Join(path, libretroarch.PlaylistName(database))
+	}
+	return path
+}
+
 // isBIOS returns true if this is a BIOS entry (should be skipped)
 func isBIOS(g datfile.Game) bool {
 	return g.IsBIOS || strings.Contains(g.Name, "[BIOS]")
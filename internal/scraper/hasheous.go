@@ -0,0 +1,117 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/sargunv/rom-tools/lib/hasheous"
+)
+
+// hasheousImageTypes maps Hasheous image attribute names to ES-DE media types.
+var hasheousImageTypes = map[string]string{
+	"Screenshot":  "screenshots",
+	"TitleScreen": "titlescreens",
+	"BoxArt":      "covers",
+	"Logo":        "marquees",
+	"Fanart":      "fanart",
+}
+
+// Hasheous metadata links to other services
+const (
+	hasheousRetroAchievements = "RetroAchievements"
+	hasheousMapped            = "Mapped"
+)
+
+// HasheousSource is a MetadataSource backed by the Hasheous hash lookup API.
+// Hasheous only matches by hash; entries without hashes are never found.
+type HasheousSource struct {
+	client *hasheous.Client
+}
+
+// NewHasheousSource creates a source using a Hasheous client.
+func NewHasheousSource(client *hasheous.Client) *HasheousSource {
+	return &HasheousSource{client: client}
+}
+
+// Name returns "hasheous".
+func (s *HasheousSource) Name() string {
+	return "hasheous"
+}
+
+// Lookup fetches game info by hashes.
+func (s *HasheousSource) Lookup(ctx context.Context, entry *LookupEntry) (*Game, error) {
+	if entry.Hashes.IsEmpty() {
+		return nil, nil
+	}
+
+	hg, err := s.client.LookupByHash(ctx, hasheous.Hashes{
+		MD5:  entry.Hashes.MD5,
+		SHA1: entry.Hashes.SHA1,
+		CRC:  entry.Hashes.CRC32,
+	})
+	if errors.Is(err, hasheous.ErrNotFound) {
+		return nil, nil
+	}
+	if errors.Is(err, hasheous.ErrRateLimited) {
+		return nil, ErrRateLimited
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.convertGame(hg), nil
+}
+
+// FetchMedia downloads an image by ID.
+func (s *HasheousSource) FetchMedia(ctx context.Context, game *Game, media Media) ([]byte, error) {
+	data, _, err := s.client.DownloadImage(ctx, media.ID)
+	if errors.Is(err, hasheous.ErrNotFound) {
+		return nil, nil
+	}
+	if errors.Is(err, hasheous.ErrRateLimited) {
+		return nil, ErrRateLimited
+	}
+	return data, err
+}
+
+// convertGame converts a Hasheous game to a source-neutral game.
+func (s *HasheousSource) convertGame(hg *hasheous.Game) *Game {
+	game := &Game{
+		Source: s.Name(),
+		ID:     strconv.FormatInt(hg.ID, 10),
+		Name:   hg.Name,
+	}
+	if hg.Publisher != nil {
+		game.Publisher = hg.Publisher.Name
+	}
+
+	// DAT signatures carry the release year
+	for _, sig := range hg.Signatures {
+		if sig.Game.Year != "" {
+			game.Dates = append(game.Dates, RegionText{Text: sig.Game.Year})
+			break
+		}
+	}
+
+	for _, m := range hg.Metadata {
+		if m.Source == hasheousRetroAchievements && m.Status == hasheousMapped {
+			game.CheevosID = m.ID
+		}
+	}
+
+	for _, a := range hg.Attributes {
+		mediaType, ok := hasheousImageTypes[a.Name]
+		if a.Type != hasheous.AttributeImageID || !ok || a.Text() == "" {
+			continue
+		}
+		// The format is only known once downloaded
+		game.Media = append(game.Media, Media{
+			Type:       mediaType,
+			SourceType: a.Name,
+			ID:         a.Text(),
+		})
+	}
+
+	return game
+}
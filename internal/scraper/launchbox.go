@@ -0,0 +1,169 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/sargunv/rom-tools/internal/region"
+	"github.com/sargunv/rom-tools/lib/launchbox"
+)
+
+// launchBoxImageTypes maps ES-DE media types to LaunchBox image types, in
+// fallback order.
+var launchBoxImageTypes = map[string][]string{
+	"screenshots":   {"Screenshot - Gameplay", "Screenshot"},
+	"titlescreens":  {"Screenshot - Game Title"},
+	"covers":        {"Box - Front", "Box - Front - Reconstructed"},
+	"3dboxes":       {"Box - 3D"},
+	"marquees":      {"Clear Logo", "Banner"},
+	"fanart":        {"Fanart - Background"},
+	"physicalmedia": {"Cart - Front", "Disc"},
+	"backcovers":    {"Box - Back", "Box - Back - Reconstructed"},
+}
+
+// launchBoxRegions maps LaunchBox region names to region codes.
+var launchBoxRegions = map[string]string{
+	"north america":  "us",
+	"united states":  "us",
+	"canada":         "ca",
+	"brazil":         "br",
+	"europe":         "eu",
+	"united kingdom": "uk",
+	"germany":        "de",
+	"france":         "fr",
+	"italy":          "it",
+	"spain":          "es",
+	"netherlands":    "nl",
+	"sweden":         "se",
+	"japan":          "jp",
+	"korea":          "kr",
+	"china":          "cn",
+	"asia":           "asi",
+	"australia":      "au",
+	"oceania":        "oce",
+	"world":          "wor",
+}
+
+// LaunchBoxSource is a MetadataSource backed by a local copy of the LaunchBox
+// games database. Games are matched by title, so no network access is needed
+// for metadata; media is downloaded from the LaunchBox image server.
+type LaunchBoxSource struct {
+	db           *launchbox.Database
+	platform     string
+	httpClient   *http.Client
+	mediaLimiter *RateLimiter
+}
+
+// NewLaunchBoxSource creates a source that looks up games of a LaunchBox
+// platform (e.g., "Sega Genesis"). Lookups are not limited, since they don't
+// leave the machine, but media downloads go through mediaLimiter.
+func NewLaunchBoxSource(db *launchbox.Database, platform string, httpClient *http.Client, mediaLimiter *RateLimiter) *LaunchBoxSource {
+	return &LaunchBoxSource{
+		db:           db,
+		platform:     platform,
+		httpClient:   httpClient,
+		mediaLimiter: mediaLimiter,
+	}
+}
+
+// Name returns "launchbox".
+func (s *LaunchBoxSource) Name() string {
+	return "launchbox"
+}
+
+// Lookup finds a game by the entry's name, falling back to its file name.
+func (s *LaunchBoxSource) Lookup(ctx context.Context, entry *LookupEntry) (*Game, error) {
+	for _, name := range []string{entry.Name, BaseName(entry.FileName)} {
+		if games := s.db.Lookup(s.platform, name); len(games) > 0 {
+			return s.convertGame(games[0]), nil
+		}
+	}
+	return nil, nil
+}
+
+// FetchMedia downloads an image from the LaunchBox image server.
+func (s *LaunchBoxSource) FetchMedia(ctx context.Context, game *Game, media Media) ([]byte, error) {
+	if err := s.mediaLimiter.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mediaLimiter.Release()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, media.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		s.mediaLimiter.TriggerBackoff()
+		return nil, ErrRateLimited
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, fmt.Errorf("image download failed: HTTP %d", resp.StatusCode)
+	}
+
+	s.mediaLimiter.ResetBackoff()
+	return io.ReadAll(resp.Body)
+}
+
+// convertGame converts a LaunchBox game to a source-neutral game.
+func (s *LaunchBoxSource) convertGame(lbGame *launchbox.Game) *Game {
+	game := &Game{
+		Source:    s.Name(),
+		ID:        strconv.FormatInt(lbGame.DatabaseID, 10),
+		Name:      lbGame.Name,
+		Developer: lbGame.Developer,
+		Publisher: lbGame.Publisher,
+		Rating:    lbGame.CommunityRating / 5,
+	}
+
+	for _, a := range lbGame.AlternateNames {
+		if code, ok := launchBoxRegions[strings.ToLower(a.Region)]; ok {
+			game.Names = append(game.Names, RegionText{Region: code, Text: a.AlternateName})
+		}
+	}
+	if lbGame.Overview != "" {
+		game.Synopsis = []region.LocalizedEntry{{Language: "en", Text: lbGame.Overview}}
+	}
+	for _, genre := range lbGame.GenreList() {
+		game.Genres = append(game.Genres, Genre{Names: []region.LocalizedEntry{{Language: "en", Text: genre}}})
+	}
+	if date := lbGame.Date(); date != "" {
+		game.Dates = []RegionText{{Text: date}}
+	}
+	if lbGame.MaxPlayers > 0 {
+		game.Players = strconv.Itoa(lbGame.MaxPlayers)
+	}
+
+	// List media by ES-DE type, keeping the fallback order of launchBoxImageTypes
+	for _, esdeType := range AllMediaTypes() {
+		for _, imageType := range launchBoxImageTypes[esdeType] {
+			for _, img := range lbGame.Images {
+				if img.Type != imageType {
+					continue
+				}
+				game.Media = append(game.Media, Media{
+					Type:       esdeType,
+					SourceType: imageType,
+					Region:     launchBoxRegions[strings.ToLower(img.Region)],
+					URL:        img.URL(),
+					Format:     strings.ToLower(strings.TrimPrefix(path.Ext(img.FileName), ".")),
+				})
+			}
+		}
+	}
+
+	return game
+}
@@ -0,0 +1,79 @@
+package scraper
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/sargunv/rom-tools/internal/region"
+	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/rdb"
+)
+
+// LibretroSource is a MetadataSource backed by RetroArch's libretro
+// databases (.rdb files). It works offline and only provides text metadata;
+// no media is available.
+type LibretroSource struct {
+	index *rdb.Index
+}
+
+// NewLibretroSource creates a source that looks up games in an index of
+// libretro databases.
+func NewLibretroSource(index *rdb.Index) *LibretroSource {
+	return &LibretroSource{index: index}
+}
+
+// Name returns "libretro".
+func (s *LibretroSource) Name() string {
+	return "libretro"
+}
+
+// Lookup finds a game by hashes, falling back to the entry's serial.
+func (s *LibretroSource) Lookup(ctx context.Context, entry *LookupEntry) (*Game, error) {
+	if !entry.Hashes.IsEmpty() {
+		e := s.index.Match(entry.Size, core.Hashes{
+			core.HashSHA1:  entry.Hashes.SHA1,
+			core.HashMD5:   entry.Hashes.MD5,
+			core.HashCRC32: entry.Hashes.CRC32,
+		})
+		if e != nil {
+			return s.convertGame(e), nil
+		}
+	}
+	if entry.Serial != "" {
+		if entries := s.index.LookupSerial(entry.Serial, ""); len(entries) > 0 {
+			return s.convertGame(entries[0]), nil
+		}
+	}
+	return nil, nil
+}
+
+// FetchMedia always returns nil, since libretro databases have no media.
+func (s *LibretroSource) FetchMedia(ctx context.Context, game *Game, media Media) ([]byte, error) {
+	return nil, nil
+}
+
+// convertGame converts a libretro database entry to a source-neutral game.
+func (s *LibretroSource) convertGame(e *rdb.Entry) *Game {
+	game := &Game{
+		Source:    s.Name(),
+		ID:        e.Database + "/" + e.Name,
+		Name:      e.Name,
+		Developer: e.Developer,
+		Publisher: e.Publisher,
+	}
+
+	if e.Description != "" && e.Description != e.Name {
+		game.Synopsis = []region.LocalizedEntry{{Language: "en", Text: e.Description}}
+	}
+	if e.Genre != "" {
+		game.Genres = []Genre{{Names: []region.LocalizedEntry{{Language: "en", Text: e.Genre}}}}
+	}
+	if date := e.Date(); date != "" {
+		game.Dates = []RegionText{{Text: date}}
+	}
+	if e.Users > 0 {
+		game.Players = strconv.Itoa(e.Users)
+	}
+
+	return game
+}
@@ -0,0 +1,149 @@
+package attractmode
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/sargunv/rom-tools/internal/scraper"
+	"github.com/sargunv/rom-tools/internal/scraper/output"
+	"github.com/sargunv/rom-tools/lib/attractmode"
+)
+
+// artworkTypes maps AttractMode artwork types to ES-DE media types, in
+// fallback order. ES-DE marquees are logos, which are the closest match for
+// both wheels and marquees.
+var artworkTypes = []struct {
+	artworkType string
+	mediaTypes  []string
+}{
+	{attractmode.ArtworkSnap, []string{"screenshots"}},
+	{attractmode.ArtworkSnap, []string{"videos"}}, // Played instead of the image
+	{attractmode.ArtworkFlyer, []string{"covers", "3dboxes"}},
+	{attractmode.ArtworkWheel, []string{"marquees"}},
+	{attractmode.ArtworkMarquee, []string{"marquees"}},
+}
+
+// Generator generates AttractMode romlist and artwork output
+type Generator struct {
+	configDir string
+	emulator  string
+	mediaDir  string
+	overwrite bool
+	regions   []string
+}
+
+// NewGenerator creates a new AttractMode output generator, writing the
+// romlist of an emulator (as named in AttractMode) in an AttractMode config
+// folder. Artwork is copied from mediaDir, where the scraper downloaded it,
+// into the emulator's scraper folder.
+func NewGenerator(configDir, emulator, mediaDir string, overwrite bool, preferredRegions []string) *Generator {
+	return &Generator{
+		configDir: configDir,
+		emulator:  emulator,
+		mediaDir:  mediaDir,
+		overwrite: overwrite,
+		regions:   preferredRegions,
+	}
+}
+
+// Generate creates AttractMode output from scrape results. Existing games
+// are matched by name, and fields the scraper doesn't fill in are kept.
+func (g *Generator) Generate(results *scraper.ScrapeResults) error {
+	path := attractmode.RomlistPath(g.configDir, g.emulator)
+	games, err := attractmode.ReadRomlist(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		games = nil // Start fresh
+	} else if err != nil {
+		return err
+	}
+
+	byName := make(map[string]int, len(games))
+	for i, game := range games {
+		byName[game.Name] = i
+	}
+
+	for _, result := range results.Results {
+		if result.Game == nil {
+			continue // Skip not found or errored
+		}
+
+		game := g.resultToGame(result)
+		if i, ok := byName[game.Name]; ok {
+			g.merge(&games[i], game)
+		} else {
+			byName[game.Name] = len(games)
+			games = append(games, game)
+		}
+
+		_ = g.saveArtwork(result) // Log but don't fail
+	}
+
+	return attractmode.WriteRomlist(path, games)
+}
+
+// merge fills in an existing game with scraped fields, replacing them if
+// overwrite is set
+func (g *Generator) merge(existing *attractmode.Game, game attractmode.Game) {
+	set := func(dst *string, src string) {
+		if src != "" && (g.overwrite || *dst == "") {
+			*dst = src
+		}
+	}
+	set(&existing.Title, game.Title)
+	set(&existing.Emulator, game.Emulator)
+	set(&existing.CloneOf, game.CloneOf)
+	set(&existing.Year, game.Year)
+	set(&existing.Manufacturer, game.Manufacturer)
+	set(&existing.Category, game.Category)
+	set(&existing.Players, game.Players)
+}
+
+// resultToGame converts a scrape result to a romlist entry
+func (g *Generator) resultToGame(result *scraper.ScrapeResult) attractmode.Game {
+	entry := result.Entry
+	game := result.Game
+	romRegions := entry.Regions
+
+	title := game.SelectName(romRegions, g.regions)
+	if title == "" {
+		title = entry.Name
+	}
+
+	year := game.SelectDate(romRegions, g.regions)
+	if len(year) > 4 {
+		year = year[:4]
+	}
+
+	manufacturer := game.Publisher
+	if manufacturer == "" {
+		manufacturer = game.Developer
+	}
+
+	return attractmode.Game{
+		Name:         entry.BaseName,
+		Title:        title,
+		Emulator:     g.emulator,
+		CloneOf:      entry.CloneOf,
+		Year:         year,
+		Manufacturer: manufacturer,
+		Category:     strings.Join(game.SelectGenres(romRegions, g.regions), " / "),
+		Players:      game.Players,
+	}
+}
+
+// saveArtwork copies a result's media into the artwork folders
+func (g *Generator) saveArtwork(result *scraper.ScrapeResult) error {
+	for _, at := range artworkTypes {
+		src := output.FindMedia(result, g.mediaDir, at.mediaTypes...)
+		if src == "" {
+			continue
+		}
+		dst := attractmode.ArtworkPath(g.configDir, g.emulator, at.artworkType, result.Entry.BaseName, filepath.Ext(src))
+		if err := output.CopyFile(src, dst, g.overwrite); err != nil {
+			return err
+		}
+	}
+	return nil
+}
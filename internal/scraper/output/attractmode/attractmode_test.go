@@ -0,0 +1,126 @@
+package attractmode
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sargunv/rom-tools/internal/region"
+	"github.com/sargunv/rom-tools/internal/scraper"
+	"github.com/sargunv/rom-tools/lib/attractmode"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	mediaDir := filepath.Join(dir, "staging")
+	configDir := filepath.Join(dir, ".attract")
+	emulator := "mame"
+
+	for _, file := range []string{"marquees/sf2.png", "screenshots/sf2.png", "videos/sf2.mp4"} {
+		path := filepath.Join(mediaDir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(file), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// An existing entry with cabinet details that must be kept
+	romlistPath := attractmode.RomlistPath(configDir, emulator)
+	existing := []attractmode.Game{{Name: "pacman", Title: "My Pac-Man", Emulator: emulator, Rotation: "90", Control: "joystick (4-way)"}}
+	if err := attractmode.WriteRomlist(romlistPath, existing); err != nil {
+		t.Fatal(err)
+	}
+
+	results := &scraper.ScrapeResults{Results: []*scraper.ScrapeResult{
+		{
+			Entry: &scraper.LookupEntry{Name: "Street Fighter II", BaseName: "sf2"},
+			Game: &scraper.Game{
+				Name:      "Street Fighter II: The World Warrior",
+				Developer: "Capcom",
+				Dates:     []scraper.RegionText{{Text: "1991-02-06"}},
+				Genres:    []scraper.Genre{{Names: []region.LocalizedEntry{{Language: "en", Text: "Fighting"}}}},
+				Players:   "2",
+			},
+			Media: map[string]string{
+				"marquees":    filepath.Join("marquees", "sf2.png"),
+				"screenshots": filepath.Join("screenshots", "sf2.png"),
+				"videos":      filepath.Join("videos", "sf2.mp4"),
+			},
+		},
+		{
+			Entry: &scraper.LookupEntry{Name: "Street Fighter II (Japan)", BaseName: "sf2j", CloneOf: "sf2"},
+			Game:  &scraper.Game{Name: "Street Fighter II: The World Warrior (Japan)", Publisher: "Capcom"},
+		},
+		{
+			Entry: &scraper.LookupEntry{Name: "Pac-Man", BaseName: "pacman"},
+			Game:  &scraper.Game{Name: "Pac-Man", Publisher: "Namco", Players: "2"},
+		},
+		{
+			Entry: &scraper.LookupEntry{Name: "Missing", BaseName: "missing"},
+		},
+	}}
+
+	g := NewGenerator(configDir, emulator, mediaDir, false, []string{"us"})
+	if err := g.Generate(results); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	games, err := attractmode.ReadRomlist(romlistPath)
+	if err != nil {
+		t.Fatalf("ReadRomlist() error = %v", err)
+	}
+	if len(games) != 3 {
+		t.Fatalf("len(games) = %d, want 3", len(games))
+	}
+
+	wantPacman := attractmode.Game{
+		Name: "pacman", Title: "My Pac-Man", Emulator: emulator, Manufacturer: "Namco", Players: "2",
+		Rotation: "90", Control: "joystick (4-way)",
+	}
+	if games[0] != wantPacman {
+		t.Errorf("existing game = %+v, want %+v", games[0], wantPacman)
+	}
+	wantSF2 := attractmode.Game{
+		Name: "sf2", Title: "Street Fighter II: The World Warrior", Emulator: emulator, Year: "1991",
+		Manufacturer: "Capcom", Category: "Fighting", Players: "2",
+	}
+	if games[1] != wantSF2 {
+		t.Errorf("new game = %+v, want %+v", games[1], wantSF2)
+	}
+	if games[2].CloneOf != "sf2" {
+		t.Errorf("clone CloneOf = %q, want sf2", games[2].CloneOf)
+	}
+
+	for _, at := range []struct{ artworkType, ext string }{
+		{attractmode.ArtworkSnap, ".png"},
+		{attractmode.ArtworkSnap, ".mp4"},
+		{attractmode.ArtworkWheel, ".png"},
+		{attractmode.ArtworkMarquee, ".png"},
+	} {
+		if _, err := os.Stat(attractmode.ArtworkPath(configDir, emulator, at.artworkType, "sf2", at.ext)); err != nil {
+			t.Errorf("%s%s not copied: %v", at.artworkType, at.ext, err)
+		}
+	}
+	if _, err := os.Stat(attractmode.ArtworkPath(configDir, emulator, attractmode.ArtworkFlyer, "sf2", ".png")); err == nil {
+		t.Error("flyer written without a cover")
+	}
+}
+
+func TestGenerate_UnreadableRomlist(t *testing.T) {
+	// A directory in place of the romlist can't be read
+	configDir := t.TempDir()
+	romlistPath := attractmode.RomlistPath(configDir, "mame")
+	if err := os.MkdirAll(romlistPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	results := &scraper.ScrapeResults{Results: []*scraper.ScrapeResult{
+		{Entry: &scraper.LookupEntry{Name: "Pac-Man", BaseName: "pacman"}, Game: &scraper.Game{Name: "Pac-Man"}},
+	}}
+
+	g := NewGenerator(configDir, "mame", t.TempDir(), false, nil)
+	if err := g.Generate(results); err == nil {
+		t.Error("Generate() expected error for unreadable romlist")
+	}
+}
@@ -0,0 +1,181 @@
+// Package batocera writes scrape results as a Batocera/RetroBat gamelist.xml.
+//
+// Manuals and Batocera genre IDs are not scraped. The manual and genreid
+// elements are only preserved from games already in the gamelist.
+package batocera
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sargunv/rom-tools/internal/region"
+	"github.com/sargunv/rom-tools/internal/scraper"
+	"github.com/sargunv/rom-tools/internal/scraper/output"
+	"github.com/sargunv/rom-tools/internal/scraper/output/esde"
+	"github.com/sargunv/rom-tools/lib/batocera"
+	libesde "github.com/sargunv/rom-tools/lib/esde"
+)
+
+// mediaField describes where a gamelist media element comes from and where
+// its file is saved, relative to the gamelist
+type mediaField struct {
+	mediaTypes []string // ES-DE media types, in fallback order
+	dir        string   // Folder next to the gamelist
+	suffix     string   // Appended to the ROM name
+	set        func(game *batocera.Game, path string)
+}
+
+// mediaFields lists the media elements written for each game
+var mediaFields = []mediaField{
+	{[]string{"screenshots"}, "images", "image", func(g *batocera.Game, p string) { g.Image = p }},
+	{[]string{"covers", "3dboxes"}, "images", "thumb", func(g *batocera.Game, p string) { g.Thumbnail = p }},
+	{[]string{"titlescreens"}, "images", "titleshot", func(g *batocera.Game, p string) { g.TitleShot = p }},
+	{[]string{"marquees"}, "images", "marquee", func(g *batocera.Game, p string) { g.Marquee, g.Wheel = p, p }},
+	{[]string{"fanart"}, "images", "fanart", func(g *batocera.Game, p string) { g.Fanart = p }},
+	{[]string{"backcovers"}, "images", "boxback", func(g *batocera.Game, p string) { g.BoxBack = p }},
+	{[]string{"videos"}, "videos", "video", func(g *batocera.Game, p string) { g.Video = p }},
+}
+
+// Generator generates Batocera/RetroBat compatible output
+type Generator struct {
+	gamelistPath string
+	mediaDir     string
+	overwrite    bool
+	regions      []string
+	base         *esde.Generator
+	now          func() time.Time
+}
+
+// NewGenerator creates a new Batocera output generator. Media is copied from
+// mediaDir, where the scraper downloaded it, into folders next to the
+// gamelist.
+func NewGenerator(gamelistPath, mediaDir string, overwrite bool, preferredRegions []string) *Generator {
+	return &Generator{
+		gamelistPath: gamelistPath,
+		mediaDir:     mediaDir,
+		overwrite:    overwrite,
+		regions:      preferredRegions,
+		base:         esde.NewGenerator(gamelistPath, mediaDir, overwrite, preferredRegions),
+		now:          time.Now,
+	}
+}
+
+// Generate creates Batocera output from scrape results
+func (g *Generator) Generate(results *scraper.ScrapeResults) error {
+	// Load existing gamelist if present
+	var existing *batocera.GameList
+	data, err := os.ReadFile(g.gamelistPath)
+	if err == nil {
+		existing, err = batocera.Parse(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse gamelist: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// Convert results to games, saving their media
+	newGames := make([]batocera.Game, 0)
+	for _, result := range results.Results {
+		if result.Game == nil {
+			continue // Skip not found or errored
+		}
+		newGames = append(newGames, g.resultToGame(result))
+	}
+
+	// Merge with existing
+	finalList := &batocera.GameList{Games: newGames}
+	if existing != nil {
+		finalList.Folders = existing.Folders
+		finalList.Games = esde.MergeGames(existing.Games, newGames, func(game *batocera.Game) string { return game.Path }, g.overwrite)
+	}
+
+	// Write gamelist
+	return g.writeGameList(finalList)
+}
+
+// resultToGame converts a scrape result to a Batocera game entry and copies
+// its media next to the gamelist
+func (g *Generator) resultToGame(result *scraper.ScrapeResult) batocera.Game {
+	entry := result.Entry
+	game := result.Game
+
+	bg := batocera.Game{
+		ID:     game.ID,
+		Source: game.Source,
+		Game:   g.base.ResultToGame(result),
+		MD5:    entry.Hashes.MD5,
+		CRC32:  entry.Hashes.CRC32,
+		Scrap:  []batocera.Scrap{{Name: game.Source, Date: libesde.DateTime{Time: g.now().UTC().Truncate(time.Second)}}},
+	}
+
+	if len(entry.Regions) > 0 {
+		bg.Region = entry.Regions[0]
+	}
+	bg.Lang = languages(entry.Regions)
+	if id, err := strconv.Atoi(game.CheevosID); err == nil {
+		bg.CheevosID = id
+	}
+
+	for _, field := range mediaFields {
+		if path := g.saveMedia(result, field); path != "" {
+			field.set(&bg, path)
+		}
+	}
+
+	return bg
+}
+
+// saveMedia copies the first available media of a field next to the
+// gamelist, returning its relative path, or "" if there is none
+func (g *Generator) saveMedia(result *scraper.ScrapeResult, field mediaField) string {
+	src := output.FindMedia(result, g.mediaDir, field.mediaTypes...)
+	if src == "" {
+		return ""
+	}
+
+	name := fmt.Sprintf("%s-%s%s", result.Entry.BaseName, field.suffix, filepath.Ext(src))
+	dst := filepath.Join(filepath.Dir(g.gamelistPath), field.dir, name)
+	if err := output.CopyFile(src, dst, g.overwrite); err != nil {
+		return "" // Log but don't fail
+	}
+	return "./" + field.dir + "/" + name
+}
+
+// writeGameList writes the gamelist.xml file
+func (g *Generator) writeGameList(list *batocera.GameList) error {
+	// Ensure directory exists
+	dir := filepath.Dir(g.gamelistPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create gamelist directory: %w", err)
+	}
+
+	data, err := batocera.Write(list)
+	if err != nil {
+		return fmt.Errorf("failed to marshal gamelist: %w", err)
+	}
+
+	if err := os.WriteFile(g.gamelistPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write gamelist: %w", err)
+	}
+
+	return nil
+}
+
+// languages returns the comma-separated languages of region codes
+func languages(regions []string) string {
+	var langs []string
+	for _, r := range regions {
+		if lang, ok := region.ToLanguage[r]; ok && !slices.Contains(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+	return strings.Join(langs, ",")
+}
@@ -0,0 +1,127 @@
+package batocera
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sargunv/rom-tools/internal/scraper"
+	"github.com/sargunv/rom-tools/lib/batocera"
+	libesde "github.com/sargunv/rom-tools/lib/esde"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	mediaDir := filepath.Join(dir, "media")
+	gamelistPath := filepath.Join(dir, "roms", "megadrive", "gamelist.xml")
+
+	for _, path := range []string{"covers/Sonic.jpg", "marquees/Sonic.png"} {
+		full := filepath.Join(mediaDir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(path), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A hand-edited entry that must be kept, and one that will be replaced
+	existing := &batocera.GameList{Games: []batocera.Game{
+		{Game: libesde.Game{Path: "./Other.md", Name: "Other"}, GenreID: 256},
+		{Game: libesde.Game{Path: "./Sonic.md", Name: "Old Name"}},
+	}}
+	data, err := batocera.Write(existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(gamelistPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(gamelistPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results := &scraper.ScrapeResults{Results: []*scraper.ScrapeResult{{
+		Entry: &scraper.LookupEntry{
+			Name:     "Sonic.md",
+			BaseName: "Sonic",
+			Regions:  []string{"us", "eu"},
+			Hashes:   scraper.Hashes{MD5: "1bc674be034e43c96b86487ac69d9293", CRC32: "f9394e97"},
+		},
+		Game: &scraper.Game{Source: "screenscraper", ID: "3", Name: "Sonic the Hedgehog", CheevosID: "1"},
+		Media: map[string]string{
+			"covers":   filepath.Join("covers", "Sonic.jpg"),
+			"marquees": filepath.Join("marquees", "Sonic.png"),
+		},
+	}}}
+
+	g := NewGenerator(gamelistPath, mediaDir, true, nil)
+	g.now = func() time.Time { return time.Date(2024, 1, 15, 14, 30, 22, 0, time.UTC) }
+	if err := g.Generate(results); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err = os.ReadFile(gamelistPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := batocera.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(list.Games) != 2 {
+		t.Fatalf("len(Games) = %d, want 2", len(list.Games))
+	}
+
+	sonic, other := list.Games[0], list.Games[1]
+	if other.Path != "./Other.md" || other.GenreID != 256 {
+		t.Errorf("kept entry = %+v", other)
+	}
+	if sonic.Name != "Sonic the Hedgehog" || sonic.ID != "3" || sonic.Source != "screenscraper" {
+		t.Errorf("entry = %+v", sonic)
+	}
+	if sonic.Thumbnail != "./images/Sonic-thumb.jpg" || sonic.Marquee != "./images/Sonic-marquee.png" || sonic.Wheel != sonic.Marquee || sonic.Image != "" {
+		t.Errorf("media = thumb %q, marquee %q, wheel %q, image %q", sonic.Thumbnail, sonic.Marquee, sonic.Wheel, sonic.Image)
+	}
+	if sonic.Region != "us" || sonic.Lang != "en" || sonic.CheevosID != 1 {
+		t.Errorf("region, lang, cheevosId = %q, %q, %d", sonic.Region, sonic.Lang, sonic.CheevosID)
+	}
+	if sonic.MD5 != "1bc674be034e43c96b86487ac69d9293" || sonic.CRC32 != "f9394e97" {
+		t.Errorf("hashes = %q, %q", sonic.MD5, sonic.CRC32)
+	}
+	if len(sonic.Scrap) != 1 || sonic.Scrap[0].Name != "screenscraper" || sonic.Scrap[0].Date.Year() != 2024 {
+		t.Errorf("Scrap = %+v", sonic.Scrap)
+	}
+
+	if _, err := os.Stat(filepath.Join(filepath.Dir(gamelistPath), "images", "Sonic-thumb.jpg")); err != nil {
+		t.Errorf("thumbnail not copied: %v", err)
+	}
+}
+
+func TestGenerate_InvalidGameList(t *testing.T) {
+	gamelistPath := filepath.Join(t.TempDir(), "gamelist.xml")
+	invalid := []byte("<gameList><game><name>Sonic</name>")
+	if err := os.WriteFile(gamelistPath, invalid, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results := &scraper.ScrapeResults{Results: []*scraper.ScrapeResult{{
+		Entry: &scraper.LookupEntry{Name: "Sonic.md", BaseName: "Sonic"},
+		Game:  &scraper.Game{Source: "screenscraper", ID: "3", Name: "Sonic the Hedgehog"},
+	}}}
+
+	g := NewGenerator(gamelistPath, t.TempDir(), false, nil)
+	if err := g.Generate(results); err == nil {
+		t.Error("Generate() expected error for invalid gamelist")
+	}
+
+	// The user's gamelist must be left alone
+	data, err := os.ReadFile(gamelistPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(invalid) {
+		t.Errorf("gamelist = %q, want it unchanged", data)
+	}
+}
@@ -9,10 +9,8 @@ import (
 	"strings"
 	"time"
 
-	"github.com/sargunv/rom-tools/internal/region"
 	"github.com/sargunv/rom-tools/internal/scraper"
 	"github.com/sargunv/rom-tools/lib/esde"
-	"github.com/sargunv/rom-tools/lib/screenscraper"
 )
 
 // Generator generates ES-DE compatible output
@@ -51,7 +49,7 @@ func (g *Generator) Generate(results *scraper.ScrapeResults) error {
 			continue // Skip not found or errored
 		}
 
-		game := g.resultToGame(result)
+		game := g.ResultToGame(result)
 		newGames = append(newGames, game)
 
 		// Save media files
@@ -68,69 +66,36 @@ func (g *Generator) Generate(results *scraper.ScrapeResults) error {
 	return g.writeGameList(finalList)
 }
 
-// resultToGame converts a scrape result to an ES-DE game entry
-func (g *Generator) resultToGame(result *scraper.ScrapeResult) esde.Game {
+// ResultToGame converts a scrape result to an ES-DE game entry
+func (g *Generator) ResultToGame(result *scraper.ScrapeResult) esde.Game {
 	entry := result.Entry
-	ssGame := result.Game
+	game := result.Game
 
 	// Get localized text
 	romRegions := entry.Regions
 	userRegions := g.regions
 
 	// Build name
-	name := selectName(ssGame.Names, romRegions, userRegions)
-	if name == "" {
-		name = ssGame.Name
-	}
+	name := game.SelectName(romRegions, userRegions)
 	if name == "" {
 		name = entry.Name
 	}
 
-	// Build description
-	desc := selectLocalizedText(ssGame.Synopsis, romRegions, userRegions)
-
-	// Build genre
-	var genres []string
-	for _, genre := range ssGame.Genres {
-		genreName := selectLocalizedText(genre.Names, romRegions, userRegions)
-		if genreName != "" {
-			genres = append(genres, genreName)
-		}
-	}
-
-	// Format rating (0-1 scale)
-	var rating float64
-	if ssGame.Note.Text != "" {
-		// Screenscraper uses 0-20 scale, convert to 0-1
-		if noteVal, err := strconv.ParseFloat(ssGame.Note.Text, 64); err == nil {
-			rating = noteVal / 20.0
-		}
-	}
-
-	// Format release date (YYYYMMDDTHHMMSS)
-	releaseDate := selectReleaseDate(ssGame.Dates, romRegions, userRegions)
-
-	// Get developer, publisher
-	developer := ssGame.Developer.Text
-	publisher := ssGame.Publisher.Text
-
 	// Parse players
 	var players int
-	if ssGame.Players.Text != "" {
-		if p, err := strconv.Atoi(ssGame.Players.Text); err == nil {
-			players = p
-		}
+	if p, err := strconv.Atoi(game.Players); err == nil {
+		players = p
 	}
 
 	return esde.Game{
 		Path:        "./" + entry.BaseName + filepath.Ext(entry.Name),
 		Name:        name,
-		Desc:        desc,
-		Rating:      rating,
-		ReleaseDate: releaseDate,
-		Developer:   developer,
-		Publisher:   publisher,
-		Genre:       strings.Join(genres, ", "),
+		Desc:        game.SelectSynopsis(romRegions, userRegions),
+		Rating:      game.Rating,
+		ReleaseDate: ParseDate(game.SelectDate(romRegions, userRegions)),
+		Developer:   game.Developer,
+		Publisher:   game.Publisher,
+		Genre:       strings.Join(game.SelectGenres(romRegions, userRegions), ", "),
 		Players:     players,
 	}
 }
@@ -164,33 +129,44 @@ func (g *Generator) merge(existing, new *esde.GameList) *esde.GameList {
 		return new
 	}
 
+	merged := *existing
+	merged.Games = MergeGames(existing.Games, new.Games, func(game *esde.Game) string { return game.Path }, g.overwrite)
+	return &merged
+}
+
+// MergeGames combines existing and new gamelist entries, matched by path.
+// A new entry replaces the existing one with the same path only when
+// overwrite is set. Existing entries without a new counterpart are kept after
+// the new ones, in their original order.
+func MergeGames[T any](existing, new []T, path func(*T) string, overwrite bool) []T {
 	// Index existing by path
-	existingByPath := make(map[string]*esde.Game)
-	for i := range existing.Games {
-		existingByPath[existing.Games[i].Path] = &existing.Games[i]
+	existingByPath := make(map[string]int, len(existing))
+	for i := range existing {
+		existingByPath[path(&existing[i])] = i
 	}
 
-	result := &esde.GameList{
-		Games: make([]esde.Game, 0, len(existing.Games)+len(new.Games)),
-	}
+	result := make([]T, 0, len(existing)+len(new))
+	used := make([]bool, len(existing))
 
 	// Process new games
-	for _, game := range new.Games {
-		if existingGame, ok := existingByPath[game.Path]; ok {
-			if g.overwrite {
-				result.Games = append(result.Games, game) // Use new
+	for i := range new {
+		if j, ok := existingByPath[path(&new[i])]; ok && !used[j] {
+			if overwrite {
+				result = append(result, new[i]) // Use new
 			} else {
-				result.Games = append(result.Games, *existingGame) // Keep existing
+				result = append(result, existing[j]) // Keep existing
 			}
-			delete(existingByPath, game.Path)
+			used[j] = true
 		} else {
-			result.Games = append(result.Games, game) // Add new
+			result = append(result, new[i]) // Add new
 		}
 	}
 
 	// Add remaining existing games
-	for _, game := range existingByPath {
-		result.Games = append(result.Games, *game)
+	for j := range existing {
+		if !used[j] {
+			result = append(result, existing[j])
+		}
 	}
 
 	return result
@@ -221,78 +197,9 @@ func (g *Generator) writeGameList(list *esde.GameList) error {
 	return nil
 }
 
-// Helper functions for localized content selection
-
-func selectName(names []screenscraper.NameEntry, romRegions, userRegions []string) string {
-	if len(names) == 0 {
-		return ""
-	}
-
-	searchOrder := region.BuildSearchOrder(romRegions, userRegions)
-
-	for _, r := range searchOrder {
-		for _, n := range names {
-			if n.Region == r && n.Text != "" {
-				return n.Text
-			}
-		}
-	}
-
-	// Fallback to any
-	for _, n := range names {
-		if n.Text != "" {
-			return n.Text
-		}
-	}
-
-	return ""
-}
-
-func selectLocalizedText(entries []screenscraper.LocalizedName, romRegions, userRegions []string) string {
-	if len(entries) == 0 {
-		return ""
-	}
-
-	// Convert to region.LocalizedEntry
-	regionEntries := make([]region.LocalizedEntry, len(entries))
-	for i, e := range entries {
-		regionEntries[i] = region.LocalizedEntry{
-			Language: e.Language,
-			Text:     e.Text,
-		}
-	}
-
-	return region.SelectLocalizedText(regionEntries, romRegions, userRegions)
-}
-
-func selectReleaseDate(dates []screenscraper.DateEntry, romRegions, userRegions []string) esde.DateTime {
-	if len(dates) == 0 {
-		return esde.DateTime{}
-	}
-
-	searchOrder := region.BuildSearchOrder(romRegions, userRegions)
-
-	for _, r := range searchOrder {
-		for _, d := range dates {
-			if d.Region == r && d.Text != "" {
-				return parseDate(d.Text)
-			}
-		}
-	}
-
-	// Fallback to any
-	for _, d := range dates {
-		if d.Text != "" {
-			return parseDate(d.Text)
-		}
-	}
-
-	return esde.DateTime{}
-}
-
-// parseDate converts screenscraper date format to esde.DateTime
-// Screenscraper: "1991-06-23" or "1991"
-func parseDate(date string) esde.DateTime {
+// ParseDate converts a metadata source date to esde.DateTime
+// Dates look like "1991-06-23", "1991-06" or "1991"
+func ParseDate(date string) esde.DateTime {
 	// Remove any dashes
 	clean := strings.ReplaceAll(date, "-", "")
 
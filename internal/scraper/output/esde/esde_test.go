@@ -0,0 +1,50 @@
+package esde
+
+import (
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/esde"
+)
+
+func TestMergeGames(t *testing.T) {
+	path := func(g *esde.Game) string { return g.Path }
+	existing := []esde.Game{
+		{Path: "./a.gb", Name: "A (edited)"},
+		{Path: "./b.gb", Name: "B"},
+		{Path: "./c.gb", Name: "C"},
+	}
+	scraped := []esde.Game{
+		{Path: "./c.gb", Name: "C (new)"},
+		{Path: "./d.gb", Name: "D"},
+		{Path: "./a.gb", Name: "A (new)"},
+	}
+
+	names := func(games []esde.Game) []string {
+		var out []string
+		for _, g := range games {
+			out = append(out, g.Name)
+		}
+		return out
+	}
+
+	got := names(MergeGames(existing, scraped, path, false))
+	want := []string{"C", "D", "A (edited)", "B"}
+	if len(got) != len(want) {
+		t.Fatalf("MergeGames() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("MergeGames() = %v, want %v", got, want)
+			break
+		}
+	}
+
+	got = names(MergeGames(existing, scraped, path, true))
+	want = []string{"C (new)", "D", "A (new)", "B"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("MergeGames(overwrite) = %v, want %v", got, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package launchbox
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sargunv/rom-tools/internal/scraper"
+	"github.com/sargunv/rom-tools/internal/scraper/output"
+	"github.com/sargunv/rom-tools/lib/launchbox"
+)
+
+// imageTypes maps LaunchBox image types to ES-DE media types, in fallback
+// order
+var imageTypes = []struct {
+	imageType  string
+	mediaTypes []string
+}{
+	{launchbox.ImageBoxFront, []string{"covers"}},
+	{launchbox.ImageBoxBack, []string{"backcovers"}},
+	{launchbox.ImageBox3D, []string{"3dboxes"}},
+	{launchbox.ImageCartFront, []string{"physicalmedia"}},
+	{launchbox.ImageClearLogo, []string{"marquees"}},
+	{launchbox.ImageFanart, []string{"fanart"}},
+	{launchbox.ImageScreenshot, []string{"screenshots"}},
+	{launchbox.ImageScreenshotTitle, []string{"titlescreens"}},
+}
+
+// Generator generates LaunchBox platform XML and image output
+type Generator struct {
+	launchBoxDir string
+	platform     string
+	romDir       string
+	mediaDir     string
+	overwrite    bool
+	regions      []string
+	location     *time.Location // Time zone of release dates
+}
+
+// NewGenerator creates a new LaunchBox output generator for a LaunchBox
+// platform (e.g., "Nintendo Game Boy"). Games point to the ROM files in
+// romDir, the ROM folder as LaunchBox sees it (e.g., "\\nas\roms\gb" or
+// "..\Roms\gb"). Media is copied from mediaDir, where the scraper downloaded
+// it, into the LaunchBox folder's Images and Videos folders.
+func NewGenerator(launchBoxDir, platform, romDir, mediaDir string, overwrite bool, preferredRegions []string) *Generator {
+	return &Generator{
+		launchBoxDir: launchBoxDir,
+		platform:     platform,
+		romDir:       romDir,
+		mediaDir:     mediaDir,
+		overwrite:    overwrite,
+		regions:      preferredRegions,
+		location:     time.Local,
+	}
+}
+
+// Generate creates LaunchBox output from scrape results. Existing games are
+// matched by application path and keep their ID, and fields and records the
+// scraper doesn't fill in are kept.
+func (g *Generator) Generate(results *scraper.ScrapeResults) error {
+	path := launchbox.PlatformFilePath(g.launchBoxDir, g.platform)
+	pf, err := launchbox.ReadPlatformFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		pf = &launchbox.PlatformFile{} // Start fresh
+	} else if err != nil {
+		return err
+	}
+
+	for _, result := range results.Results {
+		if result.Game == nil {
+			continue // Skip not found or errored
+		}
+
+		game := g.merge(pf, g.resultToGame(result))
+
+		// Media is named after the title in the platform file, which may be
+		// a kept hand edit
+		_ = g.saveMedia(result, game.Title) // Log but don't fail
+	}
+
+	return launchbox.WritePlatformFile(path, pf)
+}
+
+// merge adds a game to a platform file, or fills in the existing game with
+// the same application path, and returns the game in the file
+func (g *Generator) merge(pf *launchbox.PlatformFile, game launchbox.PlatformGame) *launchbox.PlatformGame {
+	var existing *launchbox.PlatformGame
+	for i := range pf.Games {
+		if strings.EqualFold(pf.Games[i].ApplicationPath, game.ApplicationPath) {
+			existing = &pf.Games[i]
+			break
+		}
+	}
+	if existing == nil {
+		game.ID = launchbox.NewID()
+		pf.Games = append(pf.Games, game)
+		return &pf.Games[len(pf.Games)-1]
+	}
+
+	if existing.ID == "" {
+		existing.ID = launchbox.NewID()
+	}
+	set := func(dst *string, src string) {
+		if src != "" && (g.overwrite || *dst == "") {
+			*dst = src
+		}
+	}
+	set(&existing.Title, game.Title)
+	set(&existing.Platform, game.Platform)
+	set(&existing.Developer, game.Developer)
+	set(&existing.Publisher, game.Publisher)
+	set(&existing.ReleaseDate, game.ReleaseDate)
+	set(&existing.Genre, game.Genre)
+	set(&existing.Notes, game.Notes)
+	if game.MaxPlayers > 0 && (g.overwrite || existing.MaxPlayers == 0) {
+		existing.MaxPlayers = game.MaxPlayers
+	}
+	return existing
+}
+
+// resultToGame converts a scrape result to a LaunchBox game, without an ID
+func (g *Generator) resultToGame(result *scraper.ScrapeResult) launchbox.PlatformGame {
+	entry := result.Entry
+	game := result.Game
+	romRegions := entry.Regions
+
+	title := game.SelectName(romRegions, g.regions)
+	if title == "" {
+		title = entry.Name
+	}
+
+	return launchbox.PlatformGame{
+		Title:           title,
+		ApplicationPath: g.applicationPath(entry.FileName),
+		Platform:        g.platform,
+		Developer:       game.Developer,
+		Publisher:       game.Publisher,
+		ReleaseDate:     g.formatDate(game.SelectDate(romRegions, g.regions)),
+		Genre:           strings.Join(game.SelectGenres(romRegions, g.regions), "; "),
+		Notes:           game.SelectSynopsis(romRegions, g.regions),
+		MaxPlayers:      maxPlayers(game.Players),
+	}
+}
+
+// applicationPath joins the ROM folder and a file name with the folder's
+// path separator, which is a backslash unless the folder uses slashes
+func (g *Generator) applicationPath(file string) string {
+	sep := `\`
+	if strings.Contains(g.romDir, "/") && !strings.Contains(g.romDir, `\`) {
+		sep = "/"
+	}
+	return strings.TrimRight(g.romDir, `\/`) + sep + file
+}
+
+// formatDate converts a metadata source date ("1991-06-23", "1991-06" or
+// "1991") to a LaunchBox release date at midnight, or "" if it's invalid
+func (g *Generator) formatDate(date string) string {
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006"} {
+		if t, err := time.ParseInLocation(layout, date, g.location); err == nil {
+			return t.Format("2006-01-02T15:04:05-07:00")
+		}
+	}
+	return ""
+}
+
+// maxPlayers returns the highest player count of "2" or "1-4", or 0
+func maxPlayers(players string) int {
+	if i := strings.LastIndex(players, "-"); i >= 0 {
+		players = players[i+1:]
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(players))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// saveMedia copies a result's images and video into the LaunchBox folder
+func (g *Generator) saveMedia(result *scraper.ScrapeResult, title string) error {
+	for _, it := range imageTypes {
+		src := output.FindMedia(result, g.mediaDir, it.mediaTypes...)
+		if src == "" {
+			continue
+		}
+		dst := launchbox.ImagePath(g.launchBoxDir, g.platform, it.imageType, title, filepath.Ext(src))
+		if err := output.CopyFile(src, dst, g.overwrite); err != nil {
+			return err
+		}
+	}
+
+	if src := output.FindMedia(result, g.mediaDir, "videos"); src != "" {
+		dst := launchbox.VideoPath(g.launchBoxDir, g.platform, title, filepath.Ext(src))
+		if err := output.CopyFile(src, dst, g.overwrite); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package launchbox
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sargunv/rom-tools/internal/region"
+	"github.com/sargunv/rom-tools/internal/scraper"
+	"github.com/sargunv/rom-tools/lib/launchbox"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	mediaDir := filepath.Join(dir, "staging")
+	launchBoxDir := filepath.Join(dir, "LaunchBox")
+	platform := "Nintendo Game Boy"
+	romDir := `\\nas\roms\gb`
+
+	if err := os.MkdirAll(filepath.Join(mediaDir, "covers"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mediaDir, "covers", "zelda.jpg"), []byte("jpg"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// An existing game with an ID and play data that must be kept
+	existing := &launchbox.PlatformFile{Games: []launchbox.PlatformGame{{
+		ID:              "3f8e8a3c-52a1-4d0b-9a5e-0c6f7d1e2b4a",
+		Title:           "My Tetris",
+		ApplicationPath: `\\nas\roms\gb\tetris.gb`,
+		Platform:        platform,
+		Other:           []launchbox.Element{{XMLName: xml.Name{Local: "PlayCount"}, Content: "12"}},
+	}}}
+	platformPath := launchbox.PlatformFilePath(launchBoxDir, platform)
+	if err := launchbox.WritePlatformFile(platformPath, existing); err != nil {
+		t.Fatal(err)
+	}
+
+	results := &scraper.ScrapeResults{Results: []*scraper.ScrapeResult{
+		{
+			Entry: &scraper.LookupEntry{Name: "zelda", FileName: "zelda.gb", BaseName: "zelda"},
+			Game: &scraper.Game{
+				Name:      "Zelda: Link's Awakening",
+				Developer: "Nintendo",
+				Dates:     []scraper.RegionText{{Region: "us", Text: "1993-08-01"}},
+				Genres:    []scraper.Genre{{Names: []region.LocalizedEntry{{Language: "en", Text: "Action"}}}, {Names: []region.LocalizedEntry{{Language: "en", Text: "Adventure"}}}},
+				Synopsis:  []region.LocalizedEntry{{Language: "en", Text: "An island adventure."}},
+				Players:   "1",
+			},
+			Media: map[string]string{"covers": filepath.Join("covers", "zelda.jpg")},
+		},
+		{
+			Entry: &scraper.LookupEntry{Name: "tetris", FileName: "tetris.gb", BaseName: "tetris"},
+			Game:  &scraper.Game{Name: "Tetris", Developer: "Nintendo", Players: "1-2"},
+		},
+		{
+			Entry: &scraper.LookupEntry{Name: "missing", FileName: "missing.gb", BaseName: "missing"},
+		},
+	}}
+
+	g := NewGenerator(launchBoxDir, platform, romDir, mediaDir, false, []string{"us"})
+	g.location = time.UTC
+	if err := g.Generate(results); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	pf, err := launchbox.ReadPlatformFile(platformPath)
+	if err != nil {
+		t.Fatalf("ReadPlatformFile() error = %v", err)
+	}
+	if len(pf.Games) != 2 {
+		t.Fatalf("len(Games) = %d, want 2", len(pf.Games))
+	}
+
+	tetris := pf.Games[0]
+	if tetris.ID != existing.Games[0].ID || tetris.Title != "My Tetris" || len(tetris.Other) != 1 {
+		t.Errorf("existing game = %+v, want ID, title and play data kept", tetris)
+	}
+	if tetris.Developer != "Nintendo" || tetris.MaxPlayers != 2 {
+		t.Errorf("existing game = %+v, want missing fields filled in", tetris)
+	}
+
+	zelda := pf.Games[1]
+	if zelda.ID == "" {
+		t.Error("new game has no ID")
+	}
+	want := launchbox.PlatformGame{
+		ID:              zelda.ID,
+		Title:           "Zelda: Link's Awakening",
+		ApplicationPath: `\\nas\roms\gb\zelda.gb`,
+		Platform:        platform,
+		Developer:       "Nintendo",
+		ReleaseDate:     "1993-08-01T00:00:00+00:00",
+		Genre:           "Action; Adventure",
+		Notes:           "An island adventure.",
+		MaxPlayers:      1,
+	}
+	if zelda.Title != want.Title || zelda.ApplicationPath != want.ApplicationPath || zelda.ReleaseDate != want.ReleaseDate ||
+		zelda.Genre != want.Genre || zelda.Notes != want.Notes || zelda.MaxPlayers != want.MaxPlayers || zelda.Platform != want.Platform {
+		t.Errorf("new game = %+v, want %+v", zelda, want)
+	}
+
+	box := launchbox.ImagePath(launchBoxDir, platform, launchbox.ImageBoxFront, "Zelda: Link's Awakening", ".jpg")
+	if _, err := os.Stat(box); err != nil {
+		t.Errorf("box art not copied: %v", err)
+	}
+
+	// Overwrite replaces scraped fields but keeps the ID
+	g.overwrite = true
+	if err := g.Generate(results); err != nil {
+		t.Fatalf("Generate(overwrite) error = %v", err)
+	}
+	pf, err = launchbox.ReadPlatformFile(platformPath)
+	if err != nil {
+		t.Fatalf("ReadPlatformFile() error = %v", err)
+	}
+	if tetris := pf.Games[0]; tetris.Title != "Tetris" || tetris.ID != existing.Games[0].ID {
+		t.Errorf("overwritten game = %+v", tetris)
+	}
+	if len(pf.Games) != 2 || pf.Games[1].ID != zelda.ID {
+		t.Errorf("games = %+v, want IDs kept", pf.Games)
+	}
+}
+
+func TestGenerate_InvalidPlatformFile(t *testing.T) {
+	launchBoxDir := t.TempDir()
+	platform := "Nintendo Game Boy"
+	platformPath := launchbox.PlatformFilePath(launchBoxDir, platform)
+	if err := os.MkdirAll(filepath.Dir(platformPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	invalid := []byte("<LaunchBox><Game><Title>Tetris</Title>")
+	if err := os.WriteFile(platformPath, invalid, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results := &scraper.ScrapeResults{Results: []*scraper.ScrapeResult{
+		{
+			Entry: &scraper.LookupEntry{Name: "Tetris (World)", FileName: "Tetris (World).gb", BaseName: "Tetris (World)"},
+			Game:  &scraper.Game{Name: "Tetris"},
+		},
+	}}
+
+	g := NewGenerator(launchBoxDir, platform, `..\Roms\gb`, t.TempDir(), false, nil)
+	if err := g.Generate(results); err == nil {
+		t.Error("Generate() expected error for invalid platform file")
+	}
+
+	// The user's platform file must be left alone
+	data, err := os.ReadFile(platformPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(invalid) {
+		t.Errorf("platform file = %q, want it unchanged", data)
+	}
+}
+
+func TestApplicationPath(t *testing.T) {
+	tests := []struct {
+		romDir string
+		want   string
+	}{
+		{`..\Roms\gb\`, `..\Roms\gb\tetris.gb`},
+		{`\\nas\roms\gb`, `\\nas\roms\gb\tetris.gb`},
+		{"/mnt/roms/gb", "/mnt/roms/gb/tetris.gb"},
+	}
+	for _, tt := range tests {
+		g := &Generator{romDir: tt.romDir}
+		if got := g.applicationPath("tetris.gb"); got != tt.want {
+			t.Errorf("applicationPath(%q) = %q, want %q", tt.romDir, got, tt.want)
+		}
+	}
+}
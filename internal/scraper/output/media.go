@@ -0,0 +1,104 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"  // Register GIF decoder
+	_ "image/jpeg" // Register JPEG decoder
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sargunv/rom-tools/internal/scraper"
+)
+
+// FindMedia returns the path of the first of the given media types that was
+// downloaded for a result, or "" if none was. Media paths are relative to
+// mediaDir, the scraper's media output directory.
+func FindMedia(result *scraper.ScrapeResult, mediaDir string, mediaTypes ...string) string {
+	for _, mediaType := range mediaTypes {
+		if path, ok := result.Media[mediaType]; ok {
+			return filepath.Join(mediaDir, path)
+		}
+	}
+	return ""
+}
+
+// Exists reports whether a file exists at path.
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// WriteFile writes data to path, creating its directory. Existing files are
+// kept unless overwrite is set.
+func WriteFile(path string, data []byte, overwrite bool) error {
+	if !overwrite && Exists(path) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// CopyFile copies src to dst, creating dst's directory. Existing files are
+// kept unless overwrite is set.
+func CopyFile(src, dst string, overwrite bool) error {
+	if !overwrite && Exists(dst) {
+		return nil
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return WriteFile(dst, data, true)
+}
+
+// CopyPNG copies an image to dst as PNG, converting JPEG and GIF images.
+// Existing files are kept unless overwrite is set.
+func CopyPNG(src, dst string, overwrite bool) error {
+	if strings.EqualFold(filepath.Ext(src), ".png") {
+		return CopyFile(src, dst, overwrite)
+	}
+	if !overwrite && Exists(dst) {
+		return nil
+	}
+
+	img, err := DecodeImage(src)
+	if err != nil {
+		return err
+	}
+	return WritePNG(dst, img, true)
+}
+
+// DecodeImage reads a PNG, JPEG or GIF image.
+func DecodeImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", filepath.Base(path), err)
+	}
+	return img, nil
+}
+
+// WritePNG encodes an image to path as PNG, creating its directory. Existing
+// files are kept unless overwrite is set.
+func WritePNG(path string, img image.Image, overwrite bool) error {
+	if !overwrite && Exists(path) {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+	return WriteFile(path, buf.Bytes(), true)
+}
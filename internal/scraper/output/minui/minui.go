@@ -0,0 +1,86 @@
+package minui
+
+import (
+	"github.com/sargunv/rom-tools/internal/scraper"
+	"github.com/sargunv/rom-tools/internal/scraper/output"
+	"github.com/sargunv/rom-tools/lib/minui"
+)
+
+// Media types used for box art, in fallback order
+var boxArtMediaTypes = []string{"covers", "3dboxes", "screenshots", "titlescreens"}
+
+// Generator generates MinUI/NextUI output
+type Generator struct {
+	romDir     string
+	mediaDir   string
+	boxArtSize minui.BoxArtSize
+	overwrite  bool
+	regions    []string
+}
+
+// NewGenerator creates a new MinUI/NextUI output generator for a ROM folder.
+// Box art is read from mediaDir, where the scraper downloaded it, and scaled
+// down to fit boxArtSize.
+func NewGenerator(romDir, mediaDir string, boxArtSize minui.BoxArtSize, overwrite bool, preferredRegions []string) *Generator {
+	return &Generator{
+		romDir:     romDir,
+		mediaDir:   mediaDir,
+		boxArtSize: boxArtSize,
+		overwrite:  overwrite,
+		regions:    preferredRegions,
+	}
+}
+
+// Generate creates MinUI/NextUI output from scrape results
+func (g *Generator) Generate(results *scraper.ScrapeResults) error {
+	mapPath := minui.MapPath(g.romDir)
+	names, err := minui.ReadMap(mapPath) // Empty if the file doesn't exist
+	if err != nil {
+		return err
+	}
+
+	mapChanged := false
+	for _, result := range results.Results {
+		if result.Game == nil {
+			continue // Skip not found or errored
+		}
+
+		// Save box art (errors don't stop other entries)
+		_ = g.saveBoxArt(result)
+
+		// Only names that differ from the file name need a mapping
+		entry := result.Entry
+		name := result.Game.SelectName(entry.Regions, g.regions)
+		if name == "" || name == entry.BaseName {
+			continue
+		}
+		if _, ok := names[entry.FileName]; !ok || g.overwrite {
+			names[entry.FileName] = name
+			mapChanged = true
+		}
+	}
+
+	if !mapChanged {
+		return nil
+	}
+	return minui.WriteMap(mapPath, names)
+}
+
+// saveBoxArt writes the scaled box art of a result
+func (g *Generator) saveBoxArt(result *scraper.ScrapeResult) error {
+	src := output.FindMedia(result, g.mediaDir, boxArtMediaTypes...)
+	if src == "" {
+		return nil
+	}
+
+	dst := minui.ImagePath(g.romDir, result.Entry.BaseName)
+	if !g.overwrite && output.Exists(dst) {
+		return nil
+	}
+
+	img, err := output.DecodeImage(src)
+	if err != nil {
+		return err
+	}
+	return output.WritePNG(dst, g.boxArtSize.Fit(img), true)
+}
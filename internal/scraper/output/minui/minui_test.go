@@ -0,0 +1,74 @@
+package minui
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sargunv/rom-tools/internal/scraper"
+	"github.com/sargunv/rom-tools/lib/minui"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	mediaDir := filepath.Join(dir, "media")
+	romDir := filepath.Join(dir, "Roms", "Game Boy (GB)")
+
+	// An oversized screenshot, used as box art when there is no cover
+	if err := os.MkdirAll(filepath.Join(mediaDir, "screenshots"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(filepath.Join(mediaDir, "screenshots", "tetris.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, image.NewNRGBA(image.Rect(0, 0, 320, 288))); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	// A hand-edited mapping that must be kept
+	if err := minui.WriteMap(minui.MapPath(romDir), minui.Map{"Alleyway (World).gb": "My Alleyway"}); err != nil {
+		t.Fatal(err)
+	}
+
+	results := &scraper.ScrapeResults{Results: []*scraper.ScrapeResult{
+		{
+			Entry: &scraper.LookupEntry{Name: "Tetris (World)", FileName: "Tetris (World).gb", BaseName: "Tetris (World)"},
+			Game:  &scraper.Game{Name: "Tetris"},
+			Media: map[string]string{"screenshots": filepath.Join("screenshots", "tetris.png")},
+		},
+		{
+			Entry: &scraper.LookupEntry{Name: "Alleyway (World)", FileName: "Alleyway (World).gb", BaseName: "Alleyway (World)"},
+			Game:  &scraper.Game{Name: "Alleyway"},
+		},
+	}}
+
+	g := NewGenerator(romDir, mediaDir, minui.BoxArtSize{Width: 160, Height: 160}, false, nil)
+	if err := g.Generate(results); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	f, err = os.Open(minui.ImagePath(romDir, "Tetris (World)"))
+	if err != nil {
+		t.Fatalf("box art not written: %v", err)
+	}
+	defer f.Close()
+	cfg, err := png.DecodeConfig(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 160 || cfg.Height != 144 {
+		t.Errorf("box art size = %dx%d, want 160x144", cfg.Width, cfg.Height)
+	}
+
+	names, err := minui.ReadMap(minui.MapPath(romDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names["Tetris (World).gb"] != "Tetris" || names["Alleyway (World).gb"] != "My Alleyway" || len(names) != 2 {
+		t.Errorf("map = %v", names)
+	}
+}
@@ -0,0 +1,93 @@
+package muos
+
+import (
+	"github.com/sargunv/rom-tools/internal/scraper"
+	"github.com/sargunv/rom-tools/internal/scraper/output"
+	"github.com/sargunv/rom-tools/lib/muos"
+)
+
+// Media types used for each catalogue image, in fallback order
+var (
+	boxMediaTypes     = []string{"covers", "3dboxes", "screenshots"}
+	previewMediaTypes = []string{"screenshots", "titlescreens"}
+)
+
+// Generator generates MuOS catalogue output
+type Generator struct {
+	catalogueDir string
+	system       string
+	mediaDir     string
+	overwrite    bool
+	regions      []string
+}
+
+// NewGenerator creates a new MuOS output generator. Images are copied from
+// mediaDir, where the scraper downloaded them, into the catalogue folder of a
+// MuOS system (e.g., "Nintendo Game Boy Advance").
+func NewGenerator(catalogueDir, system, mediaDir string, overwrite bool, preferredRegions []string) *Generator {
+	return &Generator{
+		catalogueDir: catalogueDir,
+		system:       system,
+		mediaDir:     mediaDir,
+		overwrite:    overwrite,
+		regions:      preferredRegions,
+	}
+}
+
+// Generate creates MuOS output from scrape results
+func (g *Generator) Generate(results *scraper.ScrapeResults) error {
+	namesPath := muos.NamesPath(g.catalogueDir)
+	names, err := muos.ReadNames(namesPath) // Empty if the file doesn't exist
+	if err != nil {
+		return err
+	}
+
+	namesChanged := false
+	for _, result := range results.Results {
+		if result.Game == nil {
+			continue // Skip not found or errored
+		}
+
+		// Save catalogue files (errors don't stop other entries)
+		_ = g.writeEntry(result)
+
+		// Only names that differ from the file name need an override
+		rom := result.Entry.BaseName
+		name := result.Game.SelectName(result.Entry.Regions, g.regions)
+		if name == "" || name == rom {
+			continue
+		}
+		if _, ok := names[rom]; !ok || g.overwrite {
+			names[rom] = name
+			namesChanged = true
+		}
+	}
+
+	if !namesChanged {
+		return nil
+	}
+	return muos.WriteNames(namesPath, names)
+}
+
+// writeEntry writes the catalogue images and description of a result
+func (g *Generator) writeEntry(result *scraper.ScrapeResult) error {
+	rom := result.Entry.BaseName
+
+	if src := output.FindMedia(result, g.mediaDir, boxMediaTypes...); src != "" {
+		if err := output.CopyPNG(src, muos.BoxPath(g.catalogueDir, g.system, rom), g.overwrite); err != nil {
+			return err
+		}
+	}
+	if src := output.FindMedia(result, g.mediaDir, previewMediaTypes...); src != "" {
+		if err := output.CopyPNG(src, muos.PreviewPath(g.catalogueDir, g.system, rom), g.overwrite); err != nil {
+			return err
+		}
+	}
+	if text := result.Game.SelectSynopsis(result.Entry.Regions, g.regions); text != "" {
+		if err := output.WriteFile(muos.TextPath(g.catalogueDir, g.system, rom), []byte(text+"\n"), g.overwrite); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
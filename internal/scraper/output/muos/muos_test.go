@@ -0,0 +1,116 @@
+package muos
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sargunv/rom-tools/internal/region"
+	"github.com/sargunv/rom-tools/internal/scraper"
+	"github.com/sargunv/rom-tools/lib/muos"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	mediaDir := filepath.Join(dir, "media")
+	catalogueDir := filepath.Join(dir, "MUOS", "info", "catalogue")
+	system := "Nintendo Game Boy"
+
+	// A JPEG cover, which must be converted to PNG
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4)), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(mediaDir, "covers"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mediaDir, "covers", "tetris.jpg"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A hand-edited name override that must be kept
+	namesPath := muos.NamesPath(catalogueDir)
+	if err := muos.WriteNames(namesPath, muos.Names{"alleyway": "My Alleyway"}); err != nil {
+		t.Fatal(err)
+	}
+
+	results := &scraper.ScrapeResults{Results: []*scraper.ScrapeResult{
+		{
+			Entry: &scraper.LookupEntry{BaseName: "tetris"},
+			Game: &scraper.Game{
+				Name:     "Tetris",
+				Synopsis: []region.LocalizedEntry{{Language: "en", Text: "Falling blocks."}},
+			},
+			Media: map[string]string{"covers": filepath.Join("covers", "tetris.jpg")},
+		},
+		{
+			Entry: &scraper.LookupEntry{BaseName: "alleyway"},
+			Game:  &scraper.Game{Name: "Alleyway"},
+		},
+		{
+			Entry: &scraper.LookupEntry{BaseName: "missing"},
+		},
+	}}
+
+	g := NewGenerator(catalogueDir, system, mediaDir, false, []string{"us"})
+	if err := g.Generate(results); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	box, err := os.ReadFile(muos.BoxPath(catalogueDir, system, "tetris"))
+	if err != nil {
+		t.Fatalf("box art not written: %v", err)
+	}
+	if _, format, err := image.Decode(bytes.NewReader(box)); err != nil || format != "png" {
+		t.Errorf("box art format = %q, %v, want png", format, err)
+	}
+	if _, err := os.Stat(muos.PreviewPath(catalogueDir, system, "tetris")); err == nil {
+		t.Error("preview written without a screenshot")
+	}
+
+	text, err := os.ReadFile(muos.TextPath(catalogueDir, system, "tetris"))
+	if err != nil || string(text) != "Falling blocks.\n" {
+		t.Errorf("text = %q, %v", text, err)
+	}
+
+	names, err := muos.ReadNames(namesPath)
+	if err != nil {
+		t.Fatalf("ReadNames() error = %v", err)
+	}
+	if names["tetris"] != "Tetris" || names["alleyway"] != "My Alleyway" || len(names) != 2 {
+		t.Errorf("names = %v", names)
+	}
+}
+
+func TestGenerate_InvalidNames(t *testing.T) {
+	catalogueDir := t.TempDir()
+	namesPath := muos.NamesPath(catalogueDir)
+	if err := os.MkdirAll(filepath.Dir(namesPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	invalid := []byte(`{"alleyway": "My Alleyway",`)
+	if err := os.WriteFile(namesPath, invalid, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results := &scraper.ScrapeResults{Results: []*scraper.ScrapeResult{
+		{Entry: &scraper.LookupEntry{BaseName: "tetris"}, Game: &scraper.Game{Name: "Tetris"}},
+	}}
+
+	g := NewGenerator(catalogueDir, "Nintendo Game Boy", t.TempDir(), false, nil)
+	if err := g.Generate(results); err == nil {
+		t.Error("Generate() expected error for invalid name.json")
+	}
+
+	// The user's file must be left alone
+	data, err := os.ReadFile(namesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, invalid) {
+		t.Errorf("name.json = %q, want it unchanged", data)
+	}
+}
@@ -0,0 +1,169 @@
+package pegasus
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sargunv/rom-tools/internal/scraper"
+	"github.com/sargunv/rom-tools/internal/scraper/output"
+	"github.com/sargunv/rom-tools/lib/pegasus"
+)
+
+// assetTypes maps ES-DE media types to Pegasus asset names
+var assetTypes = []struct {
+	mediaType string
+	asset     string
+}{
+	{"covers", "boxFront"},
+	{"backcovers", "boxBack"},
+	{"physicalmedia", "cartridge"},
+	{"marquees", "logo"},
+	{"screenshots", "screenshot"},
+	{"titlescreens", "titlescreen"},
+	{"fanart", "background"},
+	{"videos", "video"},
+}
+
+// Generator generates Pegasus metadata output
+type Generator struct {
+	metadataPath string
+	mediaDir     string
+	collection   string
+	overwrite    bool
+	regions      []string
+}
+
+// NewGenerator creates a new Pegasus output generator. Media is copied from
+// mediaDir, where the scraper downloaded it, into a media folder next to the
+// metadata file. If the file has no collection yet, one named collection is
+// added for the scraped files' extensions.
+func NewGenerator(metadataPath, mediaDir, collection string, overwrite bool, preferredRegions []string) *Generator {
+	return &Generator{
+		metadataPath: metadataPath,
+		mediaDir:     mediaDir,
+		collection:   collection,
+		overwrite:    overwrite,
+		regions:      preferredRegions,
+	}
+}
+
+// Generate creates Pegasus output from scrape results. Existing games are
+// matched by file; their fields are only replaced if overwrite is set, and
+// fields the scraper doesn't know about are always kept.
+func (g *Generator) Generate(results *scraper.ScrapeResults) error {
+	md, err := pegasus.Parse(g.metadataPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		md = &pegasus.Metadata{} // Start fresh
+	} else if err != nil {
+		return err
+	}
+
+	// Index existing games by file
+	byFile := make(map[string]*pegasus.Game)
+	for _, game := range md.Games {
+		for _, file := range game.Files() {
+			byFile[file] = game
+		}
+	}
+
+	var extensions []string
+	for _, result := range results.Results {
+		if result.Game == nil {
+			continue // Skip not found or errored
+		}
+
+		entry := result.Entry
+		file := entry.FileName
+		if ext := strings.TrimPrefix(filepath.Ext(file), "."); ext != "" && !slices.Contains(extensions, ext) {
+			extensions = append(extensions, ext)
+		}
+
+		title, fields := g.resultToFields(result, file)
+		game, ok := byFile[file]
+		if !ok {
+			md.Games = append(md.Games, &pegasus.Game{Title: title, Fields: fields})
+			continue
+		}
+
+		// Merge into the existing game
+		if g.overwrite {
+			game.Title = title
+		}
+		for _, f := range fields {
+			if g.overwrite || !game.Fields.Has(f.Key) {
+				game.Fields.SetValues(f.Key, f.Values)
+			}
+		}
+	}
+
+	if len(md.Collections) == 0 && g.collection != "" {
+		slices.Sort(extensions)
+		c := &pegasus.Collection{Name: g.collection}
+		c.Fields.Set("extensions", strings.Join(extensions, ", "))
+		md.Collections = append(md.Collections, c)
+	}
+
+	return pegasus.Write(g.metadataPath, md)
+}
+
+// resultToFields converts a scrape result to a Pegasus game title and
+// fields, copying its media next to the metadata file
+func (g *Generator) resultToFields(result *scraper.ScrapeResult, file string) (string, pegasus.Fields) {
+	entry := result.Entry
+	game := result.Game
+	romRegions := entry.Regions
+
+	title := game.SelectName(romRegions, g.regions)
+	if title == "" {
+		title = entry.Name
+	}
+
+	var fields pegasus.Fields
+	set := func(key, value string) {
+		if value != "" {
+			fields.Set(key, value)
+		}
+	}
+
+	set(pegasus.KeyFile, file)
+	set("developer", game.Developer)
+	set("publisher", game.Publisher)
+	set("genre", strings.Join(game.SelectGenres(romRegions, g.regions), ", "))
+	set("players", game.Players)
+	set("release", game.SelectDate(romRegions, g.regions))
+	if game.Rating > 0 {
+		set("rating", fmt.Sprintf("%.0f%%", game.Rating*100))
+	}
+	set(pegasus.KeyDescription, game.SelectSynopsis(romRegions, g.regions))
+
+	for _, at := range assetTypes {
+		if path := g.saveMedia(result, at.mediaType, at.asset); path != "" {
+			set("assets."+at.asset, path)
+		}
+	}
+
+	set("x-scraper", game.Source)
+	set("x-scraper-id", game.ID)
+
+	return title, fields
+}
+
+// saveMedia copies a downloaded media file next to the metadata file,
+// returning its relative path, or "" if there is none
+func (g *Generator) saveMedia(result *scraper.ScrapeResult, mediaType, asset string) string {
+	src := output.FindMedia(result, g.mediaDir, mediaType)
+	if src == "" {
+		return ""
+	}
+
+	rel := filepath.Join("media", result.Entry.BaseName, asset+filepath.Ext(src))
+	dst := filepath.Join(filepath.Dir(g.metadataPath), rel)
+	if err := output.CopyFile(src, dst, g.overwrite); err != nil {
+		return "" // Log but don't fail
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,164 @@
+package pegasus
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sargunv/rom-tools/internal/region"
+	"github.com/sargunv/rom-tools/internal/scraper"
+	"github.com/sargunv/rom-tools/lib/pegasus"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	mediaDir := filepath.Join(dir, "staging")
+	metadataPath := filepath.Join(dir, "roms", "gb", "metadata.pegasus.txt")
+
+	if err := os.MkdirAll(filepath.Join(mediaDir, "covers"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mediaDir, "covers", "tetris.png"), []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A hand-edited file whose edits must be kept
+	existing := "collection: My Game Boy\n" +
+		"extensions: gb\n" +
+		"\n" +
+		"# Favourite\n" +
+		"game: My Alleyway\n" +
+		"file: Alleyway (World).gb\n" +
+		"developer: Me\n" +
+		"x-note: keep\n"
+	if err := os.MkdirAll(filepath.Dir(metadataPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(metadataPath, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results := &scraper.ScrapeResults{Results: []*scraper.ScrapeResult{
+		{
+			Entry: &scraper.LookupEntry{Name: "Tetris (World)", FileName: "Tetris (World).gb", BaseName: "Tetris (World)"},
+			Game: &scraper.Game{
+				Source:   "screenscraper",
+				ID:       "1",
+				Name:     "Tetris",
+				Synopsis: []region.LocalizedEntry{{Language: "en", Text: "Falling blocks.\nLines clear."}},
+				Rating:   0.9,
+			},
+			Media: map[string]string{"covers": filepath.Join("covers", "tetris.png")},
+		},
+		{
+			Entry: &scraper.LookupEntry{Name: "Alleyway (World)", FileName: "Alleyway (World).gb", BaseName: "Alleyway (World)"},
+			Game:  &scraper.Game{Name: "Alleyway", Developer: "Nintendo", Publisher: "Nintendo"},
+		},
+		{
+			Entry: &scraper.LookupEntry{Name: "Missing (World)", FileName: "Missing (World).gb", BaseName: "Missing (World)"},
+		},
+	}}
+
+	g := NewGenerator(metadataPath, mediaDir, "Game Boy", false, []string{"us"})
+	if err := g.Generate(results); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	md, err := pegasus.Parse(metadataPath)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(md.Collections) != 1 || md.Collections[0].Name != "My Game Boy" {
+		t.Errorf("collections = %+v, want the existing one", md.Collections)
+	}
+	if len(md.Games) != 2 {
+		t.Fatalf("len(Games) = %d, want 2", len(md.Games))
+	}
+
+	alleyway := md.Games[0]
+	if alleyway.Title != "My Alleyway" || len(alleyway.Comments) != 1 {
+		t.Errorf("existing game = %+v, want title and comment kept", alleyway)
+	}
+	for key, want := range map[string]string{
+		"developer": "Me",
+		"publisher": "Nintendo",
+		"x-note":    "keep",
+	} {
+		if got := alleyway.Fields.Get(key); got != want {
+			t.Errorf("alleyway %s = %q, want %q", key, got, want)
+		}
+	}
+
+	tetris := md.Games[1]
+	if tetris.Title != "Tetris" {
+		t.Errorf("Title = %q, want Tetris", tetris.Title)
+	}
+	for key, want := range map[string]string{
+		"file":            "Tetris (World).gb",
+		"description":     "Falling blocks.\nLines clear.",
+		"rating":          "90%",
+		"assets.boxFront": "media/Tetris (World)/boxFront.png",
+		"x-scraper":       "screenscraper",
+		"x-scraper-id":    "1",
+	} {
+		if got := tetris.Fields.Get(key); got != want {
+			t.Errorf("tetris %s = %q, want %q", key, got, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "roms", "gb", "media", "Tetris (World)", "boxFront.png")); err != nil {
+		t.Errorf("box art not copied: %v", err)
+	}
+
+	// Overwrite replaces scraped fields but keeps custom ones
+	g = NewGenerator(metadataPath, mediaDir, "Game Boy", true, []string{"us"})
+	if err := g.Generate(results); err != nil {
+		t.Fatalf("Generate(overwrite) error = %v", err)
+	}
+	md, err = pegasus.Parse(metadataPath)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	alleyway = md.Games[0]
+	if alleyway.Title != "Alleyway" || alleyway.Fields.Get("developer") != "Nintendo" || alleyway.Fields.Get("x-note") != "keep" {
+		t.Errorf("overwritten game = %+v", alleyway)
+	}
+}
+
+func TestGenerate_NewCollection(t *testing.T) {
+	metadataPath := filepath.Join(t.TempDir(), "metadata.pegasus.txt")
+	results := &scraper.ScrapeResults{Results: []*scraper.ScrapeResult{
+		{Entry: &scraper.LookupEntry{Name: "B", FileName: "b.gbc", BaseName: "b"}, Game: &scraper.Game{Name: "B"}},
+		{Entry: &scraper.LookupEntry{Name: "A", FileName: "a.gb", BaseName: "a"}, Game: &scraper.Game{Name: "A"}},
+	}}
+
+	if err := NewGenerator(metadataPath, "", "Game Boy", false, nil).Generate(results); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	md, err := pegasus.Parse(metadataPath)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(md.Collections) != 1 {
+		t.Fatalf("len(Collections) = %d, want 1", len(md.Collections))
+	}
+	c := md.Collections[0]
+	if c.Name != "Game Boy" || c.Fields.Get("extensions") != "gb, gbc" {
+		t.Errorf("collection = %+v", c)
+	}
+}
+
+func TestGenerate_UnreadableMetadata(t *testing.T) {
+	// A directory in place of the metadata file can't be parsed
+	metadataPath := filepath.Join(t.TempDir(), "metadata.pegasus.txt")
+	if err := os.Mkdir(metadataPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	results := &scraper.ScrapeResults{Results: []*scraper.ScrapeResult{
+		{Entry: &scraper.LookupEntry{Name: "A", FileName: "a.gb", BaseName: "a"}, Game: &scraper.Game{Name: "A"}},
+	}}
+
+	if err := NewGenerator(metadataPath, "", "Game Boy", false, nil).Generate(results); err == nil {
+		t.Error("Generate() expected error for unreadable metadata file")
+	}
+}
@@ -0,0 +1,117 @@
+package retroarch
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/sargunv/rom-tools/internal/scraper"
+	"github.com/sargunv/rom-tools/internal/scraper/output"
+	"github.com/sargunv/rom-tools/lib/retroarch"
+)
+
+// thumbnailTypes maps RetroArch thumbnail types to ES-DE media types, in
+// fallback order
+var thumbnailTypes = []struct {
+	thumbnailType string
+	mediaTypes    []string
+}{
+	{retroarch.Boxarts, []string{"covers", "3dboxes"}},
+	{retroarch.Snaps, []string{"screenshots"}},
+	{retroarch.Titles, []string{"titlescreens"}},
+}
+
+// Generator generates RetroArch playlist and thumbnail output
+type Generator struct {
+	playlistPath  string
+	thumbnailsDir string
+	romDir        string
+	database      string
+	mediaDir      string
+	overwrite     bool
+	regions       []string
+}
+
+// NewGenerator creates a new RetroArch output generator. Items point to the
+// ROM files in romDir and are tagged with a libretro database (e.g.,
+// "Nintendo - Game Boy"). If thumbnailsDir is set, images are copied from
+// mediaDir, where the scraper downloaded them, into the database's
+// thumbnail folders.
+func NewGenerator(playlistPath, thumbnailsDir, romDir, database, mediaDir string, overwrite bool, preferredRegions []string) *Generator {
+	return &Generator{
+		playlistPath:  playlistPath,
+		thumbnailsDir: thumbnailsDir,
+		romDir:        romDir,
+		database:      database,
+		mediaDir:      mediaDir,
+		overwrite:     overwrite,
+		regions:       preferredRegions,
+	}
+}
+
+// Generate creates RetroArch output from scrape results. Existing items are
+// matched by path and keep their core association.
+func (g *Generator) Generate(results *scraper.ScrapeResults) error {
+	playlist, err := retroarch.ReadPlaylist(g.playlistPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		playlist = retroarch.NewPlaylist() // Start fresh
+	} else if err != nil {
+		return err
+	}
+
+	for _, result := range results.Results {
+		if result.Game == nil {
+			continue // Skip not found or errored
+		}
+
+		item := g.resultToItem(result)
+		playlist.Merge(item, g.overwrite)
+
+		// Thumbnails are looked up by the label in the playlist, which may
+		// be a kept hand edit
+		if g.thumbnailsDir != "" {
+			_ = g.saveThumbnails(result, playlist.Find(item.Path).Label) // Log but don't fail
+		}
+	}
+
+	return retroarch.WritePlaylist(g.playlistPath, playlist)
+}
+
+// resultToItem converts a scrape result to a playlist item
+func (g *Generator) resultToItem(result *scraper.ScrapeResult) retroarch.Item {
+	entry := result.Entry
+
+	path := entry.ROMPath
+	if path == "" {
+		path = filepath.Join(g.romDir, entry.FileName)
+	}
+
+	label := result.Game.SelectName(entry.Regions, g.regions)
+	if label == "" {
+		label = entry.Name
+	}
+
+	return retroarch.Item{
+		Path:     path,
+		Label:    label,
+		CorePath: retroarch.Detect,
+		CoreName: retroarch.Detect,
+		CRC32:    retroarch.FormatCRC32(entry.Hashes.CRC32),
+		DBName:   retroarch.PlaylistName(g.database),
+	}
+}
+
+// saveThumbnails copies a result's images into the thumbnail folders
+func (g *Generator) saveThumbnails(result *scraper.ScrapeResult, label string) error {
+	for _, tt := range thumbnailTypes {
+		src := output.FindMedia(result, g.mediaDir, tt.mediaTypes...)
+		if src == "" {
+			continue
+		}
+		dst := retroarch.ThumbnailPath(g.thumbnailsDir, g.database, tt.thumbnailType, label)
+		if err := output.CopyPNG(src, dst, g.overwrite); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package retroarch
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sargunv/rom-tools/internal/scraper"
+	"github.com/sargunv/rom-tools/lib/retroarch"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	mediaDir := filepath.Join(dir, "staging")
+	playlistPath := filepath.Join(dir, "playlists", "Nintendo - Game Boy.lpl")
+	thumbnailsDir := filepath.Join(dir, "thumbnails")
+	romDir := filepath.Join(dir, "roms")
+	database := "Nintendo - Game Boy"
+
+	// A JPEG cover, which must be converted to PNG
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4)), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(mediaDir, "covers"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mediaDir, "covers", "tetris.jpg"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// An existing item with a chosen core that must be kept
+	existing := retroarch.NewPlaylist()
+	existing.Items = append(existing.Items, retroarch.Item{
+		Path:     filepath.Join(romDir, "alleyway.gb"),
+		Label:    "My Alleyway",
+		CorePath: "/cores/gambatte_libretro.so",
+		CoreName: "Gambatte",
+		CRC32:    retroarch.Detect,
+	})
+	if err := retroarch.WritePlaylist(playlistPath, existing); err != nil {
+		t.Fatal(err)
+	}
+
+	results := &scraper.ScrapeResults{Results: []*scraper.ScrapeResult{
+		{
+			Entry: &scraper.LookupEntry{Name: "tetris", FileName: "tetris.gb", BaseName: "tetris", Hashes: scraper.Hashes{CRC32: "46df91ad"}},
+			Game:  &scraper.Game{Name: "Tetris: Deluxe"},
+			Media: map[string]string{"covers": filepath.Join("covers", "tetris.jpg")},
+		},
+		{
+			Entry: &scraper.LookupEntry{Name: "alleyway", FileName: "alleyway.gb", BaseName: "alleyway"},
+			Game:  &scraper.Game{Name: "Alleyway"},
+		},
+		{
+			Entry: &scraper.LookupEntry{Name: "missing", FileName: "missing.gb", BaseName: "missing"},
+		},
+	}}
+
+	g := NewGenerator(playlistPath, thumbnailsDir, romDir, database, mediaDir, false, []string{"us"})
+	if err := g.Generate(results); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	playlist, err := retroarch.ReadPlaylist(playlistPath)
+	if err != nil {
+		t.Fatalf("ReadPlaylist() error = %v", err)
+	}
+	if len(playlist.Items) != 2 {
+		t.Fatalf("len(Items) = %d, want 2", len(playlist.Items))
+	}
+	if alleyway := playlist.Items[0]; alleyway.Label != "My Alleyway" || alleyway.CoreName != "Gambatte" {
+		t.Errorf("existing item = %+v, want it kept", alleyway)
+	}
+	want := retroarch.Item{
+		Path:     filepath.Join(romDir, "tetris.gb"),
+		Label:    "Tetris: Deluxe",
+		CorePath: retroarch.Detect,
+		CoreName: retroarch.Detect,
+		CRC32:    "46DF91AD|crc",
+		DBName:   "Nintendo - Game Boy.lpl",
+	}
+	if got := playlist.Items[1]; !reflect.DeepEqual(got, want) {
+		t.Errorf("new item = %+v, want %+v", got, want)
+	}
+
+	box, err := os.ReadFile(filepath.Join(thumbnailsDir, database, "Named_Boxarts", "Tetris_ Deluxe.png"))
+	if err != nil {
+		t.Fatalf("box art not written: %v", err)
+	}
+	if _, format, err := image.Decode(bytes.NewReader(box)); err != nil || format != "png" {
+		t.Errorf("box art format = %q, %v, want png", format, err)
+	}
+}
+
+func TestGenerate_InvalidPlaylist(t *testing.T) {
+	dir := t.TempDir()
+	playlistPath := filepath.Join(dir, "Nintendo - Game Boy.lpl")
+	invalid := []byte("{\"version\": \"1.5\", \"items\": [")
+	if err := os.WriteFile(playlistPath, invalid, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results := &scraper.ScrapeResults{Results: []*scraper.ScrapeResult{
+		{
+			Entry: &scraper.LookupEntry{Name: "Tetris (World)", FileName: "Tetris (World).gb", BaseName: "Tetris (World)"},
+			Game:  &scraper.Game{Name: "Tetris"},
+		},
+	}}
+
+	g := NewGenerator(playlistPath, "", dir, "Nintendo - Game Boy", "", false, nil)
+	if err := g.Generate(results); err == nil {
+		t.Error("Generate() expected error for invalid playlist")
+	}
+
+	// The user's playlist must be left alone
+	data, err := os.ReadFile(playlistPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, invalid) {
+		t.Errorf("playlist = %q, want it unchanged", data)
+	}
+}
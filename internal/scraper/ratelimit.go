@@ -36,7 +36,8 @@ const (
 	maxBackoff      = 60 * time.Second
 )
 
-// NewRateLimiter creates a new rate limiter
+// NewRateLimiter creates a new rate limiter. A maxPerMinute of 0 disables
+// the per-minute limit.
 func NewRateLimiter(maxThreads, maxPerMinute int) *RateLimiter {
 	rl := &RateLimiter{
 		maxPerMinute: maxPerMinute,
@@ -149,7 +150,7 @@ func (rl *RateLimiter) waitForRateLimit(ctx context.Context) error {
 	rl.requestTimes = newTimes
 
 	// Check if we need to wait
-	if len(rl.requestTimes) >= rl.maxPerMinute {
+	if rl.maxPerMinute > 0 && len(rl.requestTimes) >= rl.maxPerMinute {
 		// Wait until the oldest request is more than 1 minute old
 		waitUntil := rl.requestTimes[0].Add(time.Minute)
 		waitDuration := time.Until(waitUntil)
@@ -9,12 +9,11 @@ import (
 	"github.com/sargunv/rom-tools/internal/cache"
 	"github.com/sargunv/rom-tools/internal/region"
 	"github.com/sargunv/rom-tools/lib/datfile"
-	"github.com/sargunv/rom-tools/lib/screenscraper"
 )
 
 // Scraper orchestrates the scraping process
 type Scraper struct {
-	client      *screenscraper.ScreenscraperClient
+	source      MetadataSource
 	cache       *cache.DiskCache
 	config      *Config
 	rateLimiter *RateLimiter
@@ -24,10 +23,10 @@ type Scraper struct {
 	updates chan ProgressUpdate
 }
 
-// New creates a new scraper
-func New(client *screenscraper.ScreenscraperClient, diskCache *cache.DiskCache, config *Config) *Scraper {
+// New creates a new scraper that looks up games in a metadata source
+func New(source MetadataSource, diskCache *cache.DiskCache, config *Config) *Scraper {
 	return &Scraper{
-		client:      client,
+		source:      source,
 		cache:       diskCache,
 		config:      config,
 		rateLimiter: NewRateLimiter(config.MaxThreads, config.MaxRequestsPerMin),
@@ -84,6 +83,7 @@ func (s *Scraper) ScrapeFromDAT(ctx context.Context, datPath string) (*ScrapeRes
 func (s *Scraper) datToLookupEntries(dat *datfile.Datafile) ([]*LookupEntry, int) {
 	var entries []*LookupEntry
 	filteredOut := 0
+	parents := parentBaseNames(dat)
 
 	for _, game := range dat.Games {
 		// Skip BIOS entries
@@ -129,6 +129,7 @@ func (s *Scraper) datToLookupEntries(dat *datfile.Datafile) ([]*LookupEntry, int
 			Size:     rom.Size,
 			Regions:  regions,
 			BaseName: baseName,
+			CloneOf:  parents[game.Name],
 			Source:   SourceDAT,
 		}
 
@@ -138,6 +139,34 @@ func (s *Scraper) datToLookupEntries(dat *datfile.Datafile) ([]*LookupEntry, int
 	return entries, filteredOut
 }
 
+// parentBaseNames maps the names of clones in a DAT to the base names of
+// their parents. Parents are referenced by name (Logiqx cloneof) or by ID
+// (No-Intro cloneofid).
+func parentBaseNames(dat *datfile.Datafile) map[string]string {
+	byName := make(map[string]string)
+	byID := make(map[string]string)
+	for _, game := range dat.Games {
+		if len(game.ROMs) == 0 {
+			continue
+		}
+		baseName := BaseName(game.ROMs[0].Name)
+		byName[game.Name] = baseName
+		if game.ID != "" {
+			byID[game.ID] = baseName
+		}
+	}
+
+	parents := make(map[string]string)
+	for _, game := range dat.Games {
+		if parent, ok := byName[game.CloneOf]; ok && game.CloneOf != "" {
+			parents[game.Name] = parent
+		} else if parent, ok := byID[game.CloneOfID]; ok {
+			parents[game.Name] = parent
+		}
+	}
+	return parents
+}
+
 // scrape runs the scraping operation on a list of entries
 func (s *Scraper) scrape(ctx context.Context, entries []*LookupEntry) (*ScrapeResults, error) {
 	results := &ScrapeResults{
@@ -171,7 +200,7 @@ func (s *Scraper) scrape(ctx context.Context, entries []*LookupEntry) (*ScrapeRe
 		wg.Add(1)
 		go func(workerID int) {
 			defer wg.Done()
-			worker := NewWorker(workerID, s.client, s.cache, s.config, s.rateLimiter, s.dedup, s.updates)
+			worker := NewWorker(workerID, s.source, s.cache, s.config, s.rateLimiter, s.dedup, s.updates)
 
 			for entry := range entryChan {
 				select {
@@ -0,0 +1,31 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/datfile"
+)
+
+func TestParentBaseNames(t *testing.T) {
+	dat := &datfile.Datafile{Games: []datfile.Game{
+		{Name: "Street Fighter II", ROMs: []datfile.ROM{{Name: "sf2.zip"}}},
+		{Name: "Street Fighter II (Japan)", CloneOf: "Street Fighter II", ROMs: []datfile.ROM{{Name: "sf2j.zip"}}},
+		{Name: "Tetris (World)", ID: "0001", ROMs: []datfile.ROM{{Name: "Tetris (World).gb"}}},
+		{Name: "Tetris (Japan)", CloneOfID: "0001", ROMs: []datfile.ROM{{Name: "Tetris (Japan).gb"}}},
+		{Name: "Orphan", CloneOf: "Missing", ROMs: []datfile.ROM{{Name: "orphan.zip"}}},
+	}}
+
+	got := parentBaseNames(dat)
+	want := map[string]string{
+		"Street Fighter II (Japan)": "sf2",
+		"Tetris (Japan)":            "Tetris (World)",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parentBaseNames() = %v, want %v", got, want)
+	}
+	for name, parent := range want {
+		if got[name] != parent {
+			t.Errorf("parentBaseNames()[%q] = %q, want %q", name, got[name], parent)
+		}
+	}
+}
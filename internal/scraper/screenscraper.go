@@ -0,0 +1,173 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sargunv/rom-tools/internal/region"
+	"github.com/sargunv/rom-tools/lib/screenscraper"
+)
+
+// ScreenScraperSource is a MetadataSource backed by the Screenscraper API.
+type ScreenScraperSource struct {
+	client   *screenscraper.ScreenscraperClient
+	systemID string
+}
+
+// NewScreenScraperSource creates a source that looks up games of a
+// Screenscraper system.
+func NewScreenScraperSource(client *screenscraper.ScreenscraperClient, systemID string) *ScreenScraperSource {
+	return &ScreenScraperSource{
+		client:   client,
+		systemID: systemID,
+	}
+}
+
+// Name returns "screenscraper".
+func (s *ScreenScraperSource) Name() string {
+	return "screenscraper"
+}
+
+// Lookup fetches game info by hashes, serial, file name and size.
+func (s *ScreenScraperSource) Lookup(ctx context.Context, entry *LookupEntry) (*Game, error) {
+	params := &screenscraper.GetGameInfoParams{
+		SystemID: s.systemID,
+		Crc:      entry.Hashes.CRC32,
+		Md5:      entry.Hashes.MD5,
+		Sha1:     entry.Hashes.SHA1,
+		ROMSize:  strconv.FormatInt(entry.Size, 10),
+		ROMName:  entry.FileName,
+		ROMType:  "rom",
+	}
+
+	if entry.Serial != "" {
+		params.SerialNumber = entry.Serial
+	}
+
+	resp, err := s.client.GetGameInfoWithResponse(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check for rate limiting
+	if screenscraper.IsRateLimited(resp) {
+		return nil, ErrRateLimited
+	}
+
+	// Check for not found
+	if screenscraper.IsNotFound(resp) {
+		return nil, nil
+	}
+
+	// Check for other errors
+	if !screenscraper.IsSuccess(resp) {
+		return nil, fmt.Errorf("API error: HTTP %d", resp.StatusCode())
+	}
+
+	// Since Response and Game are value types (not pointers), check for empty game ID
+	if resp.JSON200 == nil || resp.JSON200.Response.Game.Id == "" {
+		return nil, nil // No game data
+	}
+
+	return s.convertGame(&resp.JSON200.Response.Game), nil
+}
+
+// FetchMedia downloads a media item by Screenscraper type and region.
+func (s *ScreenScraperSource) FetchMedia(ctx context.Context, game *Game, media Media) ([]byte, error) {
+	// Build media identifier (e.g., "box-2D(us)")
+	mediaID := media.SourceType
+	if media.Region != "" {
+		mediaID = fmt.Sprintf("%s(%s)", media.SourceType, media.Region)
+	}
+
+	params := &screenscraper.DownloadGameMediaParams{
+		SystemID: s.systemID,
+		GameID:   game.ID,
+		Media:    mediaID,
+	}
+
+	resp, err := s.client.DownloadGameMediaWithResponse(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check for rate limiting
+	if screenscraper.IsRateLimited(resp) {
+		return nil, ErrRateLimited
+	}
+
+	// Not found is not an error for media - just means no media
+	if !screenscraper.IsSuccess(resp) {
+		return nil, nil
+	}
+
+	// Check for special responses (media doesn't exist or already up to date)
+	switch string(resp.Body) {
+	case "NOMEDIA", "CRCOK", "MD5OK", "SHA1OK":
+		return nil, nil
+	}
+
+	return resp.Body, nil
+}
+
+// convertGame converts a Screenscraper game to a source-neutral game.
+func (s *ScreenScraperSource) convertGame(ssGame *screenscraper.Game) *Game {
+	game := &Game{
+		Source:    s.Name(),
+		ID:        ssGame.Id,
+		Name:      ssGame.Name,
+		Developer: ssGame.Developer.Text,
+		Publisher: ssGame.Publisher.Text,
+		Players:   ssGame.Players.Text,
+	}
+
+	for _, n := range ssGame.Names {
+		game.Names = append(game.Names, RegionText{Region: n.Region, Text: n.Text})
+	}
+	for _, d := range ssGame.Dates {
+		game.Dates = append(game.Dates, RegionText{Region: d.Region, Text: d.Text})
+	}
+	game.Synopsis = convertLocalizedNames(ssGame.Synopsis)
+	for _, g := range ssGame.Genres {
+		game.Genres = append(game.Genres, Genre{ID: g.Id, Names: convertLocalizedNames(g.Names)})
+	}
+
+	// Screenscraper uses 0-20 scale, convert to 0-1
+	if noteVal, err := strconv.ParseFloat(ssGame.Note.Text, 64); err == nil {
+		game.Rating = noteVal / 20.0
+	}
+
+	// List media by ES-DE type, keeping the fallback order of MediaTypeMapping
+	for _, esdeType := range AllMediaTypes() {
+		for _, ssType := range MediaTypeMapping[esdeType] {
+			for _, m := range ssGame.Media {
+				if m.Type != ssType {
+					continue
+				}
+				ext := MediaExtensions[ssType]
+				if m.Format != "" {
+					ext = strings.ToLower(m.Format)
+				}
+				game.Media = append(game.Media, Media{
+					Type:       esdeType,
+					SourceType: ssType,
+					Region:     m.Region,
+					URL:        m.Url,
+					Format:     ext,
+				})
+			}
+		}
+	}
+
+	return game
+}
+
+func convertLocalizedNames(names []screenscraper.LocalizedName) []region.LocalizedEntry {
+	entries := make([]region.LocalizedEntry, 0, len(names))
+	for _, n := range names {
+		entries = append(entries, region.LocalizedEntry{Language: n.Language, Text: n.Text})
+	}
+	return entries
+}
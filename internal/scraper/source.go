@@ -0,0 +1,203 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sargunv/rom-tools/internal/region"
+)
+
+// ErrRateLimited is returned by a MetadataSource when the service asks the
+// client to slow down. The worker backs off before the next request.
+var ErrRateLimited = errors.New("rate limited")
+
+// MetadataSource looks up game metadata and media in a database or service.
+type MetadataSource interface {
+	// Name identifies the source (e.g., "screenscraper"). It is recorded in
+	// Game.Source and namespaces cache entries.
+	Name() string
+
+	// Lookup finds a game by the hashes, serial or name of an entry.
+	// Returns nil and no error if the game is not found.
+	Lookup(ctx context.Context, entry *LookupEntry) (*Game, error)
+
+	// FetchMedia downloads a media item of a game found by this source.
+	// Returns nil and no error if the media is not available.
+	FetchMedia(ctx context.Context, game *Game, media Media) ([]byte, error)
+}
+
+// Game is game metadata from a MetadataSource, independent of the source.
+type Game struct {
+	Source string `json:"source"` // Name of the source that found the game
+	ID     string `json:"id"`     // Game ID within the source
+
+	Name      string                  `json:"name,omitempty"`       // Default title
+	Names     []RegionText            `json:"names,omitempty"`      // Titles by region
+	Synopsis  []region.LocalizedEntry `json:"synopsis,omitempty"`   // Descriptions by language
+	Genres    []Genre                 `json:"genres,omitempty"`     // Genres
+	Dates     []RegionText            `json:"dates,omitempty"`      // Release dates by region ("1991-06-23" or "1991")
+	Developer string                  `json:"developer,omitempty"`  // Developer name
+	Publisher string                  `json:"publisher,omitempty"`  // Publisher name
+	Players   string                  `json:"players,omitempty"`    // Player count (e.g., "2" or "1-4")
+	Rating    float64                 `json:"rating,omitempty"`     // Rating from 0 to 1 (0 = unknown)
+	CheevosID string                  `json:"cheevos_id,omitempty"` // RetroAchievements game ID
+
+	Media []Media `json:"media,omitempty"` // Available media
+}
+
+// RegionText is a text value for a region.
+type RegionText struct {
+	Region string `json:"region"`
+	Text   string `json:"text"`
+}
+
+// Genre is a game genre with localized names.
+type Genre struct {
+	ID    string                  `json:"id,omitempty"`
+	Names []region.LocalizedEntry `json:"names"`
+}
+
+// Media is a media item available from a source.
+type Media struct {
+	Type       string `json:"type"`             // ES-DE media type (e.g., "covers")
+	SourceType string `json:"source_type"`      // Source-specific type, in fallback order (e.g., "wheel-hd" before "wheel")
+	Region     string `json:"region,omitempty"` // Region code (empty = any region)
+	ID         string `json:"id,omitempty"`     // Source-specific media ID, if the source needs one to download
+	URL        string `json:"url,omitempty"`    // Download URL, if the source provides one
+	Format     string `json:"format,omitempty"` // File extension (e.g., "png"); empty if unknown until downloaded
+}
+
+// SelectName returns the title best matching the ROM and user regions,
+// falling back to the default title and then to a title of any region.
+func (g *Game) SelectName(romRegions, userRegions []string) string {
+	if name := matchRegionText(g.Names, romRegions, userRegions); name != "" {
+		return name
+	}
+	if g.Name != "" {
+		return g.Name
+	}
+	return selectRegionText(g.Names, romRegions, userRegions)
+}
+
+// SelectSynopsis returns the description best matching the ROM and user regions.
+func (g *Game) SelectSynopsis(romRegions, userRegions []string) string {
+	return region.SelectLocalizedText(g.Synopsis, romRegions, userRegions)
+}
+
+// SelectGenres returns the genre names best matching the ROM and user regions.
+func (g *Game) SelectGenres(romRegions, userRegions []string) []string {
+	var genres []string
+	for _, genre := range g.Genres {
+		if name := region.SelectLocalizedText(genre.Names, romRegions, userRegions); name != "" {
+			genres = append(genres, name)
+		}
+	}
+	return genres
+}
+
+// SelectDate returns the release date best matching the ROM and user regions.
+func (g *Game) SelectDate(romRegions, userRegions []string) string {
+	return selectRegionText(g.Dates, romRegions, userRegions)
+}
+
+// MediaOfType returns the available media of an ES-DE media type.
+func (g *Game) MediaOfType(mediaType string) []Media {
+	var media []Media
+	for _, m := range g.Media {
+		if m.Type == mediaType {
+			media = append(media, m)
+		}
+	}
+	return media
+}
+
+// matchRegionText returns the value of the first region in search order, or
+// "" if no region matches.
+func matchRegionText(values []RegionText, romRegions, userRegions []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	for _, r := range region.BuildSearchOrder(romRegions, userRegions) {
+		for _, v := range values {
+			if v.Region == r && v.Text != "" {
+				return v.Text
+			}
+		}
+	}
+	return ""
+}
+
+// selectRegionText is matchRegionText with a fallback to any value.
+func selectRegionText(values []RegionText, romRegions, userRegions []string) string {
+	if text := matchRegionText(values, romRegions, userRegions); text != "" {
+		return text
+	}
+
+	// Fallback to any
+	for _, v := range values {
+		if v.Text != "" {
+			return v.Text
+		}
+	}
+
+	return ""
+}
+
+// Chain is a MetadataSource that tries several sources in order and returns
+// the first game found.
+type Chain struct {
+	sources []MetadataSource
+}
+
+// NewChain creates a source that tries each source in order.
+func NewChain(sources ...MetadataSource) *Chain {
+	return &Chain{sources: sources}
+}
+
+// Name returns the names of the chained sources joined with "+".
+func (c *Chain) Name() string {
+	names := make([]string, len(c.sources))
+	for i, s := range c.sources {
+		names[i] = s.Name()
+	}
+	return strings.Join(names, "+")
+}
+
+// Lookup tries each source in order. Rate limits and cancellation stop the
+// chain so the worker can back off and retry. Other errors do not; they are
+// returned only if no later source finds the game.
+func (c *Chain) Lookup(ctx context.Context, entry *LookupEntry) (*Game, error) {
+	var firstErr error
+	for _, s := range c.sources {
+		game, err := s.Lookup(ctx, entry)
+		if err != nil {
+			if errors.Is(err, ErrRateLimited) {
+				return nil, fmt.Errorf("%s: %w", s.Name(), err)
+			}
+			if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil, err
+			}
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", s.Name(), err)
+			}
+			continue
+		}
+		if game != nil {
+			return game, nil
+		}
+	}
+	return nil, firstErr
+}
+
+// FetchMedia downloads media from the source that found the game.
+func (c *Chain) FetchMedia(ctx context.Context, game *Game, media Media) ([]byte, error) {
+	for _, s := range c.sources {
+		if s.Name() == game.Source {
+			return s.FetchMedia(ctx, game, media)
+		}
+	}
+	return nil, fmt.Errorf("unknown metadata source: %q", game.Source)
+}
@@ -0,0 +1,459 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sargunv/rom-tools/internal/region"
+	"github.com/sargunv/rom-tools/lib/hasheous"
+	"github.com/sargunv/rom-tools/lib/launchbox"
+	"github.com/sargunv/rom-tools/lib/rdb"
+	"github.com/sargunv/rom-tools/lib/screenscraper"
+)
+
+// fakeSource is a MetadataSource with canned results.
+type fakeSource struct {
+	name    string
+	game    *Game
+	err     error
+	lookups int
+}
+
+func (f *fakeSource) Name() string { return f.name }
+
+func (f *fakeSource) Lookup(ctx context.Context, entry *LookupEntry) (*Game, error) {
+	f.lookups++
+	return f.game, f.err
+}
+
+func (f *fakeSource) FetchMedia(ctx context.Context, game *Game, media Media) ([]byte, error) {
+	return []byte(f.name), nil
+}
+
+func TestChain_Lookup(t *testing.T) {
+	entry := &LookupEntry{Name: "Test"}
+
+	t.Run("first found wins", func(t *testing.T) {
+		first := &fakeSource{name: "a", game: &Game{Source: "a", ID: "1"}}
+		second := &fakeSource{name: "b", game: &Game{Source: "b", ID: "2"}}
+		game, err := NewChain(first, second).Lookup(context.Background(), entry)
+		if err != nil {
+			t.Fatalf("Lookup() error = %v", err)
+		}
+		if game.Source != "a" {
+			t.Errorf("Source = %q, want %q", game.Source, "a")
+		}
+		if second.lookups != 0 {
+			t.Errorf("second source looked up %d times, want 0", second.lookups)
+		}
+	})
+
+	t.Run("falls back after not found and error", func(t *testing.T) {
+		chain := NewChain(
+			&fakeSource{name: "a"},
+			&fakeSource{name: "b", err: errors.New("boom")},
+			&fakeSource{name: "c", game: &Game{Source: "c", ID: "3"}},
+		)
+		game, err := chain.Lookup(context.Background(), entry)
+		if err != nil {
+			t.Fatalf("Lookup() error = %v", err)
+		}
+		if game.Source != "c" {
+			t.Errorf("Source = %q, want %q", game.Source, "c")
+		}
+	})
+
+	t.Run("returns first error if nothing found", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		chain := NewChain(
+			&fakeSource{name: "a", err: errBoom},
+			&fakeSource{name: "b"},
+		)
+		game, err := chain.Lookup(context.Background(), entry)
+		if game != nil {
+			t.Errorf("Lookup() = %+v, want nil", game)
+		}
+		if !errors.Is(err, errBoom) {
+			t.Errorf("Lookup() error = %v, want %v", err, errBoom)
+		}
+	})
+
+	t.Run("stops on rate limit", func(t *testing.T) {
+		second := &fakeSource{name: "b", game: &Game{Source: "b", ID: "2"}}
+		chain := NewChain(&fakeSource{name: "a", err: ErrRateLimited}, second)
+		game, err := chain.Lookup(context.Background(), entry)
+		if game != nil {
+			t.Errorf("Lookup() = %+v, want nil", game)
+		}
+		if !errors.Is(err, ErrRateLimited) {
+			t.Errorf("Lookup() error = %v, want %v", err, ErrRateLimited)
+		}
+		if second.lookups != 0 {
+			t.Errorf("second source looked up %d times, want 0", second.lookups)
+		}
+	})
+
+	t.Run("stops on cancellation", func(t *testing.T) {
+		second := &fakeSource{name: "b", game: &Game{Source: "b", ID: "2"}}
+		chain := NewChain(&fakeSource{name: "a", err: context.DeadlineExceeded}, second)
+		if _, err := chain.Lookup(context.Background(), entry); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Lookup() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+		if second.lookups != 0 {
+			t.Errorf("second source looked up %d times, want 0", second.lookups)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		game, err := NewChain(&fakeSource{name: "a"}, &fakeSource{name: "b"}).Lookup(context.Background(), entry)
+		if game != nil || err != nil {
+			t.Errorf("Lookup() = %+v, %v, want nil, nil", game, err)
+		}
+	})
+}
+
+func TestChain_FetchMedia(t *testing.T) {
+	chain := NewChain(&fakeSource{name: "a"}, &fakeSource{name: "b"})
+
+	if name := chain.Name(); name != "a+b" {
+		t.Errorf("Name() = %q, want %q", name, "a+b")
+	}
+
+	data, err := chain.FetchMedia(context.Background(), &Game{Source: "b"}, Media{})
+	if err != nil || string(data) != "b" {
+		t.Errorf("FetchMedia() = %q, %v, want %q", data, err, "b")
+	}
+
+	if _, err := chain.FetchMedia(context.Background(), &Game{Source: "c"}, Media{}); err == nil {
+		t.Error("FetchMedia() error = nil, want error for unknown source")
+	}
+}
+
+func TestGame_Select(t *testing.T) {
+	game := &Game{
+		Name:  "Default",
+		Names: []RegionText{{Region: "us", Text: "Title US"}, {Region: "jp", Text: "Title JP"}},
+		Dates: []RegionText{{Region: "jp", Text: "1991-07-26"}, {Region: "us", Text: "1991-06-23"}},
+		Genres: []Genre{
+			{ID: "1", Names: []region.LocalizedEntry{{Language: "en", Text: "Platform"}, {Language: "fr", Text: "Plateforme"}}},
+		},
+	}
+
+	if name := game.SelectName([]string{"jp"}, []string{"us"}); name != "Title JP" {
+		t.Errorf("SelectName() = %q, want %q", name, "Title JP")
+	}
+	if date := game.SelectDate(nil, []string{"us"}); date != "1991-06-23" {
+		t.Errorf("SelectDate() = %q, want %q", date, "1991-06-23")
+	}
+	if genres := game.SelectGenres(nil, []string{"us"}); len(genres) != 1 || genres[0] != "Platform" {
+		t.Errorf("SelectGenres() = %v, want [Platform]", genres)
+	}
+	if name := (&Game{Name: "Default"}).SelectName(nil, nil); name != "Default" {
+		t.Errorf("SelectName() = %q, want %q", name, "Default")
+	}
+}
+
+func TestScreenScraperSource_ConvertGame(t *testing.T) {
+	ssGame := &screenscraper.Game{
+		Id:    "3",
+		Name:  "Sonic",
+		Names: []screenscraper.NameEntry{{Region: "us", Text: "Sonic The Hedgehog"}},
+		Media: []screenscraper.Media{
+			{Type: "wheel", Region: "us", Format: "png"},
+			{Type: "wheel-hd", Region: "wor", Format: "PNG"},
+			{Type: "box-2D", Region: "eu", Format: "jpg"},
+		},
+	}
+	ssGame.Note.Text = "16"
+	ssGame.Players.Text = "1-2"
+	ssGame.Developer.Text = "Sonic Team"
+
+	game := NewScreenScraperSource(nil, "1").convertGame(ssGame)
+
+	if game.Source != "screenscraper" || game.ID != "3" {
+		t.Errorf("game = %s:%s, want screenscraper:3", game.Source, game.ID)
+	}
+	if game.Rating != 0.8 {
+		t.Errorf("Rating = %v, want 0.8", game.Rating)
+	}
+	if game.Players != "1-2" || game.Developer != "Sonic Team" {
+		t.Errorf("Players = %q, Developer = %q", game.Players, game.Developer)
+	}
+
+	// wheel-hd comes before wheel regardless of API order
+	marquees := game.MediaOfType("marquees")
+	if len(marquees) != 2 || marquees[0].SourceType != "wheel-hd" || marquees[1].SourceType != "wheel" {
+		t.Fatalf("marquees = %+v", marquees)
+	}
+	if marquees[0].Format != "png" {
+		t.Errorf("Format = %q, want png", marquees[0].Format)
+	}
+	if covers := game.MediaOfType("covers"); len(covers) != 1 || covers[0].Format != "jpg" {
+		t.Errorf("covers = %+v", covers)
+	}
+}
+
+func TestHasheousSource_Lookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/Lookup/ByHash" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": 42,
+			"name": "Test Game",
+			"publisher": {"id": 1, "name": "Test Publisher"},
+			"signatures": [{"game": {"name": "Test Game (USA)", "year": "1990"}}],
+			"metadata": [
+				{"id": "", "source": "IGDB", "status": "NotMapped"},
+				{"id": "1234", "source": "RetroAchievements", "status": "Mapped"}
+			],
+			"attributes": [
+				{"attributeType": "ImageId", "attributeName": "Logo", "value": "logo1"},
+				{"attributeType": "ImageId", "attributeName": "Unknown", "value": "x"}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	source := NewHasheousSource(hasheous.NewClient(hasheous.WithBaseURL(server.URL), hasheous.WithHTTPClient(server.Client())))
+
+	game, err := source.Lookup(context.Background(), &LookupEntry{Hashes: Hashes{SHA1: "abc"}})
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if game.Source != "hasheous" || game.ID != "42" || game.Name != "Test Game" {
+		t.Errorf("game = %s:%s %q", game.Source, game.ID, game.Name)
+	}
+	if game.Publisher != "Test Publisher" || game.CheevosID != "1234" {
+		t.Errorf("Publisher, CheevosID = %q, %q", game.Publisher, game.CheevosID)
+	}
+	if date := game.SelectDate(nil, nil); date != "1990" {
+		t.Errorf("SelectDate() = %q, want 1990", date)
+	}
+	if len(game.Media) != 1 || game.Media[0] != (Media{Type: "marquees", SourceType: "Logo", ID: "logo1"}) {
+		t.Errorf("Media = %+v", game.Media)
+	}
+
+	// Entries without hashes can't be looked up
+	if game, err := source.Lookup(context.Background(), &LookupEntry{Name: "Test"}); game != nil || err != nil {
+		t.Errorf("Lookup() = %+v, %v, want nil, nil", game, err)
+	}
+}
+
+func TestLaunchBoxSource_Lookup(t *testing.T) {
+	db, err := launchbox.ParseReader(strings.NewReader(`<LaunchBox>
+		<Game>
+			<Name>The Legend of Zelda: A Link to the Past</Name>
+			<DatabaseID>7</DatabaseID>
+			<Platform>Super Nintendo Entertainment System</Platform>
+			<Overview>Link saves Hyrule.</Overview>
+			<Genres>Action;Adventure</Genres>
+			<MaxPlayers>1</MaxPlayers>
+			<CommunityRating>4.5</CommunityRating>
+			<ReleaseDate>1991-11-21T00:00:00-08:00</ReleaseDate>
+		</Game>
+		<GameAlternateName>
+			<AlternateName>Zelda no Densetsu: Kamigami no Triforce</AlternateName>
+			<DatabaseID>7</DatabaseID>
+			<Region>Japan</Region>
+		</GameAlternateName>
+		<GameImage>
+			<DatabaseID>7</DatabaseID>
+			<FileName>box-us.PNG</FileName>
+			<Type>Box - Front</Type>
+			<Region>North America</Region>
+		</GameImage>
+		<GameImage>
+			<DatabaseID>7</DatabaseID>
+			<FileName>box-recon.jpg</FileName>
+			<Type>Box - Front - Reconstructed</Type>
+		</GameImage>
+	</LaunchBox>`))
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v", err)
+	}
+	source := NewLaunchBoxSource(db, "Super Nintendo Entertainment System", http.DefaultClient, NewRateLimiter(1, 0))
+
+	game, err := source.Lookup(context.Background(), &LookupEntry{Name: "Legend of Zelda, The - A Link to the Past (USA)"})
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if game == nil {
+		t.Fatal("Lookup() = nil")
+	}
+	if game.Source != "launchbox" || game.ID != "7" {
+		t.Errorf("game = %s:%s, want launchbox:7", game.Source, game.ID)
+	}
+	if name := game.SelectName([]string{"jp"}, nil); name != "Zelda no Densetsu: Kamigami no Triforce" {
+		t.Errorf("SelectName(jp) = %q", name)
+	}
+	if name := game.SelectName([]string{"us"}, nil); name != "The Legend of Zelda: A Link to the Past" {
+		t.Errorf("SelectName(us) = %q", name)
+	}
+	if game.Rating != 0.9 || game.Players != "1" || game.SelectDate(nil, nil) != "1991-11-21" {
+		t.Errorf("Rating = %v, Players = %q, Date = %q", game.Rating, game.Players, game.SelectDate(nil, nil))
+	}
+	if genres := game.SelectGenres(nil, nil); len(genres) != 2 {
+		t.Errorf("SelectGenres() = %v", genres)
+	}
+
+	covers := game.MediaOfType("covers")
+	if len(covers) != 2 || covers[0].SourceType != "Box - Front" || covers[0].Region != "us" || covers[0].Format != "png" {
+		t.Errorf("covers = %+v", covers)
+	}
+
+	// Falls back to the file name
+	game, _ = source.Lookup(context.Background(), &LookupEntry{Name: "Unknown", FileName: "Legend of Zelda, The - A Link to the Past (USA).sfc"})
+	if game == nil {
+		t.Error("Lookup() by file name = nil")
+	}
+
+	game, _ = source.Lookup(context.Background(), &LookupEntry{Name: "Unknown", FileName: "Unknown.sfc"})
+	if game != nil {
+		t.Errorf("Lookup() = %+v, want nil", game)
+	}
+}
+
+func TestLaunchBoxSource_FetchMedia(t *testing.T) {
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		maxActive = max(maxActive, active)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		active--
+		mu.Unlock()
+
+		if r.URL.Path == "/busy.png" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("image"))
+	}))
+	defer server.Close()
+
+	source := NewLaunchBoxSource(nil, "", server.Client(), NewRateLimiter(1, 0))
+
+	// Downloads share the source's thread limit
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := source.FetchMedia(context.Background(), &Game{}, Media{URL: server.URL + "/box.png"})
+			if err != nil || string(data) != "image" {
+				t.Errorf("FetchMedia() = %q, %v, want %q", data, err, "image")
+			}
+		}()
+	}
+	wg.Wait()
+	if maxActive != 1 {
+		t.Errorf("max concurrent downloads = %d, want 1", maxActive)
+	}
+
+	if _, err := source.FetchMedia(context.Background(), &Game{}, Media{URL: server.URL + "/busy.png"}); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("FetchMedia() error = %v, want %v", err, ErrRateLimited)
+	}
+}
+
+func TestDetectMediaExtension(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"png", []byte("\x89PNG\r\n\x1a\n"), "png"},
+		{"jpeg", []byte("\xff\xd8\xff\xe0"), "jpg"},
+		{"gif", []byte("GIF89a"), "gif"},
+		{"unknown", []byte("hello"), "png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectMediaExtension(tt.data); got != tt.want {
+				t.Errorf("detectMediaExtension() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLibretroSource_Lookup(t *testing.T) {
+	db := &rdb.Database{
+		Name: "Nintendo - Game Boy",
+		Entries: []rdb.Entry{{
+			Name:        "Tetris (World) (Rev 1)",
+			Genre:       "Puzzle",
+			Developer:   "Bullet-Proof Software",
+			Users:       2,
+			ReleaseYear: 1989,
+			Serial:      "DMG-TRA",
+			CRC32:       "46df91ad",
+			Database:    "Nintendo - Game Boy",
+		}},
+	}
+	source := NewLibretroSource(rdb.NewIndex(db))
+
+	game, err := source.Lookup(context.Background(), &LookupEntry{Hashes: Hashes{CRC32: "46DF91AD"}})
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if game == nil {
+		t.Fatal("Lookup() = nil")
+	}
+	if game.Source != "libretro" || game.Developer != "Bullet-Proof Software" || game.Players != "2" {
+		t.Errorf("game = %+v", game)
+	}
+	if got := game.SelectDate(nil, nil); got != "1989" {
+		t.Errorf("SelectDate() = %q, want %q", got, "1989")
+	}
+	if genres := game.SelectGenres(nil, nil); len(genres) != 1 || genres[0] != "Puzzle" {
+		t.Errorf("SelectGenres() = %v, want [Puzzle]", genres)
+	}
+
+	game, err = source.Lookup(context.Background(), &LookupEntry{Serial: "DMG-TRA-0"})
+	if err != nil || game != nil {
+		t.Errorf("Lookup(unknown serial) = %v, %v, want nil, nil", game, err)
+	}
+	game, err = source.Lookup(context.Background(), &LookupEntry{Serial: "dmg tra"})
+	if err != nil || game == nil || game.Name != "Tetris (World) (Rev 1)" {
+		t.Errorf("Lookup(serial) = %v, %v", game, err)
+	}
+}
+
+func TestMediaCacheID(t *testing.T) {
+	tests := []struct {
+		game *Game
+		want string
+	}{
+		{&Game{Source: "screenscraper", ID: "3"}, "3"},
+		{&Game{Source: "launchbox", ID: "7"}, filepath.Join("launchbox", "7")},
+		{&Game{Source: "libretro", ID: "Nintendo - Game Boy/Tetris (World)"}, ""},
+	}
+
+	for _, tt := range tests {
+		got := mediaCacheID(tt.game)
+		if tt.want == "" {
+			// Unsafe IDs are hashed into a single directory name
+			dir, name := filepath.Split(got)
+			if dir != "libretro"+string(filepath.Separator) || len(name) != 32 {
+				t.Errorf("mediaCacheID(%q) = %q, want libretro/<hash>", tt.game.ID, got)
+			}
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("mediaCacheID(%q) = %q, want %q", tt.game.ID, got, tt.want)
+		}
+	}
+}
@@ -12,18 +12,22 @@ import (
 // Platform names can be romident Platform values, recalbox names, or common aliases.
 var SystemMapping = map[string]string{
 	// Nintendo consoles (from screenscraper list systems)
-	"nes":          "3",
-	"famicom":      "3", // romident Platform
-	"snes":         "4",
-	"superfamicom": "4", // romident Platform
-	"n64":          "14",
-	"nintendo64":   "14", // romident Platform
-	"gc":           "13",
-	"gamecube":     "13", // romident Platform
-	"ngc":          "13", // alias
-	"wii":          "16",
-	"wiiu":         "18",
-	"fds":          "106", // Famicom Disk System
+	"nes":               "3",
+	"famicom":           "3", // romident Platform
+	"snes":              "4",
+	"superfamicom":      "4", // romident Platform
+	"n64":               "14",
+	"nintendo64":        "14", // romident Platform
+	"n64dd":             "122",
+	"nintendo64dd":      "122", // romident Platform
+	"gc":                "13",
+	"gamecube":          "13", // romident Platform
+	"ngc":               "13", // alias
+	"wii":               "16",
+	"wiiu":              "18",
+	"switch":            "225",
+	"fds":               "106", // Famicom Disk System
+	"famicomdisksystem": "106", // romident Platform
 
 	// Nintendo handhelds
 	"gb":             "9",
@@ -36,8 +40,10 @@ var SystemMapping = map[string]string{
 	"ds":             "15", // romident Platform
 	"dsi":            "15", // romident Platform (same system ID)
 	"3ds":            "17",
-	"virtualboy":     "11",
-	"vb":             "11", // alias
+	"virtualboy":     "11",  // romident Platform
+	"vb":             "11",  // alias
+	"pokemonmini":    "211", // romident Platform
+	"pokemini":       "211", // alias
 
 	// Sega consoles
 	"megadrive":    "1",
@@ -53,7 +59,9 @@ var SystemMapping = map[string]string{
 	"gg":           "21", // alias
 	"saturn":       "22",
 	"dreamcast":    "23",
-	"dc":           "23", // alias
+	"dc":           "23",  // alias
+	"sg1000":       "109", // romident Platform
+	"sg-1000":      "109", // alias
 
 	// Sony consoles
 	"psx":          "57",
@@ -75,48 +83,252 @@ var SystemMapping = map[string]string{
 	"xboxone": "34",
 
 	// NEC
-	"pcengine":     "31",
-	"pce":          "31", // alias
-	"turbografx16": "31", // alias
-	"tg16":         "31", // alias
-	"supergrafx":   "105",
+	"pcengine":     "31",  // romident Platform
+	"pce":          "31",  // alias
+	"turbografx16": "31",  // alias
+	"tg16":         "31",  // alias
+	"supergrafx":   "105", // romident Platform
 	"sgx":          "105", // alias
 	"pcfx":         "72",
 
 	// SNK
-	"neogeo":       "142",
-	"ng":           "142", // alias
-	"neogeocd":     "70",
-	"ngcd":         "70", // alias
-	"ngp":          "25",
-	"neogeopocket": "25", // alias
-	"ngpc":         "82",
+	"neogeo":            "142",
+	"ng":                "142", // alias
+	"neogeocd":          "70",
+	"ngcd":              "70", // alias
+	"ngp":               "25",
+	"neogeopocket":      "25", // romident Platform
+	"ngpc":              "82",
+	"neogeopocketcolor": "82", // romident Platform
 
 	// Atari
-	"atari2600":   "26",
+	"atari2600":   "26", // romident Platform
 	"2600":        "26", // alias
-	"atari5200":   "40",
+	"atari5200":   "40", // romident Platform
 	"5200":        "40", // alias
-	"atari7800":   "41",
+	"atari7800":   "41", // romident Platform
 	"7800":        "41", // alias
 	"lynx":        "28",
-	"atarilynx":   "28", // alias
+	"atarilynx":   "28", // romident Platform
 	"jaguar":      "27",
-	"atarijaguar": "27", // alias
+	"atarijaguar": "27", // romident Platform
 
 	// Bandai
-	"wonderswan":      "45",
+	"wonderswan":      "45", // romident Platform
 	"ws":              "45", // alias
-	"wonderswancolor": "46",
+	"wonderswancolor": "46", // romident Platform
 	"wsc":             "46", // alias
 
+	// Arcade
+	"arcade": "75", // romident Platform
+	"mame":   "75", // alias
+
+	// MSX
+	"msx":  "113", // romident Platform
+	"msx1": "113", // alias
+	"msx2": "116", // romident Platform
+
 	// Other
-	"colecovision":  "48",
-	"intellivision": "115",
-	"vectrex":       "102",
+	"colecovision":  "48",  // romident Platform
+	"coleco":        "48",  // alias
+	"intellivision": "115", // romident Platform
+	"intv":          "115", // alias
+	"vectrex":       "102", // romident Platform
+	"channelf":      "80",  // romident Platform
+	"supervision":   "207", // romident Platform
 	"3do":           "29",
 }
 
+// LaunchBoxPlatforms maps Screenscraper system IDs to LaunchBox platform names.
+var LaunchBoxPlatforms = map[string]string{
+	"1":   "Sega Genesis",
+	"2":   "Sega Master System",
+	"3":   "Nintendo Entertainment System",
+	"4":   "Super Nintendo Entertainment System",
+	"9":   "Nintendo Game Boy",
+	"10":  "Nintendo Game Boy Color",
+	"11":  "Nintendo Virtual Boy",
+	"12":  "Nintendo Game Boy Advance",
+	"13":  "Nintendo GameCube",
+	"14":  "Nintendo 64",
+	"15":  "Nintendo DS",
+	"16":  "Nintendo Wii",
+	"17":  "Nintendo 3DS",
+	"18":  "Nintendo Wii U",
+	"19":  "Sega 32X",
+	"20":  "Sega CD",
+	"21":  "Sega Game Gear",
+	"22":  "Sega Saturn",
+	"23":  "Sega Dreamcast",
+	"25":  "SNK Neo Geo Pocket",
+	"26":  "Atari 2600",
+	"27":  "Atari Jaguar",
+	"28":  "Atari Lynx",
+	"29":  "3DO Interactive Multiplayer",
+	"31":  "NEC TurboGrafx-16",
+	"32":  "Microsoft Xbox",
+	"33":  "Microsoft Xbox 360",
+	"34":  "Microsoft Xbox One",
+	"40":  "Atari 5200",
+	"41":  "Atari 7800",
+	"45":  "WonderSwan",
+	"46":  "WonderSwan Color",
+	"48":  "ColecoVision",
+	"57":  "Sony Playstation",
+	"58":  "Sony Playstation 2",
+	"59":  "Sony Playstation 3",
+	"61":  "Sony PSP",
+	"62":  "Sony Playstation Vita",
+	"70":  "SNK Neo Geo CD",
+	"72":  "NEC PC-FX",
+	"75":  "Arcade",
+	"80":  "Fairchild Channel F",
+	"82":  "SNK Neo Geo Pocket Color",
+	"102": "GCE Vectrex",
+	"105": "PC Engine SuperGrafx",
+	"106": "Nintendo Famicom Disk System",
+	"109": "Sega SG-1000",
+	"113": "Microsoft MSX",
+	"115": "Mattel Intellivision",
+	"116": "Microsoft MSX2",
+	"122": "Nintendo 64DD",
+	"142": "SNK Neo Geo AES",
+	"207": "Watara Supervision",
+	"211": "Nintendo Pokemon Mini",
+	"225": "Nintendo Switch",
+}
+
+// LookupLaunchBoxPlatform returns the LaunchBox platform name of a
+// Screenscraper system ID.
+func LookupLaunchBoxPlatform(systemID string) (string, error) {
+	if platform, ok := LaunchBoxPlatforms[systemID]; ok {
+		return platform, nil
+	}
+	return "", fmt.Errorf("no LaunchBox platform for system ID %s", systemID)
+}
+
+// LibretroDatabases maps Screenscraper system IDs to libretro database names
+// (the RDB file names shipped with RetroArch).
+var LibretroDatabases = map[string]string{
+	"1":   "Sega - Mega Drive - Genesis",
+	"2":   "Sega - Master System - Mark III",
+	"3":   "Nintendo - Nintendo Entertainment System",
+	"4":   "Nintendo - Super Nintendo Entertainment System",
+	"9":   "Nintendo - Game Boy",
+	"10":  "Nintendo - Game Boy Color",
+	"11":  "Nintendo - Virtual Boy",
+	"12":  "Nintendo - Game Boy Advance",
+	"13":  "Nintendo - GameCube",
+	"14":  "Nintendo - Nintendo 64",
+	"15":  "Nintendo - Nintendo DS",
+	"16":  "Nintendo - Wii",
+	"17":  "Nintendo - Nintendo 3DS",
+	"18":  "Nintendo - Wii U",
+	"19":  "Sega - 32X",
+	"20":  "Sega - Mega-CD - Sega CD",
+	"21":  "Sega - Game Gear",
+	"22":  "Sega - Saturn",
+	"23":  "Sega - Dreamcast",
+	"25":  "SNK - Neo Geo Pocket",
+	"26":  "Atari - 2600",
+	"27":  "Atari - Jaguar",
+	"28":  "Atari - Lynx",
+	"29":  "The 3DO Company - 3DO",
+	"31":  "NEC - PC Engine - TurboGrafx 16",
+	"32":  "Microsoft - Xbox",
+	"33":  "Microsoft - Xbox 360",
+	"40":  "Atari - 5200",
+	"41":  "Atari - 7800",
+	"45":  "Bandai - WonderSwan",
+	"46":  "Bandai - WonderSwan Color",
+	"48":  "Coleco - ColecoVision",
+	"57":  "Sony - PlayStation",
+	"58":  "Sony - PlayStation 2",
+	"59":  "Sony - PlayStation 3",
+	"61":  "Sony - PlayStation Portable",
+	"62":  "Sony - PlayStation Vita",
+	"70":  "SNK - Neo Geo CD",
+	"72":  "NEC - PC-FX",
+	"75":  "MAME",
+	"80":  "Fairchild - Channel F",
+	"82":  "SNK - Neo Geo Pocket Color",
+	"102": "GCE - Vectrex",
+	"105": "NEC - PC Engine SuperGrafx",
+	"106": "Nintendo - Family Computer Disk System",
+	"109": "Sega - SG-1000",
+	"113": "Microsoft - MSX",
+	"115": "Mattel - Intellivision",
+	"116": "Microsoft - MSX2",
+	"122": "Nintendo - Nintendo 64DD",
+	"207": "Watara - Supervision",
+	"211": "Nintendo - Pokemon Mini",
+}
+
+// LookupLibretroDatabase returns the libretro database name of a
+// Screenscraper system ID.
+func LookupLibretroDatabase(systemID string) (string, error) {
+	if db, ok := LibretroDatabases[systemID]; ok {
+		return db, nil
+	}
+	return "", fmt.Errorf("no libretro database for system ID %s", systemID)
+}
+
+// MuOSSystems maps Screenscraper system IDs to MuOS catalogue system names.
+var MuOSSystems = map[string]string{
+	"1":   "Sega Mega Drive - Genesis",
+	"2":   "Sega Master System",
+	"3":   "Nintendo NES - Famicom",
+	"4":   "Nintendo SNES - SFC",
+	"9":   "Nintendo Game Boy",
+	"10":  "Nintendo Game Boy Color",
+	"11":  "Nintendo Virtual Boy",
+	"12":  "Nintendo Game Boy Advance",
+	"14":  "Nintendo N64",
+	"15":  "Nintendo DS",
+	"19":  "Sega 32X",
+	"20":  "Sega Mega CD - Sega CD",
+	"21":  "Sega Game Gear",
+	"22":  "Sega Saturn",
+	"23":  "Sega Dreamcast",
+	"25":  "SNK Neo Geo Pocket - Color",
+	"26":  "Atari 2600",
+	"27":  "Atari Jaguar",
+	"28":  "Atari Lynx",
+	"29":  "The 3DO Company - 3DO",
+	"31":  "NEC PC Engine",
+	"40":  "Atari 5200",
+	"41":  "Atari 7800",
+	"45":  "Bandai WonderSwan - Color",
+	"46":  "Bandai WonderSwan - Color",
+	"48":  "ColecoVision",
+	"57":  "Sony PlayStation",
+	"61":  "Sony PlayStation Portable",
+	"70":  "SNK Neo Geo CD",
+	"72":  "NEC PC-FX",
+	"75":  "Arcade",
+	"80":  "Fairchild ChannelF",
+	"82":  "SNK Neo Geo Pocket - Color",
+	"102": "GCE - Vectrex",
+	"105": "NEC PC Engine SuperGrafx",
+	"106": "Nintendo Famicom Disk System",
+	"109": "Sega SG-1000",
+	"113": "Microsoft - MSX",
+	"115": "Mattel - Intellivision",
+	"116": "Microsoft - MSX",
+	"142": "SNK Neo Geo",
+	"207": "Watara Supervision",
+	"211": "Nintendo Pokemon Mini",
+}
+
+// LookupMuOSSystem returns the MuOS catalogue system name of a Screenscraper
+// system ID.
+func LookupMuOSSystem(systemID string) (string, error) {
+	if system, ok := MuOSSystems[systemID]; ok {
+		return system, nil
+	}
+	return "", fmt.Errorf("no MuOS catalogue system for system ID %s", systemID)
+}
+
 // LookupSystemID converts a platform name to a Screenscraper system ID.
 // Accepts romident Platform values, recalbox names, or common aliases.
 // Returns error if the platform is not recognized.
@@ -141,10 +353,10 @@ func AvailableSystems() []string {
 	// Primary short names (recalbox style)
 	primaryNames := []string{
 		// Nintendo
-		"nes", "snes", "n64", "gc", "wii", "wiiu", "fds",
-		"gb", "gbc", "gba", "nds", "3ds", "virtualboy",
+		"nes", "snes", "n64", "n64dd", "gc", "wii", "wiiu", "switch", "fds",
+		"gb", "gbc", "gba", "nds", "3ds", "virtualboy", "pokemonmini",
 		// Sega
-		"megadrive", "mastersystem", "sega32x", "segacd", "gamegear", "saturn", "dreamcast",
+		"megadrive", "mastersystem", "sega32x", "segacd", "gamegear", "saturn", "dreamcast", "sg1000",
 		// Sony
 		"psx", "ps2", "ps3", "psp", "psvita",
 		// Microsoft
@@ -157,8 +369,12 @@ func AvailableSystems() []string {
 		"atari2600", "atari5200", "atari7800", "lynx", "jaguar",
 		// Bandai
 		"wonderswan", "wonderswancolor",
+		// Arcade
+		"arcade",
+		// MSX
+		"msx", "msx2",
 		// Other
-		"colecovision", "vectrex", "3do",
+		"colecovision", "intellivision", "vectrex", "channelf", "supervision", "3do",
 	}
 
 	for _, name := range primaryNames {
@@ -170,12 +386,17 @@ func AvailableSystems() []string {
 
 	// Also include romident Platform values that aren't already covered
 	romidentPlatforms := []core.Platform{
-		core.PlatformNES, core.PlatformSNES, core.PlatformN64, core.PlatformGC,
+		core.PlatformNES, core.PlatformFDS, core.PlatformSNES, core.PlatformN64, core.PlatformN64DD, core.PlatformGC,
 		core.PlatformWii, core.PlatformWiiU, core.PlatformGB, core.PlatformGBC,
-		core.PlatformGBA, core.PlatformNDS, core.PlatformDSi, core.Platform3DS,
+		core.PlatformGBA, core.PlatformVirtualBoy, core.PlatformPokemonMini, core.PlatformNDS, core.PlatformDSi, core.Platform3DS,
 		core.PlatformPS1, core.PlatformPS2, core.PlatformPS3, core.PlatformPSP,
 		core.PlatformPSVita, core.PlatformMS, core.PlatformMD, core.PlatformSaturn,
-		core.PlatformDreamcast, core.PlatformGameGear, core.PlatformXbox, core.PlatformXbox360,
+		core.PlatformDreamcast, core.PlatformGameGear, core.PlatformNGP, core.PlatformNGPC,
+		core.PlatformWonderSwan, core.PlatformWonderSwanColor, core.PlatformXbox, core.PlatformXbox360,
+		core.PlatformAtari2600, core.PlatformAtari5200, core.PlatformAtari7800, core.PlatformAtariLynx, core.PlatformAtariJaguar,
+		core.PlatformPCEngine, core.PlatformSuperGrafx, core.PlatformSG1000, core.PlatformColecoVision,
+		core.PlatformIntellivision, core.PlatformVectrex, core.PlatformChannelF, core.PlatformSupervision,
+		core.PlatformMSX, core.PlatformMSX2, core.PlatformArcade,
 	}
 
 	for _, p := range romidentPlatforms {
@@ -3,8 +3,6 @@ package scraper
 import (
 	"path/filepath"
 	"strings"
-
-	"github.com/sargunv/rom-tools/lib/screenscraper"
 )
 
 // BaseName returns the filename without extension
@@ -46,7 +44,7 @@ const (
 	SourceROM
 )
 
-// LookupEntry is the unified input for Screenscraper lookups
+// LookupEntry is the unified input for metadata source lookups
 type LookupEntry struct {
 	// Identification
 	Name     string // Display name (from DAT or filename)
@@ -61,6 +59,9 @@ type LookupEntry struct {
 	// Output path (for media naming)
 	BaseName string // Filename without extension
 
+	// Parent/clone info (from DAT)
+	CloneOf string // BaseName of the parent entry, if this is a clone
+
 	// Source info
 	Source  LookupSource
 	ROMPath string // Only for ROM source
@@ -69,8 +70,8 @@ type LookupEntry struct {
 // ScrapeResult contains the result of looking up a single entry
 type ScrapeResult struct {
 	Entry     *LookupEntry
-	Game      *screenscraper.Game // nil if not found
-	Media     map[string]string   // mediaType -> local path (downloaded)
+	Game      *Game             // nil if not found
+	Media     map[string]string // mediaType -> local path (downloaded)
 	Error     error
 	Cached    bool   // true if game info from cache
 	Skipped   bool   // true if skipped (BIOS, etc.)
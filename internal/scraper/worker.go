@@ -2,22 +2,23 @@ package scraper
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
-	"strconv"
-	"strings"
 
 	"github.com/sargunv/rom-tools/internal/cache"
 	"github.com/sargunv/rom-tools/internal/region"
-	"github.com/sargunv/rom-tools/lib/screenscraper"
 )
 
 // Worker handles scraping tasks
 type Worker struct {
 	id          int
-	client      *screenscraper.ScreenscraperClient
+	source      MetadataSource
 	cache       *cache.DiskCache
 	config      *Config
 	rateLimiter *RateLimiter
@@ -26,10 +27,10 @@ type Worker struct {
 }
 
 // NewWorker creates a new worker
-func NewWorker(id int, client *screenscraper.ScreenscraperClient, cache *cache.DiskCache, config *Config, rateLimiter *RateLimiter, dedup *Deduplicator, updates chan<- ProgressUpdate) *Worker {
+func NewWorker(id int, source MetadataSource, cache *cache.DiskCache, config *Config, rateLimiter *RateLimiter, dedup *Deduplicator, updates chan<- ProgressUpdate) *Worker {
 	return &Worker{
 		id:          id,
-		client:      client,
+		source:      source,
 		cache:       cache,
 		config:      config,
 		rateLimiter: rateLimiter,
@@ -108,18 +109,18 @@ func (w *Worker) Process(ctx context.Context, entry *LookupEntry) *ScrapeResult
 			CurrentMedia: esdeType,
 		})
 
-		ssTypes, ok := MediaTypeMapping[esdeType]
-		if !ok {
+		candidates := game.MediaOfType(esdeType)
+		if len(candidates) == 0 {
 			mediaMissing++
 			continue
 		}
 
-		// Try each screenscraper type in order (fallback)
+		// Try each source type in order (fallback)
 		gotMedia := false
 		wasCached := false
 		hadError := false
-		for _, ssType := range ssTypes {
-			path, fromCache, err := w.downloadMedia(ctx, entry, game, esdeType, ssType)
+		for _, group := range groupBySourceType(candidates) {
+			path, fromCache, err := w.downloadMedia(ctx, entry, game, esdeType, group)
 			if err != nil {
 				hadError = true
 				continue
@@ -160,15 +161,17 @@ func (w *Worker) Process(ctx context.Context, entry *LookupEntry) *ScrapeResult
 	return result
 }
 
-// lookupGame fetches game info from cache or API
+// lookupGame fetches game info from cache or the metadata source
 // Returns (game, cached, notFound, error)
-func (w *Worker) lookupGame(ctx context.Context, entry *LookupEntry) (*screenscraper.Game, bool, bool, error) {
-	cacheKey := entry.Hashes.CacheKey()
+func (w *Worker) lookupGame(ctx context.Context, entry *LookupEntry) (*Game, bool, bool, error) {
+	// Cached game info is the source-neutral Game, so entries are keyed by
+	// source and never mixed up with another source's result for the file
+	cacheKey := w.source.Name() + ":" + entry.Hashes.CacheKey()
 
 	// Check cache first
 	if !w.config.SkipCacheRead {
 		if data, ok := w.cache.GetGameInfo(w.config.SystemID, cacheKey); ok {
-			var game screenscraper.Game
+			var game Game
 			if err := json.Unmarshal(data, &game); err == nil {
 				return &game, true, false, nil
 			}
@@ -179,23 +182,22 @@ func (w *Worker) lookupGame(ctx context.Context, entry *LookupEntry) (*screenscr
 	dedupKey := fmt.Sprintf("game:%s:%s", w.config.SystemID, cacheKey)
 
 	type lookupResult struct {
-		game     *screenscraper.Game
-		notFound bool
+		game *Game
 	}
 
 	result, err := DoTyped(w.dedup, dedupKey, func() (*lookupResult, error) {
-		game, notFound, err := w.fetchGameFromAPI(ctx, entry)
+		game, err := w.fetchGame(ctx, entry)
 		if err != nil {
 			return nil, err
 		}
-		return &lookupResult{game: game, notFound: notFound}, nil
+		return &lookupResult{game: game}, nil
 	})
 
 	if err != nil {
 		return nil, false, false, err
 	}
 
-	if result.notFound {
+	if result.game == nil {
 		return nil, false, true, nil
 	}
 
@@ -209,93 +211,56 @@ func (w *Worker) lookupGame(ctx context.Context, entry *LookupEntry) (*screenscr
 	return result.game, false, false, nil
 }
 
-// fetchGameFromAPI fetches game info from Screenscraper API
-// Returns (game, notFound, error)
-func (w *Worker) fetchGameFromAPI(ctx context.Context, entry *LookupEntry) (*screenscraper.Game, bool, error) {
+// fetchGame looks up a game in the metadata source
+// Returns nil if the game is not found
+func (w *Worker) fetchGame(ctx context.Context, entry *LookupEntry) (*Game, error) {
 	// Acquire rate limiter
 	if err := w.rateLimiter.Acquire(ctx); err != nil {
-		return nil, false, err
+		return nil, err
 	}
 	defer w.rateLimiter.Release()
 
-	// Prepare parameters
-	romSize := strconv.FormatInt(entry.Size, 10)
-	params := &screenscraper.GetGameInfoParams{
-		SystemID: w.config.SystemID,
-		Crc:      entry.Hashes.CRC32,
-		Md5:      entry.Hashes.MD5,
-		Sha1:     entry.Hashes.SHA1,
-		ROMSize:  romSize,
-		ROMName:  entry.FileName,
-		ROMType:  "rom",
-	}
-
-	if entry.Serial != "" {
-		params.SerialNumber = entry.Serial
-	}
-
-	resp, err := w.client.GetGameInfoWithResponse(ctx, params)
+	game, err := w.source.Lookup(ctx, entry)
 	if err != nil {
-		return nil, false, err
-	}
-
-	// Check for rate limiting
-	if screenscraper.IsRateLimited(resp) {
-		w.rateLimiter.TriggerBackoff()
-		return nil, false, fmt.Errorf("rate limited")
-	}
-
-	// Check for not found
-	if screenscraper.IsNotFound(resp) {
-		return nil, true, nil
-	}
-
-	// Check for other errors
-	if !screenscraper.IsSuccess(resp) {
-		return nil, false, fmt.Errorf("API error: HTTP %d", resp.StatusCode())
+		if errors.Is(err, ErrRateLimited) {
+			w.rateLimiter.TriggerBackoff()
+		}
+		return nil, err
 	}
 
 	w.rateLimiter.ResetBackoff()
-
-	// Extract game from response
-	// Since Response and Game are now value types (not pointers), check for empty game ID
-	if resp.JSON200 == nil || resp.JSON200.Response.Game.Id == "" {
-		return nil, true, nil // No game data
-	}
-
-	return &resp.JSON200.Response.Game, false, nil
+	return game, nil
 }
 
-// downloadMedia downloads a specific media type for a game
+// downloadMedia downloads the best of a set of candidates of one source type
 // Returns (path, cached, error) where cached indicates if the media was served from cache
-func (w *Worker) downloadMedia(ctx context.Context, entry *LookupEntry, game *screenscraper.Game, esdeType, ssType string) (string, bool, error) {
+func (w *Worker) downloadMedia(ctx context.Context, entry *LookupEntry, game *Game, esdeType string, candidates []Media) (string, bool, error) {
 	// Find the best media match based on region
-	candidates := make([]region.Media, 0)
-	for _, m := range game.Media {
-		if m.Type == ssType {
-			candidates = append(candidates, region.Media{
-				Type:   m.Type,
-				Region: m.Region,
-				URL:    m.Url,
-				Format: m.Format,
-			})
+	regionCandidates := make([]region.Media, len(candidates))
+	for i, m := range candidates {
+		regionCandidates[i] = region.Media{
+			Type:   m.Type,
+			Region: m.Region,
+			URL:    m.URL,
+			Format: m.Format,
 		}
 	}
 
-	if len(candidates) == 0 {
-		return "", false, nil // No media available
+	selected := region.SelectMedia(regionCandidates, esdeType, entry.Regions, w.config.PreferredRegions)
+	if selected == nil {
+		return "", false, nil
 	}
 
-	media := region.SelectMedia(candidates, ssType, entry.Regions, w.config.PreferredRegions)
-	if media == nil {
-		return "", false, nil
+	var media Media
+	for i := range regionCandidates {
+		if regionCandidates[i] == *selected {
+			media = candidates[i]
+			break
+		}
 	}
 
 	// Determine extension
-	ext := MediaExtensions[ssType]
-	if media.Format != "" {
-		ext = strings.ToLower(media.Format)
-	}
+	ext := media.Format
 
 	// Build output path
 	relativePath := filepath.Join(esdeType, entry.BaseName+"."+ext)
@@ -303,22 +268,26 @@ func (w *Worker) downloadMedia(ctx context.Context, entry *LookupEntry, game *sc
 
 	// Check if output file already exists (skip unless overwrite)
 	if !w.config.Overwrite {
-		if _, err := os.Stat(outputPath); err == nil {
+		if ext == "" {
+			if existing := findMediaFile(w.config.MediaOutputDir, esdeType, entry.BaseName); existing != "" {
+				return existing, true, nil // Already exists on disk
+			}
+		} else if _, err := os.Stat(outputPath); err == nil {
 			return relativePath, true, nil // Already exists on disk
 		}
 	}
 
 	// Get game ID
-	if game.Id == "" {
+	if game.ID == "" {
 		return "", false, nil // No game ID
 	}
+	gameKey := mediaCacheID(game)
 
 	// Check cache for media data
-	mediaRegion := media.Region
 	var data []byte
 	cached := false
 	if !w.config.SkipCacheRead {
-		if cachedData, cachedExt, ok := w.cache.GetMedia(w.config.SystemID, game.Id, ssType, mediaRegion); ok {
+		if cachedData, cachedExt, ok := w.cache.GetMedia(w.config.SystemID, gameKey, media.SourceType, media.Region); ok {
 			// Check if this is a cached "no media available" marker
 			if cachedExt == "nomedia" {
 				return "", false, nil
@@ -334,9 +303,9 @@ func (w *Worker) downloadMedia(ctx context.Context, entry *LookupEntry, game *sc
 	// Download if not in cache
 	if data == nil {
 		// Use deduplicator for downloads
-		dedupKey := fmt.Sprintf("media:%s:%s:%s:%s", w.config.SystemID, game.Id, ssType, mediaRegion)
+		dedupKey := fmt.Sprintf("media:%s:%s:%s:%s", w.config.SystemID, gameKey, media.SourceType, media.Region)
 		downloaded, err := DoTyped(w.dedup, dedupKey, func() ([]byte, error) {
-			return w.downloadMediaFromAPI(ctx, game.Id, ssType, mediaRegion, ext)
+			return w.fetchMedia(ctx, game, gameKey, media)
 		})
 
 		if err != nil {
@@ -345,10 +314,17 @@ func (w *Worker) downloadMedia(ctx context.Context, entry *LookupEntry, game *sc
 		data = downloaded
 	}
 
-	if data == nil || len(data) == 0 {
+	if len(data) == 0 {
 		return "", false, nil // No data
 	}
 
+	// Name the file after its content if the source didn't know the format
+	if ext == "" {
+		ext = detectMediaExtension(data)
+		relativePath = filepath.Join(esdeType, entry.BaseName+"."+ext)
+		outputPath = filepath.Join(w.config.MediaOutputDir, relativePath)
+	}
+
 	// Write to output directory
 	if w.config.MediaOutputDir != "" {
 		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
@@ -362,54 +338,26 @@ func (w *Worker) downloadMedia(ctx context.Context, entry *LookupEntry, game *sc
 	return relativePath, cached, nil
 }
 
-// downloadMediaFromAPI downloads media from Screenscraper API
-func (w *Worker) downloadMediaFromAPI(ctx context.Context, gameID, mediaType, mediaRegion, ext string) ([]byte, error) {
+// fetchMedia downloads media from the metadata source and caches it
+func (w *Worker) fetchMedia(ctx context.Context, game *Game, gameKey string, media Media) ([]byte, error) {
 	// Acquire rate limiter
 	if err := w.rateLimiter.Acquire(ctx); err != nil {
 		return nil, err
 	}
 	defer w.rateLimiter.Release()
 
-	// Build media identifier (e.g., "box-2D(us)")
-	mediaID := mediaType
-	if mediaRegion != "" {
-		mediaID = fmt.Sprintf("%s(%s)", mediaType, mediaRegion)
-	}
-
-	params := &screenscraper.DownloadGameMediaParams{
-		SystemID: w.config.SystemID,
-		GameID:   gameID,
-		Media:    mediaID,
-	}
-
-	resp, err := w.client.DownloadGameMediaWithResponse(ctx, params)
+	data, err := w.source.FetchMedia(ctx, game, media)
 	if err != nil {
+		if errors.Is(err, ErrRateLimited) {
+			w.rateLimiter.TriggerBackoff()
+		}
 		return nil, err
 	}
 
-	// Check for rate limiting
-	if screenscraper.IsRateLimited(resp) {
-		w.rateLimiter.TriggerBackoff()
-		return nil, fmt.Errorf("rate limited")
-	}
-
-	// Check for errors (not found is not an error for media - just means no media)
-	if !screenscraper.IsSuccess(resp) {
+	if len(data) == 0 {
 		// Media doesn't exist - cache this so we don't retry
 		if !w.config.SkipCacheWrite {
-			w.cache.SetMedia(w.config.SystemID, gameID, mediaType, mediaRegion, []byte("NOMEDIA"), "nomedia")
-		}
-		return nil, nil
-	}
-
-	data := resp.Body
-
-	// Check for special responses
-	dataStr := string(data)
-	if dataStr == "NOMEDIA" || dataStr == "CRCOK" || dataStr == "MD5OK" || dataStr == "SHA1OK" {
-		// Media doesn't exist or already up to date - cache this so we don't retry
-		if !w.config.SkipCacheWrite {
-			w.cache.SetMedia(w.config.SystemID, gameID, mediaType, mediaRegion, []byte("NOMEDIA"), "nomedia")
+			w.cache.SetMedia(w.config.SystemID, gameKey, media.SourceType, media.Region, []byte("NOMEDIA"), "nomedia")
 		}
 		return nil, nil
 	}
@@ -418,12 +366,81 @@ func (w *Worker) downloadMediaFromAPI(ctx context.Context, gameID, mediaType, me
 
 	// Cache the media
 	if !w.config.SkipCacheWrite {
-		w.cache.SetMedia(w.config.SystemID, gameID, mediaType, mediaRegion, data, ext)
+		ext := media.Format
+		if ext == "" {
+			ext = detectMediaExtension(data)
+		}
+		w.cache.SetMedia(w.config.SystemID, gameKey, media.SourceType, media.Region, data, ext)
 	}
 
 	return data, nil
 }
 
+// findMediaFile returns the relative path of an existing media file with any
+// extension, or "" if there is none
+func findMediaFile(mediaDir, mediaType, baseName string) string {
+	entries, err := os.ReadDir(filepath.Join(mediaDir, mediaType))
+	if err != nil {
+		return ""
+	}
+	for _, e := range entries {
+		if !e.IsDir() && BaseName(e.Name()) == baseName {
+			return filepath.Join(mediaType, e.Name())
+		}
+	}
+	return ""
+}
+
+// detectMediaExtension returns a file extension for media data by sniffing
+// its content type, defaulting to "png"
+func detectMediaExtension(data []byte) string {
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return "jpg"
+	case "image/gif":
+		return "gif"
+	case "image/webp":
+		return "webp"
+	case "video/mp4":
+		return "mp4"
+	default:
+		return "png"
+	}
+}
+
+// mediaCacheID returns the cache directory for a game's media. ScreenScraper
+// media stays under the bare game ID so existing caches remain valid; other
+// sources get a subdirectory, with IDs that aren't safe as a directory name
+// (like libretro's database/name IDs) hashed.
+func mediaCacheID(game *Game) string {
+	if game.Source == "screenscraper" {
+		return game.ID
+	}
+	id := game.ID
+	for _, c := range id {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '-' || c == '_') {
+			h := sha256.Sum256([]byte(game.ID))
+			id = hex.EncodeToString(h[:16])
+			break
+		}
+	}
+	return filepath.Join(game.Source, id)
+}
+
+// groupBySourceType splits media into runs of the same source type, keeping
+// their order
+func groupBySourceType(media []Media) [][]Media {
+	var groups [][]Media
+	for _, m := range media {
+		if n := len(groups); n > 0 && groups[n-1][0].SourceType == m.SourceType {
+			groups[n-1] = append(groups[n-1], m)
+		} else {
+			groups = append(groups, []Media{m})
+		}
+	}
+	return groups
+}
+
 func (w *Worker) sendUpdate(update ProgressUpdate) {
 	w.updates <- update
 }
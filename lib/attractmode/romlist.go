@@ -0,0 +1,140 @@
+// Package attractmode provides AttractMode romlists and artwork layout.
+//
+// AttractMode lists the games of an emulator in a romlist, a text file with
+// one game per line and semicolon-separated fields, starting with a
+// commented header:
+//
+//	#Name;Title;Emulator;CloneOf;Year;Manufacturer;Category;Players;Rotation;Control;Status;DisplayCount;DisplayType;AltRomname;AltTitle;Extra;Buttons
+//	sf2;Street Fighter II: The World Warrior;mame;;1991;Capcom;Fighter;2;0;joystick (8-way);good;1;raster;;;;6
+//
+// Name is the ROM file name without its extension, and CloneOf the Name of
+// the parent game. Fields can't contain semicolons; there is no escaping.
+//
+// In the AttractMode config folder, romlists and the artwork its scraper
+// downloads live in folders per emulator, with artwork named after the game:
+//
+//	romlists/<emulator>.txt
+//	scraper/<emulator>/snap/<name>.png
+//	scraper/<emulator>/wheel/<name>.png
+package attractmode
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Header is the romlist header line
+const Header = "#Name;Title;Emulator;CloneOf;Year;Manufacturer;Category;Players;Rotation;Control;Status;DisplayCount;DisplayType;AltRomname;AltTitle;Extra;Buttons"
+
+// Artwork types (folder names under scraper/<emulator>)
+const (
+	ArtworkSnap    = "snap"
+	ArtworkFlyer   = "flyer"
+	ArtworkWheel   = "wheel"
+	ArtworkMarquee = "marquee"
+)
+
+// Game is a romlist entry
+type Game struct {
+	Name         string
+	Title        string
+	Emulator     string
+	CloneOf      string
+	Year         string
+	Manufacturer string
+	Category     string
+	Players      string
+	Rotation     string
+	Control      string
+	Status       string
+	DisplayCount string
+	DisplayType  string
+	AltRomname   string
+	AltTitle     string
+	Extra        string
+	Buttons      string
+}
+
+// fields returns pointers to the game's fields, in romlist order
+func (g *Game) fields() []*string {
+	return []*string{
+		&g.Name, &g.Title, &g.Emulator, &g.CloneOf, &g.Year, &g.Manufacturer,
+		&g.Category, &g.Players, &g.Rotation, &g.Control, &g.Status,
+		&g.DisplayCount, &g.DisplayType, &g.AltRomname, &g.AltTitle, &g.Extra,
+		&g.Buttons,
+	}
+}
+
+// RomlistPath returns the path of an emulator's romlist in an AttractMode
+// config folder.
+func RomlistPath(configDir, emulator string) string {
+	return filepath.Join(configDir, "romlists", emulator+".txt")
+}
+
+// ArtworkPath returns the path of a game's artwork of a type in an
+// AttractMode config folder. ext includes the dot.
+func ArtworkPath(configDir, emulator, artworkType, name, ext string) string {
+	return filepath.Join(configDir, "scraper", emulator, artworkType, name+ext)
+}
+
+// ReadRomlist reads a romlist. Comment lines are skipped, and fields after
+// the last known one are ignored.
+func ReadRomlist(path string) ([]Game, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open romlist: %w", err)
+	}
+	defer f.Close()
+
+	var games []Game
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var g Game
+		values := strings.Split(line, ";")
+		for i, field := range g.fields() {
+			if i < len(values) {
+				*field = values[i]
+			}
+		}
+		games = append(games, g)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read romlist: %w", err)
+	}
+	return games, nil
+}
+
+// fieldReplacer removes the characters a romlist field can't contain
+var fieldReplacer = strings.NewReplacer(";", ",", "\r\n", " ", "\n", " ", "\r", " ")
+
+// WriteRomlist writes a romlist with a header. Semicolons in values become
+// commas and line breaks become spaces.
+func WriteRomlist(path string, games []Game) error {
+	var b strings.Builder
+	b.WriteString(Header + "\n")
+	for _, g := range games {
+		for i, field := range g.fields() {
+			if i > 0 {
+				b.WriteString(";")
+			}
+			b.WriteString(fieldReplacer.Replace(*field))
+		}
+		b.WriteString("\n")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create romlist directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write romlist: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package attractmode
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadWriteRomlist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "romlists", "mame.txt")
+	games := []Game{
+		{Name: "sf2", Title: "Street Fighter II", Emulator: "mame", Year: "1991", Manufacturer: "Capcom", Rotation: "0", Buttons: "6"},
+		{Name: "sf2j", Title: "Street Fighter II; Japan\nRelease", Emulator: "mame", CloneOf: "sf2"},
+	}
+	if err := WriteRomlist(path, games); err != nil {
+		t.Fatalf("WriteRomlist() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Header + "\n" +
+		"sf2;Street Fighter II;mame;;1991;Capcom;;;0;;;;;;;;6\n" +
+		"sf2j;Street Fighter II, Japan Release;mame;sf2;;;;;;;;;;;;;\n"
+	if string(data) != want {
+		t.Errorf("WriteRomlist() wrote\n%s\nwant\n%s", data, want)
+	}
+
+	got, err := ReadRomlist(path)
+	if err != nil {
+		t.Fatalf("ReadRomlist() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != games[0] || got[1].CloneOf != "sf2" || got[1].Title != "Street Fighter II, Japan Release" {
+		t.Errorf("ReadRomlist() = %+v", got)
+	}
+}
+
+func TestReadRomlist_ShortLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "short.txt")
+	if err := os.WriteFile(path, []byte("# comment\r\npacman;Pac-Man;mame\r\n\r\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadRomlist(path)
+	if err != nil {
+		t.Fatalf("ReadRomlist() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != (Game{Name: "pacman", Title: "Pac-Man", Emulator: "mame"}) {
+		t.Errorf("ReadRomlist() = %+v", got)
+	}
+}
+
+func TestPaths(t *testing.T) {
+	dir := ".attract"
+	if got, want := RomlistPath(dir, "mame"), filepath.Join(dir, "romlists", "mame.txt"); got != want {
+		t.Errorf("RomlistPath() = %q, want %q", got, want)
+	}
+	if got, want := ArtworkPath(dir, "mame", ArtworkWheel, "sf2", ".png"), filepath.Join(dir, "scraper", "mame", "wheel", "sf2.png"); got != want {
+		t.Errorf("ArtworkPath() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,74 @@
+// Package batocera provides the extended gamelist.xml format read by Batocera
+// and RetroBat.
+//
+// It is a superset of the ES-DE format: each game has additional media
+// (marquee, fanart, manual, box back, wheel, ...), region and language tags,
+// ROM hashes, a RetroAchievements ID and a record of which scraper filled it
+// in. Media paths are relative to the gamelist, conventionally
+// "./images/<rom>-<type>.<ext>", "./videos/<rom>-video.mp4" and
+// "./manuals/<rom>-manual.pdf".
+//
+// Gamelist.xml specification:
+// https://github.com/batocera-linux/batocera-emulationstation/blob/master/GAMELISTS.md
+package batocera
+
+import (
+	"encoding/xml"
+
+	"github.com/sargunv/rom-tools/lib/esde"
+)
+
+// GameList represents a Batocera gamelist.xml
+type GameList struct {
+	XMLName xml.Name      `xml:"gameList"`
+	Games   []Game        `xml:"game"`
+	Folders []esde.Folder `xml:"folder"`
+}
+
+// Game represents a single game entry, with the ES-DE fields followed by the
+// Batocera extensions
+type Game struct {
+	ID     string `xml:"id,attr,omitempty"`     // Game ID in the scraper's database
+	Source string `xml:"source,attr,omitempty"` // Scraper database (e.g., "ScreenScraper")
+
+	esde.Game
+
+	Marquee   string `xml:"marquee,omitempty"`
+	Wheel     string `xml:"wheel,omitempty"`
+	Fanart    string `xml:"fanart,omitempty"`
+	TitleShot string `xml:"titleshot,omitempty"`
+	BoxBack   string `xml:"boxback,omitempty"`
+	Manual    string `xml:"manual,omitempty"`
+	Region    string `xml:"region,omitempty"`    // Region code (e.g., "us")
+	Lang      string `xml:"lang,omitempty"`      // Comma-separated language codes (e.g., "en,fr")
+	GenreID   int    `xml:"genreid,omitempty"`   // Batocera genre ID
+	MD5       string `xml:"md5,omitempty"`       // ROM MD5
+	CRC32     string `xml:"crc32,omitempty"`     // ROM CRC32
+	CheevosID int    `xml:"cheevosId,omitempty"` // RetroAchievements game ID
+
+	Scrap []Scrap `xml:"scrap,omitempty"`
+}
+
+// Scrap records when a scraper last filled in a game
+type Scrap struct {
+	Name string        `xml:"name,attr"`
+	Date esde.DateTime `xml:"date,attr"`
+}
+
+// Parse parses gamelist.xml data into a GameList
+func Parse(data []byte) (*GameList, error) {
+	var gamelist GameList
+	if err := xml.Unmarshal(data, &gamelist); err != nil {
+		return nil, err
+	}
+	return &gamelist, nil
+}
+
+// Write serializes a GameList to XML with proper formatting
+func Write(list *GameList) ([]byte, error) {
+	data, err := xml.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
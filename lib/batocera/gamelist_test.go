@@ -0,0 +1,92 @@
+package batocera
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseExtendedEntry(t *testing.T) {
+	xml := `<?xml version="1.0" encoding="UTF-8"?>
+<gameList>
+  <game id="3" source="ScreenScraper">
+    <path>./Sonic.md</path>
+    <name>Sonic the Hedgehog</name>
+    <image>./images/Sonic-image.png</image>
+    <thumbnail>./images/Sonic-thumb.png</thumbnail>
+    <releasedate>19910623T000000</releasedate>
+    <players>1</players>
+    <marquee>./images/Sonic-marquee.png</marquee>
+    <wheel>./images/Sonic-wheel.png</wheel>
+    <fanart>./images/Sonic-fanart.jpg</fanart>
+    <boxback>./images/Sonic-boxback.png</boxback>
+    <manual>./manuals/Sonic-manual.pdf</manual>
+    <region>us</region>
+    <lang>en</lang>
+    <genreid>257</genreid>
+    <md5>1bc674be034e43c96b86487ac69d9293</md5>
+    <crc32>f9394e97</crc32>
+    <cheevosId>1</cheevosId>
+    <scrap name="ScreenScraper" date="20240115T143022" />
+  </game>
+</gameList>`
+
+	list, err := Parse([]byte(xml))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(list.Games) != 1 {
+		t.Fatalf("len(Games) = %d, want 1", len(list.Games))
+	}
+
+	game := list.Games[0]
+	if game.ID != "3" || game.Source != "ScreenScraper" {
+		t.Errorf("id, source = %q, %q", game.ID, game.Source)
+	}
+	if game.Path != "./Sonic.md" || game.Name != "Sonic the Hedgehog" || game.Players != 1 {
+		t.Errorf("ES-DE fields = %+v", game.Game)
+	}
+	if game.ReleaseDate.Year() != 1991 {
+		t.Errorf("ReleaseDate = %v", game.ReleaseDate)
+	}
+	if game.Marquee != "./images/Sonic-marquee.png" || game.Wheel != "./images/Sonic-wheel.png" ||
+		game.Fanart != "./images/Sonic-fanart.jpg" || game.BoxBack != "./images/Sonic-boxback.png" ||
+		game.Manual != "./manuals/Sonic-manual.pdf" {
+		t.Errorf("media = %+v", game)
+	}
+	if game.Region != "us" || game.Lang != "en" || game.GenreID != 257 || game.CheevosID != 1 {
+		t.Errorf("region, lang, genreid, cheevosId = %q, %q, %d, %d", game.Region, game.Lang, game.GenreID, game.CheevosID)
+	}
+	if game.MD5 != "1bc674be034e43c96b86487ac69d9293" || game.CRC32 != "f9394e97" {
+		t.Errorf("hashes = %q, %q", game.MD5, game.CRC32)
+	}
+	want := time.Date(2024, 1, 15, 14, 30, 22, 0, time.UTC)
+	if len(game.Scrap) != 1 || game.Scrap[0].Name != "ScreenScraper" || !game.Scrap[0].Date.Equal(want) {
+		t.Errorf("Scrap = %+v", game.Scrap)
+	}
+}
+
+func TestRoundtrip(t *testing.T) {
+	original := `<?xml version="1.0" encoding="UTF-8"?>
+<gameList>
+  <game source="libretro">
+    <path>./a.gb</path>
+    <name>A</name>
+    <marquee>./images/a-marquee.png</marquee>
+    <crc32>00000000</crc32>
+    <scrap name="libretro" date="20240101T000000"></scrap>
+  </game>
+</gameList>`
+
+	list, err := Parse([]byte(original))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	data, err := Write(list)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != original {
+		t.Errorf("Write() =\n%s\nwant\n%s", got, original)
+	}
+}
@@ -0,0 +1,364 @@
+// Package cdi provides support for reading DiscJuggler CDI disc images.
+//
+// The API mirrors the chd package: use NewReader to open an image, then
+// access individual tracks via the Tracks slice.
+//
+// CDI images store raw track data back to back, followed by session and
+// track descriptors. The last 8 bytes of the file hold the format version
+// and the location of the descriptors.
+//
+// Format reference: cdirip (https://github.com/jkbenaim/cdirip)
+package cdi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Footer layout (last 8 bytes, little-endian):
+//
+//	Offset  Size  Description
+//	0       4     Version (0x80000004 = v2, 0x80000005 = v3, 0x80000006 = v3.5)
+//	4       4     Descriptor offset (v2/v3: from start of file, v3.5: from end of file)
+//
+// Descriptor layout (little-endian):
+//
+//	2 bytes    Session count
+//	For each session:
+//	  2 bytes  Track count (0 for an open session)
+//	  For each track: variable-size track descriptor (see parseTrack)
+//	  12 bytes Session trailer (13 bytes for v3 and later)
+const (
+	footerSize        = 8
+	maxDescriptorSize = 1024 * 1024
+
+	// rawSectorSize is the size of a raw CD sector without subchannel data.
+	rawSectorSize = 2352
+)
+
+// trackStartMark appears twice at the start of each track descriptor.
+var trackStartMark = []byte{0, 0, 0x01, 0, 0, 0, 0xFF, 0xFF, 0xFF, 0xFF}
+
+// Version identifies the DiscJuggler image format version.
+type Version uint32
+
+// Known CDI versions.
+const (
+	Version2  Version = 0x80000004
+	Version3  Version = 0x80000005
+	Version35 Version = 0x80000006
+)
+
+// TrackMode is the CD mode of a track.
+type TrackMode uint32
+
+// Track modes.
+const (
+	TrackModeAudio TrackMode = 0
+	TrackModeMode1 TrackMode = 1
+	TrackModeMode2 TrackMode = 2
+)
+
+// sectorSizes maps the descriptor sector size code to bytes per sector.
+var sectorSizes = map[uint32]int{
+	0: 2048, // Cooked MODE1 or MODE2 form 1 user data
+	1: 2336, // MODE2 without sync and header
+	2: 2352, // Raw sector
+	4: 2448, // Raw sector with 96 bytes of subchannel data
+}
+
+// Reader provides access to a CDI image's tracks.
+type Reader struct {
+	// Version is the image format version.
+	Version Version
+	// Tracks contains all tracks across all sessions, in disc order.
+	Tracks []*Track
+}
+
+// Track represents a single track in the CDI image.
+type Track struct {
+	Number     int       // Track number (1-based, counted across sessions)
+	Session    int       // Session number (1-based)
+	Mode       TrackMode // Track mode (audio, MODE1 or MODE2)
+	SectorSize int       // Stored bytes per sector: 2048, 2336, 2352 or 2448
+	StartLBA   uint32    // Logical block address of the first track sector
+	Pregap     int       // Pregap sectors stored before the track data
+	Frames     int       // Number of sectors in the track, excluding the pregap
+
+	// unexported
+	r           io.ReaderAt
+	offset      int64 // File offset of the first sector after the pregap
+	totalFrames int   // Stored sectors, including the pregap
+}
+
+// IsData reports whether the track holds data rather than audio.
+func (t *Track) IsData() bool {
+	return t.Mode != TrackModeAudio
+}
+
+// Open returns a reader for this track's sector data. Subchannel data is
+// stripped from 2448-byte sectors, so each sector is at most 2352 bytes.
+func (t *Track) Open() io.ReaderAt {
+	if t.SectorSize <= rawSectorSize {
+		return io.NewSectionReader(t.r, t.offset, t.Size())
+	}
+	return &subchannelReader{track: t}
+}
+
+// Size returns the track size in bytes as seen through Open.
+func (t *Track) Size() int64 {
+	return int64(t.Frames) * int64(min(t.SectorSize, rawSectorSize))
+}
+
+// subchannelReader reads 2448-byte sectors as 2352-byte raw sectors.
+type subchannelReader struct {
+	track *Track
+}
+
+// ReadAt implements io.ReaderAt for raw track data.
+func (s *subchannelReader) ReadAt(p []byte, off int64) (int, error) {
+	size := s.track.Size()
+	n := 0
+	for n < len(p) {
+		logicalOffset := off + int64(n)
+		if logicalOffset >= size {
+			return n, io.EOF
+		}
+
+		sector := logicalOffset / rawSectorSize
+		offsetInSector := logicalOffset % rawSectorSize
+		physicalOffset := s.track.offset + sector*int64(s.track.SectorSize) + offsetInSector
+
+		bytesToRead := min(int64(len(p)-n), rawSectorSize-offsetInSector)
+		read, err := s.track.r.ReadAt(p[n:n+int(bytesToRead)], physicalOffset)
+		n += read
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// NewReader creates a Reader reading from r, which must be an io.ReaderAt.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	if size < footerSize {
+		return nil, fmt.Errorf("file too small for CDI footer: %d bytes", size)
+	}
+
+	footer := make([]byte, footerSize)
+	if _, err := r.ReadAt(footer, size-footerSize); err != nil {
+		return nil, fmt.Errorf("failed to read CDI footer: %w", err)
+	}
+
+	version := Version(binary.LittleEndian.Uint32(footer[0:4]))
+	headerOffset := int64(binary.LittleEndian.Uint32(footer[4:8]))
+
+	switch version {
+	case Version2, Version3:
+	case Version35:
+		headerOffset = size - headerOffset
+	default:
+		return nil, fmt.Errorf("not a valid CDI image: unknown version 0x%08X", uint32(version))
+	}
+
+	descriptorSize := size - footerSize - headerOffset
+	if headerOffset < 0 || descriptorSize < 2 || descriptorSize > maxDescriptorSize {
+		return nil, fmt.Errorf("not a valid CDI image: invalid descriptor offset %d", headerOffset)
+	}
+
+	descriptor := make([]byte, descriptorSize)
+	if _, err := r.ReadAt(descriptor, headerOffset); err != nil {
+		return nil, fmt.Errorf("failed to read CDI descriptors: %w", err)
+	}
+
+	tracks, err := parseSessions(descriptor, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Track data is stored back to back from the start of the file
+	var position int64
+	for _, track := range tracks {
+		track.r = r
+		track.offset = position + int64(track.Pregap)*int64(track.SectorSize)
+		position += int64(track.totalFrames) * int64(track.SectorSize)
+	}
+	if position > headerOffset {
+		return nil, fmt.Errorf("CDI track data extends beyond descriptors")
+	}
+
+	return &Reader{Version: version, Tracks: tracks}, nil
+}
+
+// parseSessions parses the session and track descriptors.
+func parseSessions(data []byte, version Version) ([]*Track, error) {
+	d := &descriptorReader{data: data}
+
+	sessionCount := int(d.uint16())
+	var tracks []*Track
+	for session := 1; session <= sessionCount; session++ {
+		trackCount := int(d.uint16())
+		if d.err != nil {
+			return nil, d.err
+		}
+		if trackCount == 0 {
+			// Open session, no trailer
+			continue
+		}
+
+		for range trackCount {
+			track, err := parseTrack(d, version)
+			if err != nil {
+				return nil, fmt.Errorf("session %d track %d: %w", session, len(tracks)+1, err)
+			}
+			track.Number = len(tracks) + 1
+			track.Session = session
+			tracks = append(tracks, track)
+		}
+
+		// Session trailer
+		d.skip(12)
+		if version != Version2 {
+			d.skip(1)
+		}
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+	if len(tracks) == 0 {
+		return nil, fmt.Errorf("CDI image has no tracks")
+	}
+	return tracks, nil
+}
+
+// parseTrack parses a single track descriptor.
+//
+// Track descriptor layout (little-endian, fields not listed are skipped):
+//
+//	Size  Description
+//	4     Extra data flag (non-zero: 8 bytes of extra data follow)
+//	10    Track start mark
+//	10    Track start mark
+//	4     Unknown
+//	1     Filename length (n)
+//	n     Filename of the original image
+//	19    Unknown
+//	4     DiscJuggler 4 flag (0x80000000: 8 bytes of extra data follow)
+//	2     Unknown
+//	4     Pregap length (sectors)
+//	4     Track length (sectors)
+//	6     Unknown
+//	4     Track mode (0 = audio, 1 = MODE1, 2 = MODE2)
+//	12    Unknown
+//	4     Start LBA
+//	4     Total length (pregap + track length)
+//	16    Unknown
+//	4     Sector size code (0 = 2048, 1 = 2336, 2 = 2352, 4 = 2448)
+//	29    Unknown
+//	v3 and later:
+//	5     Unknown
+//	4     Extra data flag (0xFFFFFFFF: 78 bytes of extra data follow)
+func parseTrack(d *descriptorReader, version Version) (*Track, error) {
+	if d.uint32() != 0 {
+		d.skip(8)
+	}
+	for range 2 {
+		if mark := d.bytes(len(trackStartMark)); d.err == nil && !bytes.Equal(mark, trackStartMark) {
+			return nil, fmt.Errorf("missing track start mark")
+		}
+	}
+	d.skip(4)
+	d.skip(int(d.uint8()))
+	d.skip(19)
+	if d.uint32() == 0x80000000 {
+		d.skip(8)
+	}
+	d.skip(2)
+	pregap := d.uint32()
+	length := d.uint32()
+	d.skip(6)
+	mode := TrackMode(d.uint32())
+	d.skip(12)
+	startLBA := d.uint32()
+	totalLength := d.uint32()
+	d.skip(16)
+	sectorSizeCode := d.uint32()
+	d.skip(29)
+	if version != Version2 {
+		d.skip(5)
+		if d.uint32() == 0xFFFFFFFF {
+			d.skip(78)
+		}
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	sectorSize, ok := sectorSizes[sectorSizeCode]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sector size code %d", sectorSizeCode)
+	}
+	if totalLength < pregap+length {
+		return nil, fmt.Errorf("invalid track length %d (pregap %d, track %d)", totalLength, pregap, length)
+	}
+	if mode > TrackModeMode2 {
+		return nil, fmt.Errorf("unsupported track mode %d", mode)
+	}
+
+	return &Track{
+		Mode:        mode,
+		SectorSize:  sectorSize,
+		StartLBA:    startLBA,
+		Pregap:      int(pregap),
+		Frames:      int(length),
+		totalFrames: int(totalLength),
+	}, nil
+}
+
+// descriptorReader reads little-endian values from the descriptor block.
+// The first out-of-bounds read sets err; later reads return zero values.
+type descriptorReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (d *descriptorReader) bytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || d.pos+n > len(d.data) {
+		d.err = fmt.Errorf("CDI descriptor truncated at offset %d", d.pos)
+		return nil
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b
+}
+
+func (d *descriptorReader) skip(n int) { d.bytes(n) }
+
+func (d *descriptorReader) uint8() uint8 {
+	if b := d.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (d *descriptorReader) uint16() uint16 {
+	if b := d.bytes(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (d *descriptorReader) uint32() uint32 {
+	if b := d.bytes(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
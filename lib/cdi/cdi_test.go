@@ -0,0 +1,163 @@
+package cdi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// testTrack describes a track for makeTestCDI. Each sector is filled with
+// its track number so reads can be checked.
+type testTrack struct {
+	mode           TrackMode
+	sectorSizeCode uint32
+	pregap         int
+	frames         int
+	startLBA       uint32
+}
+
+// makeTestCDI builds a CDI image with one session per track.
+func makeTestCDI(version Version, tracks []testTrack) []byte {
+	var data, desc bytes.Buffer
+	le := func(v any) { binary.Write(&desc, binary.LittleEndian, v) }
+
+	le(uint16(len(tracks) + 1)) // Trailing open session
+	for i, tt := range tracks {
+		sectorSize := sectorSizes[tt.sectorSizeCode]
+		data.Write(bytes.Repeat([]byte{0xEE}, tt.pregap*sectorSize))
+		data.Write(bytes.Repeat([]byte{byte(i + 1)}, tt.frames*sectorSize))
+
+		le(uint16(1))
+		le(uint32(0))
+		desc.Write(trackStartMark)
+		desc.Write(trackStartMark)
+		desc.Write(make([]byte, 4))
+		filename := "image.cdi"
+		le(uint8(len(filename)))
+		desc.WriteString(filename)
+		desc.Write(make([]byte, 19))
+		le(uint32(0))
+		desc.Write(make([]byte, 2))
+		le(uint32(tt.pregap))
+		le(uint32(tt.frames))
+		desc.Write(make([]byte, 6))
+		le(uint32(tt.mode))
+		desc.Write(make([]byte, 12))
+		le(tt.startLBA)
+		le(uint32(tt.pregap + tt.frames))
+		desc.Write(make([]byte, 16))
+		le(tt.sectorSizeCode)
+		desc.Write(make([]byte, 29))
+		if version != Version2 {
+			desc.Write(make([]byte, 5))
+			le(uint32(0))
+		}
+
+		desc.Write(make([]byte, 12))
+		if version != Version2 {
+			desc.Write(make([]byte, 1))
+		}
+	}
+	le(uint16(0))
+
+	headerOffset := uint32(data.Len())
+	data.Write(desc.Bytes())
+	if version == Version35 {
+		headerOffset = uint32(data.Len()+footerSize) - headerOffset
+	}
+	binary.Write(&data, binary.LittleEndian, uint32(version))
+	binary.Write(&data, binary.LittleEndian, headerOffset)
+	return data.Bytes()
+}
+
+func TestNewReader(t *testing.T) {
+	for _, version := range []Version{Version2, Version3, Version35} {
+		image := makeTestCDI(version, []testTrack{
+			{mode: TrackModeAudio, sectorSizeCode: 2, pregap: 150, frames: 10},
+			{mode: TrackModeMode2, sectorSizeCode: 1, pregap: 150, frames: 20, startLBA: 11702},
+		})
+
+		reader, err := NewReader(bytes.NewReader(image), int64(len(image)))
+		if err != nil {
+			t.Fatalf("NewReader(0x%08X) error = %v", uint32(version), err)
+		}
+
+		if reader.Version != version {
+			t.Errorf("Version = 0x%08X, want 0x%08X", uint32(reader.Version), uint32(version))
+		}
+		if len(reader.Tracks) != 2 {
+			t.Fatalf("len(Tracks) = %d, want 2", len(reader.Tracks))
+		}
+
+		audio, data := reader.Tracks[0], reader.Tracks[1]
+		if audio.Number != 1 || audio.Session != 1 || audio.IsData() {
+			t.Errorf("track 1 = number %d, session %d, data %v; want 1, 1, false", audio.Number, audio.Session, audio.IsData())
+		}
+		if data.Number != 2 || data.Session != 2 || !data.IsData() {
+			t.Errorf("track 2 = number %d, session %d, data %v; want 2, 2, true", data.Number, data.Session, data.IsData())
+		}
+		if data.SectorSize != 2336 || data.StartLBA != 11702 || data.Pregap != 150 || data.Frames != 20 {
+			t.Errorf("track 2 = sector size %d, LBA %d, pregap %d, frames %d; want 2336, 11702, 150, 20",
+				data.SectorSize, data.StartLBA, data.Pregap, data.Frames)
+		}
+		if data.Size() != 20*2336 {
+			t.Errorf("track 2 Size() = %d, want %d", data.Size(), 20*2336)
+		}
+
+		// Track data must skip the pregap and start with the track's own sectors
+		buf := make([]byte, 4)
+		if _, err := data.Open().ReadAt(buf, 0); err != nil {
+			t.Fatalf("ReadAt() error = %v", err)
+		}
+		if !bytes.Equal(buf, []byte{2, 2, 2, 2}) {
+			t.Errorf("track 2 data = %v, want [2 2 2 2]", buf)
+		}
+	}
+}
+
+func TestTrackOpen_StripsSubchannel(t *testing.T) {
+	image := makeTestCDI(Version3, []testTrack{
+		{mode: TrackModeMode1, sectorSizeCode: 4, frames: 3},
+	})
+	// Mark the start of each sector's subchannel data
+	for i := range 3 {
+		image[i*2448+rawSectorSize] = 0xFF
+	}
+
+	reader, err := NewReader(bytes.NewReader(image), int64(len(image)))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	track := reader.Tracks[0]
+	if track.Size() != 3*rawSectorSize {
+		t.Errorf("Size() = %d, want %d", track.Size(), 3*rawSectorSize)
+	}
+
+	data := make([]byte, track.Size())
+	if _, err := track.Open().ReadAt(data, 0); err != nil {
+		t.Fatalf("ReadAt() error = %v", err)
+	}
+	if bytes.IndexByte(data, 0xFF) != -1 {
+		t.Error("track data contains subchannel bytes")
+	}
+}
+
+func TestNewReader_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"too small", []byte{0x04, 0x00}},
+		{"unknown version", make([]byte, 64)},
+		{"bad offset", append(make([]byte, 56), 0x05, 0x00, 0x00, 0x80, 0xFF, 0xFF, 0x00, 0x00)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewReader(bytes.NewReader(tt.data), int64(len(tt.data))); err == nil {
+				t.Error("NewReader() expected error")
+			}
+		})
+	}
+}
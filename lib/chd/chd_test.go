@@ -1,6 +1,8 @@
 package chd
 
 import (
+	"bytes"
+	"encoding/binary"
 	"os"
 	"testing"
 )
@@ -141,3 +143,52 @@ func TestTrackSize(t *testing.T) {
 		t.Errorf("Track.Size() = %v, want %v", got, want)
 	}
 }
+
+func TestParseTrackMetadata_FrameOffsets(t *testing.T) {
+	entries := []struct {
+		tag  MetadataTag
+		data string
+	}{
+		{TagGDROM, "TRACK:1 TYPE:MODE1_RAW SUBTYPE:NONE FRAMES:302 PAD:0 PREGAP:0 PGTYPE:MODE1 PGSUB:NONE POSTGAP:0"},
+		{TagGDROM, "TRACK:2 TYPE:AUDIO SUBTYPE:NONE FRAMES:750 PAD:0 PREGAP:150 PGTYPE:VAUDIO PGSUB:NONE POSTGAP:0"},
+		{TagGDROM, "TRACK:3 TYPE:MODE1_RAW SUBTYPE:NONE FRAMES:1000 PAD:0 PREGAP:150 PGTYPE:MODE1 PGSUB:NONE POSTGAP:0"},
+	}
+
+	// Header with the metadata offset at byte 48, followed by linked metadata entries
+	data := make([]byte, headerSize)
+	binary.BigEndian.PutUint64(data[48:], headerSize)
+	for i, e := range entries {
+		entry := make([]byte, 16)
+		copy(entry[0:4], e.tag)
+		binary.BigEndian.PutUint32(entry[4:8], uint32(len(e.data)))
+		if i < len(entries)-1 {
+			binary.BigEndian.PutUint64(entry[8:16], uint64(len(data)+16+len(e.data)))
+		}
+		data = append(data, entry...)
+		data = append(data, e.data...)
+	}
+
+	reader := &Reader{}
+	tracks, err := parseTrackMetadata(bytes.NewReader(data), nil, reader)
+	if err != nil {
+		t.Fatalf("parseTrackMetadata() error = %v", err)
+	}
+	if !reader.IsGDROM() {
+		t.Error("IsGDROM() = false, want true")
+	}
+	if len(tracks) != 3 {
+		t.Fatalf("len(tracks) = %d, want 3", len(tracks))
+	}
+
+	// Tracks are padded to 4 frames; track 2 stores its pregap, track 3 does not
+	wantStart := []int64{0, 304 + 150, 304 + 752}
+	wantSize := []int64{302 * rawSectorSize, 600 * rawSectorSize, 1000 * rawSectorSize}
+	for i, track := range tracks {
+		if track.startFrame != wantStart[i] {
+			t.Errorf("track %d startFrame = %d, want %d", track.Number, track.startFrame, wantStart[i])
+		}
+		if track.Size() != wantSize[i] {
+			t.Errorf("track %d Size() = %d, want %d", track.Number, track.Size(), wantSize[i])
+		}
+	}
+}
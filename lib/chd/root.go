@@ -84,6 +84,7 @@ type Reader struct {
 
 	file      io.ReaderAt
 	header    *Header
+	gdrom     bool
 	hunkMap   *chdMap
 	hunkCache map[uint32][]byte
 	cacheMu   sync.RWMutex
@@ -127,6 +128,12 @@ func (r *Reader) Header() *Header {
 	return r.header
 }
 
+// IsGDROM reports whether the CHD holds a GD-ROM (Dreamcast) disc.
+// The game data of a GD-ROM starts at GDROMHighDensityTrack.
+func (r *Reader) IsGDROM() bool {
+	return r.gdrom
+}
+
 // Size returns the logical (uncompressed) size in bytes.
 func (r *Reader) Size() int64 {
 	return int64(r.header.LogicalBytes)
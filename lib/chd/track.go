@@ -13,6 +13,14 @@ import (
 // rawSectorSize is the size of a raw CD sector (2352 bytes).
 const rawSectorSize = 2352
 
+// trackPadding is the frame alignment of each track in CD and GD-ROM CHDs.
+// Each track is padded with extra frames up to a multiple of this value.
+const trackPadding = 4
+
+// GDROMHighDensityTrack is the first track of the GD-ROM high-density area,
+// which holds the game data on Dreamcast discs.
+const GDROMHighDensityTrack = 3
+
 // Track represents a single track in the CHD (like zip.File).
 type Track struct {
 	Number int    // Track number (1-based)
@@ -21,8 +29,9 @@ type Track struct {
 	Type   string // Raw type string: "AUDIO", "MODE1_RAW", "MODE2_RAW", etc.
 
 	// unexported
-	reader     *Reader
-	startFrame int64
+	reader       *Reader
+	startFrame   int64 // CHD frame where the track data (after any stored pregap) begins
+	pregapStored bool  // Pregap data is included in Frames (PGTYPE starts with "V")
 }
 
 // Open returns a reader for this track's raw sector data (2352 bytes/sector).
@@ -30,13 +39,21 @@ func (t *Track) Open() io.ReaderAt {
 	return &trackReader{
 		reader:     t.reader,
 		track:      t,
-		numSectors: int64(t.Frames),
+		numSectors: int64(t.dataFrames()),
 	}
 }
 
-// Size returns the track size in bytes (Frames * 2352).
+// Size returns the track size in bytes (data frames * 2352).
 func (t *Track) Size() int64 {
-	return int64(t.Frames) * rawSectorSize
+	return int64(t.dataFrames()) * rawSectorSize
+}
+
+// dataFrames returns the number of frames of track data, excluding any stored pregap.
+func (t *Track) dataFrames() int {
+	if t.pregapStored {
+		return max(t.Frames-t.Pregap, 0)
+	}
+	return t.Frames
 }
 
 // trackReader provides access to a track's raw sector data within a CHD file.
@@ -63,8 +80,8 @@ func (tr *trackReader) ReadAt(p []byte, off int64) (int, error) {
 			return 0, io.EOF
 		}
 
-		// Calculate actual sector number in the CHD (skip pregap)
-		actualSector := uint64(tr.track.startFrame + int64(tr.track.Pregap) + sector)
+		// Calculate actual sector number in the CHD
+		actualSector := uint64(tr.track.startFrame + sector)
 
 		// Read the physical sector from CHD
 		sectorData, err := tr.reader.readSector(actualSector)
@@ -147,6 +164,10 @@ func parseTrackMetadata(r io.ReaderAt, header *Header, reader *Reader) ([]*Track
 			}
 		}
 
+		if tag == TagGDROM || tag == TagGDROMOld {
+			reader.gdrom = true
+		}
+
 		// Parse track metadata (CHTR, CHT2, CHGD all use same format)
 		if tag == TagCDROM || tag == TagCDROM2 || tag == TagGDROM {
 			if track, err := parseTrackMetadataEntry(data); err == nil {
@@ -158,11 +179,17 @@ func parseTrackMetadata(r io.ReaderAt, header *Header, reader *Reader) ([]*Track
 		offset = nextOffset
 	}
 
-	// Calculate start frames for each track
+	// Calculate start frames for each track. Frames includes the pregap only
+	// when its data is stored in the CHD, and each track is padded to a
+	// multiple of trackPadding frames.
 	var currentFrame int64
 	for _, track := range tracks {
 		track.startFrame = currentFrame
-		currentFrame += int64(track.Pregap + track.Frames)
+		if track.pregapStored {
+			track.startFrame += int64(track.Pregap)
+		}
+		padded := (track.Frames + trackPadding - 1) / trackPadding * trackPadding
+		currentFrame += int64(padded)
 	}
 
 	return tracks, nil
@@ -188,6 +215,9 @@ func parseTrackMetadataEntry(data []byte) (*Track, error) {
 	if v, ok := fields["PREGAP"]; ok {
 		track.Pregap, _ = strconv.Atoi(v)
 	}
+	if v, ok := fields["PGTYPE"]; ok {
+		track.pregapStored = strings.HasPrefix(v, "V")
+	}
 
 	if track.Number == 0 {
 		return nil, fmt.Errorf("invalid track metadata")
@@ -1,5 +1,7 @@
 package core
 
+import "image"
+
 // GameInfo is implemented by all platform-specific ROM info structs.
 // It provides common identification fields while allowing type assertion
 // for platform-specific details.
@@ -9,3 +11,9 @@ type GameInfo interface {
 	GameSerial() string // May be empty if format doesn't have serial
 	GameRegions() []Region
 }
+
+// IconProvider is optionally implemented by GameInfo types whose format embeds
+// an icon (e.g., NDS banners, 3DS SMDH).
+type IconProvider interface {
+	GameIcon() image.Image // May be nil if the icon is unavailable
+}
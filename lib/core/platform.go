@@ -6,21 +6,25 @@ type Platform string
 
 const (
 	PlatformNES     Platform = "famicom"
+	PlatformFDS     Platform = "famicomdisksystem"
 	PlatformSNES    Platform = "superfamicom"
 	PlatformN64     Platform = "nintendo64"
+	PlatformN64DD   Platform = "nintendo64dd"
 	PlatformGC      Platform = "gamecube"
 	PlatformWii     Platform = "wii"
 	PlatformWiiU    Platform = "wiiu"
 	PlatformSwitch  Platform = "switch"
 	PlatformSwitch2 Platform = "switch2"
 
-	PlatformGB     Platform = "gameboy"
-	PlatformGBC    Platform = "gameboycolor"
-	PlatformGBA    Platform = "gameboyadvance"
-	PlatformNDS    Platform = "ds"
-	PlatformDSi    Platform = "dsi"
-	Platform3DS    Platform = "3ds"
-	PlatformNew3DS Platform = "new3ds"
+	PlatformGB          Platform = "gameboy"
+	PlatformGBC         Platform = "gameboycolor"
+	PlatformGBA         Platform = "gameboyadvance"
+	PlatformVirtualBoy  Platform = "virtualboy"
+	PlatformPokemonMini Platform = "pokemonmini"
+	PlatformNDS         Platform = "ds"
+	PlatformDSi         Platform = "dsi"
+	Platform3DS         Platform = "3ds"
+	PlatformNew3DS      Platform = "new3ds"
 
 	PlatformPS1 Platform = "playstation"
 	PlatformPS2 Platform = "playstation2"
@@ -41,8 +45,34 @@ const (
 
 	PlatformGameGear Platform = "gamegear"
 
+	PlatformNGP  Platform = "neogeopocket"
+	PlatformNGPC Platform = "neogeopocketcolor"
+
+	PlatformWonderSwan      Platform = "wonderswan"
+	PlatformWonderSwanColor Platform = "wonderswancolor"
+
 	PlatformXbox       Platform = "xbox"
 	PlatformXbox360    Platform = "xbox360"
 	PlatformXboxOne    Platform = "xboxone"
 	PlatformXboxSeries Platform = "xboxseries"
+
+	PlatformAtari2600   Platform = "atari2600"
+	PlatformAtari5200   Platform = "atari5200"
+	PlatformAtari7800   Platform = "atari7800"
+	PlatformAtariLynx   Platform = "atarilynx"
+	PlatformAtariJaguar Platform = "atarijaguar"
+
+	PlatformPCEngine   Platform = "pcengine"
+	PlatformSuperGrafx Platform = "supergrafx"
+
+	PlatformSG1000        Platform = "sg1000"
+	PlatformColecoVision  Platform = "colecovision"
+	PlatformIntellivision Platform = "intellivision"
+	PlatformVectrex       Platform = "vectrex"
+	PlatformChannelF      Platform = "channelf"
+	PlatformSupervision   Platform = "supervision"
+	PlatformMSX           Platform = "msx"
+	PlatformMSX2          Platform = "msx2"
+
+	PlatformArcade Platform = "arcade"
 )
@@ -1,5 +1,7 @@
 package core
 
+import "strings"
+
 // Region represents a geographic region for ROM/asset matching.
 // Regions form a hierarchy (e.g., Germany -> Europe -> World) used for
 // fallback matching when exact region assets aren't available.
@@ -166,3 +168,37 @@ func (r Region) IsAncestorOf(other Region) (bool, int) {
 func (r Region) IsDescendantOf(other Region) (bool, int) {
 	return other.IsAncestorOf(r)
 }
+
+// regionAliases maps other common names of regions, lowercased, to regions.
+var regionAliases = map[string]Region{
+	"united kingdom":       RegionUK,
+	"great britain":        RegionUK,
+	"united states":        RegionUSA,
+	"north america":        RegionAmericas,
+	"south korea":          RegionKorea,
+	"czech republic":       RegionCzechia,
+	"united arab emirates": RegionUAE,
+	"scandinavia":          RegionEurope,
+}
+
+// ParseRegion returns the region with the given name, ignoring case, like
+// "USA" or "europe". Common alternative names like "United Kingdom" are
+// accepted too. Returns RegionUnknown for unrecognized names.
+func ParseRegion(name string) Region {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return RegionUnknown
+	}
+	if r, ok := regionAliases[name]; ok {
+		return r
+	}
+	if name == strings.ToLower(string(RegionWorld)) {
+		return RegionWorld
+	}
+	for r := range regionParents {
+		if name == strings.ToLower(string(r)) {
+			return r
+		}
+	}
+	return RegionUnknown
+}
@@ -46,6 +46,28 @@ func (d *DateTime) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error
 	return nil
 }
 
+// MarshalXMLAttr formats the DateTime as YYYYMMDDTHHMMSS in an attribute
+func (d DateTime) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if d.IsZero() {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: d.Format(DateTimeFormat)}, nil
+}
+
+// UnmarshalXMLAttr parses a YYYYMMDDTHHMMSS attribute into DateTime
+func (d *DateTime) UnmarshalXMLAttr(attr xml.Attr) error {
+	if attr.Value == "" {
+		d.Time = time.Time{}
+		return nil
+	}
+	t, err := time.Parse(DateTimeFormat, attr.Value)
+	if err != nil {
+		return err
+	}
+	d.Time = t
+	return nil
+}
+
 // GameList represents an ES-DE gamelist.xml
 type GameList struct {
 	XMLName xml.Name `xml:"gameList"`
@@ -0,0 +1,232 @@
+// Package hasheous is a client for the Hasheous ROM hash lookup API.
+//
+// Hasheous matches ROM hashes against DAT files (No-Intro, Redump, TOSEC,
+// MAME and others) and links the matched game to metadata from IGDB,
+// TheGamesDB, RetroAchievements and similar services. The API is public and
+// needs no credentials. See https://hasheous.org/swagger for the full API.
+package hasheous
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBaseURL is the base URL of the public Hasheous service.
+const DefaultBaseURL = "https://hasheous.org"
+
+var (
+	// ErrNotFound is returned when no game matches the request.
+	ErrNotFound = errors.New("hasheous: not found")
+
+	// ErrRateLimited is returned when the service responds with HTTP 429.
+	ErrRateLimited = errors.New("hasheous: rate limited")
+)
+
+// Client is a Hasheous API client.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL overrides the service URL (e.g., for a self-hosted instance).
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with requests.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// NewClient creates a client for the public Hasheous service.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		baseURL:    DefaultBaseURL,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Hashes are the ROM hashes sent to a lookup. At least one must be set.
+type Hashes struct {
+	MD5  string `json:"md5,omitempty"`
+	SHA1 string `json:"sha1,omitempty"`
+	CRC  string `json:"crc,omitempty"`
+}
+
+// Game is a game matched by a lookup.
+type Game struct {
+	ID         int64       `json:"id"`
+	Name       string      `json:"name"`
+	Platform   *Reference  `json:"platform,omitempty"`
+	Publisher  *Reference  `json:"publisher,omitempty"`
+	Metadata   []Metadata  `json:"metadata,omitempty"`
+	Attributes []Attribute `json:"attributes,omitempty"`
+	Signatures []Signature `json:"signatures,omitempty"`
+}
+
+// Reference is a named object such as a platform or publisher.
+type Reference struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Metadata links a game to an entry in another metadata service.
+type Metadata struct {
+	ID     string `json:"id"`     // ID in the other service
+	Source string `json:"source"` // Service name (e.g., "IGDB", "TheGamesDB")
+	Status string `json:"status"` // Match status (e.g., "Mapped", "NotMapped")
+	Link   string `json:"link,omitempty"`
+}
+
+// Attribute is an extra value attached to a game, such as an image or a
+// description.
+type Attribute struct {
+	Type  string          `json:"attributeType"` // e.g., "ImageId", "LongString"
+	Name  string          `json:"attributeName"` // e.g., "Logo", "Description"
+	Value json.RawMessage `json:"value"`
+}
+
+// Text returns the attribute value as a string. Non-string values are
+// returned as raw JSON.
+func (a Attribute) Text() string {
+	var s string
+	if err := json.Unmarshal(a.Value, &s); err == nil {
+		return s
+	}
+	return string(a.Value)
+}
+
+// Signature is a DAT entry matched by a lookup.
+type Signature struct {
+	Game struct {
+		Name      string `json:"name"`
+		Year      string `json:"year,omitempty"`
+		Publisher string `json:"publisher,omitempty"`
+		Country   string `json:"country,omitempty"`
+		Language  string `json:"language,omitempty"`
+	} `json:"game"`
+	ROM struct {
+		Name string `json:"name"`
+		Size int64  `json:"size"`
+	} `json:"rom"`
+}
+
+// Attribute types
+const (
+	AttributeImageID = "ImageId"
+)
+
+// Images returns the image IDs of a game by attribute name (e.g., "Logo").
+func (g *Game) Images() map[string]string {
+	images := make(map[string]string)
+	for _, a := range g.Attributes {
+		if a.Type == AttributeImageID {
+			if id := a.Text(); id != "" {
+				images[a.Name] = id
+			}
+		}
+	}
+	return images
+}
+
+// LookupByHash finds a game by ROM hashes.
+// Returns ErrNotFound if no game matches.
+func (c *Client) LookupByHash(ctx context.Context, hashes Hashes) (*Game, error) {
+	if hashes == (Hashes{}) {
+		return nil, fmt.Errorf("hasheous: no hashes to look up")
+	}
+
+	body, err := json.Marshal(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/Lookup/ByHash", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	data, _, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var game Game
+	if err := json.Unmarshal(data, &game); err != nil {
+		return nil, fmt.Errorf("hasheous: failed to decode lookup response: %w", err)
+	}
+	return &game, nil
+}
+
+// DownloadImage downloads an image by ID.
+// Returns the image data and its content type.
+func (c *Client) DownloadImage(ctx context.Context, id string) ([]byte, string, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/images/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	return c.do(req)
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	return req, nil
+}
+
+// do sends a request and returns the response body and content type.
+func (c *Client) do(req *http.Request) ([]byte, string, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, "", ErrNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, "", ErrRateLimited
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, "", fmt.Errorf("hasheous: HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
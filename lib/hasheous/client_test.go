@@ -0,0 +1,148 @@
+package hasheous
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newMockServer creates an httptest.Server that answers lookups for a single
+// SHA1 and serves a single image.
+func newMockServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	fixture, err := os.ReadFile(filepath.Join("testdata", "lookup.json"))
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/Lookup/ByHash", func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		if ua := r.Header.Get("User-Agent"); ua != "rom-tools-test" {
+			t.Errorf("User-Agent = %q, want rom-tools-test", ua)
+		}
+
+		var hashes Hashes
+		if err := json.NewDecoder(r.Body).Decode(&hashes); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		switch hashes.SHA1 {
+		case "69e102855d4389c3fd1a8f3dc7d193f8eee5fe5b":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(fixture)
+		case "ratelimited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	mux.HandleFunc("GET /api/v1/images/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "a1b2c3d4" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("\x89PNG\r\n\x1a\n"))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	server := newMockServer(t)
+	return NewClient(WithBaseURL(server.URL+"/"), WithHTTPClient(server.Client()), WithUserAgent("rom-tools-test"))
+}
+
+func TestLookupByHash(t *testing.T) {
+	client := newTestClient(t)
+
+	game, err := client.LookupByHash(context.Background(), Hashes{
+		SHA1: "69e102855d4389c3fd1a8f3dc7d193f8eee5fe5b",
+		CRC:  "f9394e97",
+	})
+	if err != nil {
+		t.Fatalf("LookupByHash() error = %v", err)
+	}
+
+	if game.ID != 8162 || game.Name != "Sonic The Hedgehog" {
+		t.Errorf("game = %d %q, want 8162 %q", game.ID, game.Name, "Sonic The Hedgehog")
+	}
+	if game.Platform == nil || game.Platform.Name != "Sega Mega Drive/Genesis" {
+		t.Errorf("Platform = %+v", game.Platform)
+	}
+	if game.Publisher == nil || game.Publisher.Name != "Sega" {
+		t.Errorf("Publisher = %+v", game.Publisher)
+	}
+	if len(game.Metadata) != 2 || game.Metadata[0].Source != "IGDB" || game.Metadata[0].Status != "Mapped" {
+		t.Errorf("Metadata = %+v", game.Metadata)
+	}
+	if len(game.Signatures) != 1 || game.Signatures[0].Game.Year != "1991" || game.Signatures[0].ROM.Size != 524288 {
+		t.Errorf("Signatures = %+v", game.Signatures)
+	}
+
+	images := game.Images()
+	if len(images) != 1 || images["Logo"] != "a1b2c3d4" {
+		t.Errorf("Images() = %v, want map[Logo:a1b2c3d4]", images)
+	}
+	if desc := game.Attributes[1].Text(); desc != "Sonic races through Green Hill Zone." {
+		t.Errorf("Description = %q", desc)
+	}
+}
+
+func TestLookupByHash_Errors(t *testing.T) {
+	client := newTestClient(t)
+
+	tests := []struct {
+		name    string
+		hashes  Hashes
+		wantErr error
+	}{
+		{"not found", Hashes{SHA1: "0000000000000000000000000000000000000000"}, ErrNotFound},
+		{"rate limited", Hashes{SHA1: "ratelimited"}, ErrRateLimited},
+		{"server error", Hashes{SHA1: "broken"}, nil},
+		{"no hashes", Hashes{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.LookupByHash(context.Background(), tt.hashes)
+			if err == nil {
+				t.Fatal("LookupByHash() error = nil, want error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("LookupByHash() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDownloadImage(t *testing.T) {
+	client := newTestClient(t)
+
+	data, contentType, err := client.DownloadImage(context.Background(), "a1b2c3d4")
+	if err != nil {
+		t.Fatalf("DownloadImage() error = %v", err)
+	}
+	if string(data) != "\x89PNG\r\n\x1a\n" {
+		t.Errorf("data = %q", data)
+	}
+	if contentType != "image/png" {
+		t.Errorf("content type = %q, want image/png", contentType)
+	}
+
+	if _, _, err := client.DownloadImage(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DownloadImage(missing) error = %v, want %v", err, ErrNotFound)
+	}
+}
@@ -3,7 +3,9 @@ package identify
 import (
 	"bytes"
 	"io"
+	"slices"
 
+	"github.com/sargunv/rom-tools/lib/cdi"
 	"github.com/sargunv/rom-tools/lib/chd"
 	"github.com/sargunv/rom-tools/lib/core"
 	"github.com/sargunv/rom-tools/lib/iso9660"
@@ -14,7 +16,7 @@ import (
 	"github.com/sargunv/rom-tools/lib/roms/sega/saturn"
 )
 
-func identifyCHD(r io.ReaderAt, size int64) (core.GameInfo, core.Hashes, error) {
+func identifyCHD(r io.ReaderAt, size int64, opts Options) (core.GameInfo, core.Hashes, error) {
 	reader, err := chd.NewReader(r, size)
 	if err != nil {
 		return nil, nil, err
@@ -31,9 +33,14 @@ func identifyCHD(r io.ReaderAt, size int64) (core.GameInfo, core.Hashes, error)
 	// Dreamcast) use custom headers rather than ISO9660. Failure to parse
 	// just means we return CHD hashes without game metadata, which is fine
 	// since CHD hashes are the primary identifier for DAT matching.
+	// GD-ROM low-density tracks only hold a warning, so skip to the
+	// high-density area.
 	for _, track := range reader.Tracks {
+		if reader.IsGDROM() && track.Number < chd.GDROMHighDensityTrack {
+			continue
+		}
 		if track.Type != "AUDIO" {
-			content, _, _ := identifyISO9660(track.Open(), track.Size())
+			content, _, _ := identifyISO9660(track.Open(), track.Size(), opts)
 			if content != nil {
 				return content, hashes, nil
 			}
@@ -42,11 +49,32 @@ func identifyCHD(r io.ReaderAt, size int64) (core.GameInfo, core.Hashes, error)
 	}
 
 	// Try raw CHD access (for hard disk images, etc.)
-	content, _, _ := identifyISO9660(reader, reader.Size())
+	content, _, _ := identifyISO9660(reader, reader.Size(), opts)
 	return content, hashes, nil
 }
 
-func identifyISO9660(r io.ReaderAt, size int64) (core.GameInfo, core.Hashes, error) {
+// identifyCDI identifies a DiscJuggler CDI image.
+// Dreamcast CDIs keep the game in the data track of the last session, so data
+// tracks are tried from last to first.
+func identifyCDI(r io.ReaderAt, size int64, opts Options) (core.GameInfo, core.Hashes, error) {
+	reader, err := cdi.NewReader(r, size)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, track := range slices.Backward(reader.Tracks) {
+		if !track.IsData() {
+			continue
+		}
+		if content, _, _ := identifyISO9660(track.Open(), track.Size(), opts); content != nil {
+			return content, nil, nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
+func identifyISO9660(r io.ReaderAt, size int64, _ Options) (core.GameInfo, core.Hashes, error) {
 	reader, err := iso9660.NewReader(r, size)
 	if err != nil {
 		return nil, nil, err
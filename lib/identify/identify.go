@@ -12,6 +12,9 @@ import (
 	"github.com/sargunv/rom-tools/internal/container/zip"
 	"github.com/sargunv/rom-tools/internal/util"
 	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/mame"
+	"github.com/sargunv/rom-tools/lib/rdb"
+	"github.com/sargunv/rom-tools/lib/roms/generic"
 )
 
 // Identify identifies a ROM file, ZIP archive, or folder.
@@ -88,10 +91,34 @@ func identifyContainer(path string, c util.FileContainer, opts Options) (*Result
 		items = append(items, *item)
 	}
 
-	return &Result{
+	result := &Result{
 		Path:  path,
 		Items: items,
-	}, nil
+	}
+	if opts.MAME != nil {
+		result.Arcade = matchArcade(path, items, opts.MAME)
+	}
+	return result, nil
+}
+
+// matchArcade identifies a container as a MAME ROM set from the CRC32s of its items.
+func matchArcade(path string, items []Item, index *mame.Index) *mame.Match {
+	files := make([]mame.File, 0, len(items))
+	for _, item := range items {
+		crc := item.Hashes[core.HashZipCRC32]
+		if crc == "" {
+			crc = item.Hashes[core.HashCRC32]
+		}
+		if crc != "" {
+			files = append(files, mame.File{Name: item.Name, CRC: crc})
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return index.Match(name, files)
 }
 
 // identifyContainerEntry identifies a single entry within a container.
@@ -109,7 +136,7 @@ func identifyContainerEntry(c util.FileContainer, entry util.FileEntry, opts Opt
 	defer reader.Close()
 
 	// Identify the content (may also return embedded hashes for formats like CHD)
-	game, embeddedHashes := identifyContent(reader, size, entry.Name)
+	game, embeddedHashes := identifyContent(reader, size, entry.Name, opts)
 	item.Game = game
 
 	// Build hashes: merge container metadata with embedded hashes
@@ -133,6 +160,8 @@ func identifyContainerEntry(c util.FileContainer, entry util.FileEntry, opts Opt
 		item.Hashes = hashes
 	}
 
+	matchSoftware(item, opts.SoftwareLists)
+	lookupDatabase(item, opts.Lookup)
 	return item, nil
 }
 
@@ -140,7 +169,7 @@ func identifyContainerEntry(c util.FileContainer, entry util.FileEntry, opts Opt
 // Returns an Item with hashes and game info.
 func identifyReader(r util.RandomAccessReader, size int64, name string, opts Options) (*Item, error) {
 	// Try to identify content (may also return embedded hashes for formats like CHD)
-	game, embeddedHashes := identifyContent(r, size, name)
+	game, embeddedHashes := identifyContent(r, size, name, opts)
 
 	item := &Item{
 		Name: name,
@@ -148,30 +177,65 @@ func identifyReader(r util.RandomAccessReader, size int64, name string, opts Opt
 		Game: game,
 	}
 
-	// Use embedded hashes if provided (CHD, etc.)
+	// Use embedded hashes if provided (CHD, etc.), otherwise calculate them
+	// unless the file exceeds MaxHashSize (-1 = no limit)
 	if embeddedHashes != nil {
 		item.Hashes = embeddedHashes
-		return item, nil
+	} else if opts.MaxHashSize < 0 || size <= opts.MaxHashSize {
+		hashes, err := calculateHashes(r, size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate hashes: %w", err)
+		}
+		item.Hashes = hashes
 	}
 
-	// Skip hashes for files exceeding MaxHashSize (-1 = no limit)
-	if opts.MaxHashSize >= 0 && size > opts.MaxHashSize {
-		return item, nil
+	matchSoftware(item, opts.SoftwareLists)
+	lookupDatabase(item, opts.Lookup)
+	return item, nil
+}
+
+// matchSoftware identifies an item against MAME software lists by hash when
+// no parser recognized it, or only its platform is known from the extension.
+func matchSoftware(item *Item, index *mame.SoftwareIndex) {
+	if index == nil || item.Hashes == nil {
+		return
 	}
+	if _, headerless := item.Game.(*generic.Info); item.Game != nil && !headerless {
+		return
+	}
+	if match := index.Match(item.Size, item.Hashes); match != nil {
+		item.Game = match
+	}
+}
 
-	// Calculate hashes
-	hashes, err := calculateHashes(r, size)
-	if err != nil {
-		return nil, fmt.Errorf("failed to calculate hashes: %w", err)
+// lookupDatabase finds an item in libretro databases by hash, or by the
+// serial of the identified game if no hash matches.
+func lookupDatabase(item *Item, index *rdb.Index) {
+	if index == nil {
+		return
+	}
+	if item.Hashes != nil {
+		if entry := index.Match(item.Size, item.Hashes); entry != nil {
+			item.Lookup = entry
+			return
+		}
 	}
+	if item.Game != nil && item.Game.GameSerial() != "" {
+		if entries := index.LookupSerial(item.Game.GameSerial(), item.Game.GamePlatform()); len(entries) > 0 {
+			item.Lookup = entries[0]
+		}
+	}
+}
 
-	item.Hashes = hashes
-	return item, nil
+// fileNameApplier is implemented by game info that can fill in details from
+// tags in the file name, like Switch title IDs, when the content lacks them.
+type fileNameApplier interface {
+	ApplyFileName(name string)
 }
 
 // identifyContent tries to identify the content from a reader.
 // Returns the game info and any embedded hashes (both may be nil).
-func identifyContent(r io.ReaderAt, size int64, name string) (core.GameInfo, core.Hashes) {
+func identifyContent(r io.ReaderAt, size int64, name string, opts Options) (core.GameInfo, core.Hashes) {
 	// Get candidate parsers by extension
 	parsers := identifyByExtension(name)
 	if len(parsers) == 0 {
@@ -181,8 +245,11 @@ func identifyContent(r io.ReaderAt, size int64, name string) (core.GameInfo, cor
 	// Try each parser
 	// TODO: log parser errors at debug level when logging is available
 	for _, parser := range parsers {
-		game, hashes, err := parser(r, size)
+		game, hashes, err := parser(r, size, opts)
 		if err == nil && game != nil {
+			if f, ok := game.(fileNameApplier); ok {
+				f.ApplyFileName(name)
+			}
 			return game, hashes
 		}
 		// If game is nil but hashes exist (e.g., CHD with unknown content), keep them
@@ -1,9 +1,18 @@
 package identify
 
 import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/mame"
+	"github.com/sargunv/rom-tools/lib/rdb"
 )
 
 func TestIdentifyZIP(t *testing.T) {
@@ -191,3 +200,326 @@ func TestIdentifyLooseFileNoLimitHashes(t *testing.T) {
 		t.Errorf("Expected 3 hashes with MaxHashSize=-1, got %d", len(item.Hashes))
 	}
 }
+
+func TestIdentifyFDSHashesWithoutHeader(t *testing.T) {
+	side := make([]byte, 65500)
+	side[0] = 0x01
+	copy(side[1:], "*NINTENDO-HVC*")
+	copy(side[0x10:], "ZEL")
+
+	header := make([]byte, 16)
+	copy(header, "FDS\x1A")
+	header[4] = 1
+
+	path := filepath.Join(t.TempDir(), "zelda.fds")
+	if err := os.WriteFile(path, append(header, side...), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	result, err := Identify(path, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Identify() error = %v", err)
+	}
+
+	item := result.Items[0]
+	if item.Game == nil || item.Game.GamePlatform() != core.PlatformFDS {
+		t.Fatalf("Expected platform %s, got %v", core.PlatformFDS, item.Game)
+	}
+
+	want, err := calculateHashes(bytes.NewReader(side), int64(len(side)))
+	if err != nil {
+		t.Fatalf("calculateHashes() error = %v", err)
+	}
+	if item.Hashes[core.HashSHA1] != want[core.HashSHA1] {
+		t.Errorf("Expected headerless SHA1 %s, got %s", want[core.HashSHA1], item.Hashes[core.HashSHA1])
+	}
+}
+
+func TestIdentifyFDSSkipsHashForLargeFiles(t *testing.T) {
+	side := make([]byte, 65500)
+	side[0] = 0x01
+	copy(side[1:], "*NINTENDO-HVC*")
+
+	header := make([]byte, 16)
+	copy(header, "FDS\x1A")
+	header[4] = 1
+
+	path := filepath.Join(t.TempDir(), "zelda.fds")
+	if err := os.WriteFile(path, append(header, side...), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	result, err := Identify(path, Options{MaxHashSize: 1024})
+	if err != nil {
+		t.Fatalf("Identify() error = %v", err)
+	}
+
+	item := result.Items[0]
+	if item.Game == nil || item.Game.GamePlatform() != core.PlatformFDS {
+		t.Fatalf("Expected platform %s, got %v", core.PlatformFDS, item.Game)
+	}
+	if len(item.Hashes) != 0 {
+		t.Errorf("Expected 0 hashes with MaxHashSize=1024, got %d", len(item.Hashes))
+	}
+}
+
+func TestIdentifyArcadeZIP(t *testing.T) {
+	p1 := []byte("program rom")
+	s1 := []byte("fix layer rom")
+
+	zipPath := filepath.Join(t.TempDir(), "puzzle.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatalf("failed to create zip: %v", err)
+	}
+	zw := zip.NewWriter(f)
+	for name, data := range map[string][]byte{"p1.bin": p1, "s1.bin": s1} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("failed to add %s: %v", name, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close file: %v", err)
+	}
+
+	listXML := fmt.Sprintf(`<mame build="test">
+	<machine name="puzzle">
+		<description>Puzzle Game</description>
+		<year>1995</year>
+		<manufacturer>Test</manufacturer>
+		<rom name="p1.bin" size="%d" crc="%08x"/>
+		<rom name="s1.bin" size="%d" crc="%08x"/>
+	</machine>
+</mame>`, len(p1), crc32.ChecksumIEEE(p1), len(s1), crc32.ChecksumIEEE(s1))
+	list, err := mame.ParseReader(strings.NewReader(listXML))
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v", err)
+	}
+
+	opts := DefaultOptions()
+	opts.MAME = mame.NewIndex(list)
+	result, err := Identify(zipPath, opts)
+	if err != nil {
+		t.Fatalf("Identify() error = %v", err)
+	}
+
+	if result.Arcade == nil {
+		t.Fatal("Expected arcade match, got nil")
+	}
+	if result.Arcade.Name != "puzzle" {
+		t.Errorf("Expected machine 'puzzle', got '%s'", result.Arcade.Name)
+	}
+	if result.Arcade.Description != "Puzzle Game" {
+		t.Errorf("Expected description 'Puzzle Game', got '%s'", result.Arcade.Description)
+	}
+	if !result.Arcade.Complete() {
+		t.Errorf("Expected complete set, got missing %v, extra %v", result.Arcade.Missing, result.Arcade.Extra)
+	}
+}
+
+// msxSoftwareIndex returns a software list index holding one MSX cartridge
+// with the given data.
+func msxSoftwareIndex(t *testing.T, data []byte) *mame.SoftwareIndex {
+	t.Helper()
+	listXML := fmt.Sprintf(`<softwarelist name="msx1_cart" description="MSX1 cartridges">
+	<software name="cart">
+		<description>Test Cartridge</description>
+		<year>1985</year>
+		<publisher>Test</publisher>
+		<part name="cart" interface="msx_cart">
+			<dataarea name="rom" size="%d">
+				<rom name="cart.rom" size="%d" crc="%08x" offset="0"/>
+			</dataarea>
+		</part>
+	</software>
+</softwarelist>`, len(data), len(data), crc32.ChecksumIEEE(data))
+	list, err := mame.ParseSoftwareListReader(strings.NewReader(listXML))
+	if err != nil {
+		t.Fatalf("ParseSoftwareListReader() error = %v", err)
+	}
+	return mame.NewSoftwareIndex(list)
+}
+
+func TestIdentifySoftwareListFile(t *testing.T) {
+	data := []byte("msx cartridge rom")
+	romPath := filepath.Join(t.TempDir(), "cart.rom")
+	if err := os.WriteFile(romPath, data, 0644); err != nil {
+		t.Fatalf("failed to write ROM: %v", err)
+	}
+
+	opts := DefaultOptions()
+	opts.SoftwareLists = msxSoftwareIndex(t, data)
+	result, err := Identify(romPath, opts)
+	if err != nil {
+		t.Fatalf("Identify() error = %v", err)
+	}
+
+	item := result.Items[0]
+	if item.Game == nil {
+		t.Fatal("Expected software list identification, got nil")
+	}
+	if item.Game.GameTitle() != "Test Cartridge" {
+		t.Errorf("Expected title 'Test Cartridge', got '%s'", item.Game.GameTitle())
+	}
+}
+
+func TestIdentifySoftwareListHeaderlessFile(t *testing.T) {
+	// The extension alone identifies the platform; the software list refines it
+	data := []byte("msx cartridge rom")
+	romPath := filepath.Join(t.TempDir(), "cart.mx1")
+	if err := os.WriteFile(romPath, data, 0644); err != nil {
+		t.Fatalf("failed to write ROM: %v", err)
+	}
+
+	result, err := Identify(romPath, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Identify() error = %v", err)
+	}
+	if game := result.Items[0].Game; game == nil || game.GamePlatform() != core.PlatformMSX || game.GameTitle() != "" {
+		t.Fatalf("Expected untitled %s identification, got %+v", core.PlatformMSX, game)
+	}
+
+	opts := DefaultOptions()
+	opts.SoftwareLists = msxSoftwareIndex(t, data)
+	result, err = Identify(romPath, opts)
+	if err != nil {
+		t.Fatalf("Identify() error = %v", err)
+	}
+
+	item := result.Items[0]
+	if item.Game == nil {
+		t.Fatal("Expected software list identification, got nil")
+	}
+	if item.Game.GameTitle() != "Test Cartridge" {
+		t.Errorf("Expected title 'Test Cartridge', got '%s'", item.Game.GameTitle())
+	}
+	if item.Game.GamePlatform() != core.PlatformMSX {
+		t.Errorf("Expected platform %s, got %s", core.PlatformMSX, item.Game.GamePlatform())
+	}
+}
+
+func TestIdentifyLookup(t *testing.T) {
+	data := bytes.Repeat([]byte{0x5A}, 4096)
+	romPath := filepath.Join(t.TempDir(), "game.bin")
+	if err := os.WriteFile(romPath, data, 0644); err != nil {
+		t.Fatalf("failed to write ROM: %v", err)
+	}
+
+	db := &rdb.Database{
+		Name: "Atari - 2600",
+		Entries: []rdb.Entry{
+			{Name: "Other Game", CRC32: "00000000", Database: "Atari - 2600"},
+			{Name: "Test Game", CRC32: fmt.Sprintf("%08x", crc32.ChecksumIEEE(data)), Size: uint64(len(data)), Database: "Atari - 2600"},
+		},
+	}
+
+	opts := DefaultOptions()
+	opts.Lookup = rdb.NewIndex(db)
+	result, err := Identify(romPath, opts)
+	if err != nil {
+		t.Fatalf("Identify() error = %v", err)
+	}
+
+	item := result.Items[0]
+	if item.Lookup == nil {
+		t.Fatal("Expected database lookup, got nil")
+	}
+	if item.Lookup.Name != "Test Game" {
+		t.Errorf("Expected lookup 'Test Game', got '%s'", item.Lookup.Name)
+	}
+}
+
+func TestIdentifyHeaderlessPlatform(t *testing.T) {
+	rom := make([]byte, 8192)
+	rom[0], rom[1] = 0xAA, 0x55
+	romPath := filepath.Join(t.TempDir(), "game.col")
+	if err := os.WriteFile(romPath, rom, 0644); err != nil {
+		t.Fatalf("failed to write ROM: %v", err)
+	}
+
+	result, err := Identify(romPath, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Identify() error = %v", err)
+	}
+
+	item := result.Items[0]
+	if item.Game == nil {
+		t.Fatal("Expected platform identification, got nil")
+	}
+	if item.Game.GamePlatform() != core.PlatformColecoVision {
+		t.Errorf("Expected platform %s, got %s", core.PlatformColecoVision, item.Game.GamePlatform())
+	}
+	if len(item.Hashes) != 3 {
+		t.Errorf("Expected 3 hashes, got %d", len(item.Hashes))
+	}
+}
+
+func TestIdentifySwitchFileNameTags(t *testing.T) {
+	// An empty PFS0 partition: no tickets or content metadata to read
+	nsp := make([]byte, 16)
+	copy(nsp, "PFS0")
+	romPath := filepath.Join(t.TempDir(), "Game [0100000000010000][v65536].nsp")
+	if err := os.WriteFile(romPath, nsp, 0644); err != nil {
+		t.Fatalf("failed to write ROM: %v", err)
+	}
+
+	result, err := Identify(romPath, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Identify() error = %v", err)
+	}
+
+	item := result.Items[0]
+	if item.Game == nil {
+		t.Fatal("Expected Switch identification, got nil")
+	}
+	if item.Game.GameSerial() != "0100000000010000" {
+		t.Errorf("Expected serial 0100000000010000, got %q", item.Game.GameSerial())
+	}
+}
+
+func TestIdentifySTFSRequiresPackageName(t *testing.T) {
+	// Minimal STFS header: magic, title ID and space for the title strings
+	header := make([]byte, 0x1711)
+	copy(header, "LIVE")
+	header[0x360], header[0x361], header[0x362], header[0x363] = 0x58, 0x41, 0x08, 0x02
+
+	tests := []struct {
+		name   string
+		wantID bool
+	}{
+		{"B1E2A4C0D5F60718293A4B5C6D7E8F9012345678", true},
+		{"584108FF", true},
+		{"README", false},
+		{"B1E2A4C0D5F6071829", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			romPath := filepath.Join(t.TempDir(), tt.name)
+			if err := os.WriteFile(romPath, header, 0644); err != nil {
+				t.Fatalf("failed to write package: %v", err)
+			}
+
+			result, err := Identify(romPath, DefaultOptions())
+			if err != nil {
+				t.Fatalf("Identify() error = %v", err)
+			}
+
+			game := result.Items[0].Game
+			if tt.wantID {
+				if game == nil || game.GamePlatform() != core.PlatformXbox360 {
+					t.Errorf("Expected platform %s, got %v", core.PlatformXbox360, game)
+				}
+			} else if game != nil {
+				t.Errorf("Expected no identification, got %s", game.GamePlatform())
+			}
+		})
+	}
+}
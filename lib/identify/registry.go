@@ -6,32 +6,44 @@ import (
 	"strings"
 
 	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/roms/bandai/wonderswan"
+	"github.com/sargunv/rom-tools/lib/roms/generic"
+	"github.com/sargunv/rom-tools/lib/roms/nintendo/ciso"
 	"github.com/sargunv/rom-tools/lib/roms/nintendo/gb"
 	"github.com/sargunv/rom-tools/lib/roms/nintendo/gba"
 	"github.com/sargunv/rom-tools/lib/roms/nintendo/gcm"
+	"github.com/sargunv/rom-tools/lib/roms/nintendo/gcz"
 	"github.com/sargunv/rom-tools/lib/roms/nintendo/n3ds"
 	"github.com/sargunv/rom-tools/lib/roms/nintendo/n64"
 	"github.com/sargunv/rom-tools/lib/roms/nintendo/nds"
 	"github.com/sargunv/rom-tools/lib/roms/nintendo/nes"
+	"github.com/sargunv/rom-tools/lib/roms/nintendo/nsw"
+	"github.com/sargunv/rom-tools/lib/roms/nintendo/pokemini"
 	"github.com/sargunv/rom-tools/lib/roms/nintendo/rvz"
 	"github.com/sargunv/rom-tools/lib/roms/nintendo/sfc"
+	"github.com/sargunv/rom-tools/lib/roms/nintendo/vb"
+	"github.com/sargunv/rom-tools/lib/roms/nintendo/wbfs"
 	"github.com/sargunv/rom-tools/lib/roms/playstation/pkg"
 	"github.com/sargunv/rom-tools/lib/roms/sega/md"
 	"github.com/sargunv/rom-tools/lib/roms/sega/sms"
+	"github.com/sargunv/rom-tools/lib/roms/snk/ngp"
+	"github.com/sargunv/rom-tools/lib/roms/xbox/stfs"
 	"github.com/sargunv/rom-tools/lib/roms/xbox/xbe"
+	"github.com/sargunv/rom-tools/lib/roms/xbox/xex"
 	"github.com/sargunv/rom-tools/lib/roms/xbox/xiso"
 )
 
 // identifyFunc attempts to identify content from a reader.
 // Returns game info, optional embedded hashes (for formats like CHD), and error.
-type identifyFunc func(r io.ReaderAt, size int64) (core.GameInfo, core.Hashes, error)
+// Parsers that hash decompressed content must respect opts.MaxHashSize.
+type identifyFunc func(r io.ReaderAt, size int64, opts Options) (core.GameInfo, core.Hashes, error)
 
 // wrapParser converts a typed parser function to the generic signature.
 // This is needed because Go function types are invariant - a function returning
 // *GBAInfo is not assignable to a function returning GameInfo even though
 // *GBAInfo implements GameInfo.
 func wrapParser[T core.GameInfo](fn func(io.ReaderAt, int64) (T, error)) identifyFunc {
-	return func(r io.ReaderAt, size int64) (core.GameInfo, core.Hashes, error) {
+	return func(r io.ReaderAt, size int64, _ Options) (core.GameInfo, core.Hashes, error) {
 		info, err := fn(r, size)
 		return info, nil, err
 	}
@@ -43,36 +55,144 @@ var registry = map[string][]identifyFunc{
 	".gba":  {wrapParser(gba.Parse)},
 	".gb":   {wrapParser(gb.Parse)},
 	".gbc":  {wrapParser(gb.Parse)},
+	".vb":   {wrapParser(vb.Parse)},
+	".min":  {wrapParser(pokemini.Parse)},
 	".nds":  {wrapParser(nds.Parse)},
 	".dsi":  {wrapParser(nds.Parse)},
 	".ids":  {wrapParser(nds.Parse)},
 	".3ds":  {wrapParser(n3ds.Parse)},
 	".cci":  {wrapParser(n3ds.Parse)},
+	".cia":  {wrapParser(n3ds.ParseCIA)},
+	".cxi":  {wrapParser(n3ds.ParseNCCH)},
+	".3dsx": {wrapParser(n3ds.Parse3DSX)},
 	".nes":  {wrapParser(nes.Parse)},
+	".fds":  {identifyFDS},
+	".qd":   {identifyFDS},
 	".sfc":  {wrapParser(sfc.Parse)},
 	".smc":  {wrapParser(sfc.Parse)},
 	".z64":  {wrapParser(n64.Parse)},
 	".v64":  {wrapParser(n64.Parse)},
 	".n64":  {wrapParser(n64.Parse)},
+	".ndd":  {wrapParser(n64.ParseNDD)},
+	".d64":  {wrapParser(n64.ParseD64)},
 	".md":   {wrapParser(md.Parse)},
 	".gen":  {wrapParser(md.Parse)},
 	".32x":  {wrapParser(md.Parse)},
 	".smd":  {wrapParser(md.Parse)},
 	".sms":  {wrapParser(sms.Parse)},
 	".gg":   {wrapParser(sms.Parse)},
+	".ws":   {wrapParser(wonderswan.Parse)},
+	".wsc":  {wrapParser(wonderswan.Parse)},
+	".ngp":  {wrapParser(ngp.Parse)},
+	".ngc":  {wrapParser(ngp.Parse)},
 	".xbe":  {wrapParser(xbe.Parse)},
+	".xex":  {wrapParser(xex.Parse)},
 	".pkg":  {wrapParser(pkg.Parse)},
 	".chd":  {identifyCHD},
-	".rvz":  {wrapParser(rvz.Parse)},
-	".wia":  {wrapParser(rvz.Parse)},
+	".cdi":  {identifyCDI},
+	".rvz":  {identifyRVZ},
+	".wia":  {identifyRVZ},
+	".gcz":  {wrapParser(gcz.Parse)},
+	".ciso": {wrapParser(ciso.Parse)},
+	".wbfs": {wrapParser(wbfs.Parse)},
+	".nsp":  {wrapParser(nsw.ParseNSP)},
+	".nsz":  {wrapParser(nsw.ParseNSP)},
+	".xci":  {wrapParser(nsw.ParseXCI)},
+	".xcz":  {wrapParser(nsw.ParseXCI)},
 	".gcm":  {wrapParser(gcm.Parse)},
 	".xiso": {wrapParser(xiso.Parse)},
-	".iso":  {wrapParser(xiso.Parse), wrapParser(gcm.Parse), identifyISO9660},
+	".iso":  {wrapParser(xiso.Parse), wrapParser(xex.ParseDisc), wrapParser(gcm.Parse), identifyISO9660},
 	".bin":  {identifyISO9660, wrapParser(md.Parse)},
+
+	// Xbox 360 STFS packages (XBLA, GOD headers) have no file extension.
+	// Only names that look like a title or content ID reach this entry.
+	"": {wrapParser(stfs.Parse)},
+}
+
+func init() {
+	// Headerless formats only know the platform, so they are tried after any
+	// dedicated parser for the same extension
+	for _, ext := range generic.Extensions() {
+		registry[ext] = append(registry[ext], identifyGeneric(ext))
+	}
+}
+
+// identifyGeneric returns a parser that identifies the platform of a
+// headerless ROM with the given extension.
+func identifyGeneric(ext string) identifyFunc {
+	return func(r io.ReaderAt, size int64, _ Options) (core.GameInfo, core.Hashes, error) {
+		info, err := generic.Parse(r, size, ext)
+		return info, nil, err
+	}
+}
+
+// identifyFDS identifies a Famicom Disk System image.
+// fwNES-headered images are hashed without the header to match No-Intro DATs.
+func identifyFDS(r io.ReaderAt, size int64, opts Options) (core.GameInfo, core.Hashes, error) {
+	info, err := nes.ParseFDS(r, size)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.HeaderSize == 0 {
+		return info, nil, nil
+	}
+
+	headerSize := int64(info.HeaderSize)
+	if opts.MaxHashSize >= 0 && size-headerSize > opts.MaxHashSize {
+		return info, nil, nil
+	}
+	hashes, err := calculateHashes(io.NewSectionReader(r, headerSize, size-headerSize), size-headerSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return info, hashes, nil
+}
+
+// identifyRVZ identifies an RVZ/WIA image. GameCube discs are hashed after
+// decompression so the hashes match the original disc in Redump DATs.
+// Wii partitions are stored decrypted, so Wii discs are not hashed this way.
+func identifyRVZ(r io.ReaderAt, size int64, opts Options) (core.GameInfo, core.Hashes, error) {
+	reader, err := rvz.NewReader(r, size)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := reader.Info()
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.DiscType != rvz.DiscTypeGameCube {
+		return info, nil, nil
+	}
+	if opts.MaxHashSize >= 0 && reader.Size() > opts.MaxHashSize {
+		return info, nil, nil
+	}
+
+	hashes, err := calculateHashes(reader, reader.Size())
+	if err != nil {
+		return nil, nil, err
+	}
+	return info, hashes, nil
 }
 
 // identifyByExtension returns the list of parsers to try for a given filename.
 func identifyByExtension(filename string) []identifyFunc {
 	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == "" && !isSTFSName(filepath.Base(filename)) {
+		return nil
+	}
 	return registry[ext]
 }
+
+// isSTFSName reports whether an extensionless file name looks like an Xbox
+// 360 package: an 8-digit hex title ID or a 40-digit hex content ID.
+func isSTFSName(name string) bool {
+	if len(name) != 8 && len(name) != 40 {
+		return false
+	}
+	for _, c := range name {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
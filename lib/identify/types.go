@@ -1,7 +1,11 @@
 // Package identify provides ROM identification and hashing utilities.
 package identify
 
-import "github.com/sargunv/rom-tools/lib/core"
+import (
+	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/mame"
+	"github.com/sargunv/rom-tools/lib/rdb"
+)
 
 // Item represents one identifiable unit (a file or entry within a container).
 type Item struct {
@@ -9,12 +13,20 @@ type Item struct {
 	Size   int64         `json:"size"`             // file size in bytes
 	Hashes core.Hashes   `json:"hashes,omitempty"` // hash values by type
 	Game   core.GameInfo `json:"game,omitempty"`   // identified game info (platform-specific struct)
+
+	// Lookup is the libretro database entry matched by hash or serial (only
+	// set when Options.Lookup is provided).
+	Lookup *rdb.Entry `json:"lookup,omitempty"`
 }
 
 // Result is the result of identifying a path.
 type Result struct {
 	Path  string `json:"path"`  // absolute path that was identified
 	Items []Item `json:"items"` // identified items (1 for single file, N for containers)
+
+	// Arcade is the MAME machine matched from the CRC32s of a ZIP archive or
+	// folder (only set when Options.MAME is provided).
+	Arcade *mame.Match `json:"arcade,omitempty"`
 }
 
 // Options controls ROM identification behavior.
@@ -23,10 +35,26 @@ type Options struct {
 	// This only applies when hashes are not already available from:
 	//   - Container metadata (e.g., ZIP files provide zip-crc32)
 	//   - Embedded format hashes (e.g., CHD files provide chd-*-sha1)
+	// Compressed disc images hashed as their original disc (e.g., GameCube RVZ files) are
+	// compared against the size of the original disc.
 	// Files exceeding this limit will have no hashes unless provided by the above sources.
 	// Use -1 for no limit (always calculate when needed).
 	// Default is -1 (no limit).
 	MaxHashSize int64
+
+	// MAME is an index of a MAME machine list (-listxml output). When set,
+	// ZIP archives and folders are identified as arcade ROM sets by matching
+	// the CRC32s of their files.
+	MAME *mame.Index
+
+	// SoftwareLists is an index of MAME software lists (hash/*.xml). When set,
+	// files that no parser recognizes are identified by their hashes.
+	SoftwareLists *mame.SoftwareIndex
+
+	// Lookup is an index of libretro databases (RetroArch .rdb files). When
+	// set, items are looked up by hash, falling back to the serial reported
+	// by their parser.
+	Lookup *rdb.Index
 }
 
 // DefaultOptions returns Options with sensible defaults.
@@ -330,3 +330,44 @@ func TestNewReader_RawMODE2(t *testing.T) {
 		t.Errorf("Size() = %d, want %d", reader.Size(), expectedSize)
 	}
 }
+
+func TestNewReader_MODE2_2336(t *testing.T) {
+	// Create a MODE2/2336 ISO (no sync or header, as stored in CDI images)
+	numSectors := 18
+	data := make([]byte, numSectors*sectorSize2336)
+
+	// For MODE2/2336, data starts after the 8-byte subheader
+	pvdSector := 16
+	pvdPhysicalOffset := pvdSector*sectorSize2336 + mode2SubHeader
+
+	data[pvdPhysicalOffset+0] = 0x01
+	copy(data[pvdPhysicalOffset+1:], "CD001")
+	data[pvdPhysicalOffset+6] = 0x01
+
+	// Root directory record
+	rootRecordOffset := pvdPhysicalOffset + pvdRootDirOffset
+	data[rootRecordOffset+0] = 34
+	binary.LittleEndian.PutUint32(data[rootRecordOffset+dirEntryExtentLoc:], 17)
+	binary.LittleEndian.PutUint32(data[rootRecordOffset+dirEntryDataLen:], sectorSize2048)
+
+	// System area data in sector 0
+	copy(data[mode2SubHeader:], "SEGA SEGAKATANA ")
+
+	reader, err := NewReader(&mockReaderAt{data}, int64(len(data)))
+	if err != nil {
+		t.Fatalf("NewReader failed for MODE2/2336: %v", err)
+	}
+
+	expectedSize := int64(numSectors * sectorSize2048)
+	if reader.Size() != expectedSize {
+		t.Errorf("Size() = %d, want %d", reader.Size(), expectedSize)
+	}
+
+	buf := make([]byte, 16)
+	if _, err := reader.ReadAt(buf, 0); err != nil {
+		t.Fatalf("ReadAt failed: %v", err)
+	}
+	if string(buf) != "SEGA SEGAKATANA " {
+		t.Errorf("ReadAt(0) = %q, want %q", buf, "SEGA SEGAKATANA ")
+	}
+}
@@ -6,6 +6,7 @@ import "io"
 const (
 	sectorSize2048 = 2048 // Standard ISO9660 sector (cooked)
 	sectorSize2352 = 2352 // Raw CD sector (MODE1/MODE2)
+	sectorSize2336 = 2336 // MODE2 sector without sync and header (e.g., DiscJuggler CDI)
 
 	// For MODE1/2352, user data starts at offset 16 within each sector:
 	// 12 bytes sync + 4 bytes header = 16 bytes before data
@@ -14,6 +15,9 @@ const (
 	// For MODE2/2352, user data starts at offset 24 within each sector:
 	// 12 bytes sync + 4 bytes header + 8 bytes subheader = 24 bytes before data
 	mode2SectorHeader = 24
+
+	// For MODE2/2336, user data starts after the 8-byte subheader
+	mode2SubHeader = 8
 )
 
 // sectorFormat describes the physical layout of a CD image.
@@ -33,11 +37,13 @@ var sectorFormats = []sectorFormat{
 	{sectorSize2352, mode1SectorHeader, 16*sectorSize2352 + mode1SectorHeader, "MODE1/2352"},
 	// Raw MODE2 (2352 bytes/sector, 24-byte header) - used by PS1/PS2
 	{sectorSize2352, mode2SectorHeader, 16*sectorSize2352 + mode2SectorHeader, "MODE2/2352"},
+	// MODE2 without sync and header (2336 bytes/sector) - used by DiscJuggler CDI
+	{sectorSize2336, mode2SubHeader, 16*sectorSize2336 + mode2SubHeader, "MODE2/2336"},
 }
 
 // sectorReader wraps an io.ReaderAt to translate logical sector reads
 // (2048 bytes/sector as expected by ISO9660) to physical sector reads
-// in a raw BIN file (which may use 2352 bytes/sector).
+// in a raw BIN file (which may use 2352 or 2336 bytes/sector).
 type sectorReader struct {
 	r              io.ReaderAt
 	physicalSector int64 // bytes per physical sector (2352 or 2048)
@@ -0,0 +1,126 @@
+package launchbox
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Database is an indexed LaunchBox games database
+type Database struct {
+	Games     []Game
+	Platforms []Platform
+	Emulators []Emulator
+
+	byID       map[int64]int
+	byName     map[nameKey][]int
+	byPlatform map[string]int
+}
+
+// nameKey indexes games by platform and normalized title
+type nameKey struct {
+	platform string
+	name     string
+}
+
+// index attaches alternate names and images to their games and builds the
+// lookup indexes
+func (db *Database) index(altNames []GameAlternateName, images []GameImage) {
+	db.byID = make(map[int64]int, len(db.Games))
+	db.byName = make(map[nameKey][]int, len(db.Games))
+	db.byPlatform = make(map[string]int, len(db.Platforms))
+
+	for i, g := range db.Games {
+		db.byID[g.DatabaseID] = i
+	}
+	for _, a := range altNames {
+		if i, ok := db.byID[a.DatabaseID]; ok {
+			db.Games[i].AlternateNames = append(db.Games[i].AlternateNames, a)
+		}
+	}
+	for _, img := range images {
+		if i, ok := db.byID[img.DatabaseID]; ok {
+			db.Games[i].Images = append(db.Games[i].Images, img)
+		}
+	}
+
+	for i, g := range db.Games {
+		platform := strings.ToLower(g.Platform)
+		db.addName(platform, g.Name, i)
+		for _, a := range g.AlternateNames {
+			db.addName(platform, a.AlternateName, i)
+		}
+	}
+
+	for i, p := range db.Platforms {
+		db.byPlatform[strings.ToLower(p.Name)] = i
+	}
+}
+
+func (db *Database) addName(platform, name string, i int) {
+	key := nameKey{platform: platform, name: NormalizeName(name)}
+	if key.name == "" {
+		return
+	}
+	for _, existing := range db.byName[key] {
+		if existing == i {
+			return
+		}
+	}
+	db.byName[key] = append(db.byName[key], i)
+}
+
+// Game returns the game with a database ID, or nil if there is none.
+func (db *Database) Game(id int64) *Game {
+	if i, ok := db.byID[id]; ok {
+		return &db.Games[i]
+	}
+	return nil
+}
+
+// Platform returns the platform with a name (case-insensitive), or nil if
+// there is none.
+func (db *Database) Platform(name string) *Platform {
+	if i, ok := db.byPlatform[strings.ToLower(name)]; ok {
+		return &db.Platforms[i]
+	}
+	return nil
+}
+
+// Lookup finds games on a platform by title or alternate name. Titles are
+// compared after NormalizeName, so No-Intro and Redump names such as
+// "Legend of Zelda, The (USA)" match "The Legend of Zelda".
+func (db *Database) Lookup(platform, name string) []*Game {
+	key := nameKey{platform: strings.ToLower(platform), name: NormalizeName(name)}
+	var games []*Game
+	for _, i := range db.byName[key] {
+		games = append(games, &db.Games[i])
+	}
+	return games
+}
+
+var (
+	// Parenthesized and bracketed tags, e.g. "(USA)" or "[!]"
+	tagPattern = regexp.MustCompile(`\s*[(\[][^)\]]*[)\]]`)
+
+	// A trailing article moved to the end of the main title, e.g.
+	// "Legend of Zelda, The - A Link to the Past"
+	articlePattern = regexp.MustCompile(`(?i)^(.*?), (the|a|an)(\s+-\s+.*|:.*)?$`)
+)
+
+// NormalizeName reduces a title to a form for matching: tags such as
+// "(USA)" are removed, a trailing ", The" is moved to the front, "&" becomes
+// "and", and everything other than letters and digits is dropped.
+func NormalizeName(name string) string {
+	name = strings.TrimSpace(tagPattern.ReplaceAllString(name, ""))
+	name = articlePattern.ReplaceAllString(name, "$2 $1$3")
+	name = strings.ReplaceAll(name, "&", "and")
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,197 @@
+// Package launchbox reads the LaunchBox games database (Metadata.xml), and
+// reads and writes the platform files of a LaunchBox install.
+package launchbox
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// LaunchBox publishes its games database as a zipped Metadata.xml:
+// https://gamesdb.launchbox-app.com/Metadata.zip
+//
+// The file is large (hundreds of MB), so records are decoded one at a time.
+// Top-level elements other than those modeled here (Files, Mame,
+// PlatformAlternateName, EmulatorPlatform, ...) are skipped.
+
+// ImageBaseURL is the URL images in the database are served from.
+const ImageBaseURL = "https://images.launchbox-app.com/"
+
+// Game is a game record
+type Game struct {
+	Name                 string  `xml:"Name"`
+	DatabaseID           int64   `xml:"DatabaseID"`
+	Platform             string  `xml:"Platform"`
+	ReleaseYear          int     `xml:"ReleaseYear"`
+	ReleaseDate          string  `xml:"ReleaseDate"` // e.g., "1991-06-23T00:00:00-07:00"
+	Overview             string  `xml:"Overview"`
+	MaxPlayers           int     `xml:"MaxPlayers"`
+	ReleaseType          string  `xml:"ReleaseType"`
+	Cooperative          bool    `xml:"Cooperative"`
+	VideoURL             string  `xml:"VideoURL"`
+	WikipediaURL         string  `xml:"WikipediaURL"`
+	CommunityRating      float64 `xml:"CommunityRating"` // 0-5
+	CommunityRatingCount int     `xml:"CommunityRatingCount"`
+	ESRB                 string  `xml:"ESRB"`
+	Genres               string  `xml:"Genres"` // Semicolon-separated
+	Developer            string  `xml:"Developer"`
+	Publisher            string  `xml:"Publisher"`
+
+	// Filled in from GameAlternateName and GameImage records
+	AlternateNames []GameAlternateName `xml:"-"`
+	Images         []GameImage         `xml:"-"`
+}
+
+// GenreList returns the game's genres.
+func (g *Game) GenreList() []string {
+	var genres []string
+	for _, genre := range strings.Split(g.Genres, ";") {
+		if genre = strings.TrimSpace(genre); genre != "" {
+			genres = append(genres, genre)
+		}
+	}
+	return genres
+}
+
+// Date returns the release date as "YYYY-MM-DD", or the release year as
+// "YYYY" if the full date is unknown.
+func (g *Game) Date() string {
+	if len(g.ReleaseDate) >= 10 {
+		return g.ReleaseDate[:10]
+	}
+	if g.ReleaseYear > 0 {
+		return fmt.Sprintf("%04d", g.ReleaseYear)
+	}
+	return ""
+}
+
+// GameAlternateName is an alternate (usually regional) title of a game
+type GameAlternateName struct {
+	AlternateName string `xml:"AlternateName"`
+	DatabaseID    int64  `xml:"DatabaseID"`
+	Region        string `xml:"Region"` // e.g., "Japan", "North America"
+}
+
+// GameImage is an image of a game
+type GameImage struct {
+	DatabaseID int64  `xml:"DatabaseID"`
+	FileName   string `xml:"FileName"`
+	Type       string `xml:"Type"`   // e.g., "Box - Front", "Screenshot - Gameplay"
+	Region     string `xml:"Region"` // e.g., "North America"; empty for all regions
+	CRC32      string `xml:"CRC32"`
+}
+
+// URL returns the download URL of the image.
+func (i *GameImage) URL() string {
+	return ImageBaseURL + i.FileName
+}
+
+// Platform is a platform record
+type Platform struct {
+	Name           string `xml:"Name"`
+	Emulated       bool   `xml:"Emulated"`
+	ReleaseDate    string `xml:"ReleaseDate"`
+	Developer      string `xml:"Developer"`
+	Manufacturer   string `xml:"Manufacturer"`
+	Cpu            string `xml:"Cpu"`
+	Memory         string `xml:"Memory"`
+	Graphics       string `xml:"Graphics"`
+	Sound          string `xml:"Sound"`
+	Display        string `xml:"Display"`
+	Media          string `xml:"Media"`
+	MaxControllers string `xml:"MaxControllers"`
+	Notes          string `xml:"Notes"`
+	Category       string `xml:"Category"`
+	UseMameFiles   bool   `xml:"UseMameFiles"`
+}
+
+// Emulator is an emulator record
+type Emulator struct {
+	Name                     string `xml:"Name"`
+	CommandLine              string `xml:"CommandLine"`
+	ApplicableFileExtensions string `xml:"ApplicableFileExtensions"`
+	URL                      string `xml:"URL"`
+	BinaryFileName           string `xml:"BinaryFileName"`
+	NoQuotes                 bool   `xml:"NoQuotes"`
+	NoSpace                  bool   `xml:"NoSpace"`
+	HideConsole              bool   `xml:"HideConsole"`
+	FileNameOnly             bool   `xml:"FileNameOnly"`
+	AutoExtract              bool   `xml:"AutoExtract"`
+}
+
+// Parse reads and indexes a Metadata.xml file
+func Parse(path string) (*Database, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open LaunchBox metadata: %w", err)
+	}
+	defer f.Close()
+
+	return ParseReader(f)
+}
+
+// ParseReader reads and indexes Metadata.xml from a reader
+func ParseReader(r io.Reader) (*Database, error) {
+	decoder := xml.NewDecoder(r)
+	db := &Database{}
+	var altNames []GameAlternateName
+	var images []GameImage
+	foundRoot := false
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse LaunchBox metadata: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		var v any
+		switch start.Name.Local {
+		case "LaunchBox":
+			foundRoot = true
+			continue
+		case "Game":
+			db.Games = append(db.Games, Game{})
+			v = &db.Games[len(db.Games)-1]
+		case "GameAlternateName":
+			altNames = append(altNames, GameAlternateName{})
+			v = &altNames[len(altNames)-1]
+		case "GameImage":
+			images = append(images, GameImage{})
+			v = &images[len(images)-1]
+		case "Platform":
+			db.Platforms = append(db.Platforms, Platform{})
+			v = &db.Platforms[len(db.Platforms)-1]
+		case "Emulator":
+			db.Emulators = append(db.Emulators, Emulator{})
+			v = &db.Emulators[len(db.Emulators)-1]
+		default:
+			if err := decoder.Skip(); err != nil {
+				return nil, fmt.Errorf("failed to parse LaunchBox metadata: %w", err)
+			}
+			continue
+		}
+
+		if err := decoder.DecodeElement(v, &start); err != nil {
+			return nil, fmt.Errorf("failed to parse LaunchBox %s: %w", start.Name.Local, err)
+		}
+	}
+
+	if !foundRoot {
+		return nil, fmt.Errorf("failed to parse LaunchBox metadata: no <LaunchBox> element")
+	}
+
+	db.index(altNames, images)
+	return db, nil
+}
@@ -0,0 +1,138 @@
+package launchbox
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func loadTestDatabase(t *testing.T) *Database {
+	t.Helper()
+	db, err := Parse(filepath.Join("testdata", "Metadata.xml"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return db
+}
+
+func TestParse(t *testing.T) {
+	db := loadTestDatabase(t)
+
+	if len(db.Games) != 3 || len(db.Platforms) != 1 || len(db.Emulators) != 1 {
+		t.Fatalf("got %d games, %d platforms, %d emulators, want 3, 1, 1", len(db.Games), len(db.Platforms), len(db.Emulators))
+	}
+
+	sonic := db.Game(1001)
+	if sonic == nil {
+		t.Fatal("Game(1001) = nil")
+	}
+	if sonic.Name != "Sonic the Hedgehog" || sonic.Platform != "Sega Genesis" {
+		t.Errorf("game = %q (%q)", sonic.Name, sonic.Platform)
+	}
+	if sonic.CommunityRating != 4.2 || sonic.MaxPlayers != 1 || sonic.Developer != "Sonic Team" {
+		t.Errorf("game = %+v", sonic)
+	}
+	if got := sonic.GenreList(); len(got) != 2 || got[0] != "Platform" || got[1] != "Action" {
+		t.Errorf("GenreList() = %v, want [Platform Action]", got)
+	}
+	if sonic.Date() != "1991-06-23" {
+		t.Errorf("Date() = %q, want %q", sonic.Date(), "1991-06-23")
+	}
+	if len(sonic.Images) != 2 {
+		t.Fatalf("len(Images) = %d, want 2", len(sonic.Images))
+	}
+	if img := sonic.Images[0]; img.Type != "Box - Front" || img.Region != "North America" || img.URL() != ImageBaseURL+"a1b2c3d4-0001.jpg" {
+		t.Errorf("Images[0] = %+v", img)
+	}
+
+	zelda := db.Game(1002)
+	if zelda.Date() != "1991" {
+		t.Errorf("Date() = %q, want %q", zelda.Date(), "1991")
+	}
+	if len(zelda.AlternateNames) != 1 || zelda.AlternateNames[0].Region != "Japan" {
+		t.Errorf("AlternateNames = %+v", zelda.AlternateNames)
+	}
+
+	if p := db.Platform("sega genesis"); p == nil || p.Category != "Consoles" || !p.Emulated {
+		t.Errorf("Platform() = %+v", p)
+	}
+	if e := db.Emulators[0]; e.Name != "RetroArch" || !e.HideConsole || e.ApplicableFileExtensions != "md,gen,bin" {
+		t.Errorf("Emulator = %+v", e)
+	}
+	if db.Game(9999) != nil {
+		t.Error("Game(9999) != nil for an orphaned image")
+	}
+}
+
+func TestParseReader_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		xml  string
+	}{
+		{"empty", ""},
+		{"wrong root", `<datafile><game name="x"/></datafile>`},
+		{"malformed", `<LaunchBox><Game><Name>x</Game></LaunchBox>`},
+		{"bad number", `<LaunchBox><Game><DatabaseID>abc</DatabaseID></Game></LaunchBox>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseReader(strings.NewReader(tt.xml)); err == nil {
+				t.Error("ParseReader() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestDatabase_Lookup(t *testing.T) {
+	db := loadTestDatabase(t)
+
+	tests := []struct {
+		name     string
+		platform string
+		title    string
+		wantID   int64
+	}{
+		{"exact", "Sega Genesis", "Sonic the Hedgehog", 1001},
+		{"no-intro name", "sega genesis", "Sonic The Hedgehog (USA, Europe)", 1001},
+		{"other platform", "Sega Master System", "Sonic the Hedgehog (Europe, Brazil)", 1003},
+		{"trailing article", "Super Nintendo Entertainment System", "Legend of Zelda, The - A Link to the Past (USA)", 1002},
+		{"alternate name", "Super Nintendo Entertainment System", "Zelda no Densetsu - Kamigami no Triforce (Japan)", 1002},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			games := db.Lookup(tt.platform, tt.title)
+			if len(games) != 1 {
+				t.Fatalf("Lookup() returned %d games, want 1", len(games))
+			}
+			if games[0].DatabaseID != tt.wantID {
+				t.Errorf("DatabaseID = %d, want %d", games[0].DatabaseID, tt.wantID)
+			}
+		})
+	}
+
+	if games := db.Lookup("Nintendo 64", "Sonic the Hedgehog"); len(games) != 0 {
+		t.Errorf("Lookup() on wrong platform = %d games, want 0", len(games))
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Sonic The Hedgehog (USA, Europe)", "sonicthehedgehog"},
+		{"Legend of Zelda, The (USA) [!]", "thelegendofzelda"},
+		{"Legend of Zelda, The - A Link to the Past", "thelegendofzeldaalinktothepast"},
+		{"Mario & Luigi", "marioandluigi"},
+		{"Pokémon Red", "pokémonred"},
+		{"(Prototype)", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeName(tt.name); got != tt.want {
+			t.Errorf("NormalizeName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package launchbox
+
+import (
+	"crypto/rand"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A LaunchBox install keeps its games in one XML file per platform, with
+// images and videos in folders per platform and type, named after the
+// game's title:
+//
+//	Data/Platforms/<platform>.xml
+//	Images/<platform>/Box - Front/<title>-01.png
+//	Videos/<platform>/<title>-01.mp4
+//
+// Game records have dozens of fields, most of them play statistics and
+// settings LaunchBox maintains. Only the fields filled in from scraped
+// metadata are modeled; the others, and top-level records other than games
+// (AdditionalApplication, AlternateName, CustomField, ...), are kept as-is
+// when a file is read and written back.
+
+// Image types (folder names under Images/<platform>)
+const (
+	ImageBoxFront        = "Box - Front"
+	ImageBoxBack         = "Box - Back"
+	ImageBox3D           = "Box - 3D"
+	ImageCartFront       = "Cart - Front"
+	ImageClearLogo       = "Clear Logo"
+	ImageFanart          = "Fanart - Background"
+	ImageScreenshot      = "Screenshot - Gameplay"
+	ImageScreenshotTitle = "Screenshot - Game Title"
+)
+
+// PlatformFile is a platform XML file (Data/Platforms/<platform>.xml)
+type PlatformFile struct {
+	XMLName xml.Name       `xml:"LaunchBox"`
+	Games   []PlatformGame `xml:"Game"`
+	Other   []Element      `xml:",any"` // Records other than games
+}
+
+// PlatformGame is a game in a platform XML file
+type PlatformGame struct {
+	ID              string    `xml:"ID"` // GUID
+	Title           string    `xml:"Title"`
+	ApplicationPath string    `xml:"ApplicationPath"`
+	Platform        string    `xml:"Platform"`
+	Developer       string    `xml:"Developer,omitempty"`
+	Publisher       string    `xml:"Publisher,omitempty"`
+	ReleaseDate     string    `xml:"ReleaseDate,omitempty"` // e.g., "1991-06-23T00:00:00-07:00"
+	Genre           string    `xml:"Genre,omitempty"`       // Semicolon-separated
+	Notes           string    `xml:"Notes,omitempty"`
+	MaxPlayers      int       `xml:"MaxPlayers,omitempty"`
+	Other           []Element `xml:",any"` // Fields not modeled here
+}
+
+// Element is an XML element kept verbatim
+type Element struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Content string     `xml:",innerxml"`
+}
+
+// NewID returns a new random game ID.
+func NewID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // Version 4
+	b[8] = b[8]&0x3f | 0x80 // Variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// PlatformFilePath returns the path of a platform's XML file in a LaunchBox
+// folder.
+func PlatformFilePath(launchBoxDir, platform string) string {
+	return filepath.Join(launchBoxDir, "Data", "Platforms", platform+".xml")
+}
+
+// ImagePath returns the path of a game's first image of a type in a
+// LaunchBox folder. ext includes the dot.
+func ImagePath(launchBoxDir, platform, imageType, title, ext string) string {
+	return filepath.Join(launchBoxDir, "Images", platform, imageType, MediaFileName(title)+"-01"+ext)
+}
+
+// VideoPath returns the path of a game's video in a LaunchBox folder. ext
+// includes the dot.
+func VideoPath(launchBoxDir, platform, title, ext string) string {
+	return filepath.Join(launchBoxDir, "Videos", platform, MediaFileName(title)+"-01"+ext)
+}
+
+// mediaFileReplacer replaces the characters LaunchBox doesn't allow in
+// media file names
+var mediaFileReplacer = strings.NewReplacer(
+	":", "_", "'", "_", "/", "_", "\\", "_", "?", "_",
+	"*", "_", "\"", "_", "<", "_", ">", "_", "|", "_",
+)
+
+// MediaFileName returns the name LaunchBox gives a title's media files,
+// without the number suffix and extension.
+func MediaFileName(title string) string {
+	return mediaFileReplacer.Replace(title)
+}
+
+// ReadPlatformFile reads a platform XML file.
+func ReadPlatformFile(path string) (*PlatformFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read platform file: %w", err)
+	}
+
+	var pf PlatformFile
+	if err := xml.Unmarshal(data, &pf); err != nil {
+		return nil, fmt.Errorf("failed to parse platform file: %w", err)
+	}
+	return &pf, nil
+}
+
+// WritePlatformFile writes a platform XML file.
+func WritePlatformFile(path string, pf *PlatformFile) error {
+	data, err := xml.MarshalIndent(pf, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal platform file: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create platform directory: %w", err)
+	}
+	data = append([]byte("<?xml version=\"1.0\" standalone=\"yes\"?>\n"), append(data, '\n')...)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write platform file: %w", err)
+	}
+	return nil
+}
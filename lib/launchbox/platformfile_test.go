@@ -0,0 +1,64 @@
+package launchbox
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestReadWritePlatformFile(t *testing.T) {
+	pf, err := ReadPlatformFile(filepath.Join("testdata", "Nintendo Game Boy.xml"))
+	if err != nil {
+		t.Fatalf("ReadPlatformFile() error = %v", err)
+	}
+
+	if len(pf.Games) != 1 || len(pf.Other) != 1 {
+		t.Fatalf("got %d games, %d other records, want 1, 1", len(pf.Games), len(pf.Other))
+	}
+	tetris := pf.Games[0]
+	if tetris.ID != "3f8e8a3c-52a1-4d0b-9a5e-0c6f7d1e2b4a" || tetris.Title != "Tetris" || tetris.MaxPlayers != 2 {
+		t.Errorf("game = %+v", tetris)
+	}
+	if len(tetris.Other) != 2 || tetris.Other[0].XMLName.Local != "Favorite" || tetris.Other[1].Content != "12" {
+		t.Errorf("unmodeled fields = %+v, want Favorite and PlayCount", tetris.Other)
+	}
+
+	// Unmodeled fields and records survive a roundtrip
+	path := filepath.Join(t.TempDir(), "Data", "Platforms", "Nintendo Game Boy.xml")
+	if err := WritePlatformFile(path, pf); err != nil {
+		t.Fatalf("WritePlatformFile() error = %v", err)
+	}
+	got, err := ReadPlatformFile(path)
+	if err != nil {
+		t.Fatalf("ReadPlatformFile() error = %v", err)
+	}
+	if len(got.Games) != 1 || len(got.Games[0].Other) != 2 || len(got.Other) != 1 || got.Other[0].XMLName.Local != "AdditionalApplication" {
+		t.Errorf("roundtrip = %+v", got)
+	}
+}
+
+func TestPaths(t *testing.T) {
+	dir := "LaunchBox"
+	platform := "Nintendo Game Boy"
+
+	if got, want := PlatformFilePath(dir, platform), filepath.Join(dir, "Data", "Platforms", "Nintendo Game Boy.xml"); got != want {
+		t.Errorf("PlatformFilePath() = %q, want %q", got, want)
+	}
+	if got, want := ImagePath(dir, platform, ImageBoxFront, "Zelda: Link's Awakening", ".png"),
+		filepath.Join(dir, "Images", platform, "Box - Front", "Zelda_ Link_s Awakening-01.png"); got != want {
+		t.Errorf("ImagePath() = %q, want %q", got, want)
+	}
+	if got, want := VideoPath(dir, platform, "Tetris", ".mp4"), filepath.Join(dir, "Videos", platform, "Tetris-01.mp4"); got != want {
+		t.Errorf("VideoPath() = %q, want %q", got, want)
+	}
+}
+
+func TestNewID(t *testing.T) {
+	id := NewID()
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(id) {
+		t.Errorf("NewID() = %q, want a version 4 GUID", id)
+	}
+	if NewID() == id {
+		t.Error("NewID() returned the same ID twice")
+	}
+}
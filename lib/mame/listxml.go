@@ -0,0 +1,273 @@
+// Package mame parses MAME machine lists (-listxml output) and identifies
+// arcade ROM sets against them.
+package mame
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sargunv/rom-tools/lib/datfile"
+)
+
+// MAME -listxml format reference:
+// https://docs.mamedev.org/commandline/commandline-all.html#mame-commandline-listxml
+//
+// The output is large (hundreds of MB), so machines are decoded one at a time.
+// Only the elements needed for set identification are kept.
+
+// DriverStatus is the emulation status reported by a machine's driver.
+type DriverStatus string
+
+const (
+	DriverStatusUnspecified DriverStatus = ""
+	DriverStatusGood        DriverStatus = "good"
+	DriverStatusImperfect   DriverStatus = "imperfect"
+	DriverStatusPreliminary DriverStatus = "preliminary"
+)
+
+// MachineList represents a parsed -listxml document
+type MachineList struct {
+	Build    string
+	Machines []Machine
+}
+
+// Machine represents a machine (game, BIOS or device) in the list
+type Machine struct {
+	Name         string
+	SourceFile   string
+	CloneOf      string
+	RomOf        string
+	SampleOf     string
+	IsBIOS       bool
+	IsDevice     bool
+	IsMechanical bool
+	Runnable     bool
+
+	Description  string
+	Year         string
+	Manufacturer string
+
+	BIOSSets   []datfile.BIOSSet
+	ROMs       []ROM
+	Disks      []Disk
+	DeviceRefs []string
+	Driver     *Driver
+}
+
+func (m *Machine) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type rawDeviceRef struct {
+		Name string `xml:"name,attr"`
+	}
+	type rawMachine struct {
+		Name         string `xml:"name,attr"`
+		SourceFile   string `xml:"sourcefile,attr"`
+		CloneOf      string `xml:"cloneof,attr"`
+		RomOf        string `xml:"romof,attr"`
+		SampleOf     string `xml:"sampleof,attr"`
+		IsBIOS       string `xml:"isbios,attr"`
+		IsDevice     string `xml:"isdevice,attr"`
+		IsMechanical string `xml:"ismechanical,attr"`
+		Runnable     string `xml:"runnable,attr"`
+
+		Description  string            `xml:"description"`
+		Year         string            `xml:"year"`
+		Manufacturer string            `xml:"manufacturer"`
+		BIOSSets     []datfile.BIOSSet `xml:"biosset"`
+		ROMs         []ROM             `xml:"rom"`
+		Disks        []Disk            `xml:"disk"`
+		DeviceRefs   []rawDeviceRef    `xml:"device_ref"`
+		Driver       *Driver           `xml:"driver"`
+	}
+	var raw rawMachine
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	m.Name = raw.Name
+	m.SourceFile = raw.SourceFile
+	m.CloneOf = raw.CloneOf
+	m.RomOf = raw.RomOf
+	m.SampleOf = raw.SampleOf
+	m.IsBIOS = parseBool(raw.IsBIOS)
+	m.IsDevice = parseBool(raw.IsDevice)
+	m.IsMechanical = parseBool(raw.IsMechanical)
+	m.Runnable = raw.Runnable == "" || parseBool(raw.Runnable) // DTD default is "yes"
+	m.Description = raw.Description
+	m.Year = raw.Year
+	m.Manufacturer = raw.Manufacturer
+	m.BIOSSets = raw.BIOSSets
+	m.ROMs = raw.ROMs
+	m.Disks = raw.Disks
+	m.Driver = raw.Driver
+
+	m.DeviceRefs = make([]string, 0, len(raw.DeviceRefs))
+	for _, ref := range raw.DeviceRefs {
+		m.DeviceRefs = append(m.DeviceRefs, ref.Name)
+	}
+
+	return nil
+}
+
+// ROM represents a ROM entry of a machine
+type ROM struct {
+	Name     string
+	BIOS     string // BIOS set this ROM belongs to, if any
+	Size     int64
+	CRC      string
+	SHA1     string
+	Merge    string // Name of the ROM in the parent or BIOS set, if inherited
+	Region   string
+	Offset   string
+	Status   datfile.DumpStatus
+	Optional bool
+}
+
+func (r *ROM) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type rawROM struct {
+		Name     string `xml:"name,attr"`
+		BIOS     string `xml:"bios,attr"`
+		Size     string `xml:"size,attr"`
+		CRC      string `xml:"crc,attr"`
+		SHA1     string `xml:"sha1,attr"`
+		Merge    string `xml:"merge,attr"`
+		Region   string `xml:"region,attr"`
+		Offset   string `xml:"offset,attr"`
+		Status   string `xml:"status,attr"`
+		Optional string `xml:"optional,attr"`
+	}
+	var raw rawROM
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	r.Name = raw.Name
+	r.BIOS = raw.BIOS
+	r.Size, _ = strconv.ParseInt(raw.Size, 10, 64)
+	r.CRC = strings.ToLower(raw.CRC)
+	r.SHA1 = strings.ToLower(raw.SHA1)
+	r.Merge = raw.Merge
+	r.Region = raw.Region
+	r.Offset = raw.Offset
+	r.Status = datfile.DumpStatus(raw.Status)
+	r.Optional = parseBool(raw.Optional)
+
+	return nil
+}
+
+// Disk represents a CHD disk entry of a machine
+type Disk struct {
+	Name     string
+	SHA1     string
+	Merge    string
+	Region   string
+	Index    int
+	Writable bool
+	Status   datfile.DumpStatus
+	Optional bool
+}
+
+func (disk *Disk) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type rawDisk struct {
+		Name      string `xml:"name,attr"`
+		SHA1      string `xml:"sha1,attr"`
+		Merge     string `xml:"merge,attr"`
+		Region    string `xml:"region,attr"`
+		Index     string `xml:"index,attr"`
+		Writable  string `xml:"writable,attr"`
+		Writeable string `xml:"writeable,attr"` // Software list spelling
+		Status    string `xml:"status,attr"`
+		Optional  string `xml:"optional,attr"`
+	}
+	var raw rawDisk
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	disk.Name = raw.Name
+	disk.SHA1 = strings.ToLower(raw.SHA1)
+	disk.Merge = raw.Merge
+	disk.Region = raw.Region
+	disk.Index, _ = strconv.Atoi(raw.Index)
+	disk.Writable = parseBool(raw.Writable) || parseBool(raw.Writeable)
+	disk.Status = datfile.DumpStatus(raw.Status)
+	disk.Optional = parseBool(raw.Optional)
+
+	return nil
+}
+
+// Driver contains the emulation status of a machine
+type Driver struct {
+	Status    DriverStatus `xml:"status,attr"`
+	Emulation DriverStatus `xml:"emulation,attr"`
+	Cocktail  DriverStatus `xml:"cocktail,attr"`
+	SaveState string       `xml:"savestate,attr"`
+}
+
+// Parse reads and parses a MAME -listxml file
+func Parse(path string) (*MachineList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open MAME XML file: %w", err)
+	}
+	defer f.Close()
+
+	return ParseReader(f)
+}
+
+// ParseReader parses MAME -listxml output from a reader.
+// Both the <mame> root of -listxml and the <datafile> root of MAME-derived
+// DATs are accepted, with <machine> or <game> elements.
+func ParseReader(r io.Reader) (*MachineList, error) {
+	decoder := xml.NewDecoder(r)
+	list := &MachineList{}
+	foundRoot := false
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse MAME XML file: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "mame", "datafile":
+			foundRoot = true
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "build" {
+					list.Build = attr.Value
+				}
+			}
+		case "machine", "game":
+			var m Machine
+			if err := decoder.DecodeElement(&m, &start); err != nil {
+				return nil, fmt.Errorf("failed to parse MAME machine: %w", err)
+			}
+			list.Machines = append(list.Machines, m)
+		default:
+			if err := decoder.Skip(); err != nil {
+				return nil, fmt.Errorf("failed to parse MAME XML file: %w", err)
+			}
+		}
+	}
+
+	if !foundRoot {
+		return nil, fmt.Errorf("failed to parse MAME XML file: no <mame> or <datafile> element")
+	}
+	return list, nil
+}
+
+func parseBool(s string) bool {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return s == "yes" || s == "true" || s == "1"
+}
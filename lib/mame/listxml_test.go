@@ -0,0 +1,122 @@
+package mame
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/datfile"
+)
+
+func TestParse(t *testing.T) {
+	list, err := Parse(filepath.Join("testdata", "listxml.xml"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if list.Build != "0.280 (mame0280)" {
+		t.Errorf("Build = %q, want %q", list.Build, "0.280 (mame0280)")
+	}
+	if len(list.Machines) != 5 {
+		t.Fatalf("len(Machines) = %d, want 5", len(list.Machines))
+	}
+
+	bios := list.Machines[0]
+	if bios.Name != "neogeo" || !bios.IsBIOS {
+		t.Errorf("Machines[0] = %q (IsBIOS %v), want neogeo BIOS", bios.Name, bios.IsBIOS)
+	}
+	if len(bios.BIOSSets) != 2 || bios.BIOSSets[0].Name != "euro" || !bios.BIOSSets[0].Default {
+		t.Errorf("BIOSSets = %+v, want default euro set first", bios.BIOSSets)
+	}
+	if bios.ROMs[0].CRC != "9036d879" {
+		t.Errorf("ROMs[0].CRC = %q, want lowercase %q", bios.ROMs[0].CRC, "9036d879")
+	}
+	if bios.ROMs[0].BIOS != "euro" {
+		t.Errorf("ROMs[0].BIOS = %q, want %q", bios.ROMs[0].BIOS, "euro")
+	}
+
+	device := list.Machines[1]
+	if !device.IsDevice || device.Runnable {
+		t.Errorf("ng_memcard IsDevice = %v, Runnable = %v, want true, false", device.IsDevice, device.Runnable)
+	}
+
+	parent := list.Machines[2]
+	if parent.RomOf != "neogeo" || parent.CloneOf != "" {
+		t.Errorf("mslug RomOf = %q, CloneOf = %q, want neogeo, empty", parent.RomOf, parent.CloneOf)
+	}
+	if !parent.Runnable {
+		t.Error("mslug Runnable = false, want true")
+	}
+	if parent.Year != "1996" || parent.Manufacturer != "Nazca" {
+		t.Errorf("mslug Year = %q, Manufacturer = %q", parent.Year, parent.Manufacturer)
+	}
+	if len(parent.ROMs) != 5 {
+		t.Fatalf("len(mslug ROMs) = %d, want 5", len(parent.ROMs))
+	}
+	if parent.ROMs[1].Merge != "000-lo.lo" {
+		t.Errorf("ROMs[1].Merge = %q, want %q", parent.ROMs[1].Merge, "000-lo.lo")
+	}
+	p1 := parent.ROMs[2]
+	if p1.Size != 2097152 || p1.Region != "cslot1:maincpu" || p1.Offset != "100000" {
+		t.Errorf("201-p1.p1 = %+v", p1)
+	}
+	if parent.ROMs[4].Status != datfile.DumpStatusNoDump {
+		t.Errorf("ROMs[4].Status = %q, want %q", parent.ROMs[4].Status, datfile.DumpStatusNoDump)
+	}
+	if len(parent.DeviceRefs) != 1 || parent.DeviceRefs[0] != "ng_memcard" {
+		t.Errorf("DeviceRefs = %v, want [ng_memcard]", parent.DeviceRefs)
+	}
+	if parent.Driver == nil {
+		t.Fatal("Driver = nil")
+	}
+	if parent.Driver.Status != DriverStatusGood || parent.Driver.Cocktail != DriverStatusPreliminary {
+		t.Errorf("Driver = %+v", parent.Driver)
+	}
+
+	clone := list.Machines[3]
+	if clone.CloneOf != "mslug" || clone.Driver.Status != DriverStatusImperfect {
+		t.Errorf("mslugh CloneOf = %q, Driver.Status = %q", clone.CloneOf, clone.Driver.Status)
+	}
+
+	kinst := list.Machines[4]
+	if len(kinst.Disks) != 1 {
+		t.Fatalf("len(kinst Disks) = %d, want 1", len(kinst.Disks))
+	}
+	disk := kinst.Disks[0]
+	if disk.Name != "kinst" || disk.Region != "ata:0:hdd" || !disk.Writable {
+		t.Errorf("Disk = %+v", disk)
+	}
+}
+
+func TestParseReader_DatafileRoot(t *testing.T) {
+	xml := `<datafile><game name="pacman"><description>Pac-Man</description><rom name="pacman.6e" size="4096" crc="c1e6ab10"/></game></datafile>`
+	list, err := ParseReader(strings.NewReader(xml))
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v", err)
+	}
+	if len(list.Machines) != 1 || list.Machines[0].Name != "pacman" {
+		t.Fatalf("Machines = %+v, want pacman", list.Machines)
+	}
+	if list.Machines[0].ROMs[0].CRC != "c1e6ab10" {
+		t.Errorf("CRC = %q, want %q", list.Machines[0].ROMs[0].CRC, "c1e6ab10")
+	}
+}
+
+func TestParseReader_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		xml  string
+	}{
+		{"empty", ""},
+		{"wrong root", `<softwarelist name="nes"></softwarelist>`},
+		{"malformed", `<mame><machine name="pacman"><rom></mame>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseReader(strings.NewReader(tt.xml)); err == nil {
+				t.Error("ParseReader() error = nil, want error")
+			}
+		})
+	}
+}
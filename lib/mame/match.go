@@ -0,0 +1,328 @@
+package mame
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/datfile"
+)
+
+// SetType describes how the ROMs of related machines are distributed
+// across ROM set archives.
+type SetType string
+
+const (
+	// SetTypeSplit sets hold only the ROMs unique to a machine. ROMs shared
+	// with the parent, BIOS or devices live in their own sets.
+	SetTypeSplit SetType = "split"
+	// SetTypeNonMerged sets hold every ROM a machine needs, including parent,
+	// BIOS and device ROMs.
+	SetTypeNonMerged SetType = "non-merged"
+	// SetTypeMerged sets hold a parent machine together with all of its clones.
+	SetTypeMerged SetType = "merged"
+)
+
+// setTypes lists set types in order of preference when several match equally.
+var setTypes = []SetType{SetTypeSplit, SetTypeNonMerged, SetTypeMerged}
+
+// File is a file in a ROM set archive.
+type File struct {
+	Name string
+	CRC  string // Lowercase hex CRC32
+}
+
+// BadROM is a ROM present under its expected name but with the wrong CRC32.
+type BadROM struct {
+	Name        string `json:"name"`
+	ExpectedCRC string `json:"expected_crc"`
+	ActualCRC   string `json:"actual_crc"`
+}
+
+// Match is the result of identifying a ROM set against a machine list.
+type Match struct {
+	// Name is the short name of the matched machine.
+	Name string `json:"name"`
+	// Description is the full title of the machine.
+	Description string `json:"description"`
+	// Year is the release year.
+	Year string `json:"year,omitempty"`
+	// Manufacturer is the machine manufacturer.
+	Manufacturer string `json:"manufacturer,omitempty"`
+	// CloneOf is the parent machine, if the machine is a clone.
+	CloneOf string `json:"clone_of,omitempty"`
+	// SetType is the set layout that best explains the files.
+	SetType SetType `json:"set_type"`
+	// Missing lists required ROMs that are not present.
+	Missing []string `json:"missing,omitempty"`
+	// Extra lists files that are not part of the set.
+	Extra []string `json:"extra,omitempty"`
+	// Bad lists ROMs present under their expected name with the wrong CRC32.
+	Bad []BadROM `json:"bad,omitempty"`
+}
+
+// Complete reports whether the set has every required ROM and nothing else.
+func (m *Match) Complete() bool {
+	return len(m.Missing) == 0 && len(m.Extra) == 0 && len(m.Bad) == 0
+}
+
+// GamePlatform implements core.GameInfo.
+func (m *Match) GamePlatform() core.Platform { return core.PlatformArcade }
+
+// GameTitle implements core.GameInfo.
+func (m *Match) GameTitle() string { return m.Description }
+
+// GameSerial implements core.GameInfo. Arcade machines have no serial.
+func (m *Match) GameSerial() string { return "" }
+
+// GameRegions implements core.GameInfo. Regions are not recorded by MAME.
+func (m *Match) GameRegions() []core.Region { return nil }
+
+// Index provides machine lookup and ROM set identification for a machine list.
+type Index struct {
+	machines map[string]*Machine
+	clones   map[string][]*Machine // Parent name -> clones
+	byCRC    map[string][]*Machine // ROM CRC32 -> machines with that ROM
+}
+
+// NewIndex indexes the machines of a machine list.
+func NewIndex(list *MachineList) *Index {
+	idx := &Index{
+		machines: make(map[string]*Machine, len(list.Machines)),
+		clones:   make(map[string][]*Machine),
+		byCRC:    make(map[string][]*Machine),
+	}
+
+	for i := range list.Machines {
+		m := &list.Machines[i]
+		idx.machines[m.Name] = m
+		if m.CloneOf != "" {
+			idx.clones[m.CloneOf] = append(idx.clones[m.CloneOf], m)
+		}
+		for _, rom := range m.ROMs {
+			if rom.CRC == "" {
+				continue
+			}
+			if machines := idx.byCRC[rom.CRC]; len(machines) == 0 || machines[len(machines)-1] != m {
+				idx.byCRC[rom.CRC] = append(machines, m)
+			}
+		}
+	}
+
+	return idx
+}
+
+// Machine returns the machine with the given short name, or nil.
+func (idx *Index) Machine(name string) *Machine {
+	return idx.machines[name]
+}
+
+// Match identifies a ROM set from the CRC32s of its files. name is the set
+// name (usually the archive name without extension) and is used to break
+// ties between equally good matches. Returns nil if no machine matches.
+func (idx *Index) Match(name string, files []File) *Match {
+	present := make(map[string]bool, len(files))
+	for _, f := range files {
+		present[strings.ToLower(f.CRC)] = true
+	}
+
+	var best *candidate
+	for _, m := range idx.candidates(name, files) {
+		for _, setType := range setTypes {
+			if setType == SetTypeMerged && m.CloneOf != "" {
+				continue
+			}
+			c := idx.evaluate(m, setType, files, present)
+			if c.matched == 0 {
+				continue
+			}
+			if best == nil || c.betterThan(best, name) {
+				best = c
+			}
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	return best.result(files)
+}
+
+// candidates returns the machines that own any of the files, plus their
+// parents for merged sets.
+func (idx *Index) candidates(name string, files []File) []*Machine {
+	seen := make(map[string]bool)
+	var result []*Machine
+	add := func(m *Machine) {
+		if m != nil && !seen[m.Name] {
+			seen[m.Name] = true
+			result = append(result, m)
+		}
+	}
+
+	add(idx.machines[name])
+	for _, f := range files {
+		for _, m := range idx.byCRC[strings.ToLower(f.CRC)] {
+			add(m)
+			add(idx.machines[m.CloneOf])
+		}
+	}
+	return result
+}
+
+// expectedROMs returns the ROMs a set of the given type must contain.
+// ROMs without a dump cannot be verified and are left out.
+func (idx *Index) expectedROMs(m *Machine, setType SetType) []ROM {
+	var roms []ROM
+	switch setType {
+	case SetTypeSplit:
+		roms = ownROMs(m)
+	case SetTypeNonMerged:
+		roms = dumpedROMs(m.ROMs)
+		seen := map[string]bool{m.Name: true}
+		for _, device := range idx.devices(m, seen) {
+			roms = append(roms, dumpedROMs(device.ROMs)...)
+		}
+	case SetTypeMerged:
+		roms = ownROMs(m)
+		for _, clone := range idx.clones[m.Name] {
+			roms = append(roms, ownROMs(clone)...)
+		}
+	}
+	return roms
+}
+
+// devices returns the machines referenced by m through device_ref, recursively.
+func (idx *Index) devices(m *Machine, seen map[string]bool) []*Machine {
+	var result []*Machine
+	for _, ref := range m.DeviceRefs {
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		if device := idx.machines[ref]; device != nil {
+			result = append(result, device)
+			result = append(result, idx.devices(device, seen)...)
+		}
+	}
+	return result
+}
+
+// ownROMs returns the dumped ROMs of a machine that are not inherited from
+// its parent or BIOS.
+func ownROMs(m *Machine) []ROM {
+	var roms []ROM
+	for _, rom := range dumpedROMs(m.ROMs) {
+		if rom.Merge == "" {
+			roms = append(roms, rom)
+		}
+	}
+	return roms
+}
+
+func dumpedROMs(roms []ROM) []ROM {
+	var result []ROM
+	for _, rom := range roms {
+		if rom.CRC != "" && rom.Status != datfile.DumpStatusNoDump {
+			result = append(result, rom)
+		}
+	}
+	return result
+}
+
+// candidate is a machine and set type evaluated against a set of files.
+type candidate struct {
+	machine  *Machine
+	setType  SetType
+	expected []ROM
+	matched  int // Files whose CRC32 is expected
+	missing  int // Required ROMs not present
+	extra    int // Files whose CRC32 is not expected
+}
+
+func (idx *Index) evaluate(m *Machine, setType SetType, files []File, present map[string]bool) *candidate {
+	c := &candidate{machine: m, setType: setType, expected: idx.expectedROMs(m, setType)}
+
+	expected := make(map[string]bool, len(c.expected))
+	for _, rom := range c.expected {
+		expected[rom.CRC] = true
+		if !rom.Optional && !present[rom.CRC] {
+			c.missing++
+		}
+	}
+	for _, f := range files {
+		if expected[strings.ToLower(f.CRC)] {
+			c.matched++
+		} else {
+			c.extra++
+		}
+	}
+	return c
+}
+
+// betterThan reports whether c explains the files better than other.
+// More matched files win, then fewer discrepancies, then the machine named
+// like the set, then the simplest set type.
+func (c *candidate) betterThan(other *candidate, name string) bool {
+	if c.matched != other.matched {
+		return c.matched > other.matched
+	}
+	if c.missing+c.extra != other.missing+other.extra {
+		return c.missing+c.extra < other.missing+other.extra
+	}
+	if (c.machine.Name == name) != (other.machine.Name == name) {
+		return c.machine.Name == name
+	}
+	return slices.Index(setTypes, c.setType) < slices.Index(setTypes, other.setType)
+}
+
+// result builds the match report. Files found under the name of a missing
+// ROM are reported as bad dumps instead of missing and extra.
+func (c *candidate) result(files []File) *Match {
+	m := c.machine
+	match := &Match{
+		Name:         m.Name,
+		Description:  m.Description,
+		Year:         m.Year,
+		Manufacturer: m.Manufacturer,
+		CloneOf:      m.CloneOf,
+		SetType:      c.setType,
+	}
+
+	expected := make(map[string]bool, len(c.expected))
+	for _, rom := range c.expected {
+		expected[rom.CRC] = true
+	}
+	present := make(map[string]bool, len(files))
+	extra := make(map[string]File)
+	for _, f := range files {
+		crc := strings.ToLower(f.CRC)
+		present[crc] = true
+		if !expected[crc] {
+			extra[strings.ToLower(path.Base(f.Name))] = f
+		}
+	}
+
+	reported := make(map[string]bool)
+	for _, rom := range c.expected {
+		if rom.Optional || present[rom.CRC] || reported[rom.Name] {
+			continue
+		}
+		reported[rom.Name] = true
+		if f, ok := extra[strings.ToLower(rom.Name)]; ok {
+			match.Bad = append(match.Bad, BadROM{Name: rom.Name, ExpectedCRC: rom.CRC, ActualCRC: strings.ToLower(f.CRC)})
+			delete(extra, strings.ToLower(rom.Name))
+			continue
+		}
+		match.Missing = append(match.Missing, rom.Name)
+	}
+
+	for _, f := range files {
+		if _, ok := extra[strings.ToLower(path.Base(f.Name))]; ok {
+			match.Extra = append(match.Extra, f.Name)
+		}
+	}
+
+	return match
+}
@@ -0,0 +1,208 @@
+package mame
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func loadTestIndex(t *testing.T) *Index {
+	t.Helper()
+	list, err := Parse(filepath.Join("testdata", "listxml.xml"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return NewIndex(list)
+}
+
+func TestIndex_Match(t *testing.T) {
+	idx := loadTestIndex(t)
+
+	tests := []struct {
+		name        string
+		set         string
+		files       []File
+		wantName    string
+		wantSetType SetType
+	}{
+		{
+			name: "split parent",
+			set:  "mslug",
+			files: []File{
+				{Name: "201-p1.p1", CRC: "08d8daa5"},
+				{Name: "201-s1.s1", CRC: "2f55958d"},
+			},
+			wantName:    "mslug",
+			wantSetType: SetTypeSplit,
+		},
+		{
+			name: "split clone",
+			set:  "mslugh",
+			files: []File{
+				{Name: "201-p1h.p1", CRC: "AABBCCDD"},
+			},
+			wantName:    "mslugh",
+			wantSetType: SetTypeSplit,
+		},
+		{
+			name: "non-merged parent with BIOS and device ROMs",
+			set:  "mslug",
+			files: []File{
+				{Name: "sp-s2.sp1", CRC: "9036d879"},
+				{Name: "000-lo.lo", CRC: "5a86cff2"},
+				{Name: "201-p1.p1", CRC: "08d8daa5"},
+				{Name: "201-s1.s1", CRC: "2f55958d"},
+				{Name: "memcard.bin", CRC: "11111111"},
+			},
+			wantName:    "mslug",
+			wantSetType: SetTypeNonMerged,
+		},
+		{
+			name: "merged parent with clones",
+			set:  "mslug",
+			files: []File{
+				{Name: "201-p1.p1", CRC: "08d8daa5"},
+				{Name: "201-s1.s1", CRC: "2f55958d"},
+				{Name: "mslugh/201-p1h.p1", CRC: "aabbccdd"},
+			},
+			wantName:    "mslug",
+			wantSetType: SetTypeMerged,
+		},
+		{
+			name: "renamed archive",
+			set:  "Metal Slug",
+			files: []File{
+				{Name: "201-p1.p1", CRC: "08d8daa5"},
+				{Name: "201-s1.s1", CRC: "2f55958d"},
+			},
+			wantName:    "mslug",
+			wantSetType: SetTypeSplit,
+		},
+		{
+			name: "BIOS",
+			set:  "neogeo",
+			files: []File{
+				{Name: "sp-s2.sp1", CRC: "9036d879"},
+				{Name: "sp-u2.sp1", CRC: "e72943de"},
+				{Name: "000-lo.lo", CRC: "5a86cff2"},
+			},
+			wantName:    "neogeo",
+			wantSetType: SetTypeSplit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := idx.Match(tt.set, tt.files)
+			if match == nil {
+				t.Fatal("Match() = nil")
+			}
+			if match.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", match.Name, tt.wantName)
+			}
+			if match.SetType != tt.wantSetType {
+				t.Errorf("SetType = %q, want %q", match.SetType, tt.wantSetType)
+			}
+			if !match.Complete() {
+				t.Errorf("Complete() = false (missing %v, extra %v, bad %v)", match.Missing, match.Extra, match.Bad)
+			}
+		})
+	}
+}
+
+func TestIndex_Match_Metadata(t *testing.T) {
+	idx := loadTestIndex(t)
+
+	match := idx.Match("mslugh", []File{{Name: "201-p1h.p1", CRC: "aabbccdd"}})
+	if match == nil {
+		t.Fatal("Match() = nil")
+	}
+	if match.Description != "Metal Slug - Super Vehicle-001 (hack)" {
+		t.Errorf("Description = %q", match.Description)
+	}
+	if match.GameTitle() != match.Description {
+		t.Errorf("GameTitle() = %q, want %q", match.GameTitle(), match.Description)
+	}
+	if match.Year != "1996" {
+		t.Errorf("Year = %q, want %q", match.Year, "1996")
+	}
+	if match.Manufacturer != "bootleg" {
+		t.Errorf("Manufacturer = %q, want %q", match.Manufacturer, "bootleg")
+	}
+	if match.CloneOf != "mslug" {
+		t.Errorf("CloneOf = %q, want %q", match.CloneOf, "mslug")
+	}
+}
+
+func TestIndex_Match_Incomplete(t *testing.T) {
+	idx := loadTestIndex(t)
+
+	t.Run("missing", func(t *testing.T) {
+		match := idx.Match("mslug", []File{{Name: "201-p1.p1", CRC: "08d8daa5"}})
+		if match == nil {
+			t.Fatal("Match() = nil")
+		}
+		if !slices.Equal(match.Missing, []string{"201-s1.s1"}) {
+			t.Errorf("Missing = %v, want [201-s1.s1]", match.Missing)
+		}
+		if match.Complete() {
+			t.Error("Complete() = true, want false")
+		}
+	})
+
+	t.Run("extra", func(t *testing.T) {
+		match := idx.Match("mslug", []File{
+			{Name: "201-p1.p1", CRC: "08d8daa5"},
+			{Name: "201-s1.s1", CRC: "2f55958d"},
+			{Name: "readme.txt", CRC: "01234567"},
+		})
+		if match == nil {
+			t.Fatal("Match() = nil")
+		}
+		if len(match.Missing) != 0 {
+			t.Errorf("Missing = %v, want none", match.Missing)
+		}
+		if !slices.Equal(match.Extra, []string{"readme.txt"}) {
+			t.Errorf("Extra = %v, want [readme.txt]", match.Extra)
+		}
+	})
+
+	t.Run("bad", func(t *testing.T) {
+		match := idx.Match("mslug", []File{
+			{Name: "201-p1.p1", CRC: "deadbeef"},
+			{Name: "201-s1.s1", CRC: "2f55958d"},
+		})
+		if match == nil {
+			t.Fatal("Match() = nil")
+		}
+		if match.Name != "mslug" {
+			t.Errorf("Name = %q, want %q", match.Name, "mslug")
+		}
+		want := []BadROM{{Name: "201-p1.p1", ExpectedCRC: "08d8daa5", ActualCRC: "deadbeef"}}
+		if !slices.Equal(match.Bad, want) {
+			t.Errorf("Bad = %v, want %v", match.Bad, want)
+		}
+		if len(match.Missing) != 0 || len(match.Extra) != 0 {
+			t.Errorf("Missing = %v, Extra = %v, want none", match.Missing, match.Extra)
+		}
+	})
+}
+
+func TestIndex_Match_NoMatch(t *testing.T) {
+	idx := loadTestIndex(t)
+
+	if match := idx.Match("unknown", []File{{Name: "a.bin", CRC: "01234567"}}); match != nil {
+		t.Errorf("Match() = %+v, want nil", match)
+	}
+}
+
+func TestIndex_Machine(t *testing.T) {
+	idx := loadTestIndex(t)
+
+	if m := idx.Machine("kinst"); m == nil || m.Description != "Killer Instinct (v1.5d)" {
+		t.Errorf("Machine(kinst) = %+v", m)
+	}
+	if m := idx.Machine("missing"); m != nil {
+		t.Errorf("Machine(missing) = %+v, want nil", m)
+	}
+}
@@ -0,0 +1,266 @@
+package mame
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sargunv/rom-tools/lib/datfile"
+)
+
+// MAME software list format reference:
+// https://docs.mamedev.org/usingmame/softwarelists.html
+// DTD: https://github.com/mamedev/mame/blob/master/hash/softwarelist.dtd
+//
+// Software lists (hash/*.xml in a MAME installation) describe cartridges,
+// disks and tapes for home computers and consoles. Each software item has one
+// or more parts (media), each holding data areas (ROM chips) or disk areas
+// (CHDs).
+
+// Supported is the emulation support level of a software item.
+type Supported string
+
+const (
+	SupportedYes     Supported = "yes"
+	SupportedPartial Supported = "partial"
+	SupportedNo      Supported = "no"
+)
+
+// SoftwareList represents a parsed software list
+type SoftwareList struct {
+	Name        string
+	Description string
+	Software    []Software
+}
+
+func (l *SoftwareList) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type rawSoftwareList struct {
+		Name        string     `xml:"name,attr"`
+		Description string     `xml:"description,attr"`
+		Software    []Software `xml:"software"`
+	}
+	if start.Name.Local != "softwarelist" {
+		return fmt.Errorf("unexpected root element <%s>", start.Name.Local)
+	}
+	var raw rawSoftwareList
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	l.Name = raw.Name
+	l.Description = raw.Description
+	l.Software = raw.Software
+
+	return nil
+}
+
+// Software represents a software item in a software list
+type Software struct {
+	Name      string
+	CloneOf   string
+	Supported Supported
+
+	Description string
+	Year        string
+	Publisher   string
+	Notes       string
+
+	Info        []Feature // Release information (serial, release date, developer, ...)
+	SharedFeats []Feature // Features shared by all parts
+	Parts       []Part
+}
+
+func (s *Software) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type rawSoftware struct {
+		Name        string    `xml:"name,attr"`
+		CloneOf     string    `xml:"cloneof,attr"`
+		Supported   string    `xml:"supported,attr"`
+		Description string    `xml:"description"`
+		Year        string    `xml:"year"`
+		Publisher   string    `xml:"publisher"`
+		Notes       string    `xml:"notes"`
+		Info        []Feature `xml:"info"`
+		SharedFeats []Feature `xml:"sharedfeat"`
+		Parts       []Part    `xml:"part"`
+	}
+	var raw rawSoftware
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	s.Name = raw.Name
+	s.CloneOf = raw.CloneOf
+	s.Supported = Supported(raw.Supported)
+	if s.Supported == "" {
+		s.Supported = SupportedYes // DTD default
+	}
+	s.Description = raw.Description
+	s.Year = raw.Year
+	s.Publisher = raw.Publisher
+	s.Notes = strings.TrimSpace(raw.Notes)
+	s.Info = raw.Info
+	s.SharedFeats = raw.SharedFeats
+	s.Parts = raw.Parts
+
+	return nil
+}
+
+// InfoValue returns the value of the named info element (e.g. "serial"),
+// or "" if absent.
+func (s *Software) InfoValue(name string) string {
+	for _, info := range s.Info {
+		if info.Name == name {
+			return info.Value
+		}
+	}
+	return ""
+}
+
+// Feature is a name/value pair attached to a software item or part
+type Feature struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// Part represents a single medium of a software item (a cartridge, a disk
+// side, a tape, ...)
+type Part struct {
+	Name      string
+	Interface string // Slot interface the part plugs into (e.g. "msx_cart")
+	Features  []Feature
+	DataAreas []DataArea
+	DiskAreas []DiskArea
+}
+
+func (p *Part) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type rawPart struct {
+		Name      string     `xml:"name,attr"`
+		Interface string     `xml:"interface,attr"`
+		Features  []Feature  `xml:"feature"`
+		DataAreas []DataArea `xml:"dataarea"`
+		DiskAreas []DiskArea `xml:"diskarea"`
+	}
+	var raw rawPart
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	p.Name = raw.Name
+	p.Interface = raw.Interface
+	p.Features = raw.Features
+	p.DataAreas = raw.DataAreas
+	p.DiskAreas = raw.DiskAreas
+
+	return nil
+}
+
+// DataArea represents a memory region of a part and the ROMs loaded into it
+type DataArea struct {
+	Name       string
+	Size       int64
+	Width      int    // Data bus width in bits (8, 16, 32 or 64)
+	Endianness string // "big" or "little"
+	ROMs       []SoftwareROM
+}
+
+func (a *DataArea) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type rawDataArea struct {
+		Name       string        `xml:"name,attr"`
+		Size       string        `xml:"size,attr"`
+		Width      string        `xml:"width,attr"`
+		Endianness string        `xml:"endianness,attr"`
+		ROMs       []SoftwareROM `xml:"rom"`
+	}
+	var raw rawDataArea
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	a.Name = raw.Name
+	a.Size, _ = strconv.ParseInt(raw.Size, 0, 64)
+	a.Width = 8 // DTD default
+	if raw.Width != "" {
+		a.Width, _ = strconv.Atoi(raw.Width)
+	}
+	a.Endianness = raw.Endianness
+	if a.Endianness == "" {
+		a.Endianness = "little" // DTD default
+	}
+	a.ROMs = raw.ROMs
+
+	return nil
+}
+
+// SoftwareROM represents a ROM entry of a data area. Entries with a LoadFlag
+// such as "continue" or "reload" describe how the previous ROM is loaded and
+// have no name or hashes of their own.
+type SoftwareROM struct {
+	Name     string
+	Size     int64
+	CRC      string
+	SHA1     string
+	Offset   int64 // Load offset within the data area
+	Value    string
+	Status   datfile.DumpStatus
+	LoadFlag string
+}
+
+func (r *SoftwareROM) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type rawSoftwareROM struct {
+		Name     string `xml:"name,attr"`
+		Size     string `xml:"size,attr"`
+		CRC      string `xml:"crc,attr"`
+		SHA1     string `xml:"sha1,attr"`
+		Offset   string `xml:"offset,attr"`
+		Value    string `xml:"value,attr"`
+		Status   string `xml:"status,attr"`
+		LoadFlag string `xml:"loadflag,attr"`
+	}
+	var raw rawSoftwareROM
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	r.Name = raw.Name
+	r.Size, _ = strconv.ParseInt(raw.Size, 0, 64)
+	r.CRC = strings.ToLower(raw.CRC)
+	r.SHA1 = strings.ToLower(raw.SHA1)
+	r.Offset, _ = strconv.ParseInt(raw.Offset, 0, 64)
+	r.Value = raw.Value
+	r.Status = datfile.DumpStatus(raw.Status)
+	r.LoadFlag = raw.LoadFlag
+
+	return nil
+}
+
+// DiskArea represents a disk region of a part and the CHDs loaded into it
+type DiskArea struct {
+	Name  string `xml:"name,attr"`
+	Disks []Disk `xml:"disk"`
+}
+
+// ParseSoftwareList reads and parses a MAME software list file
+func ParseSoftwareList(path string) (*SoftwareList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open software list: %w", err)
+	}
+	defer f.Close()
+
+	return ParseSoftwareListReader(f)
+}
+
+// ParseSoftwareListReader parses a MAME software list from a reader
+func ParseSoftwareListReader(r io.Reader) (*SoftwareList, error) {
+	var list SoftwareList
+	if err := xml.NewDecoder(r).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to parse software list: %w", err)
+	}
+	if list.Name == "" {
+		return nil, fmt.Errorf("failed to parse software list: missing list name")
+	}
+	return &list, nil
+}
@@ -0,0 +1,253 @@
+package mame
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/datfile"
+)
+
+func loadTestSoftwareList(t *testing.T) *SoftwareList {
+	t.Helper()
+	list, err := ParseSoftwareList(filepath.Join("testdata", "softlist.xml"))
+	if err != nil {
+		t.Fatalf("ParseSoftwareList() error = %v", err)
+	}
+	return list
+}
+
+func TestParseSoftwareList(t *testing.T) {
+	list := loadTestSoftwareList(t)
+
+	if list.Name != "msx1_cart" || list.Description != "MSX1 cartridges" {
+		t.Errorf("list = %q (%q), want msx1_cart (MSX1 cartridges)", list.Name, list.Description)
+	}
+	if len(list.Software) != 3 {
+		t.Fatalf("len(Software) = %d, want 3", len(list.Software))
+	}
+
+	goonies := list.Software[0]
+	if goonies.Description != "The Goonies (Euro, Japan)" || goonies.Year != "1986" || goonies.Publisher != "Konami" {
+		t.Errorf("goonies = %q, %q, %q", goonies.Description, goonies.Year, goonies.Publisher)
+	}
+	if goonies.Supported != SupportedYes {
+		t.Errorf("Supported = %q, want %q", goonies.Supported, SupportedYes)
+	}
+	if goonies.InfoValue("serial") != "RC-734" {
+		t.Errorf("InfoValue(serial) = %q, want %q", goonies.InfoValue("serial"), "RC-734")
+	}
+	if goonies.InfoValue("release") != "" {
+		t.Errorf("InfoValue(release) = %q, want empty", goonies.InfoValue("release"))
+	}
+	if len(goonies.Parts) != 1 {
+		t.Fatalf("len(Parts) = %d, want 1", len(goonies.Parts))
+	}
+	part := goonies.Parts[0]
+	if part.Name != "cart" || part.Interface != "msx_cart" {
+		t.Errorf("Part = %q (%q), want cart (msx_cart)", part.Name, part.Interface)
+	}
+	if len(part.Features) != 1 || part.Features[0] != (Feature{Name: "slot", Value: "nomapper"}) {
+		t.Errorf("Features = %v", part.Features)
+	}
+	area := part.DataAreas[0]
+	if area.Name != "rom" || area.Size != 32768 || area.Width != 8 || area.Endianness != "little" {
+		t.Errorf("DataArea = %+v", area)
+	}
+	rom := area.ROMs[0]
+	if rom.Name != "goonies.rom" || rom.Size != 32768 || rom.CRC != "a0ff0af5" || rom.Offset != 0 {
+		t.Errorf("ROM = %+v", rom)
+	}
+
+	clone := list.Software[1]
+	if clone.CloneOf != "goonies" || clone.Supported != SupportedPartial {
+		t.Errorf("gooniesa CloneOf = %q, Supported = %q", clone.CloneOf, clone.Supported)
+	}
+	area = clone.Parts[0].DataAreas[0]
+	if area.Size != 0x10000 || area.Width != 16 || area.Endianness != "big" {
+		t.Errorf("DataArea = %+v", area)
+	}
+	if len(area.ROMs) != 3 {
+		t.Fatalf("len(ROMs) = %d, want 3", len(area.ROMs))
+	}
+	if area.ROMs[1].LoadFlag != "reload" || area.ROMs[1].Offset != 0x8000 {
+		t.Errorf("ROMs[1] = %+v", area.ROMs[1])
+	}
+	if area.ROMs[2].Status != datfile.DumpStatusNoDump {
+		t.Errorf("ROMs[2].Status = %q, want %q", area.ROMs[2].Status, datfile.DumpStatusNoDump)
+	}
+
+	hdtool := list.Software[2]
+	if hdtool.Publisher != "<unknown>" {
+		t.Errorf("Publisher = %q, want %q", hdtool.Publisher, "<unknown>")
+	}
+	if hdtool.Notes != "Requires a hard disk interface." {
+		t.Errorf("Notes = %q", hdtool.Notes)
+	}
+	diskArea := hdtool.Parts[0].DiskAreas[0]
+	if diskArea.Name != "harddriv" || len(diskArea.Disks) != 1 {
+		t.Fatalf("DiskArea = %+v", diskArea)
+	}
+	if disk := diskArea.Disks[0]; disk.Name != "hdtool" || !disk.Writable {
+		t.Errorf("Disk = %+v", disk)
+	}
+}
+
+func TestParseSoftwareListReader_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		xml  string
+	}{
+		{"empty", ""},
+		{"wrong root", `<mame build="0.280"><machine name="pacman"/></mame>`},
+		{"missing name", `<softwarelist description="Unnamed"></softwarelist>`},
+		{"malformed", `<softwarelist name="nes"><software name="smb">`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSoftwareListReader(strings.NewReader(tt.xml)); err == nil {
+				t.Error("ParseSoftwareListReader() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSoftwareIndex_Match(t *testing.T) {
+	idx := NewSoftwareIndex(loadTestSoftwareList(t))
+
+	tests := []struct {
+		name     string
+		size     int64
+		hashes   core.Hashes
+		wantName string
+		wantFile string
+	}{
+		{
+			name:     "sha1",
+			size:     32768,
+			hashes:   core.Hashes{core.HashSHA1: "7EC2BD1B4C1C2C2C8E2E5A3B7E4E0A7A4B7E5E31"},
+			wantName: "goonies",
+			wantFile: "goonies.rom",
+		},
+		{
+			name:     "crc32",
+			size:     32768,
+			hashes:   core.Hashes{core.HashCRC32: "a0ff0af5"},
+			wantName: "goonies",
+			wantFile: "goonies.rom",
+		},
+		{
+			name:     "zip crc32",
+			size:     16384,
+			hashes:   core.Hashes{core.HashZipCRC32: "12345678"},
+			wantName: "gooniesa",
+			wantFile: "gooniesa.ic1",
+		},
+		{
+			name:     "chd",
+			size:     1 << 20,
+			hashes:   core.Hashes{core.HashCHDCompressedSHA1: "81d833236e994528d1482979261401b198d1ca53"},
+			wantName: "hdtool",
+			wantFile: "hdtool",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := idx.Match(tt.size, tt.hashes)
+			if match == nil {
+				t.Fatal("Match() = nil")
+			}
+			if match.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", match.Name, tt.wantName)
+			}
+			if match.File != tt.wantFile {
+				t.Errorf("File = %q, want %q", match.File, tt.wantFile)
+			}
+			if match.List != "msx1_cart" {
+				t.Errorf("List = %q, want %q", match.List, "msx1_cart")
+			}
+		})
+	}
+}
+
+func TestSoftwareIndex_Match_Metadata(t *testing.T) {
+	idx := NewSoftwareIndex(loadTestSoftwareList(t))
+
+	match := idx.Match(32768, core.Hashes{core.HashCRC32: "a0ff0af5"})
+	if match == nil {
+		t.Fatal("Match() = nil")
+	}
+	if match.GameTitle() != "The Goonies (Euro, Japan)" {
+		t.Errorf("GameTitle() = %q", match.GameTitle())
+	}
+	if match.GameSerial() != "RC-734" {
+		t.Errorf("GameSerial() = %q, want %q", match.GameSerial(), "RC-734")
+	}
+	if match.Part != "cart" || match.Interface != "msx_cart" {
+		t.Errorf("Part = %q (%q), want cart (msx_cart)", match.Part, match.Interface)
+	}
+	if match.Year != "1986" || match.Publisher != "Konami" {
+		t.Errorf("Year = %q, Publisher = %q", match.Year, match.Publisher)
+	}
+}
+
+func TestSoftwareIndex_Match_NoMatch(t *testing.T) {
+	idx := NewSoftwareIndex(loadTestSoftwareList(t))
+
+	tests := []struct {
+		name   string
+		size   int64
+		hashes core.Hashes
+	}{
+		{"crc32 with wrong size", 16384, core.Hashes{core.HashCRC32: "a0ff0af5"}},
+		{"unknown sha1", 32768, core.Hashes{core.HashSHA1: "0000000000000000000000000000000000000000"}},
+		{"no hashes", 32768, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if match := idx.Match(tt.size, tt.hashes); match != nil {
+				t.Errorf("Match() = %+v, want nil", match)
+			}
+		})
+	}
+}
+
+func TestSoftwareIndex_Platform(t *testing.T) {
+	nes := &SoftwareList{
+		Name: "nes",
+		Software: []Software{{
+			Name:        "smb",
+			Description: "Super Mario Bros. (World)",
+			Parts: []Part{{
+				Name: "cart",
+				DataAreas: []DataArea{{
+					Name: "prg",
+					ROMs: []SoftwareROM{{Name: "smb.prg", Size: 32768, CRC: "5cf548d3"}},
+				}},
+			}},
+		}},
+	}
+	idx := NewSoftwareIndex(nes, loadTestSoftwareList(t))
+
+	if match := idx.Match(32768, core.Hashes{core.HashCRC32: "5cf548d3"}); match == nil || match.GamePlatform() != core.PlatformNES {
+		t.Errorf("Match() = %+v, want platform %q", match, core.PlatformNES)
+	}
+	if match := idx.Match(32768, core.Hashes{core.HashCRC32: "a0ff0af5"}); match == nil || match.GamePlatform() != core.PlatformMSX {
+		t.Errorf("Match() = %+v, want platform %q", match, core.PlatformMSX)
+	}
+
+	unknown := NewSoftwareIndex(&SoftwareList{
+		Name: "unknown_cart",
+		Software: []Software{{
+			Name:  "demo",
+			Parts: []Part{{Name: "cart", DataAreas: []DataArea{{ROMs: []SoftwareROM{{Name: "demo.bin", Size: 16, CRC: "01234567"}}}}}},
+		}},
+	})
+	if match := unknown.Match(16, core.Hashes{core.HashCRC32: "01234567"}); match == nil || match.GamePlatform() != "" {
+		t.Errorf("Match() = %+v, want empty platform", match)
+	}
+}
@@ -0,0 +1,200 @@
+package mame
+
+import (
+	"strings"
+
+	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/datfile"
+)
+
+// softwareListPlatforms maps software list names to platforms with dedicated
+// identification. Files matched in other lists report an empty platform.
+var softwareListPlatforms = map[string]core.Platform{
+	"nes":          core.PlatformNES,
+	"famicom":      core.PlatformNES,
+	"famicom_flop": core.PlatformFDS,
+	"snes":         core.PlatformSNES,
+	"n64":          core.PlatformN64,
+	"n64dd":        core.PlatformN64DD,
+	"gameboy":      core.PlatformGB,
+	"gbcolor":      core.PlatformGBC,
+	"gba":          core.PlatformGBA,
+	"vboy":         core.PlatformVirtualBoy,
+	"pokemini":     core.PlatformPokemonMini,
+	"sms":          core.PlatformMS,
+	"gamegear":     core.PlatformGameGear,
+	"megadriv":     core.PlatformMD,
+	"genesis":      core.PlatformMD,
+	"32x":          core.Platform32X,
+	"segacd":       core.PlatformSegaCD,
+	"megacd":       core.PlatformSegaCD,
+	"megacdj":      core.PlatformSegaCD,
+	"saturn":       core.PlatformSaturn,
+	"dc":           core.PlatformDreamcast,
+	"psx":          core.PlatformPS1,
+	"ngp":          core.PlatformNGP,
+	"ngpc":         core.PlatformNGPC,
+	"wswan":        core.PlatformWonderSwan,
+	"wscolor":      core.PlatformWonderSwanColor,
+	"a2600":        core.PlatformAtari2600,
+	"a5200":        core.PlatformAtari5200,
+	"a7800":        core.PlatformAtari7800,
+	"lynx":         core.PlatformAtariLynx,
+	"jaguar":       core.PlatformAtariJaguar,
+	"pce":          core.PlatformPCEngine,
+	"tg16":         core.PlatformPCEngine,
+	"sgx":          core.PlatformSuperGrafx,
+	"sg1000":       core.PlatformSG1000,
+	"coleco":       core.PlatformColecoVision,
+	"intv":         core.PlatformIntellivision,
+	"vectrex":      core.PlatformVectrex,
+	"channelf":     core.PlatformChannelF,
+	"svision":      core.PlatformSupervision,
+	"msx1_cart":    core.PlatformMSX,
+	"msx1_flop":    core.PlatformMSX,
+	"msx1_cass":    core.PlatformMSX,
+	"msx2_cart":    core.PlatformMSX2,
+	"msx2_flop":    core.PlatformMSX2,
+}
+
+// SoftwareMatch is a file identified against a software list.
+type SoftwareMatch struct {
+	// List is the short name of the software list (e.g. "msx1_cart").
+	List string `json:"list"`
+	// ListDescription is the full name of the software list.
+	ListDescription string `json:"list_description,omitempty"`
+	// Name is the short name of the software item.
+	Name string `json:"name"`
+	// Description is the full title of the software item.
+	Description string `json:"description"`
+	// Year is the release year.
+	Year string `json:"year,omitempty"`
+	// Publisher is the software publisher.
+	Publisher string `json:"publisher,omitempty"`
+	// CloneOf is the parent software item, if the item is a clone.
+	CloneOf string `json:"clone_of,omitempty"`
+	// Serial is the serial from the item's release information.
+	Serial string `json:"serial,omitempty"`
+	// Supported is the emulation support level of the item.
+	Supported Supported `json:"supported"`
+	// Part is the name of the part containing the file (e.g. "cart", "flop1").
+	Part string `json:"part"`
+	// Interface is the slot interface of the part (e.g. "msx_cart").
+	Interface string `json:"interface,omitempty"`
+	// File is the name of the matched ROM or disk within the part.
+	File string `json:"file"`
+}
+
+// GamePlatform implements core.GameInfo.
+func (m *SoftwareMatch) GamePlatform() core.Platform { return softwareListPlatforms[m.List] }
+
+// GameTitle implements core.GameInfo.
+func (m *SoftwareMatch) GameTitle() string { return m.Description }
+
+// GameSerial implements core.GameInfo.
+func (m *SoftwareMatch) GameSerial() string { return m.Serial }
+
+// GameRegions implements core.GameInfo. Regions are not recorded by software lists.
+func (m *SoftwareMatch) GameRegions() []core.Region { return nil }
+
+// SoftwareIndex provides hash lookup across software lists.
+type SoftwareIndex struct {
+	bySHA1 map[string][]*SoftwareMatch
+	byCRC  map[string][]softwareEntry // CRC32 -> entries, checked against size
+}
+
+type softwareEntry struct {
+	size  int64
+	match *SoftwareMatch
+}
+
+// NewSoftwareIndex indexes the ROMs and disks of software lists by hash.
+// When several lists contain the same file, the list given first wins.
+func NewSoftwareIndex(lists ...*SoftwareList) *SoftwareIndex {
+	idx := &SoftwareIndex{
+		bySHA1: make(map[string][]*SoftwareMatch),
+		byCRC:  make(map[string][]softwareEntry),
+	}
+
+	for _, list := range lists {
+		for i := range list.Software {
+			sw := &list.Software[i]
+			for _, part := range sw.Parts {
+				newMatch := func(file string) *SoftwareMatch {
+					return &SoftwareMatch{
+						List:            list.Name,
+						ListDescription: list.Description,
+						Name:            sw.Name,
+						Description:     sw.Description,
+						Year:            sw.Year,
+						Publisher:       sw.Publisher,
+						CloneOf:         sw.CloneOf,
+						Serial:          sw.InfoValue("serial"),
+						Supported:       sw.Supported,
+						Part:            part.Name,
+						Interface:       part.Interface,
+						File:            file,
+					}
+				}
+
+				for _, area := range part.DataAreas {
+					for _, rom := range area.ROMs {
+						if rom.Name == "" || rom.Status == datfile.DumpStatusNoDump {
+							continue
+						}
+						match := newMatch(rom.Name)
+						if rom.SHA1 != "" {
+							idx.bySHA1[rom.SHA1] = append(idx.bySHA1[rom.SHA1], match)
+						}
+						if rom.CRC != "" {
+							idx.byCRC[rom.CRC] = append(idx.byCRC[rom.CRC], softwareEntry{size: rom.Size, match: match})
+						}
+					}
+				}
+				for _, area := range part.DiskAreas {
+					for _, disk := range area.Disks {
+						if disk.SHA1 != "" {
+							idx.bySHA1[disk.SHA1] = append(idx.bySHA1[disk.SHA1], newMatch(disk.Name))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return idx
+}
+
+// Match identifies a file from its size and hashes. SHA1 is preferred; CRC32
+// (calculated or from ZIP metadata) is only trusted when the size also
+// matches. CHD files are matched by the SHA1 in their header. Returns nil if
+// no software item contains the file.
+func (idx *SoftwareIndex) Match(size int64, hashes core.Hashes) *SoftwareMatch {
+	if matches := idx.Matches(size, hashes); len(matches) > 0 {
+		return matches[0]
+	}
+	return nil
+}
+
+// Matches returns every software item containing the file, for files shared
+// by several items or lists. Matching follows the same rules as Match.
+func (idx *SoftwareIndex) Matches(size int64, hashes core.Hashes) []*SoftwareMatch {
+	for _, hashType := range []core.HashType{core.HashSHA1, core.HashCHDCompressedSHA1} {
+		if matches := idx.bySHA1[strings.ToLower(hashes[hashType])]; len(matches) > 0 {
+			return matches
+		}
+	}
+
+	var result []*SoftwareMatch
+	for _, hashType := range []core.HashType{core.HashCRC32, core.HashZipCRC32} {
+		for _, entry := range idx.byCRC[strings.ToLower(hashes[hashType])] {
+			if entry.size == size {
+				result = append(result, entry.match)
+			}
+		}
+		if len(result) > 0 {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+package minui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// NextUI shows box art beside the game list and doesn't need images larger
+// than the screen, so scraped art is scaled down to save SD card space and
+// loading time. The defaults leave room for the list on 1024x768 and 1280x720
+// screens.
+const (
+	DefaultBoxArtWidth  = 500
+	DefaultBoxArtHeight = 500
+)
+
+// BoxArtSize is the maximum size of box art. A zero width or height means
+// no limit in that direction.
+type BoxArtSize struct {
+	Width  int
+	Height int
+}
+
+// DefaultBoxArtSize returns the default maximum box art size.
+func DefaultBoxArtSize() BoxArtSize {
+	return BoxArtSize{Width: DefaultBoxArtWidth, Height: DefaultBoxArtHeight}
+}
+
+// ParseBoxArtSize parses a size like "500x500". "0" means no limit.
+func ParseBoxArtSize(s string) (BoxArtSize, error) {
+	if s == "0" {
+		return BoxArtSize{}, nil
+	}
+	w, h, ok := strings.Cut(strings.ToLower(s), "x")
+	if !ok {
+		return BoxArtSize{}, fmt.Errorf("invalid box art size %q (expected WIDTHxHEIGHT)", s)
+	}
+	width, err := strconv.Atoi(w)
+	if err != nil || width < 0 {
+		return BoxArtSize{}, fmt.Errorf("invalid box art width %q", w)
+	}
+	height, err := strconv.Atoi(h)
+	if err != nil || height < 0 {
+		return BoxArtSize{}, fmt.Errorf("invalid box art height %q", h)
+	}
+	return BoxArtSize{Width: width, Height: height}, nil
+}
+
+// Fit scales an image down to fit within the size, keeping its aspect ratio.
+// Images that already fit are returned unchanged; images are never scaled up.
+func (s BoxArtSize) Fit(img image.Image) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return img
+	}
+
+	scale := 1.0
+	if s.Width > 0 && w > s.Width {
+		scale = float64(s.Width) / float64(w)
+	}
+	if s.Height > 0 && h > s.Height {
+		scale = min(scale, float64(s.Height)/float64(h))
+	}
+	if scale == 1.0 {
+		return img
+	}
+
+	return resize(img, max(1, int(float64(w)*scale)), max(1, int(float64(h)*scale)))
+}
+
+// resize scales an image down by averaging the source pixels covered by each
+// destination pixel.
+func resize(src image.Image, width, height int) image.Image {
+	b := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := range height {
+		y0 := b.Min.Y + y*b.Dy()/height
+		y1 := max(y0+1, b.Min.Y+(y+1)*b.Dy()/height)
+		for x := range width {
+			x0 := b.Min.X + x*b.Dx()/width
+			x1 := max(x0+1, b.Min.X+(x+1)*b.Dx()/width)
+
+			// Sum premultiplied colors so transparent pixels don't darken edges
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+
+			c := color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(bl / n),
+				A: uint16(a / n),
+			}
+			dst.Set(x, y, c)
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,97 @@
+// Package minui provides the MinUI and NextUI ROM folder layout.
+//
+// MinUI-based launchers keep everything next to the ROMs of a system folder
+// (e.g., "Roms/Game Boy Advance (GBA)"):
+//
+//	<rom folder>/map.txt           Display names, one "<file>\t<name>" per line
+//	<rom folder>/.media/<rom>.png  Box art shown by NextUI, named after the ROM
+//	                               without its extension
+//
+// The .media folder is hidden, so the launcher doesn't list it as a game.
+package minui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// MediaDir is the folder inside a ROM folder that holds box art
+const MediaDir = ".media"
+
+// MapFile is the name of the display name file inside a ROM folder
+const MapFile = "map.txt"
+
+// ImagePath returns the path of a ROM's box art in a ROM folder.
+func ImagePath(romDir, rom string) string {
+	return filepath.Join(romDir, MediaDir, rom+".png")
+}
+
+// MapPath returns the path of a ROM folder's display name file.
+func MapPath(romDir string) string {
+	return filepath.Join(romDir, MapFile)
+}
+
+// Map maps ROM file names (with extension) to display names
+type Map map[string]string
+
+// ReadMap reads a display name file. A missing file reads as empty. Lines
+// without a tab are ignored.
+func ReadMap(path string) (Map, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Map{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open map: %w", err)
+	}
+	defer f.Close()
+
+	return ParseMap(f)
+}
+
+// ParseMap parses a display name file from a reader.
+func ParseMap(r io.Reader) (Map, error) {
+	m := Map{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		file, name, ok := strings.Cut(line, "\t")
+		if !ok || file == "" {
+			continue
+		}
+		m[file] = name
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read map: %w", err)
+	}
+	return m, nil
+}
+
+// WriteMap writes a display name file, sorted by file name.
+func WriteMap(path string, m Map) error {
+	files := make([]string, 0, len(m))
+	for file := range m {
+		files = append(files, file)
+	}
+	slices.Sort(files)
+
+	var b strings.Builder
+	for _, file := range files {
+		fmt.Fprintf(&b, "%s\t%s\n", file, m[file])
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create map directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write map: %w", err)
+	}
+	return nil
+}
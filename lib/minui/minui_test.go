@@ -0,0 +1,100 @@
+package minui
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseMap(t *testing.T) {
+	m, err := ParseMap(strings.NewReader("Tetris (World).gb\tTetris\r\nno tab here\n\n.hidden.gb\t\nZelda.gb\tLink's Awakening\n"))
+	if err != nil {
+		t.Fatalf("ParseMap() error = %v", err)
+	}
+	if len(m) != 3 || m["Tetris (World).gb"] != "Tetris" || m["Zelda.gb"] != "Link's Awakening" {
+		t.Errorf("ParseMap() = %v", m)
+	}
+	if name, ok := m[".hidden.gb"]; !ok || name != "" {
+		t.Errorf("ParseMap()[.hidden.gb] = %q, %v", name, ok)
+	}
+}
+
+func TestReadWriteMap(t *testing.T) {
+	romDir := t.TempDir()
+
+	m, err := ReadMap(MapPath(romDir))
+	if err != nil {
+		t.Fatalf("ReadMap() error = %v", err)
+	}
+	if len(m) != 0 {
+		t.Errorf("ReadMap() = %v, want empty", m)
+	}
+
+	m["b.gb"] = "Bravo"
+	m["a.gb"] = "Alpha"
+	if err := WriteMap(MapPath(romDir), m); err != nil {
+		t.Fatalf("WriteMap() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(romDir, "map.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "a.gb\tAlpha\nb.gb\tBravo\n"; string(data) != want {
+		t.Errorf("map.txt = %q, want %q", data, want)
+	}
+}
+
+func TestImagePath(t *testing.T) {
+	if got, want := ImagePath("Roms/GB", "Tetris"), filepath.Join("Roms/GB", ".media", "Tetris.png"); got != want {
+		t.Errorf("ImagePath() = %q, want %q", got, want)
+	}
+}
+
+func TestParseBoxArtSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    BoxArtSize
+		wantErr bool
+	}{
+		{"500x500", BoxArtSize{500, 500}, false},
+		{"640X0", BoxArtSize{640, 0}, false},
+		{"0", BoxArtSize{}, false},
+		{"500", BoxArtSize{}, true},
+		{"ax500", BoxArtSize{}, true},
+		{"500x-1", BoxArtSize{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseBoxArtSize(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseBoxArtSize(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestBoxArtSize_Fit(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 1000, 400))
+	for y := range 400 {
+		for x := range 1000 {
+			src.Set(x, y, color.NRGBA{R: 200, A: 255})
+		}
+	}
+
+	got := BoxArtSize{Width: 500, Height: 500}.Fit(src)
+	if b := got.Bounds(); b.Dx() != 500 || b.Dy() != 200 {
+		t.Errorf("Fit() size = %dx%d, want 500x200", b.Dx(), b.Dy())
+	}
+	if r, _, _, a := got.At(10, 10).RGBA(); r>>8 != 200 || a>>8 != 255 {
+		t.Errorf("Fit() color = %v", got.At(10, 10))
+	}
+
+	if got := (BoxArtSize{Height: 100}).Fit(src); got.Bounds().Dx() != 250 || got.Bounds().Dy() != 100 {
+		t.Errorf("Fit() height-only size = %v", got.Bounds())
+	}
+	if got := DefaultBoxArtSize().Fit(image.NewNRGBA(image.Rect(0, 0, 100, 100))); got.Bounds().Dx() != 100 {
+		t.Errorf("Fit() scaled up a small image to %v", got.Bounds())
+	}
+}
@@ -0,0 +1,91 @@
+// Package muos provides the MuOS content catalogue layout.
+//
+// MuOS shows per-game artwork and descriptions from a catalogue folder on the
+// SD card, organized by catalogue system name (e.g., "Nintendo Game Boy
+// Advance") and named after the ROM without its extension:
+//
+//	MUOS/info/catalogue/<system>/box/<rom>.png      Box art
+//	MUOS/info/catalogue/<system>/preview/<rom>.png  Screenshot
+//	MUOS/info/catalogue/<system>/text/<rom>.txt     Description
+//
+// Display names that differ from the file name are read from
+// MUOS/info/name.json, a JSON object mapping ROM names (without extension)
+// to display names.
+package muos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Catalogue subdirectories
+const (
+	BoxDir     = "box"
+	PreviewDir = "preview"
+	TextDir    = "text"
+)
+
+// NamesFile is the name of the display name overrides file, which sits next
+// to the catalogue folder.
+const NamesFile = "name.json"
+
+// BoxPath returns the path of a ROM's box art in a catalogue.
+func BoxPath(catalogueDir, system, rom string) string {
+	return filepath.Join(catalogueDir, system, BoxDir, rom+".png")
+}
+
+// PreviewPath returns the path of a ROM's preview image in a catalogue.
+func PreviewPath(catalogueDir, system, rom string) string {
+	return filepath.Join(catalogueDir, system, PreviewDir, rom+".png")
+}
+
+// TextPath returns the path of a ROM's description in a catalogue.
+func TextPath(catalogueDir, system, rom string) string {
+	return filepath.Join(catalogueDir, system, TextDir, rom+".txt")
+}
+
+// NamesPath returns the path of the name overrides file for a catalogue
+// (MUOS/info/name.json for MUOS/info/catalogue).
+func NamesPath(catalogueDir string) string {
+	return filepath.Join(filepath.Dir(filepath.Clean(catalogueDir)), NamesFile)
+}
+
+// Names maps ROM names (without extension) to display names
+type Names map[string]string
+
+// ReadNames reads a name overrides file. A missing file reads as empty.
+func ReadNames(path string) (Names, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Names{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read names: %w", err)
+	}
+
+	names := Names{}
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("failed to parse names: %w", err)
+	}
+	return names, nil
+}
+
+// WriteNames writes a name overrides file, sorted by ROM name.
+func WriteNames(path string, names Names) error {
+	data, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal names: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create names directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write names: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package muos
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPaths(t *testing.T) {
+	catalogue := filepath.Join("MUOS", "info", "catalogue")
+	system := "Nintendo Game Boy"
+
+	if got, want := BoxPath(catalogue, system, "Tetris"), filepath.Join(catalogue, system, "box", "Tetris.png"); got != want {
+		t.Errorf("BoxPath() = %q, want %q", got, want)
+	}
+	if got, want := PreviewPath(catalogue, system, "Tetris"), filepath.Join(catalogue, system, "preview", "Tetris.png"); got != want {
+		t.Errorf("PreviewPath() = %q, want %q", got, want)
+	}
+	if got, want := TextPath(catalogue, system, "Tetris"), filepath.Join(catalogue, system, "text", "Tetris.txt"); got != want {
+		t.Errorf("TextPath() = %q, want %q", got, want)
+	}
+	if got, want := NamesPath(catalogue+"/"), filepath.Join("MUOS", "info", "name.json"); got != want {
+		t.Errorf("NamesPath() = %q, want %q", got, want)
+	}
+}
+
+func TestReadWriteNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "info", NamesFile)
+
+	names, err := ReadNames(path)
+	if err != nil {
+		t.Fatalf("ReadNames() error = %v", err)
+	}
+	if len(names) != 0 {
+		t.Errorf("ReadNames() = %v, want empty", names)
+	}
+
+	names["sf2"] = "Street Fighter II"
+	if err := WriteNames(path, names); err != nil {
+		t.Fatalf("WriteNames() error = %v", err)
+	}
+
+	names, err = ReadNames(path)
+	if err != nil {
+		t.Fatalf("ReadNames() error = %v", err)
+	}
+	if names["sf2"] != "Street Fighter II" {
+		t.Errorf("ReadNames() = %v", names)
+	}
+}
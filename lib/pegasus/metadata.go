@@ -0,0 +1,165 @@
+// Package pegasus reads and writes Pegasus frontend metadata files
+// (metadata.pegasus.txt).
+//
+// A metadata file is a list of "key: value" fields. A "collection:" field
+// starts a collection and a "game:" field starts a game; the fields after it
+// belong to that entry. Values continue on following lines indented with
+// whitespace, where a line with a single "." is a paragraph break. Lines
+// starting with "#" are comments.
+//
+//	collection: Super Nintendo
+//	extensions: sfc, smc
+//
+//	game: Super Mario World
+//	file: Super Mario World (USA).sfc
+//	description:
+//	  Mario's first adventure on the Super Nintendo.
+//	  .
+//	  Includes Yoshi.
+//	assets.boxFront: media/Super Mario World (USA)/boxFront.png
+//	x-scraper: screenscraper
+//
+// Keys are case-insensitive. Besides the documented keys, "assets.*" keys
+// name media files and "x-" keys are custom fields Pegasus ignores.
+//
+// Format specification:
+// https://pegasus-frontend.org/docs/user-guide/meta-files/
+package pegasus
+
+import (
+	"strings"
+)
+
+// Well-known keys
+const (
+	KeyCollection  = "collection"
+	KeyGame        = "game"
+	KeyFile        = "file"
+	KeyFiles       = "files"
+	KeyDescription = "description"
+)
+
+// Metadata is the content of a metadata file
+type Metadata struct {
+	Collections []*Collection
+	Games       []*Game
+
+	// Comments are comment lines after the last field
+	Comments []string
+}
+
+// Collection is a collection entry
+type Collection struct {
+	Name   string
+	Fields Fields // Fields after the collection name
+
+	// Comments are the comment lines before the collection field
+	Comments []string
+}
+
+// Game is a game entry
+type Game struct {
+	Title  string
+	Fields Fields // Fields after the game title
+
+	// Comments are the comment lines before the game field
+	Comments []string
+}
+
+// Files returns the game's files, from both "file" and "files" fields.
+func (g *Game) Files() []string {
+	var files []string
+	for _, f := range g.Fields {
+		if f.Is(KeyFile) || f.Is(KeyFiles) {
+			for _, v := range f.Values {
+				if v != "" {
+					files = append(files, v)
+				}
+			}
+		}
+	}
+	return files
+}
+
+// Field is a key with one value per line
+type Field struct {
+	Key    string
+	Values []string // Lines of the value; "" is a paragraph break
+
+	// Comments are the comment lines before the field
+	Comments []string
+}
+
+// Is reports whether the field has a key, ignoring case.
+func (f *Field) Is(key string) bool {
+	return strings.EqualFold(f.Key, key)
+}
+
+// Fields is an ordered list of fields
+type Fields []Field
+
+// Find returns the first field with a key, or nil.
+func (fs Fields) Find(key string) *Field {
+	for i := range fs {
+		if fs[i].Is(key) {
+			return &fs[i]
+		}
+	}
+	return nil
+}
+
+// Has reports whether a field with a key exists.
+func (fs Fields) Has(key string) bool {
+	return fs.Find(key) != nil
+}
+
+// Get returns a field's value as a single string: lines are joined with
+// spaces and paragraph breaks become newlines, as Pegasus reads text.
+// Returns "" if the field doesn't exist.
+func (fs Fields) Get(key string) string {
+	f := fs.Find(key)
+	if f == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	startOfLine := true
+	for _, v := range f.Values {
+		if v == "" {
+			b.WriteString("\n")
+			startOfLine = true
+			continue
+		}
+		if !startOfLine {
+			b.WriteString(" ")
+		}
+		b.WriteString(v)
+		startOfLine = false
+	}
+	return b.String()
+}
+
+// Set sets a field's value, replacing the first field with the key or
+// adding one at the end. Newlines in the value become paragraph breaks.
+func (fs *Fields) Set(key, value string) {
+	var values []string
+	for i, line := range strings.Split(value, "\n") {
+		if i > 0 {
+			values = append(values, "") // Paragraph break
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			values = append(values, line)
+		}
+	}
+	fs.SetValues(key, values)
+}
+
+// SetValues sets a field's lines, replacing the first field with the key or
+// adding one at the end.
+func (fs *Fields) SetValues(key string, values []string) {
+	if f := fs.Find(key); f != nil {
+		f.Values = values
+		return
+	}
+	*fs = append(*fs, Field{Key: key, Values: values})
+}
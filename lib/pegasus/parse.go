@@ -0,0 +1,99 @@
+package pegasus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Parse reads a metadata file.
+func Parse(path string) (*Metadata, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metadata file: %w", err)
+	}
+	defer f.Close()
+
+	return ParseReader(f)
+}
+
+// ParseReader reads a metadata file from a reader.
+func ParseReader(r io.Reader) (*Metadata, error) {
+	md := &Metadata{}
+
+	var (
+		fields   *Fields  // Fields of the current entry
+		field    *Field   // Field receiving continuation lines
+		comments []string // Comments waiting for the next field
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\ufeff") // Byte order mark
+		}
+
+		switch {
+		case line == "":
+			continue
+
+		case strings.HasPrefix(line, "#"):
+			comments = append(comments, line)
+			continue
+
+		case line[0] == ' ' || line[0] == '\t':
+			if field == nil {
+				return nil, fmt.Errorf("line %d: continuation line without a field", lineNum)
+			}
+			value := strings.TrimSpace(line)
+			if value == "." {
+				value = "" // Paragraph break
+			}
+			field.Values = append(field.Values, value)
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\", got %q", lineNum, line)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(key) {
+		case KeyCollection:
+			c := &Collection{Name: value, Comments: comments}
+			md.Collections = append(md.Collections, c)
+			fields, field = &c.Fields, nil
+
+		case KeyGame:
+			g := &Game{Title: value, Comments: comments}
+			md.Games = append(md.Games, g)
+			fields, field = &g.Fields, nil
+
+		default:
+			if fields == nil {
+				return nil, fmt.Errorf("line %d: field %q before any collection or game", lineNum, key)
+			}
+			f := Field{Key: key, Comments: comments}
+			if value != "" {
+				f.Values = []string{value}
+			}
+			*fields = append(*fields, f)
+			field = &(*fields)[len(*fields)-1]
+		}
+		comments = nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read metadata file: %w", err)
+	}
+
+	md.Comments = comments
+	return md, nil
+}
@@ -0,0 +1,107 @@
+package pegasus
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	md, err := Parse(filepath.Join("testdata", "metadata.pegasus.txt"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(md.Collections) != 1 || len(md.Games) != 2 {
+		t.Fatalf("got %d collections, %d games, want 1, 2", len(md.Collections), len(md.Games))
+	}
+
+	c := md.Collections[0]
+	if c.Name != "Super Nintendo" || c.Fields.Get("extensions") != "sfc, smc" {
+		t.Errorf("collection = %+v", c)
+	}
+	if len(c.Comments) != 1 || c.Comments[0] != "# Hand-written collection" {
+		t.Errorf("collection comments = %q", c.Comments)
+	}
+
+	smw := md.Games[0]
+	if smw.Title != "Super Mario World" || smw.Fields.Get("genre") != "Platform" {
+		t.Errorf("game = %+v", smw)
+	}
+	if got, want := smw.Fields.Get(KeyDescription), "Mario's first adventure on the Super Nintendo.\nIncludes Yoshi."; got != want {
+		t.Errorf("description = %q, want %q", got, want)
+	}
+	if files := smw.Files(); len(files) != 1 || files[0] != "Super Mario World (USA).sfc" {
+		t.Errorf("Files() = %q", files)
+	}
+	if f := smw.Fields.Find("x-favorite"); f == nil || len(f.Comments) != 1 {
+		t.Errorf("x-favorite = %+v", f)
+	}
+
+	sf2 := md.Games[1]
+	if files := sf2.Files(); len(files) != 2 || files[1] != "Street Fighter II Turbo (Japan).sfc" {
+		t.Errorf("Files() = %q", files)
+	}
+	if len(md.Comments) != 1 || md.Comments[0] != "# Trailing note" {
+		t.Errorf("trailing comments = %q", md.Comments)
+	}
+}
+
+func TestParseReader_Errors(t *testing.T) {
+	tests := map[string]string{
+		"field before entry":  "developer: Nintendo\n",
+		"orphan continuation": "  text\n",
+		"missing colon":       "game: A\nno colon here\n",
+	}
+	for name, input := range tests {
+		if _, err := ParseReader(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: ParseReader() error = nil", name)
+		}
+	}
+}
+
+func TestRoundtrip(t *testing.T) {
+	path := filepath.Join("testdata", "metadata.pegasus.txt")
+	md, err := Parse(path)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	out := filepath.Join(t.TempDir(), "metadata.pegasus.txt")
+	if err := Write(out, md); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	want, _ := os.ReadFile(path)
+	got, _ := os.ReadFile(out)
+	if string(got) != string(want) {
+		t.Errorf("Write() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFields_Set(t *testing.T) {
+	var fs Fields
+	fs.Set(KeyDescription, "First paragraph.\n\nSecond paragraph.")
+	fs.Set("developer", "Nintendo")
+	fs.Set("developer", "Nintendo EAD")
+
+	if len(fs) != 2 {
+		t.Fatalf("len(Fields) = %d, want 2", len(fs))
+	}
+	if got := fs.Get("Developer"); got != "Nintendo EAD" {
+		t.Errorf("Get(Developer) = %q", got)
+	}
+	if got, want := fs.Get(KeyDescription), "First paragraph.\n\nSecond paragraph."; got != want {
+		t.Errorf("Get(description) = %q, want %q", got, want)
+	}
+
+	var b strings.Builder
+	if err := WriteTo(&b, &Metadata{Games: []*Game{{Title: "A", Fields: fs}}}); err != nil {
+		t.Fatal(err)
+	}
+	want := "game: A\ndescription:\n  First paragraph.\n  .\n  .\n  Second paragraph.\ndeveloper: Nintendo EAD\n"
+	if b.String() != want {
+		t.Errorf("WriteTo() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,93 @@
+package pegasus
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Write writes a metadata file: collections first, then games, each entry
+// separated by a blank line.
+func Write(path string, md *Metadata) error {
+	var b strings.Builder
+	if err := WriteTo(&b, md); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create metadata directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write metadata file: %w", err)
+	}
+	return nil
+}
+
+// WriteTo writes a metadata file to a writer.
+func WriteTo(w io.Writer, md *Metadata) error {
+	ew := &errWriter{w: w}
+	first := true
+	entry := func(key, name string, comments []string, fields Fields) {
+		if !first {
+			ew.printf("\n")
+		}
+		first = false
+		writeField(ew, Field{Key: key, Values: []string{name}, Comments: comments})
+		for _, f := range fields {
+			writeField(ew, f)
+		}
+	}
+
+	for _, c := range md.Collections {
+		entry(KeyCollection, c.Name, c.Comments, c.Fields)
+	}
+	for _, g := range md.Games {
+		entry(KeyGame, g.Title, g.Comments, g.Fields)
+	}
+
+	if len(md.Comments) > 0 {
+		ew.printf("\n")
+		for _, c := range md.Comments {
+			ew.printf("%s\n", c)
+		}
+	}
+
+	return ew.err
+}
+
+// writeField writes a field: single values on the key's line, and multiple
+// values on indented lines after it.
+func writeField(ew *errWriter, f Field) {
+	for _, c := range f.Comments {
+		ew.printf("%s\n", c)
+	}
+
+	switch {
+	case len(f.Values) == 0 || len(f.Values) == 1 && f.Values[0] == "":
+		ew.printf("%s:\n", f.Key)
+	case len(f.Values) == 1:
+		ew.printf("%s: %s\n", f.Key, f.Values[0])
+	default:
+		ew.printf("%s:\n", f.Key)
+		for _, v := range f.Values {
+			if v == "" {
+				v = "." // Paragraph break
+			}
+			ew.printf("  %s\n", v)
+		}
+	}
+}
+
+// errWriter remembers the first write error
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...any) {
+	if ew.err == nil {
+		_, ew.err = fmt.Fprintf(ew.w, format, args...)
+	}
+}
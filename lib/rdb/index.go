@@ -0,0 +1,158 @@
+package rdb
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// databasePlatforms maps libretro database names to platforms.
+var databasePlatforms = map[string]core.Platform{
+	"Nintendo - Nintendo Entertainment System":       core.PlatformNES,
+	"Nintendo - Family Computer Disk System":         core.PlatformFDS,
+	"Nintendo - Super Nintendo Entertainment System": core.PlatformSNES,
+	"Nintendo - Nintendo 64":                         core.PlatformN64,
+	"Nintendo - Nintendo 64DD":                       core.PlatformN64DD,
+	"Nintendo - GameCube":                            core.PlatformGC,
+	"Nintendo - Wii":                                 core.PlatformWii,
+	"Nintendo - Wii U":                               core.PlatformWiiU,
+	"Nintendo - Game Boy":                            core.PlatformGB,
+	"Nintendo - Game Boy Color":                      core.PlatformGBC,
+	"Nintendo - Game Boy Advance":                    core.PlatformGBA,
+	"Nintendo - Virtual Boy":                         core.PlatformVirtualBoy,
+	"Nintendo - Pokemon Mini":                        core.PlatformPokemonMini,
+	"Nintendo - Nintendo DS":                         core.PlatformNDS,
+	"Nintendo - Nintendo DSi":                        core.PlatformDSi,
+	"Nintendo - Nintendo 3DS":                        core.Platform3DS,
+	"Sega - Master System - Mark III":                core.PlatformMS,
+	"Sega - Mega Drive - Genesis":                    core.PlatformMD,
+	"Sega - Mega-CD - Sega CD":                       core.PlatformSegaCD,
+	"Sega - 32X":                                     core.Platform32X,
+	"Sega - Saturn":                                  core.PlatformSaturn,
+	"Sega - Dreamcast":                               core.PlatformDreamcast,
+	"Sega - Game Gear":                               core.PlatformGameGear,
+	"Sega - SG-1000":                                 core.PlatformSG1000,
+	"Sony - PlayStation":                             core.PlatformPS1,
+	"Sony - PlayStation 2":                           core.PlatformPS2,
+	"Sony - PlayStation 3":                           core.PlatformPS3,
+	"Sony - PlayStation Portable":                    core.PlatformPSP,
+	"Sony - PlayStation Vita":                        core.PlatformPSVita,
+	"Microsoft - Xbox":                               core.PlatformXbox,
+	"Microsoft - Xbox 360":                           core.PlatformXbox360,
+	"Microsoft - MSX":                                core.PlatformMSX,
+	"Microsoft - MSX2":                               core.PlatformMSX2,
+	"SNK - Neo Geo Pocket":                           core.PlatformNGP,
+	"SNK - Neo Geo Pocket Color":                     core.PlatformNGPC,
+	"Bandai - WonderSwan":                            core.PlatformWonderSwan,
+	"Bandai - WonderSwan Color":                      core.PlatformWonderSwanColor,
+	"Atari - 2600":                                   core.PlatformAtari2600,
+	"Atari - 5200":                                   core.PlatformAtari5200,
+	"Atari - 7800":                                   core.PlatformAtari7800,
+	"Atari - Lynx":                                   core.PlatformAtariLynx,
+	"Atari - Jaguar":                                 core.PlatformAtariJaguar,
+	"NEC - PC Engine - TurboGrafx 16":                core.PlatformPCEngine,
+	"NEC - PC Engine SuperGrafx":                     core.PlatformSuperGrafx,
+	"Coleco - ColecoVision":                          core.PlatformColecoVision,
+	"Mattel - Intellivision":                         core.PlatformIntellivision,
+	"GCE - Vectrex":                                  core.PlatformVectrex,
+	"Fairchild - Channel F":                          core.PlatformChannelF,
+	"Watara - Supervision":                           core.PlatformSupervision,
+	"MAME":                                           core.PlatformArcade,
+	"FBNeo - Arcade Games":                           core.PlatformArcade,
+}
+
+// Index looks up entries of one or more databases by hash and serial
+type Index struct {
+	bySHA1   map[string]*Entry
+	byMD5    map[string]*Entry
+	byCRC    map[string][]*Entry
+	bySerial map[string][]*Entry
+}
+
+// NewIndex indexes the entries of the given databases. When several entries
+// share a hash, the first one wins.
+func NewIndex(dbs ...*Database) *Index {
+	idx := &Index{
+		bySHA1:   make(map[string]*Entry),
+		byMD5:    make(map[string]*Entry),
+		byCRC:    make(map[string][]*Entry),
+		bySerial: make(map[string][]*Entry),
+	}
+
+	for _, db := range dbs {
+		for i := range db.Entries {
+			e := &db.Entries[i]
+			if e.SHA1 != "" {
+				if _, ok := idx.bySHA1[e.SHA1]; !ok {
+					idx.bySHA1[e.SHA1] = e
+				}
+			}
+			if e.MD5 != "" {
+				if _, ok := idx.byMD5[e.MD5]; !ok {
+					idx.byMD5[e.MD5] = e
+				}
+			}
+			if e.CRC32 != "" {
+				idx.byCRC[e.CRC32] = append(idx.byCRC[e.CRC32], e)
+			}
+			if serial := normalizeSerial(e.Serial); serial != "" {
+				idx.bySerial[serial] = append(idx.bySerial[serial], e)
+			}
+		}
+	}
+
+	return idx
+}
+
+// Match finds an entry by hash. SHA1 and MD5 are tried first, then CRC32
+// (including a ZIP archive's CRC32), which also requires the size to match
+// when both sizes are known.
+func (idx *Index) Match(size int64, hashes core.Hashes) *Entry {
+	if sha1 := strings.ToLower(hashes[core.HashSHA1]); sha1 != "" {
+		if e, ok := idx.bySHA1[sha1]; ok {
+			return e
+		}
+	}
+	if md5 := strings.ToLower(hashes[core.HashMD5]); md5 != "" {
+		if e, ok := idx.byMD5[md5]; ok {
+			return e
+		}
+	}
+	for _, ht := range []core.HashType{core.HashCRC32, core.HashZipCRC32} {
+		crc := strings.ToLower(hashes[ht])
+		if crc == "" {
+			continue
+		}
+		for _, e := range idx.byCRC[crc] {
+			if size <= 0 || e.Size == 0 || e.Size == uint64(size) {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
+// LookupSerial finds entries by serial. Serials are compared ignoring case
+// and punctuation, so "SLUS-00594" matches "slus_005.94". If platform is set,
+// only entries of that platform are returned.
+func (idx *Index) LookupSerial(serial string, platform core.Platform) []*Entry {
+	var entries []*Entry
+	for _, e := range idx.bySerial[normalizeSerial(serial)] {
+		if platform == "" || e.GamePlatform() == platform {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// normalizeSerial keeps only the letters (uppercased) and digits of a serial
+func normalizeSerial(serial string) string {
+	var b strings.Builder
+	for _, r := range serial {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,240 @@
+package rdb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// decoder reads the subset of MessagePack used by RDB files: nil, booleans,
+// integers, floats, strings, binary, maps and arrays.
+// https://github.com/msgpack/msgpack/blob/master/spec.md
+type decoder struct {
+	r    *bufio.Reader
+	src  *countingReader
+	size int64 // Input size in bytes, or -1 if unknown
+	buf  [8]byte
+}
+
+// newDecoder creates a decoder reading size bytes from r. A size of -1 means
+// the input size is unknown.
+func newDecoder(r io.Reader, size int64) *decoder {
+	src := &countingReader{r: r}
+	return &decoder{r: bufio.NewReader(src), src: src, size: size}
+}
+
+// countingReader counts the bytes read from a reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// remaining returns the number of input bytes not yet decoded, or -1 if the
+// input size is unknown.
+func (d *decoder) remaining() int64 {
+	if d.size < 0 {
+		return -1
+	}
+	return d.size - (d.src.n - int64(d.r.Buffered()))
+}
+
+// decode reads one value. Strings decode to string, binary to []byte,
+// integers to int64 or uint64, floats to float64, maps to map[string]any
+// (non-string keys are formatted with %v) and arrays to []any.
+func (d *decoder) decode() (any, error) {
+	tag, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case tag <= 0x7f: // positive fixint
+		return uint64(tag), nil
+	case tag >= 0xe0: // negative fixint
+		return int64(int8(tag)), nil
+	case tag >= 0xa0 && tag <= 0xbf: // fixstr
+		return d.readString(int(tag & 0x1f))
+	case tag >= 0x90 && tag <= 0x9f: // fixarray
+		return d.readArray(int(tag & 0x0f))
+	case tag >= 0x80 && tag <= 0x8f: // fixmap
+		return d.readMap(int(tag & 0x0f))
+	}
+
+	switch tag {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6: // bin 8/16/32
+		n, err := d.readLength(tag - 0xc4)
+		if err != nil {
+			return nil, err
+		}
+		return d.readBytes(n)
+	case 0xca: // float 32
+		b, err := d.readFixed(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 0xcb: // float 64
+		b, err := d.readFixed(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case 0xcc, 0xcd, 0xce, 0xcf: // uint 8/16/32/64
+		return d.readUint(1 << (tag - 0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3: // int 8/16/32/64
+		size := 1 << (tag - 0xd0)
+		u, err := d.readUint(size)
+		if err != nil {
+			return nil, err
+		}
+		// Sign-extend from the encoded width
+		shift := 64 - 8*size
+		return int64(u<<shift) >> shift, nil
+	case 0xd9, 0xda, 0xdb: // str 8/16/32
+		n, err := d.readLength(tag - 0xd9)
+		if err != nil {
+			return nil, err
+		}
+		return d.readString(n)
+	case 0xdc, 0xdd: // array 16/32
+		n, err := d.readLength(tag - 0xdc + 1)
+		if err != nil {
+			return nil, err
+		}
+		return d.readArray(n)
+	case 0xde, 0xdf: // map 16/32
+		n, err := d.readLength(tag - 0xde + 1)
+		if err != nil {
+			return nil, err
+		}
+		return d.readMap(n)
+	}
+
+	return nil, fmt.Errorf("unsupported MessagePack type 0x%02x", tag)
+}
+
+// readLength reads a 1, 2 or 4 byte length for size class 0, 1 or 2.
+func (d *decoder) readLength(class byte) (int, error) {
+	u, err := d.readUint(1 << class)
+	if err != nil {
+		return 0, err
+	}
+	if u > math.MaxInt32 {
+		return 0, fmt.Errorf("MessagePack length %d too large", u)
+	}
+	return int(u), nil
+}
+
+func (d *decoder) readUint(size int) (uint64, error) {
+	b, err := d.readFixed(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+func (d *decoder) readFixed(size int) ([]byte, error) {
+	b := d.buf[:size]
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return b, nil
+}
+
+// maxUncheckedLength is the largest value read without knowing the input
+// size; longer values are read incrementally.
+const maxUncheckedLength = 1 << 16
+
+func (d *decoder) readBytes(n int) ([]byte, error) {
+	// Check the declared length before allocating, so a corrupt length can't
+	// allocate up to 2 GiB
+	remaining := d.remaining()
+	if remaining >= 0 && int64(n) > remaining {
+		return nil, fmt.Errorf("MessagePack length %d exceeds remaining %d bytes: %w", n, remaining, io.ErrUnexpectedEOF)
+	}
+	if remaining < 0 && n > maxUncheckedLength {
+		b, err := io.ReadAll(io.LimitReader(d.r, int64(n)))
+		if err != nil {
+			return nil, err
+		}
+		if len(b) < n {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return b, nil
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return b, nil
+}
+
+func (d *decoder) readString(n int) (string, error) {
+	b, err := d.readBytes(n)
+	return string(b), err
+}
+
+func (d *decoder) readArray(n int) ([]any, error) {
+	values := make([]any, n)
+	for i := range values {
+		v, err := d.decode()
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func (d *decoder) readMap(n int) (map[string]any, error) {
+	m := make(map[string]any, n)
+	for range n {
+		k, err := d.decode()
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		v, err := d.decode()
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		key, ok := k.(string)
+		if !ok {
+			key = fmt.Sprintf("%v", k)
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// unexpectedEOF converts io.EOF inside a value to io.ErrUnexpectedEOF, so
+// only a clean end between values reads as io.EOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,222 @@
+// Package rdb reads libretro-database RDB files.
+package rdb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// RetroArch ships one RDB per platform (e.g., "Nintendo - Game Boy.rdb"),
+// compiled from the DAT files in https://github.com/libretro/libretro-database.
+//
+// File layout:
+//
+//	Offset  Size  Description
+//	0x00    8     Magic "RARCHDB\0"
+//	0x08    8     Offset of the metadata map (big-endian)
+//	0x10    ...   Records: one MessagePack map per game, ending with nil
+//	...     ...   Metadata: MessagePack map {"count": N}
+//
+// Hashes (crc, md5, sha1) are stored as binary, and serials as binary or
+// strings depending on the source DAT.
+
+var magic = []byte("RARCHDB\x00")
+
+// Entry is a game record
+type Entry struct {
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	Genre        string `json:"genre,omitempty"`
+	Developer    string `json:"developer,omitempty"`
+	Publisher    string `json:"publisher,omitempty"`
+	Franchise    string `json:"franchise,omitempty"`
+	Origin       string `json:"origin,omitempty"`
+	Region       string `json:"region,omitempty"`
+	ESRBRating   string `json:"esrb_rating,omitempty"`
+	ROMName      string `json:"rom_name,omitempty"`
+	Serial       string `json:"serial,omitempty"`
+	Size         uint64 `json:"size,omitempty"`
+	Users        int    `json:"users,omitempty"` // Number of players
+	ReleaseYear  int    `json:"releaseyear,omitempty"`
+	ReleaseMonth int    `json:"releasemonth,omitempty"`
+	CRC32        string `json:"crc32,omitempty"` // Lowercase hex
+	MD5          string `json:"md5,omitempty"`   // Lowercase hex
+	SHA1         string `json:"sha1,omitempty"`  // Lowercase hex
+
+	// Database is the name of the RDB the entry came from, without the
+	// extension (e.g., "Nintendo - Game Boy").
+	Database string `json:"database"`
+}
+
+// GamePlatform implements core.GameInfo.
+func (e *Entry) GamePlatform() core.Platform { return databasePlatforms[e.Database] }
+
+// GameTitle implements core.GameInfo.
+func (e *Entry) GameTitle() string { return e.Name }
+
+// GameSerial implements core.GameInfo.
+func (e *Entry) GameSerial() string { return e.Serial }
+
+// GameRegions implements core.GameInfo. Region names that aren't known
+// regions are skipped.
+func (e *Entry) GameRegions() []core.Region {
+	var regions []core.Region
+	for _, name := range strings.Split(e.Region, ",") {
+		if r := core.ParseRegion(name); r != core.RegionUnknown && !slices.Contains(regions, r) {
+			regions = append(regions, r)
+		}
+	}
+	return regions
+}
+
+// Date returns the release date as "YYYY-MM" or "YYYY", or "" if unknown.
+func (e *Entry) Date() string {
+	switch {
+	case e.ReleaseYear > 0 && e.ReleaseMonth > 0:
+		return fmt.Sprintf("%04d-%02d", e.ReleaseYear, e.ReleaseMonth)
+	case e.ReleaseYear > 0:
+		return fmt.Sprintf("%04d", e.ReleaseYear)
+	default:
+		return ""
+	}
+}
+
+// Database is a parsed RDB file
+type Database struct {
+	Name    string // File name without extension
+	Entries []Entry
+}
+
+// Parse reads an RDB file. The database is named after the file.
+func Parse(path string) (*Database, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open RDB file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat RDB file: %w", err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return parse(f, name, info.Size())
+}
+
+// ParseReader reads an RDB file from a reader.
+func ParseReader(r io.Reader, name string) (*Database, error) {
+	size := int64(-1)
+	if l, ok := r.(interface{ Len() int }); ok {
+		size = int64(l.Len()) // bytes.Reader, strings.Reader, etc.
+	}
+	return parse(r, name, size)
+}
+
+// parse reads an RDB file of size bytes from a reader. A size of -1 means
+// the size is unknown.
+func parse(r io.Reader, name string, size int64) (*Database, error) {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("failed to read RDB header: %w", err)
+	}
+	if !bytes.Equal(header[:8], magic) {
+		return nil, fmt.Errorf("not an RDB file: bad magic %q", header[:8])
+	}
+
+	db := &Database{Name: name}
+	if size >= 0 {
+		size -= int64(len(header))
+	}
+	d := newDecoder(r, size)
+	for {
+		v, err := d.decode()
+		if errors.Is(err, io.EOF) {
+			break // Some writers omit the terminator
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RDB record %d: %w", len(db.Entries), err)
+		}
+		if v == nil {
+			break // End of records; the metadata map follows
+		}
+
+		record, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("failed to parse RDB record %d: expected map, got %T", len(db.Entries), v)
+		}
+		entry := entryFromRecord(record)
+		entry.Database = name
+		db.Entries = append(db.Entries, entry)
+	}
+
+	return db, nil
+}
+
+func entryFromRecord(m map[string]any) Entry {
+	return Entry{
+		Name:         stringField(m, "name"),
+		Description:  stringField(m, "description"),
+		Genre:        stringField(m, "genre"),
+		Developer:    stringField(m, "developer"),
+		Publisher:    stringField(m, "publisher"),
+		Franchise:    stringField(m, "franchise"),
+		Origin:       stringField(m, "origin"),
+		Region:       stringField(m, "region"),
+		ESRBRating:   stringField(m, "esrb_rating"),
+		ROMName:      stringField(m, "rom_name"),
+		Serial:       stringField(m, "serial"),
+		Size:         uintField(m, "size"),
+		Users:        int(uintField(m, "users")),
+		ReleaseYear:  int(uintField(m, "releaseyear")),
+		ReleaseMonth: int(uintField(m, "releasemonth")),
+		CRC32:        hashField(m, "crc"),
+		MD5:          hashField(m, "md5"),
+		SHA1:         hashField(m, "sha1"),
+	}
+}
+
+func stringField(m map[string]any, key string) string {
+	switch v := m[key].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return ""
+	}
+}
+
+func uintField(m map[string]any, key string) uint64 {
+	switch v := m[key].(type) {
+	case uint64:
+		return v
+	case int64:
+		if v > 0 {
+			return uint64(v)
+		}
+	}
+	return 0
+}
+
+// hashField returns a hash stored as binary (or, in some files, as a hex
+// string) as lowercase hex.
+func hashField(m map[string]any, key string) string {
+	switch v := m[key].(type) {
+	case []byte:
+		return hex.EncodeToString(v)
+	case string:
+		return strings.ToLower(v)
+	default:
+		return ""
+	}
+}
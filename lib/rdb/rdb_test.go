@@ -0,0 +1,155 @@
+package rdb
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+func loadTestDatabase(t *testing.T) *Database {
+	t.Helper()
+	db, err := Parse(filepath.Join("testdata", "Nintendo - Game Boy.rdb"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return db
+}
+
+func TestParse(t *testing.T) {
+	db := loadTestDatabase(t)
+
+	if db.Name != "Nintendo - Game Boy" {
+		t.Errorf("Name = %q, want %q", db.Name, "Nintendo - Game Boy")
+	}
+	if len(db.Entries) != 3 {
+		t.Fatalf("len(Entries) = %d, want 3", len(db.Entries))
+	}
+
+	tetris := db.Entries[0]
+	if tetris.Name != "Tetris (World) (Rev 1)" || tetris.Genre != "Puzzle" || tetris.Developer != "Bullet-Proof Software" || tetris.Publisher != "Nintendo" {
+		t.Errorf("entry = %+v", tetris)
+	}
+	if tetris.Serial != "DMG-TRA" || tetris.Size != 32768 || tetris.Users != 2 {
+		t.Errorf("serial, size, users = %q, %d, %d", tetris.Serial, tetris.Size, tetris.Users)
+	}
+	if tetris.CRC32 != "46df91ad" || tetris.MD5 != "084f1e457749cdec86183189bd88ce69" || tetris.SHA1 != "74591cc9501af93873f9a5d3eb12da12c0723bbc" {
+		t.Errorf("hashes = %s, %s, %s", tetris.CRC32, tetris.MD5, tetris.SHA1)
+	}
+	if tetris.Date() != "1989-06" {
+		t.Errorf("Date() = %q, want %q", tetris.Date(), "1989-06")
+	}
+	if tetris.GamePlatform() != core.PlatformGB {
+		t.Errorf("GamePlatform() = %q, want %q", tetris.GamePlatform(), core.PlatformGB)
+	}
+
+	alleyway := db.Entries[1]
+	if alleyway.Date() != "1989" {
+		t.Errorf("Date() = %q, want %q", alleyway.Date(), "1989")
+	}
+	if regions := alleyway.GameRegions(); len(regions) != 1 || regions[0] != "World" {
+		t.Errorf("GameRegions() = %v, want [World]", regions)
+	}
+
+	if proto := db.Entries[2]; proto.Date() != "" || proto.Serial != "dmg-xxx" {
+		t.Errorf("entry = %+v", proto)
+	}
+}
+
+func TestEntry_GameRegions(t *testing.T) {
+	e := Entry{Region: "USA, europe,United Kingdom, Atlantis, USA"}
+	want := []core.Region{core.RegionUSA, core.RegionEurope, core.RegionUK}
+	if got := e.GameRegions(); !slices.Equal(got, want) {
+		t.Errorf("GameRegions() = %v, want %v", got, want)
+	}
+}
+
+func TestParseReader_BadMagic(t *testing.T) {
+	_, err := ParseReader(strings.NewReader("NOTANRDB\x00\x00\x00\x00\x00\x00\x00\x10"), "x")
+	if err == nil || !strings.Contains(err.Error(), "bad magic") {
+		t.Errorf("ParseReader() error = %v, want bad magic", err)
+	}
+}
+
+func TestParseReader_Truncated(t *testing.T) {
+	// Header followed by a fixmap of one pair whose value is missing
+	data := append([]byte("RARCHDB\x00\x00\x00\x00\x00\x00\x00\x00\x00"), 0x81, 0xa4, 'n', 'a', 'm', 'e')
+	_, err := ParseReader(bytes.NewReader(data), "x")
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ParseReader() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestParseReader_OversizedLength(t *testing.T) {
+	// A fixmap whose value is bin 32 declaring nearly 2 GiB of data
+	data := append([]byte("RARCHDB\x00\x00\x00\x00\x00\x00\x00\x00\x00"), 0x81, 0xa3, 'c', 'r', 'c', 0xc6, 0x7f, 0xff, 0xff, 0xff, 0x01)
+
+	// Known size: rejected before allocating
+	if _, err := ParseReader(bytes.NewReader(data), "x"); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ParseReader() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+	// Unknown size: read incrementally until the input runs out
+	if _, err := ParseReader(io.MultiReader(bytes.NewReader(data)), "x"); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ParseReader() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestParseReader_NoTerminator(t *testing.T) {
+	data := append([]byte("RARCHDB\x00\x00\x00\x00\x00\x00\x00\x00\x00"), 0x81, 0xa4, 'n', 'a', 'm', 'e', 0xa1, 'A')
+	db, err := ParseReader(bytes.NewReader(data), "x")
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v", err)
+	}
+	if len(db.Entries) != 1 || db.Entries[0].Name != "A" {
+		t.Errorf("Entries = %+v", db.Entries)
+	}
+}
+
+func TestIndex_Match(t *testing.T) {
+	idx := NewIndex(loadTestDatabase(t))
+
+	tests := []struct {
+		name   string
+		size   int64
+		hashes core.Hashes
+		want   string
+	}{
+		{"sha1", 0, core.Hashes{core.HashSHA1: "74591CC9501AF93873F9A5D3EB12DA12C0723BBC"}, "Tetris (World) (Rev 1)"},
+		{"md5", 0, core.Hashes{core.HashMD5: "084f1e457749cdec86183189bd88ce69"}, "Tetris (World) (Rev 1)"},
+		{"crc32", 32768, core.Hashes{core.HashCRC32: "4d7ec8d6"}, "Alleyway (World)"},
+		{"zip crc32", 0, core.Hashes{core.HashZipCRC32: "4d7ec8d6"}, "Alleyway (World)"},
+		{"crc32 size mismatch", 65536, core.Hashes{core.HashCRC32: "4d7ec8d6"}, ""},
+		{"unknown", 0, core.Hashes{core.HashSHA1: "0000000000000000000000000000000000000000"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := idx.Match(tt.size, tt.hashes)
+			switch {
+			case tt.want == "" && got != nil:
+				t.Errorf("Match() = %q, want nil", got.Name)
+			case tt.want != "" && (got == nil || got.Name != tt.want):
+				t.Errorf("Match() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndex_LookupSerial(t *testing.T) {
+	idx := NewIndex(loadTestDatabase(t))
+
+	if got := idx.LookupSerial("dmg_tra", ""); len(got) != 1 || got[0].Name != "Tetris (World) (Rev 1)" {
+		t.Errorf("LookupSerial(dmg_tra) = %v", got)
+	}
+	if got := idx.LookupSerial("DMG-XXX", core.PlatformGB); len(got) != 1 {
+		t.Errorf("LookupSerial(DMG-XXX, gb) = %v, want 1 entry", got)
+	}
+	if got := idx.LookupSerial("DMG-TRA", core.PlatformGBA); len(got) != 0 {
+		t.Errorf("LookupSerial(DMG-TRA, gba) = %v, want none", got)
+	}
+}
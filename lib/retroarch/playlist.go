@@ -0,0 +1,231 @@
+// Package retroarch provides RetroArch playlists and thumbnail layout.
+//
+// RetroArch lists games in JSON playlists (.lpl), one per system, named
+// after the system's libretro database (e.g., "Nintendo - Game Boy.lpl"):
+//
+//	{
+//	  "version": "1.5",
+//	  "default_core_path": "",
+//	  "default_core_name": "",
+//	  "items": [
+//	    {
+//	      "path": "/roms/gb/Tetris (World).gb",
+//	      "label": "Tetris (World)",
+//	      "core_path": "DETECT",
+//	      "core_name": "DETECT",
+//	      "crc32": "46DF91AD|crc",
+//	      "db_name": "Nintendo - Game Boy.lpl"
+//	    }
+//	  ]
+//	}
+//
+// Thumbnails are PNG files named after the item's label, in a folder per
+// database and thumbnail type:
+//
+//	thumbnails/<database>/Named_Boxarts/<label>.png
+//	thumbnails/<database>/Named_Snaps/<label>.png
+//	thumbnails/<database>/Named_Titles/<label>.png
+package retroarch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// PlaylistVersion is the playlist format version written by RetroArch
+const PlaylistVersion = "1.5"
+
+// PlaylistExt is the playlist file extension
+const PlaylistExt = ".lpl"
+
+// Detect is the core path and name of items RetroArch picks a core for at
+// launch, and the CRC32 of items it hasn't hashed.
+const Detect = "DETECT"
+
+// Playlist is a RetroArch JSON playlist
+type Playlist struct {
+	Version              string `json:"version"`
+	DefaultCorePath      string `json:"default_core_path"`
+	DefaultCoreName      string `json:"default_core_name"`
+	BaseContentDirectory string `json:"base_content_directory,omitempty"`
+	LabelDisplayMode     int    `json:"label_display_mode"`
+	RightThumbnailMode   int    `json:"right_thumbnail_mode"`
+	LeftThumbnailMode    int    `json:"left_thumbnail_mode"`
+	SortMode             int    `json:"sort_mode"`
+	Items                []Item `json:"items"`
+
+	// Other holds fields not modeled above (e.g., "scan_content_dir"), so
+	// they are written back unchanged
+	Other map[string]json.RawMessage `json:"-"`
+}
+
+// Item is a playlist entry
+type Item struct {
+	Path     string `json:"path"`
+	Label    string `json:"label"`
+	CorePath string `json:"core_path"`
+	CoreName string `json:"core_name"`
+	CRC32    string `json:"crc32"`   // "<CRC32>|crc", or "DETECT"
+	DBName   string `json:"db_name"` // Database name with the .lpl extension
+
+	// Other holds fields not modeled above (e.g., "entry_slot"), so they
+	// are written back unchanged
+	Other map[string]json.RawMessage `json:"-"`
+}
+
+// playlistFields and itemFields are the types' fields without the JSON
+// methods, for the default encoding
+type (
+	playlistFields Playlist
+	itemFields     Item
+)
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields.
+func (p *Playlist) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*playlistFields)(p)); err != nil {
+		return err
+	}
+	other, err := otherFields(data, playlistFields{})
+	p.Other = other
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, writing unknown fields after the
+// known ones.
+func (p Playlist) MarshalJSON() ([]byte, error) {
+	return marshalWithOther(playlistFields(p), p.Other)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields.
+func (i *Item) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*itemFields)(i)); err != nil {
+		return err
+	}
+	other, err := otherFields(data, itemFields{})
+	i.Other = other
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, writing unknown fields after the
+// known ones.
+func (i Item) MarshalJSON() ([]byte, error) {
+	return marshalWithOther(itemFields(i), i.Other)
+}
+
+// otherFields returns the fields of a JSON object that aren't fields of the
+// struct v, or nil if there are none.
+func otherFields(data []byte, v any) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf(v)
+	for i := range t.NumField() {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ","); name != "-" {
+			delete(fields, name)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// marshalWithOther encodes v as a JSON object followed by the other fields,
+// sorted by name.
+func marshalWithOther(v any, other map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(other) == 0 {
+		return data, err
+	}
+	extra, err := json.Marshal(other)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == len("{}") {
+		return extra, nil
+	}
+	return append(append(data[:len(data)-1], ','), extra[1:]...), nil
+}
+
+// NewPlaylist returns an empty playlist.
+func NewPlaylist() *Playlist {
+	return &Playlist{Version: PlaylistVersion, Items: []Item{}}
+}
+
+// PlaylistName returns the playlist file name of a database.
+func PlaylistName(database string) string {
+	return database + PlaylistExt
+}
+
+// FormatCRC32 formats a hex CRC32 for an item, or returns "DETECT" if
+// there is none.
+func FormatCRC32(crc string) string {
+	if crc == "" {
+		return Detect
+	}
+	return strings.ToUpper(crc) + "|crc"
+}
+
+// Find returns the item with a path, or nil.
+func (p *Playlist) Find(path string) *Item {
+	for i := range p.Items {
+		if p.Items[i].Path == path {
+			return &p.Items[i]
+		}
+	}
+	return nil
+}
+
+// Merge adds an item, or updates the item with the same path. An existing
+// item keeps its core, and its other fields are only replaced if overwrite
+// is set.
+func (p *Playlist) Merge(item Item, overwrite bool) {
+	existing := p.Find(item.Path)
+	if existing == nil {
+		p.Items = append(p.Items, item)
+		return
+	}
+	if !overwrite {
+		return
+	}
+	existing.Label = item.Label
+	existing.CRC32 = item.CRC32
+	existing.DBName = item.DBName
+}
+
+// ReadPlaylist reads a playlist. Only the JSON format is supported, not the
+// old line-based format.
+func ReadPlaylist(path string) (*Playlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read playlist: %w", err)
+	}
+
+	p := NewPlaylist()
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("failed to parse playlist: %w", err)
+	}
+	return p, nil
+}
+
+// WritePlaylist writes a playlist.
+func WritePlaylist(path string, p *Playlist) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal playlist: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create playlist directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write playlist: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package retroarch
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestThumbnailPath(t *testing.T) {
+	got := ThumbnailPath("thumbnails", "Nintendo - Game Boy", Boxarts, "Mario & Luigi: A/B <1>?")
+	want := filepath.Join("thumbnails", "Nintendo - Game Boy", "Named_Boxarts", "Mario _ Luigi_ A_B _1__.png")
+	if got != want {
+		t.Errorf("ThumbnailPath() = %q, want %q", got, want)
+	}
+	if got := ThumbnailName("a*b`c\\d|e"); got != "a_b_c_d_e" {
+		t.Errorf("ThumbnailName() = %q, want %q", got, "a_b_c_d_e")
+	}
+}
+
+func TestFormatCRC32(t *testing.T) {
+	if got := FormatCRC32("46df91ad"); got != "46DF91AD|crc" {
+		t.Errorf("FormatCRC32() = %q, want %q", got, "46DF91AD|crc")
+	}
+	if got := FormatCRC32(""); got != Detect {
+		t.Errorf("FormatCRC32(\"\") = %q, want %q", got, Detect)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	p := NewPlaylist()
+	p.Items = append(p.Items, Item{Path: "/roms/a.gb", Label: "A (edited)", CorePath: "/cores/gambatte.so", CoreName: "Gambatte"})
+
+	p.Merge(Item{Path: "/roms/a.gb", Label: "A", CorePath: Detect, CoreName: Detect}, false)
+	p.Merge(Item{Path: "/roms/b.gb", Label: "B", CorePath: Detect, CoreName: Detect}, false)
+	if len(p.Items) != 2 || p.Items[0].Label != "A (edited)" || p.Items[1].Label != "B" {
+		t.Errorf("Merge() items = %+v", p.Items)
+	}
+
+	p.Merge(Item{Path: "/roms/a.gb", Label: "A", CorePath: Detect, CoreName: Detect}, true)
+	if a := p.Items[0]; a.Label != "A" || a.CoreName != "Gambatte" {
+		t.Errorf("Merge(overwrite) = %+v, want new label and kept core", a)
+	}
+}
+
+func TestReadWritePlaylist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "playlists", PlaylistName("Nintendo - Game Boy"))
+
+	p := NewPlaylist()
+	p.Items = append(p.Items, Item{
+		Path:     "/roms/gb/Tetris (World).gb",
+		Label:    "Tetris",
+		CorePath: Detect,
+		CoreName: Detect,
+		CRC32:    FormatCRC32("46df91ad"),
+		DBName:   PlaylistName("Nintendo - Game Boy"),
+	})
+	if err := WritePlaylist(path, p); err != nil {
+		t.Fatalf("WritePlaylist() error = %v", err)
+	}
+
+	got, err := ReadPlaylist(path)
+	if err != nil {
+		t.Fatalf("ReadPlaylist() error = %v", err)
+	}
+	if got.Version != PlaylistVersion || len(got.Items) != 1 || !reflect.DeepEqual(got.Items[0], p.Items[0]) {
+		t.Errorf("ReadPlaylist() = %+v, want %+v", got, p)
+	}
+}
+
+func TestReadWritePlaylist_UnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), PlaylistName("Nintendo - Game Boy"))
+	data := `{
+  "version": "1.5",
+  "default_core_path": "",
+  "default_core_name": "",
+  "label_display_mode": 0,
+  "right_thumbnail_mode": 0,
+  "left_thumbnail_mode": 0,
+  "sort_mode": 0,
+  "scan_content_dir": "/roms/gb",
+  "scan_search_recursively": true,
+  "items": [
+    {
+      "path": "/roms/gb/Tetris (World).gb",
+      "label": "Tetris",
+      "core_path": "DETECT",
+      "core_name": "DETECT",
+      "crc32": "46DF91AD|crc",
+      "db_name": "Nintendo - Game Boy.lpl",
+      "entry_slot": 2
+    }
+  ]
+}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := ReadPlaylist(path)
+	if err != nil {
+		t.Fatalf("ReadPlaylist() error = %v", err)
+	}
+	if string(p.Other["scan_content_dir"]) != `"/roms/gb"` || string(p.Items[0].Other["entry_slot"]) != "2" {
+		t.Fatalf("ReadPlaylist() Other = %v, item Other = %v", p.Other, p.Items[0].Other)
+	}
+	if _, ok := p.Items[0].Other["label"]; ok {
+		t.Error("known field kept in Other")
+	}
+
+	if err := WritePlaylist(path, p); err != nil {
+		t.Fatalf("WritePlaylist() error = %v", err)
+	}
+	got, err := ReadPlaylist(path)
+	if err != nil {
+		t.Fatalf("ReadPlaylist() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("round trip = %+v, want %+v", got, p)
+	}
+}
@@ -0,0 +1,32 @@
+package retroarch
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Thumbnail types
+const (
+	Boxarts = "Named_Boxarts"
+	Snaps   = "Named_Snaps"
+	Titles  = "Named_Titles"
+)
+
+// thumbnailReplacer replaces the characters RetroArch doesn't allow in
+// thumbnail names
+var thumbnailReplacer = strings.NewReplacer(
+	"&", "_", "*", "_", "/", "_", ":", "_", "`", "_",
+	"<", "_", ">", "_", "?", "_", "\\", "_", "|", "_",
+)
+
+// ThumbnailName returns the file name RetroArch looks up for an item's
+// label, without the extension.
+func ThumbnailName(label string) string {
+	return thumbnailReplacer.Replace(label)
+}
+
+// ThumbnailPath returns the path of an item's thumbnail in a thumbnails
+// directory.
+func ThumbnailPath(thumbnailsDir, database, thumbnailType, label string) string {
+	return filepath.Join(thumbnailsDir, database, thumbnailType, ThumbnailName(label)+".png")
+}
@@ -0,0 +1,206 @@
+package wonderswan
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// Bandai WonderSwan / WonderSwan Color ROM format parsing.
+//
+// WonderSwan ROMs store their metadata in a footer at the end of the ROM,
+// right after the reset vector (a far jump at ROM end - 16).
+//
+// Footer specification:
+// https://ws.nesdev.org/wiki/ROM_header
+//
+// Footer layout (10 bytes at ROM end - 10):
+//
+//	Offset  Size  Description
+//	0x00    1     Publisher ID
+//	0x01    1     Color flag (0 = WonderSwan, 1 = WonderSwan Color required)
+//	0x02    1     Game ID
+//	0x03    1     Game version
+//	0x04    1     ROM size code
+//	0x05    1     Save type/size code
+//	0x06    1     Flags (bit 0 = vertical, bit 1 = 8-bit bus, bit 2 = 1-cycle ROM access)
+//	0x07    1     Mapper (0 = Bandai 2001, 1 = Bandai 2003 with RTC)
+//	0x08    2     Checksum (little-endian, sum of all ROM bytes except the checksum)
+
+const (
+	wsFooterSize      = 10
+	wsPublisherOffset = 0x00
+	wsColorOffset     = 0x01
+	wsGameIDOffset    = 0x02
+	wsVersionOffset   = 0x03
+	wsROMSizeOffset   = 0x04
+	wsSaveTypeOffset  = 0x05
+	wsFlagsOffset     = 0x06
+	wsMapperOffset    = 0x07
+	wsChecksumOffset  = 0x08
+
+	wsFlagVertical  = 0x01
+	wsFlagBus8Bit   = 0x02
+	wsFlagFastROM   = 0x04
+	wsMapperRTC     = 0x01
+	wsMaxColorValue = 0x01
+)
+
+// ROMSize represents the ROM size code from the footer.
+type ROMSize byte
+
+// ROMSize values per WSdev wiki.
+const (
+	ROMSize128KB ROMSize = 0x00
+	ROMSize256KB ROMSize = 0x01
+	ROMSize512KB ROMSize = 0x02
+	ROMSize1MB   ROMSize = 0x03
+	ROMSize2MB   ROMSize = 0x04
+	ROMSize3MB   ROMSize = 0x05
+	ROMSize4MB   ROMSize = 0x06
+	ROMSize6MB   ROMSize = 0x07
+	ROMSize8MB   ROMSize = 0x08
+	ROMSize16MB  ROMSize = 0x09
+)
+
+// romSizeBytes maps ROM size codes to sizes in bytes.
+var romSizeBytes = map[ROMSize]int{
+	ROMSize128KB: 128 * 1024,
+	ROMSize256KB: 256 * 1024,
+	ROMSize512KB: 512 * 1024,
+	ROMSize1MB:   1024 * 1024,
+	ROMSize2MB:   2 * 1024 * 1024,
+	ROMSize3MB:   3 * 1024 * 1024,
+	ROMSize4MB:   4 * 1024 * 1024,
+	ROMSize6MB:   6 * 1024 * 1024,
+	ROMSize8MB:   8 * 1024 * 1024,
+	ROMSize16MB:  16 * 1024 * 1024,
+}
+
+// SaveType represents the save type/size code from the footer.
+type SaveType byte
+
+// SaveType values per WSdev wiki.
+const (
+	SaveTypeNone       SaveType = 0x00
+	SaveTypeSRAM8KB    SaveType = 0x01
+	SaveTypeSRAM32KB   SaveType = 0x02
+	SaveTypeSRAM128KB  SaveType = 0x03
+	SaveTypeSRAM256KB  SaveType = 0x04
+	SaveTypeSRAM512KB  SaveType = 0x05
+	SaveTypeEEPROM128B SaveType = 0x10
+	SaveTypeEEPROM2KB  SaveType = 0x20
+	SaveTypeEEPROM1KB  SaveType = 0x50
+)
+
+// saveSizeBytes maps save type codes to sizes in bytes.
+var saveSizeBytes = map[SaveType]int{
+	SaveTypeNone:       0,
+	SaveTypeSRAM8KB:    8 * 1024,
+	SaveTypeSRAM32KB:   32 * 1024,
+	SaveTypeSRAM128KB:  128 * 1024,
+	SaveTypeSRAM256KB:  256 * 1024,
+	SaveTypeSRAM512KB:  512 * 1024,
+	SaveTypeEEPROM128B: 128,
+	SaveTypeEEPROM2KB:  2 * 1024,
+	SaveTypeEEPROM1KB:  1024,
+}
+
+// Info contains metadata extracted from a WonderSwan ROM footer.
+type Info struct {
+	// PublisherID is the publisher identifier.
+	PublisherID byte `json:"publisher_id"`
+	// Color indicates the game requires a WonderSwan Color.
+	Color bool `json:"color"`
+	// GameID is the game identifier (unique per publisher).
+	GameID byte `json:"game_id"`
+	// Version is the game version.
+	Version int `json:"version"`
+	// ROMSize is the ROM size code.
+	ROMSize ROMSize `json:"rom_size"`
+	// ROMSizeBytes is the ROM size in bytes (0 if the code is unknown).
+	ROMSizeBytes int `json:"rom_size_bytes"`
+	// SaveType is the save type/size code.
+	SaveType SaveType `json:"save_type"`
+	// SaveSizeBytes is the save size in bytes (0 if none or unknown).
+	SaveSizeBytes int `json:"save_size_bytes"`
+	// EEPROM indicates the save memory is EEPROM rather than SRAM.
+	EEPROM bool `json:"eeprom"`
+	// Vertical indicates the game is played with the console held vertically.
+	Vertical bool `json:"vertical"`
+	// Bus8Bit indicates an 8-bit ROM bus (16-bit otherwise).
+	Bus8Bit bool `json:"bus_8bit"`
+	// FastROM indicates 1-cycle ROM access (3 cycles otherwise).
+	FastROM bool `json:"fast_rom"`
+	// RTC indicates the Bandai 2003 mapper with a real-time clock.
+	RTC bool `json:"rtc"`
+	// Checksum is the ROM checksum from the footer.
+	// TODO: validate this checksum
+	Checksum uint16 `json:"checksum"`
+}
+
+// GamePlatform implements core.GameInfo.
+func (i *Info) GamePlatform() core.Platform {
+	if i.Color {
+		return core.PlatformWonderSwanColor
+	}
+	return core.PlatformWonderSwan
+}
+
+// GameTitle implements core.GameInfo. WonderSwan ROMs don't have embedded titles.
+func (i *Info) GameTitle() string { return "" }
+
+// GameSerial implements core.GameInfo.
+// The footer only has numeric publisher and game IDs, not the printed serial.
+func (i *Info) GameSerial() string { return "" }
+
+// GameRegions implements core.GameInfo.
+// The WonderSwan was only released in Japan.
+func (i *Info) GameRegions() []core.Region {
+	return []core.Region{core.RegionJapan}
+}
+
+// Parse extracts game information from a WonderSwan or WonderSwan Color ROM file.
+func Parse(r io.ReaderAt, size int64) (*Info, error) {
+	if size < wsFooterSize {
+		return nil, fmt.Errorf("file too small for WonderSwan footer: %d bytes", size)
+	}
+
+	footer := make([]byte, wsFooterSize)
+	if _, err := r.ReadAt(footer, size-wsFooterSize); err != nil {
+		return nil, fmt.Errorf("failed to read WonderSwan footer: %w", err)
+	}
+
+	// There is no magic, so sanity check the fields with a fixed set of values
+	color := footer[wsColorOffset]
+	if color > wsMaxColorValue {
+		return nil, fmt.Errorf("not a valid WonderSwan ROM: invalid color flag 0x%02X", color)
+	}
+	romSize := ROMSize(footer[wsROMSizeOffset])
+	romSizeBytes, ok := romSizeBytes[romSize]
+	if !ok {
+		return nil, fmt.Errorf("not a valid WonderSwan ROM: invalid ROM size code 0x%02X", romSize)
+	}
+
+	saveType := SaveType(footer[wsSaveTypeOffset])
+	flags := footer[wsFlagsOffset]
+
+	return &Info{
+		PublisherID:   footer[wsPublisherOffset],
+		Color:         color == 1,
+		GameID:        footer[wsGameIDOffset],
+		Version:       int(footer[wsVersionOffset]),
+		ROMSize:       romSize,
+		ROMSizeBytes:  romSizeBytes,
+		SaveType:      saveType,
+		SaveSizeBytes: saveSizeBytes[saveType],
+		EEPROM:        saveType >= SaveTypeEEPROM128B,
+		Vertical:      flags&wsFlagVertical != 0,
+		Bus8Bit:       flags&wsFlagBus8Bit != 0,
+		FastROM:       flags&wsFlagFastROM != 0,
+		RTC:           footer[wsMapperOffset]&wsMapperRTC != 0,
+		Checksum:      binary.LittleEndian.Uint16(footer[wsChecksumOffset:]),
+	}, nil
+}
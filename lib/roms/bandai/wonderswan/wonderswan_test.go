@@ -0,0 +1,85 @@
+package wonderswan
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// makeTestROM creates a minimal ROM with the given footer bytes.
+func makeTestROM(footer []byte) []byte {
+	rom := make([]byte, 0x100)
+	rom[len(rom)-16] = 0xEA // Far jump reset vector
+	copy(rom[len(rom)-wsFooterSize:], footer)
+	return rom
+}
+
+func TestParse_Color(t *testing.T) {
+	rom := makeTestROM([]byte{0x01, 0x01, 0x27, 0x02, byte(ROMSize2MB), byte(SaveTypeEEPROM2KB), 0x05, 0x01, 0x34, 0x12})
+
+	info, err := Parse(bytes.NewReader(rom), int64(len(rom)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.GamePlatform() != core.PlatformWonderSwanColor {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformWonderSwanColor)
+	}
+	if info.PublisherID != 0x01 || info.GameID != 0x27 || info.Version != 2 {
+		t.Errorf("PublisherID, GameID, Version = 0x%02X, 0x%02X, %d, want 0x01, 0x27, 2", info.PublisherID, info.GameID, info.Version)
+	}
+	if info.ROMSizeBytes != 2*1024*1024 {
+		t.Errorf("ROMSizeBytes = %d, want 2 MiB", info.ROMSizeBytes)
+	}
+	if !info.EEPROM || info.SaveSizeBytes != 2048 {
+		t.Errorf("EEPROM, SaveSizeBytes = %v, %d, want true, 2048", info.EEPROM, info.SaveSizeBytes)
+	}
+	if !info.Vertical || info.Bus8Bit || !info.FastROM {
+		t.Errorf("Vertical, Bus8Bit, FastROM = %v, %v, %v, want true, false, true", info.Vertical, info.Bus8Bit, info.FastROM)
+	}
+	if !info.RTC {
+		t.Error("RTC = false, want true")
+	}
+	if info.Checksum != 0x1234 {
+		t.Errorf("Checksum = 0x%04X, want 0x1234", info.Checksum)
+	}
+}
+
+func TestParse_Mono(t *testing.T) {
+	rom := makeTestROM([]byte{0x01, 0x00, 0x01, 0x00, byte(ROMSize512KB), byte(SaveTypeSRAM32KB), 0x04, 0x00, 0x00, 0x00})
+
+	info, err := Parse(bytes.NewReader(rom), int64(len(rom)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.GamePlatform() != core.PlatformWonderSwan {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformWonderSwan)
+	}
+	if info.EEPROM || info.SaveSizeBytes != 32*1024 {
+		t.Errorf("EEPROM, SaveSizeBytes = %v, %d, want false, 32768", info.EEPROM, info.SaveSizeBytes)
+	}
+	if info.Vertical {
+		t.Error("Vertical = true, want false")
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		footer []byte
+	}{
+		{"invalid color flag", []byte{0x01, 0x02, 0x01, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		{"invalid ROM size", []byte{0x01, 0x00, 0x01, 0x00, 0x0F, 0x00, 0x00, 0x00, 0x00, 0x00}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rom := makeTestROM(tc.footer)
+			if _, err := Parse(bytes.NewReader(rom), int64(len(rom))); err == nil {
+				t.Error("Parse() expected error, got nil")
+			}
+		})
+	}
+}
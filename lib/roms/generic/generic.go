@@ -0,0 +1,144 @@
+// Package generic identifies the platform of headerless ROM formats.
+package generic
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// Many older systems have no ROM header, so the only identifying information
+// is the file extension. Where possible the extension is confirmed by the
+// file size or by bytes the system BIOS checks at boot:
+//
+//	System         Magic                Offset
+//	Atari 7800     "ATARI7800"          0x01 (A78 header)
+//	Atari Lynx     "LYNX"               0x00 (LNX header)
+//	ColecoVision   0xAA 0x55/0x55 0xAA  0x00 (cartridge test bytes)
+//	Intellivision  0xA8                 0x00 (.rom format header)
+//	Vectrex        "g GCE"              0x00 (copyright string)
+//	MSX            "AB"                 0x00 or 0x4000 (cartridge ID)
+//
+// Games are identified by their hashes alone, so Info only carries the platform.
+
+// format describes a headerless ROM format.
+type format struct {
+	platform   core.Platform
+	extensions []string
+
+	// maxSize bounds the file size (0 = no bound).
+	maxSize int64
+
+	// magic lists byte sequences of which one must appear at magicOffset
+	// (empty = no check).
+	magic       [][]byte
+	magicOffset int64
+}
+
+const (
+	kib = 1024
+	mib = 1024 * kib
+)
+
+// formats lists the known headerless formats. Formats sharing an extension
+// are tried in order.
+var formats = []format{
+	{platform: core.PlatformAtari2600, extensions: []string{".a26"}, maxSize: 512 * kib},
+	{platform: core.PlatformAtari5200, extensions: []string{".a52"}, maxSize: 512 * kib},
+	{platform: core.PlatformAtari7800, extensions: []string{".a78"}, magic: [][]byte{[]byte("ATARI7800")}, magicOffset: 0x01},
+	{platform: core.PlatformAtariLynx, extensions: []string{".lnx"}, magic: [][]byte{[]byte("LYNX")}},
+	{platform: core.PlatformAtariLynx, extensions: []string{".lyx"}, maxSize: 512 * kib},
+	{platform: core.PlatformAtariJaguar, extensions: []string{".j64"}, maxSize: 6 * mib},
+	{platform: core.PlatformPCEngine, extensions: []string{".pce"}, maxSize: 2560*kib + 512}, // Optional 512-byte copier header
+	{platform: core.PlatformSuperGrafx, extensions: []string{".sgx"}, maxSize: 2560*kib + 512},
+	{platform: core.PlatformSG1000, extensions: []string{".sg", ".sc"}, maxSize: 1 * mib},
+	{platform: core.PlatformColecoVision, extensions: []string{".col"}, magic: [][]byte{{0xAA, 0x55}, {0x55, 0xAA}}},
+	{platform: core.PlatformIntellivision, extensions: []string{".int"}, maxSize: 1 * mib},
+	{platform: core.PlatformVectrex, extensions: []string{".vec", ".gam"}, magic: [][]byte{[]byte("g GCE")}},
+	{platform: core.PlatformChannelF, extensions: []string{".chf"}, maxSize: 256 * kib},
+	{platform: core.PlatformSupervision, extensions: []string{".sv"}, maxSize: 512 * kib},
+	{platform: core.PlatformMSX, extensions: []string{".mx1"}},
+	{platform: core.PlatformMSX2, extensions: []string{".mx2"}},
+
+	// .rom is shared by several systems, so it always requires a magic match
+	{platform: core.PlatformMSX, extensions: []string{".rom"}, magic: [][]byte{[]byte("AB")}},
+	{platform: core.PlatformMSX, extensions: []string{".rom"}, magic: [][]byte{[]byte("AB")}, magicOffset: 0x4000},
+	{platform: core.PlatformIntellivision, extensions: []string{".rom"}, magic: [][]byte{{0xA8}}},
+}
+
+// Info is the identification of a headerless ROM. Only the platform is known.
+type Info struct {
+	Platform core.Platform `json:"platform"`
+}
+
+// GamePlatform implements core.GameInfo.
+func (i *Info) GamePlatform() core.Platform { return i.Platform }
+
+// GameTitle implements core.GameInfo. Headerless ROMs have no title.
+func (i *Info) GameTitle() string { return "" }
+
+// GameSerial implements core.GameInfo. Headerless ROMs have no serial.
+func (i *Info) GameSerial() string { return "" }
+
+// GameRegions implements core.GameInfo. Headerless ROMs have no region.
+func (i *Info) GameRegions() []core.Region { return nil }
+
+// Extensions returns every extension with a known headerless format.
+func Extensions() []string {
+	var exts []string
+	for _, f := range formats {
+		for _, ext := range f.extensions {
+			if !slices.Contains(exts, ext) {
+				exts = append(exts, ext)
+			}
+		}
+	}
+	return exts
+}
+
+// Parse identifies the platform of a headerless ROM from its extension
+// (including the leading dot), confirmed by size and magic where the format
+// defines them.
+func Parse(r io.ReaderAt, size int64, ext string) (*Info, error) {
+	ext = strings.ToLower(ext)
+	known := false
+	for _, f := range formats {
+		if !slices.Contains(f.extensions, ext) {
+			continue
+		}
+		known = true
+		if f.matches(r, size) {
+			return &Info{Platform: f.platform}, nil
+		}
+	}
+
+	if !known {
+		return nil, fmt.Errorf("unknown headerless ROM extension: %q", ext)
+	}
+	return nil, fmt.Errorf("not a recognized %s ROM", ext)
+}
+
+// matches reports whether a file satisfies the format's size and magic checks.
+func (f *format) matches(r io.ReaderAt, size int64) bool {
+	if size <= 0 || (f.maxSize > 0 && size > f.maxSize) {
+		return false
+	}
+	if len(f.magic) == 0 {
+		return true
+	}
+
+	for _, magic := range f.magic {
+		buf := make([]byte, len(magic))
+		if _, err := r.ReadAt(buf, f.magicOffset); err != nil {
+			continue
+		}
+		if bytes.Equal(buf, magic) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package generic
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+func TestParse(t *testing.T) {
+	msxOffset := make([]byte, 0x8000)
+	copy(msxOffset[0x4000:], "AB")
+
+	tests := []struct {
+		name string
+		ext  string
+		rom  []byte
+		want core.Platform
+	}{
+		{"atari 2600", ".a26", make([]byte, 4096), core.PlatformAtari2600},
+		{"uppercase extension", ".A26", make([]byte, 4096), core.PlatformAtari2600},
+		{"atari 7800", ".a78", append([]byte{0x01}, "ATARI7800"...), core.PlatformAtari7800},
+		{"lynx", ".lnx", []byte("LYNX\x00\x02"), core.PlatformAtariLynx},
+		{"colecovision", ".col", []byte{0xAA, 0x55, 0x00, 0x00}, core.PlatformColecoVision},
+		{"colecovision test mode", ".col", []byte{0x55, 0xAA, 0x00, 0x00}, core.PlatformColecoVision},
+		{"vectrex", ".vec", []byte("g GCE 1982\x80"), core.PlatformVectrex},
+		{"sg-1000", ".sg", make([]byte, 32768), core.PlatformSG1000},
+		{"sc-3000", ".sc", make([]byte, 32768), core.PlatformSG1000},
+		{"intellivision", ".int", make([]byte, 8192), core.PlatformIntellivision},
+		{"msx rom", ".rom", []byte("AB\x10\x40"), core.PlatformMSX},
+		{"msx rom at 0x4000", ".rom", msxOffset, core.PlatformMSX},
+		{"intellivision rom", ".rom", []byte{0xA8, 0x01, 0x57}, core.PlatformIntellivision},
+		{"msx2", ".mx2", []byte{0x00}, core.PlatformMSX2},
+		{"pc engine", ".pce", make([]byte, 256*1024), core.PlatformPCEngine},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Parse(bytes.NewReader(tt.rom), int64(len(tt.rom)), tt.ext)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if info.GamePlatform() != tt.want {
+				t.Errorf("GamePlatform() = %q, want %q", info.GamePlatform(), tt.want)
+			}
+			if info.GameTitle() != "" || info.GameSerial() != "" || info.GameRegions() != nil {
+				t.Errorf("expected no title, serial or regions, got %+v", info)
+			}
+		})
+	}
+}
+
+func TestParse_Rejected(t *testing.T) {
+	tests := []struct {
+		name string
+		ext  string
+		rom  []byte
+	}{
+		{"unknown extension", ".xyz", make([]byte, 16)},
+		{"empty file", ".a26", nil},
+		{"too large", ".a26", make([]byte, 1024*1024)},
+		{"bad magic", ".col", []byte{0x00, 0x00, 0x00, 0x00}},
+		{"short file", ".vec", []byte("g G")},
+		{"unrecognized rom", ".rom", make([]byte, 0x8000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(bytes.NewReader(tt.rom), int64(len(tt.rom)), tt.ext); err == nil {
+				t.Error("Parse() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	exts := Extensions()
+	for _, ext := range []string{".a26", ".col", ".int", ".vec", ".sg", ".rom", ".mx1"} {
+		if !slices.Contains(exts, ext) {
+			t.Errorf("Extensions() missing %q", ext)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, ext := range exts {
+		if seen[ext] {
+			t.Errorf("Extensions() contains %q twice", ext)
+		}
+		seen[ext] = true
+	}
+}
@@ -0,0 +1,191 @@
+package ciso
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/roms/nintendo/gcm"
+)
+
+// CISO (compact ISO) GameCube/Wii disc image format parsing.
+//
+// Format reference:
+// https://github.com/dolphin-emu/dolphin/blob/master/Source/Core/DiscIO/CISOBlob.h
+//
+// CISO header (0x8000 bytes):
+//
+//	Offset  Size    Description
+//	0x0000  4       Magic ("CISO")
+//	0x0004  4       Block size (little-endian)
+//	0x0008  0x7FF8  Block map (1 = block present, 0 = block is unused)
+//
+// Present blocks are stored in order after the header. Unused blocks read
+// as zeros. CISO files do not record the original disc size, so it is derived
+// from the disc type and the last present block.
+
+const (
+	headerSize = 0x8000
+	magic      = "CISO"
+	mapOffset  = 0x0008
+	mapSize    = headerSize - mapOffset
+
+	blockSizeOffset = 0x0004
+	maxBlockSize    = 64 * 1024 * 1024
+
+	unusedBlock = -1
+
+	// discHeaderPeek covers the disc header up to the Wii magic word
+	discHeaderPeek = 0x20
+)
+
+// Reader provides random access to the original disc inside a CISO file.
+type Reader struct {
+	r         io.ReaderAt
+	blockSize int64
+	blocks    []int64 // Index of each disc block in the file data, or unusedBlock
+	size      int64
+}
+
+// NewReader opens a CISO file.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	if size < headerSize {
+		return nil, fmt.Errorf("file too small for CISO header: %d bytes", size)
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read CISO header: %w", err)
+	}
+
+	if string(header[:len(magic)]) != magic {
+		return nil, fmt.Errorf("not a valid CISO file: invalid magic %q", header[:len(magic)])
+	}
+
+	blockSize := int64(binary.LittleEndian.Uint32(header[blockSizeOffset:]))
+	if blockSize == 0 || blockSize > maxBlockSize {
+		return nil, fmt.Errorf("invalid CISO block size: %d", blockSize)
+	}
+
+	blocks := make([]int64, mapSize)
+	var present int64
+	lastUsed := -1
+	for i, used := range header[mapOffset:] {
+		if used == 1 {
+			blocks[i] = present
+			present++
+			lastUsed = i
+		} else {
+			blocks[i] = unusedBlock
+		}
+	}
+	if lastUsed < 0 {
+		return nil, fmt.Errorf("CISO file has no data blocks")
+	}
+	if headerSize+present*blockSize > size {
+		return nil, fmt.Errorf("CISO data extends beyond file: %d blocks of %d bytes", present, blockSize)
+	}
+
+	reader := &Reader{
+		r:         r,
+		blockSize: blockSize,
+		blocks:    blocks,
+	}
+
+	// Trailing unused blocks are not recorded; size the disc from its header
+	discHeader := make([]byte, discHeaderPeek)
+	if _, err := reader.readBlocks(discHeader, 0, int64(lastUsed+1)*blockSize); err != nil {
+		return nil, err
+	}
+	reader.size = min(
+		gcm.StandardDiscSize(gcm.IsWiiDisc(discHeader), int64(lastUsed+1)*blockSize),
+		int64(mapSize)*blockSize,
+	)
+
+	return reader, nil
+}
+
+// Size returns the size of the original disc.
+func (c *Reader) Size() int64 {
+	return c.size
+}
+
+// ReadAt implements io.ReaderAt over the original disc.
+func (c *Reader) ReadAt(p []byte, off int64) (int, error) {
+	return c.readBlocks(p, off, c.size)
+}
+
+func (c *Reader) readBlocks(p []byte, off, size int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset")
+	}
+
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= size {
+			return n, io.EOF
+		}
+
+		block := pos / c.blockSize
+		offsetInBlock := pos % c.blockSize
+		chunk := p[n:min(len(p), n+int(c.blockSize-offsetInBlock), n+int(size-pos))]
+
+		if c.blocks[block] == unusedBlock {
+			clear(chunk)
+		} else {
+			fileOffset := headerSize + c.blocks[block]*c.blockSize + offsetInBlock
+			if _, err := c.r.ReadAt(chunk, fileOffset); err != nil {
+				return n, fmt.Errorf("failed to read CISO block %d: %w", block, err)
+			}
+		}
+		n += len(chunk)
+	}
+	return n, nil
+}
+
+// Info contains metadata extracted from a CISO file.
+type Info struct {
+	// GCM contains the game identification info parsed from the disc.
+	GCM *gcm.Info `json:"gcm,omitempty"`
+	// BlockSize is the size of each block.
+	BlockSize int64 `json:"block_size"`
+	// DiscSize is the size of the original disc.
+	DiscSize int64 `json:"disc_size"`
+}
+
+// GamePlatform implements core.GameInfo by delegating to GCM.
+func (i *Info) GamePlatform() core.Platform { return i.GCM.GamePlatform() }
+
+// GameTitle implements core.GameInfo by delegating to GCM.
+func (i *Info) GameTitle() string { return i.GCM.GameTitle() }
+
+// GameSerial implements core.GameInfo by delegating to GCM.
+func (i *Info) GameSerial() string { return i.GCM.GameSerial() }
+
+// GameRegions implements core.GameInfo by delegating to GCM.
+func (i *Info) GameRegions() []core.Region { return i.GCM.GameRegions() }
+
+// GameIcon implements core.IconProvider by delegating to GCM.
+func (i *Info) GameIcon() image.Image { return i.GCM.GameIcon() }
+
+// Parse opens a CISO file and parses the disc header of the original disc.
+func Parse(r io.ReaderAt, size int64) (*Info, error) {
+	reader, err := NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	gcmInfo, err := gcm.Parse(reader, reader.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse disc header from CISO: %w", err)
+	}
+
+	return &Info{
+		GCM:       gcmInfo,
+		BlockSize: reader.blockSize,
+		DiscSize:  reader.Size(),
+	}, nil
+}
@@ -0,0 +1,129 @@
+package ciso
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/roms/nintendo/gcm"
+)
+
+const testBlockSize = 0x200000 // 2 MiB, as written by common tools
+
+// makeSyntheticDiscHeader creates a synthetic GameCube/Wii disc header for testing.
+func makeSyntheticDiscHeader(gameID string, title string, isWii bool) []byte {
+	header := make([]byte, 0x400)
+	copy(header, gameID)
+	copy(header[0x04:], "01")
+	if isWii {
+		binary.BigEndian.PutUint32(header[0x18:], 0x5D1C9EA3)
+	} else {
+		binary.BigEndian.PutUint32(header[0x1C:], 0xC2339F3D)
+	}
+	copy(header[0x20:], title)
+	return header
+}
+
+// makeSyntheticCISO builds a CISO file. Each entry of blocks is the content of
+// a disc block, or nil for an unused block.
+func makeSyntheticCISO(blocks [][]byte) []byte {
+	file := make([]byte, headerSize)
+	copy(file, magic)
+	binary.LittleEndian.PutUint32(file[blockSizeOffset:], testBlockSize)
+	for i, block := range blocks {
+		if block == nil {
+			continue
+		}
+		file[mapOffset+i] = 1
+		data := make([]byte, testBlockSize)
+		copy(data, block)
+		file = append(file, data...)
+	}
+	return file
+}
+
+func TestParse_GameCube(t *testing.T) {
+	file := makeSyntheticCISO([][]byte{makeSyntheticDiscHeader("GALE", "Test GameCube Game", false)})
+
+	info, err := Parse(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.GamePlatform() != core.PlatformGC {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformGC)
+	}
+	if info.GameTitle() != "Test GameCube Game" {
+		t.Errorf("GameTitle() = %q, want %q", info.GameTitle(), "Test GameCube Game")
+	}
+	if info.GameSerial() != "GALE" {
+		t.Errorf("GameSerial() = %q, want %q", info.GameSerial(), "GALE")
+	}
+	if info.BlockSize != testBlockSize {
+		t.Errorf("BlockSize = %d, want %d", info.BlockSize, testBlockSize)
+	}
+	if info.DiscSize != gcm.GameCubeDiscSize {
+		t.Errorf("DiscSize = %d, want %d", info.DiscSize, gcm.GameCubeDiscSize)
+	}
+}
+
+func TestParse_Wii(t *testing.T) {
+	file := makeSyntheticCISO([][]byte{makeSyntheticDiscHeader("RSBE", "Test Wii Game", true)})
+
+	info, err := Parse(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.GamePlatform() != core.PlatformWii {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformWii)
+	}
+	if info.DiscSize != gcm.WiiSingleLayerDiscSize {
+		t.Errorf("DiscSize = %d, want %d", info.DiscSize, gcm.WiiSingleLayerDiscSize)
+	}
+}
+
+func TestReader_ReadAt(t *testing.T) {
+	header := makeSyntheticDiscHeader("GALE", "Test", false)
+	second := bytes.Repeat([]byte{0xAA}, testBlockSize)
+	file := makeSyntheticCISO([][]byte{header, nil, second})
+
+	reader, err := NewReader(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	// Unused block reads as zeros
+	got := make([]byte, 0x10)
+	if _, err := reader.ReadAt(got, testBlockSize+0x10); err != nil {
+		t.Fatalf("ReadAt() error = %v", err)
+	}
+	if !bytes.Equal(got, make([]byte, 0x10)) {
+		t.Errorf("ReadAt() unused block = %X, want zeros", got)
+	}
+
+	// Present blocks are remapped past the unused one
+	got = make([]byte, 0x20)
+	if _, err := reader.ReadAt(got, 3*testBlockSize-0x10); err != nil {
+		t.Fatalf("ReadAt() error = %v", err)
+	}
+	want := append(bytes.Repeat([]byte{0xAA}, 0x10), make([]byte, 0x10)...)
+	if !bytes.Equal(got, want) {
+		t.Errorf("ReadAt() across block boundary = %X, want %X", got, want)
+	}
+}
+
+func TestParse_InvalidMagic(t *testing.T) {
+	file := make([]byte, headerSize)
+	if _, err := Parse(bytes.NewReader(file), int64(len(file))); err == nil {
+		t.Error("Parse() expected error for invalid magic")
+	}
+}
+
+func TestParse_NoBlocks(t *testing.T) {
+	file := makeSyntheticCISO(nil)
+	if _, err := Parse(bytes.NewReader(file), int64(len(file))); err == nil {
+		t.Error("Parse() expected error for CISO without blocks")
+	}
+}
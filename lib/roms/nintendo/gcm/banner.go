@@ -0,0 +1,257 @@
+package gcm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/sargunv/rom-tools/internal/util"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// GameCube banner (opening.bnr) parsing.
+//
+// Banner specification:
+// https://www.gc-forever.com/yagcd/chap14.html#sec14.1
+//
+// Banner layout (big-endian):
+//
+//	Offset  Size    Description
+//	0x0000  4       Magic ("BNR1" for NTSC, "BNR2" for PAL)
+//	0x0004  0x1C    Padding
+//	0x0020  0x1800  Banner image (96x32, RGB5A3, 4x4 tiles)
+//	0x1820  0x140   Description entries (1 for BNR1, 6 for BNR2)
+//
+// Description entry layout:
+//
+//	Offset  Size  Description
+//	0x00    0x20  Short game name
+//	0x20    0x20  Short developer name
+//	0x40    0x40  Full game name
+//	0x80    0x40  Full developer name
+//	0xC0    0x80  Description
+//
+// BNR1 banners hold a single entry in Shift-JIS on Japanese discs and in
+// Windows-1252 elsewhere. BNR2 banners hold English, German, French,
+// Spanish, Italian and Dutch entries in Windows-1252.
+
+// BannerFileName is the name of the banner file in the disc root.
+const BannerFileName = "opening.bnr"
+
+const (
+	bannerMagicBNR1     = "BNR1"
+	bannerMagicBNR2     = "BNR2"
+	bannerImageOffset   = 0x0020
+	bannerImageSize     = 0x1800
+	bannerEntriesOffset = 0x1820
+	bannerEntrySize     = 0x140
+	bannerSizeBNR1      = bannerEntriesOffset + bannerEntrySize
+	bannerSizeBNR2      = bannerEntriesOffset + 6*bannerEntrySize
+
+	// BannerWidth and BannerHeight are the banner image dimensions.
+	BannerWidth  = 96
+	BannerHeight = 32
+)
+
+// Language identifies a banner description entry.
+type Language string
+
+// Language values.
+const (
+	LanguageJapanese Language = "ja"
+	LanguageEnglish  Language = "en"
+	LanguageGerman   Language = "de"
+	LanguageFrench   Language = "fr"
+	LanguageSpanish  Language = "es"
+	LanguageItalian  Language = "it"
+	LanguageDutch    Language = "nl"
+)
+
+// bnr2Languages lists the languages of BNR2 description entries in order.
+var bnr2Languages = []Language{
+	LanguageEnglish,
+	LanguageGerman,
+	LanguageFrench,
+	LanguageSpanish,
+	LanguageItalian,
+	LanguageDutch,
+}
+
+// BannerDescription is a localized description entry from the banner.
+type BannerDescription struct {
+	// ShortName is the short game name.
+	ShortName string `json:"short_name,omitempty"`
+	// ShortMaker is the short developer name.
+	ShortMaker string `json:"short_maker,omitempty"`
+	// LongName is the full game name.
+	LongName string `json:"long_name,omitempty"`
+	// LongMaker is the full developer name.
+	LongMaker string `json:"long_maker,omitempty"`
+	// Description is the game description shown in the system menu.
+	Description string `json:"description,omitempty"`
+}
+
+// Name returns the full game name, falling back to the short name.
+// Line breaks are replaced with spaces.
+func (d BannerDescription) Name() string {
+	name := d.LongName
+	if name == "" {
+		name = d.ShortName
+	}
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// Maker returns the full developer name, falling back to the short name.
+func (d BannerDescription) Maker() string {
+	if d.LongMaker != "" {
+		return d.LongMaker
+	}
+	return d.ShortMaker
+}
+
+// Banner contains the contents of a GameCube opening.bnr file.
+type Banner struct {
+	// Magic is the banner format ("BNR1" or "BNR2").
+	Magic string `json:"magic"`
+	// Descriptions maps languages to their description entries. Empty entries are omitted.
+	Descriptions map[Language]BannerDescription `json:"descriptions,omitempty"`
+	// Image is the 96x32 banner image.
+	Image image.Image `json:"-"`
+}
+
+// Title returns the game name, preferring English, then Japanese, then any other language.
+func (b *Banner) Title() string {
+	return b.localized(BannerDescription.Name)
+}
+
+// Maker returns the developer name, preferring English, then Japanese, then any other language.
+func (b *Banner) Maker() string {
+	return b.localized(BannerDescription.Maker)
+}
+
+// Description returns the game description, preferring English, then Japanese, then any other language.
+func (b *Banner) Description() string {
+	return b.localized(func(d BannerDescription) string { return d.Description })
+}
+
+func (b *Banner) localized(field func(BannerDescription) string) string {
+	for _, lang := range append([]Language{LanguageEnglish, LanguageJapanese}, bnr2Languages...) {
+		if v := field(b.Descriptions[lang]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ParseBanner parses a GameCube opening.bnr file. The disc region selects the
+// text encoding and language of BNR1 banners.
+func ParseBanner(r io.ReaderAt, size int64, region Region) (*Banner, error) {
+	if size < bannerSizeBNR1 {
+		return nil, fmt.Errorf("file too small for GameCube banner: %d bytes (need %d)", size, bannerSizeBNR1)
+	}
+
+	magic := make([]byte, 4)
+	if _, err := r.ReadAt(magic, 0); err != nil {
+		return nil, fmt.Errorf("failed to read banner magic: %w", err)
+	}
+
+	var bannerSize int64
+	var languages []Language
+	switch string(magic) {
+	case bannerMagicBNR1:
+		bannerSize = bannerSizeBNR1
+		languages = []Language{LanguageEnglish}
+		if region == RegionJapan {
+			languages = []Language{LanguageJapanese}
+		}
+	case bannerMagicBNR2:
+		bannerSize = bannerSizeBNR2
+		languages = bnr2Languages
+	default:
+		return nil, fmt.Errorf("not a valid GameCube banner: invalid magic %q", magic)
+	}
+	if bannerSize > size {
+		return nil, fmt.Errorf("GameCube banner %s extends beyond file", magic)
+	}
+
+	data := make([]byte, bannerSize)
+	if _, err := r.ReadAt(data, 0); err != nil {
+		return nil, fmt.Errorf("failed to read banner: %w", err)
+	}
+
+	descriptions := make(map[Language]BannerDescription)
+	for i, lang := range languages {
+		entry := data[bannerEntriesOffset+i*bannerEntrySize:]
+		decode := decodeWindows1252
+		if lang == LanguageJapanese {
+			decode = util.ExtractShiftJIS
+		}
+		desc := BannerDescription{
+			ShortName:   decode(entry[0x00:0x20]),
+			ShortMaker:  decode(entry[0x20:0x40]),
+			LongName:    decode(entry[0x40:0x80]),
+			LongMaker:   decode(entry[0x80:0xC0]),
+			Description: decode(entry[0xC0:0x140]),
+		}
+		if desc != (BannerDescription{}) {
+			descriptions[lang] = desc
+		}
+	}
+
+	return &Banner{
+		Magic:        string(magic),
+		Descriptions: descriptions,
+		Image:        decodeRGB5A3(data[bannerImageOffset:bannerImageOffset+bannerImageSize], BannerWidth, BannerHeight),
+	}, nil
+}
+
+// decodeRGB5A3 decodes a 4x4-tiled RGB5A3 texture.
+// Pixels with the top bit set are opaque RGB555; others are 3-bit alpha with RGB444.
+func decodeRGB5A3(data []byte, width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	i := 0
+	for tileY := 0; tileY < height; tileY += 4 {
+		for tileX := 0; tileX < width; tileX += 4 {
+			for y := tileY; y < tileY+4; y++ {
+				for x := tileX; x < tileX+4; x++ {
+					img.SetNRGBA(x, y, rgb5a3(binary.BigEndian.Uint16(data[i:])))
+					i += 2
+				}
+			}
+		}
+	}
+	return img
+}
+
+// rgb5a3 converts an RGB5A3 pixel to a color.
+func rgb5a3(p uint16) color.NRGBA {
+	if p&0x8000 != 0 {
+		r, g, b := byte(p>>10)&0x1F, byte(p>>5)&0x1F, byte(p)&0x1F
+		return color.NRGBA{R: r<<3 | r>>2, G: g<<3 | g>>2, B: b<<3 | b>>2, A: 0xFF}
+	}
+	a := byte(p>>12) & 0x07
+	r, g, b := byte(p>>8)&0x0F, byte(p>>4)&0x0F, byte(p)&0x0F
+	return color.NRGBA{R: r<<4 | r, G: g<<4 | g, B: b<<4 | b, A: a<<5 | a<<2 | a>>1}
+}
+
+// decodeWindows1252 decodes a null-terminated Windows-1252 string.
+func decodeWindows1252(data []byte) string {
+	decoded, err := charmap.Windows1252.NewDecoder().Bytes(trimNull(data))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(decoded))
+}
+
+// trimNull returns data up to the first null byte.
+func trimNull(data []byte) []byte {
+	for i, b := range data {
+		if b == 0 {
+			return data[:i]
+		}
+	}
+	return data
+}
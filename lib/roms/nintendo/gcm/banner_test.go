@@ -0,0 +1,134 @@
+package gcm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// makeTestBanner creates a banner with the given description entries.
+// The first image pixel is opaque red and the second is half-transparent blue.
+func makeTestBanner(magic string, entries []BannerDescription, encode func(string) []byte) []byte {
+	size := bannerSizeBNR1
+	if magic == bannerMagicBNR2 {
+		size = bannerSizeBNR2
+	}
+	data := make([]byte, size)
+	copy(data, magic)
+	binary.BigEndian.PutUint16(data[bannerImageOffset:], 0x8000|0x1F<<10)
+	binary.BigEndian.PutUint16(data[bannerImageOffset+2:], 0x4<<12|0xF)
+
+	for i, e := range entries {
+		entry := data[bannerEntriesOffset+i*bannerEntrySize:]
+		copy(entry[0x00:0x20], encode(e.ShortName))
+		copy(entry[0x20:0x40], encode(e.ShortMaker))
+		copy(entry[0x40:0x80], encode(e.LongName))
+		copy(entry[0x80:0xC0], encode(e.LongMaker))
+		copy(entry[0xC0:0x140], encode(e.Description))
+	}
+	return data
+}
+
+func ascii(s string) []byte { return []byte(s) }
+
+func TestParseBanner_BNR2(t *testing.T) {
+	data := makeTestBanner(bannerMagicBNR2, []BannerDescription{
+		{ShortName: "Test", ShortMaker: "Dev", LongName: "Test Game:\nThe Sequel", LongMaker: "Developer Inc.", Description: "A game."},
+		{ShortName: "Testspiel", LongName: "Das Testspiel"},
+	}, ascii)
+
+	banner, err := ParseBanner(bytes.NewReader(data), int64(len(data)), RegionEurope)
+	if err != nil {
+		t.Fatalf("ParseBanner() error = %v", err)
+	}
+
+	if banner.Magic != bannerMagicBNR2 {
+		t.Errorf("Magic = %q, want %q", banner.Magic, bannerMagicBNR2)
+	}
+	if len(banner.Descriptions) != 2 {
+		t.Errorf("len(Descriptions) = %d, want 2", len(banner.Descriptions))
+	}
+	if got := banner.Title(); got != "Test Game: The Sequel" {
+		t.Errorf("Title() = %q, want %q", got, "Test Game: The Sequel")
+	}
+	if got := banner.Maker(); got != "Developer Inc." {
+		t.Errorf("Maker() = %q, want %q", got, "Developer Inc.")
+	}
+	if got := banner.Description(); got != "A game." {
+		t.Errorf("Description() = %q, want %q", got, "A game.")
+	}
+	if got := banner.Descriptions[LanguageGerman].Name(); got != "Das Testspiel" {
+		t.Errorf("German Name() = %q, want %q", got, "Das Testspiel")
+	}
+
+	bounds := banner.Image.Bounds()
+	if bounds.Dx() != BannerWidth || bounds.Dy() != BannerHeight {
+		t.Errorf("Image size = %dx%d, want %dx%d", bounds.Dx(), bounds.Dy(), BannerWidth, BannerHeight)
+	}
+	if got := color.NRGBAModel.Convert(banner.Image.At(0, 0)); got != (color.NRGBA{R: 0xFF, A: 0xFF}) {
+		t.Errorf("pixel (0,0) = %v, want opaque red", got)
+	}
+	if got := color.NRGBAModel.Convert(banner.Image.At(1, 0)); got != (color.NRGBA{B: 0xFF, A: 0x92}) {
+		t.Errorf("pixel (1,0) = %v, want translucent blue", got)
+	}
+}
+
+func TestParseBanner_BNR1Japanese(t *testing.T) {
+	// "ゲーム" in Shift-JIS
+	sjis := []byte{0x83, 0x51, 0x81, 0x5B, 0x83, 0x80}
+	data := makeTestBanner(bannerMagicBNR1, []BannerDescription{{LongName: "x"}}, func(string) []byte { return sjis })
+
+	banner, err := ParseBanner(bytes.NewReader(data), int64(len(data)), RegionJapan)
+	if err != nil {
+		t.Fatalf("ParseBanner() error = %v", err)
+	}
+
+	if got := banner.Descriptions[LanguageJapanese].LongName; got != "ゲーム" {
+		t.Errorf("Japanese LongName = %q, want %q", got, "ゲーム")
+	}
+	if got := banner.Title(); got != "ゲーム" {
+		t.Errorf("Title() = %q, want %q", got, "ゲーム")
+	}
+}
+
+func TestParseBanner_Invalid(t *testing.T) {
+	data := make([]byte, bannerSizeBNR2)
+	copy(data, "XXXX")
+
+	if _, err := ParseBanner(bytes.NewReader(data), int64(len(data)), RegionEurope); err == nil {
+		t.Error("ParseBanner() expected error for invalid magic")
+	}
+
+	copy(data, bannerMagicBNR2)
+	if _, err := ParseBanner(bytes.NewReader(data[:bannerSizeBNR1]), bannerSizeBNR1, RegionEurope); err == nil {
+		t.Error("ParseBanner() expected error for truncated BNR2")
+	}
+}
+
+func TestParse_Banner(t *testing.T) {
+	bnr := makeTestBanner(bannerMagicBNR1, []BannerDescription{{LongName: "Full Game Name"}}, ascii)
+	disc := makeSyntheticDisc(RegionNorthAmerica, bnr)
+
+	info, err := Parse(bytes.NewReader(disc), int64(len(disc)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.Banner == nil {
+		t.Fatal("Banner = nil, want banner")
+	}
+	if info.Title != "TEST GAME HEADER" {
+		t.Errorf("Title = %q, want %q", info.Title, "TEST GAME HEADER")
+	}
+	if got := info.GameTitle(); got != "Full Game Name" {
+		t.Errorf("GameTitle() = %q, want %q", got, "Full Game Name")
+	}
+
+	var provider core.IconProvider = info
+	if icon := provider.GameIcon(); icon == nil || icon.Bounds().Dx() != BannerWidth {
+		t.Errorf("GameIcon() = %v, want %dx%d banner", icon, BannerWidth, BannerHeight)
+	}
+}
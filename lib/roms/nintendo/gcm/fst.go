@@ -0,0 +1,326 @@
+package gcm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+)
+
+// GameCube file system table (FST) parsing.
+//
+// FST specification:
+// https://wiki.tockdom.com/wiki/Filesystem_(GameCube)
+//
+// The disc header stores the FST location at 0x424 and its size at 0x428.
+// On Wii partitions these values (and the file offsets in the FST) are
+// stored shifted right by 2.
+//
+// FST layout:
+//
+//	Offset    Size       Description
+//	0x00      12 * n     Entries (the first entry is the root directory)
+//	12 * n    variable   String table (null-terminated names)
+//
+// FST entry layout (12 bytes, big-endian):
+//
+//	Offset  Size  Description
+//	0x00    1     Flags (0 = file, 1 = directory)
+//	0x01    3     Name offset in string table
+//	0x04    4     File: data offset; Directory: parent entry index
+//	0x08    4     File: data length; Directory: index of the first entry after it
+//	              (for the root, the total number of entries)
+
+const (
+	fstOffsetOffset = 0x424
+	fstSizeOffset   = 0x428
+	fstEntrySize    = 12
+	maxFSTSize      = 64 * 1024 * 1024
+
+	fstFlagDirectory = 1
+)
+
+// fstNode is a file or directory in the FST.
+type fstNode struct {
+	name     string
+	dir      bool
+	offset   int64
+	size     int64
+	children []*fstNode
+}
+
+// FS provides access to the files of a GameCube disc (or a decrypted Wii
+// partition) through the io/fs interfaces.
+type FS struct {
+	r    io.ReaderAt
+	root *fstNode
+}
+
+var (
+	_ fs.FS        = (*FS)(nil)
+	_ fs.ReadDirFS = (*FS)(nil)
+	_ fs.StatFS    = (*FS)(nil)
+)
+
+// NewFS reads the FST of a GameCube disc image (or decrypted Wii partition data).
+func NewFS(r io.ReaderAt, size int64) (*FS, error) {
+	header := make([]byte, discHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read disc header: %w", err)
+	}
+	isWii := binary.BigEndian.Uint32(header[wiiMagicOffset:]) == wiiMagicWord
+	isGC := binary.BigEndian.Uint32(header[gcMagicOffset:]) == gcMagicWord
+	if !isWii && !isGC {
+		return nil, fmt.Errorf("not a valid GameCube/Wii disc: no magic word found")
+	}
+
+	// Wii partitions store offsets divided by 4
+	var shift uint
+	if isWii {
+		shift = 2
+	}
+
+	buf := make([]byte, 8)
+	if _, err := r.ReadAt(buf, fstOffsetOffset); err != nil {
+		return nil, fmt.Errorf("failed to read FST location: %w", err)
+	}
+	fstOffset := int64(binary.BigEndian.Uint32(buf[0:4])) << shift
+	fstSize := int64(binary.BigEndian.Uint32(buf[4:8])) << shift
+
+	if fstOffset == 0 || fstSize < fstEntrySize || fstSize > maxFSTSize || fstOffset+fstSize > size {
+		return nil, fmt.Errorf("invalid FST location: offset 0x%X, size 0x%X", fstOffset, fstSize)
+	}
+
+	fst := make([]byte, fstSize)
+	if _, err := r.ReadAt(fst, fstOffset); err != nil {
+		return nil, fmt.Errorf("failed to read FST: %w", err)
+	}
+
+	root, err := parseFST(fst, shift, size)
+	if err != nil {
+		return nil, err
+	}
+	return &FS{r: r, root: root}, nil
+}
+
+// parseFST builds the directory tree from the raw FST.
+func parseFST(fst []byte, shift uint, discSize int64) (*fstNode, error) {
+	if fst[0] != fstFlagDirectory {
+		return nil, fmt.Errorf("invalid FST: root is not a directory")
+	}
+	count := int(binary.BigEndian.Uint32(fst[8:12]))
+	stringTable := count * fstEntrySize
+	if count < 1 || stringTable > len(fst) {
+		return nil, fmt.Errorf("invalid FST: %d entries do not fit in %d bytes", count, len(fst))
+	}
+
+	nameAt := func(off int) (string, error) {
+		start := stringTable + off
+		if start >= len(fst) {
+			return "", fmt.Errorf("invalid FST: name offset 0x%X out of range", off)
+		}
+		end := start
+		for end < len(fst) && fst[end] != 0 {
+			end++
+		}
+		return string(fst[start:end]), nil
+	}
+
+	// build parses entries [start, end) into the children of dir
+	var build func(dir *fstNode, start, end int) error
+	build = func(dir *fstNode, start, end int) error {
+		for i := start; i < end; {
+			entry := fst[i*fstEntrySize : (i+1)*fstEntrySize]
+			name, err := nameAt(int(binary.BigEndian.Uint32(entry[0:4]) & 0x00FFFFFF))
+			if err != nil {
+				return err
+			}
+			if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+				return fmt.Errorf("invalid FST: bad name %q at entry %d", name, i)
+			}
+
+			value1 := binary.BigEndian.Uint32(entry[4:8])
+			value2 := binary.BigEndian.Uint32(entry[8:12])
+
+			if entry[0] == fstFlagDirectory {
+				next := int(value2)
+				if next <= i || next > end {
+					return fmt.Errorf("invalid FST: directory %q at entry %d ends at %d", name, i, next)
+				}
+				child := &fstNode{name: name, dir: true}
+				if err := build(child, i+1, next); err != nil {
+					return err
+				}
+				sortNodes(child.children)
+				dir.children = append(dir.children, child)
+				i = next
+				continue
+			}
+
+			offset := int64(value1) << shift
+			length := int64(value2)
+			if offset+length > discSize {
+				return fmt.Errorf("invalid FST: file %q extends beyond disc", name)
+			}
+			dir.children = append(dir.children, &fstNode{name: name, offset: offset, size: length})
+			i++
+		}
+		return nil
+	}
+
+	root := &fstNode{name: ".", dir: true}
+	if err := build(root, 1, count); err != nil {
+		return nil, err
+	}
+	sortNodes(root.children)
+	return root, nil
+}
+
+// sortNodes sorts directory entries by name, as required by fs.ReadDirFS.
+// FST entries are usually sorted case-insensitively, which differs for mixed-case names.
+func sortNodes(nodes []*fstNode) {
+	slices.SortFunc(nodes, func(a, b *fstNode) int { return strings.Compare(a.name, b.name) })
+}
+
+// lookup finds the node at the given slash-separated path.
+func (f *FS) lookup(op, name string) (*fstNode, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	node := f.root
+	if name == "." {
+		return node, nil
+	}
+	for part := range strings.SplitSeq(name, "/") {
+		if node = node.child(part); node == nil {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+	}
+	return node, nil
+}
+
+// child returns the named entry of a directory, or nil if there is none.
+// Names are matched exactly first, then case-insensitively.
+func (n *fstNode) child(name string) *fstNode {
+	var folded *fstNode
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+		if folded == nil && strings.EqualFold(c.name, name) {
+			folded = c
+		}
+	}
+	return folded
+}
+
+// Open implements fs.FS. Opened files also implement io.ReaderAt and io.Seeker.
+func (f *FS) Open(name string) (fs.File, error) {
+	node, err := f.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if node.dir {
+		return &fstDir{node: node}, nil
+	}
+	return &fstFile{node: node, SectionReader: io.NewSectionReader(f.r, node.offset, node.size)}, nil
+}
+
+// ReadDir implements fs.ReadDirFS. Entries are sorted by name.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	node, err := f.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !node.dir {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	entries := make([]fs.DirEntry, len(node.children))
+	for i, child := range node.children {
+		entries[i] = fs.FileInfoToDirEntry(fstFileInfo{child})
+	}
+	return entries, nil
+}
+
+// Stat implements fs.StatFS.
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	node, err := f.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return fstFileInfo{node}, nil
+}
+
+// OpenFile opens a file for random access, returning the reader and its size.
+func (f *FS) OpenFile(name string) (io.ReaderAt, int64, error) {
+	node, err := f.lookup("open", name)
+	if err != nil {
+		return nil, 0, err
+	}
+	if node.dir {
+		return nil, 0, &fs.PathError{Op: "open", Path: name, Err: errors.New("is a directory")}
+	}
+	return io.NewSectionReader(f.r, node.offset, node.size), node.size, nil
+}
+
+// fstFile is an open file.
+type fstFile struct {
+	*io.SectionReader
+	node *fstNode
+}
+
+func (f *fstFile) Stat() (fs.FileInfo, error) { return fstFileInfo{f.node}, nil }
+func (f *fstFile) Close() error               { return nil }
+
+// fstDir is an open directory.
+type fstDir struct {
+	node *fstNode
+	pos  int
+}
+
+func (d *fstDir) Stat() (fs.FileInfo, error) { return fstFileInfo{d.node}, nil }
+func (d *fstDir) Close() error               { return nil }
+
+func (d *fstDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.node.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (d *fstDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.node.children[d.pos:]
+	if n > 0 {
+		if len(remaining) == 0 {
+			return nil, io.EOF
+		}
+		remaining = remaining[:min(n, len(remaining))]
+	}
+	entries := make([]fs.DirEntry, len(remaining))
+	for i, child := range remaining {
+		entries[i] = fs.FileInfoToDirEntry(fstFileInfo{child})
+	}
+	d.pos += len(remaining)
+	return entries, nil
+}
+
+// fstFileInfo implements fs.FileInfo for an FST node.
+type fstFileInfo struct {
+	node *fstNode
+}
+
+func (i fstFileInfo) Name() string       { return i.node.name }
+func (i fstFileInfo) Size() int64        { return i.node.size }
+func (i fstFileInfo) ModTime() time.Time { return time.Time{} }
+func (i fstFileInfo) IsDir() bool        { return i.node.dir }
+func (i fstFileInfo) Sys() any           { return nil }
+
+func (i fstFileInfo) Mode() fs.FileMode {
+	if i.node.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
@@ -0,0 +1,136 @@
+package gcm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+// makeSyntheticDisc creates a GameCube disc image with this file system:
+//
+//	opening.bnr
+//	files/a.txt ("hello")
+//	files/sub/b.bin ({1, 2})
+func makeSyntheticDisc(region Region, bnr []byte) []byte {
+	disc := make([]byte, 0x1000)
+	copy(disc, makeSyntheticGCM(SystemCodeGameCube, "MK", region, "TEST GAME HEADER", false))
+
+	addFile := func(data []byte) uint32 {
+		offset := uint32(len(disc))
+		disc = append(disc, data...)
+		return offset
+	}
+	bnrOffset := addFile(bnr)
+	aOffset := addFile([]byte("hello"))
+	bOffset := addFile([]byte{1, 2})
+
+	names := "opening.bnr\x00files\x00a.txt\x00sub\x00b.bin\x00"
+	entries := []struct {
+		dir            bool
+		nameOffset     uint32
+		value1, value2 uint32
+	}{
+		{true, 0, 0, 6},                         // root (6 entries)
+		{false, 0, bnrOffset, uint32(len(bnr))}, // opening.bnr
+		{true, 12, 0, 6},                        // files/ (parent 0, ends at 6)
+		{false, 18, aOffset, 5},                 // files/a.txt
+		{true, 24, 2, 6},                        // files/sub/ (parent 2, ends at 6)
+		{false, 28, bOffset, 2},                 // files/sub/b.bin
+	}
+
+	var fst bytes.Buffer
+	for _, e := range entries {
+		flags := e.nameOffset
+		if e.dir {
+			flags |= fstFlagDirectory << 24
+		}
+		binary.Write(&fst, binary.BigEndian, flags)
+		binary.Write(&fst, binary.BigEndian, e.value1)
+		binary.Write(&fst, binary.BigEndian, e.value2)
+	}
+	fst.WriteString(names)
+
+	binary.BigEndian.PutUint32(disc[fstOffsetOffset:], uint32(len(disc)))
+	binary.BigEndian.PutUint32(disc[fstSizeOffset:], uint32(fst.Len()))
+	return append(disc, fst.Bytes()...)
+}
+
+func TestNewFS(t *testing.T) {
+	disc := makeSyntheticDisc(RegionNorthAmerica, []byte("BNR1"))
+
+	fsys, err := NewFS(bytes.NewReader(disc), int64(len(disc)))
+	if err != nil {
+		t.Fatalf("NewFS() error = %v", err)
+	}
+
+	if err := fstest.TestFS(fsys, "opening.bnr", "files/a.txt", "files/sub/b.bin"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := fs.ReadFile(fsys, "files/a.txt")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("ReadFile() = %q, want %q", data, "hello")
+	}
+
+	var paths []string
+	fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		paths = append(paths, path)
+		return err
+	})
+	want := []string{".", "files", "files/a.txt", "files/sub", "files/sub/b.bin", "opening.bnr"}
+	if len(paths) != len(want) {
+		t.Fatalf("WalkDir() = %v, want %v", paths, want)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("WalkDir()[%d] = %q, want %q", i, paths[i], want[i])
+		}
+	}
+}
+
+func TestFS_OpenFile(t *testing.T) {
+	disc := makeSyntheticDisc(RegionNorthAmerica, []byte("BNR1"))
+
+	fsys, err := NewFS(bytes.NewReader(disc), int64(len(disc)))
+	if err != nil {
+		t.Fatalf("NewFS() error = %v", err)
+	}
+
+	// Lookups fall back to case-insensitive matching
+	r, size, err := fsys.OpenFile("FILES/Sub/B.BIN")
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	data, err := io.ReadAll(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if !bytes.Equal(data, []byte{1, 2}) {
+		t.Errorf("file data = %v, want [1 2]", data)
+	}
+
+	if _, _, err := fsys.OpenFile("files"); err == nil {
+		t.Error("OpenFile() expected error for directory")
+	}
+	if _, _, err := fsys.OpenFile("files/a.txt/x"); err == nil {
+		t.Error("OpenFile() expected error for path through a file")
+	}
+	if _, _, err := fsys.OpenFile("missing"); err == nil {
+		t.Error("OpenFile() expected error for missing file")
+	}
+}
+
+func TestNewFS_NoFST(t *testing.T) {
+	header := makeSyntheticGCM(SystemCodeGameCube, "MK", RegionNorthAmerica, "Test", false)
+	disc := append(header, make([]byte, 0x1000)...)
+
+	if _, err := NewFS(bytes.NewReader(disc), int64(len(disc))); err == nil {
+		t.Error("NewFS() expected error for disc without FST")
+	}
+}
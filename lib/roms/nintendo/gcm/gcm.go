@@ -3,6 +3,7 @@ package gcm
 import (
 	"encoding/binary"
 	"fmt"
+	"image"
 	"io"
 
 	"github.com/sargunv/rom-tools/internal/util"
@@ -27,6 +28,9 @@ import (
 //	0x018   4     Wii magic word (0x5D1C9EA3 for Wii, 0x00000000 for GameCube)
 //	0x01C   4     GameCube magic word (0xC2339F3D for GameCube, 0x00000000 for Wii)
 //	0x020   64    Game title (ASCII, null-terminated)
+//
+// For GameCube discs, Parse also reads the localized titles and banner image
+// from opening.bnr in the disc file system (see fst.go and banner.go).
 
 // SystemCode represents the console/platform identifier (first byte of disc ID).
 // Source: https://wiki.dolphin-emu.org/index.php?title=GameIDs
@@ -107,6 +111,34 @@ const (
 	gcMagicWord  = 0xC2339F3D
 )
 
+// Sizes of full GameCube and Wii disc images, as dumped by Redump.
+const (
+	GameCubeDiscSize       = 1459978240
+	WiiSingleLayerDiscSize = 4699979776
+	WiiDualLayerDiscSize   = 8511160320
+)
+
+// StandardDiscSize returns the full disc size for a disc whose data ends at
+// used bytes. Container formats that drop trailing unused blocks (CISO, WBFS)
+// use it to report the size of the original disc.
+func StandardDiscSize(isWii bool, used int64) int64 {
+	switch {
+	case !isWii && used <= GameCubeDiscSize:
+		return GameCubeDiscSize
+	case isWii && used <= WiiSingleLayerDiscSize:
+		return WiiSingleLayerDiscSize
+	case isWii && used <= WiiDualLayerDiscSize:
+		return WiiDualLayerDiscSize
+	default:
+		return used
+	}
+}
+
+// IsWiiDisc reports whether a disc header has the Wii magic word.
+func IsWiiDisc(header []byte) bool {
+	return len(header) >= wiiMagicOffset+4 && binary.BigEndian.Uint32(header[wiiMagicOffset:]) == wiiMagicWord
+}
+
 // Info contains metadata extracted from a GameCube/Wii disc header.
 type Info struct {
 	// SystemCode is the console/platform identifier (G=GameCube, R/S=Wii, etc.).
@@ -123,6 +155,8 @@ type Info struct {
 	Version int `json:"version"`
 	// Title is the game title.
 	Title string `json:"title,omitempty"`
+	// Banner is the parsed opening.bnr (GameCube only, nil if not found).
+	Banner *Banner `json:"banner,omitempty"`
 	// platform is the target platform (GameCube or Wii) (internal, used by GamePlatform).
 	platform core.Platform
 }
@@ -130,8 +164,23 @@ type Info struct {
 // GamePlatform implements core.GameInfo.
 func (i *Info) GamePlatform() core.Platform { return i.platform }
 
-// GameTitle implements core.GameInfo.
-func (i *Info) GameTitle() string { return i.Title }
+// GameTitle implements core.GameInfo. Prefers the full name from the banner.
+func (i *Info) GameTitle() string {
+	if i.Banner != nil {
+		if title := i.Banner.Title(); title != "" {
+			return title
+		}
+	}
+	return i.Title
+}
+
+// GameIcon implements core.IconProvider, returning the banner image.
+func (i *Info) GameIcon() image.Image {
+	if i.Banner == nil {
+		return nil
+	}
+	return i.Banner.Image
+}
 
 // GameSerial implements core.GameInfo. Returns the full game ID (SystemCode + GameCode + Region).
 func (i *Info) GameSerial() string {
@@ -191,7 +240,30 @@ func Parse(r io.ReaderAt, size int64) (*Info, error) {
 		return nil, fmt.Errorf("failed to read disc header: %w", err)
 	}
 
-	return parseGCMBytes(header)
+	info, err := parseGCMBytes(header)
+	if err != nil {
+		return nil, err
+	}
+
+	// The banner is optional; discs without a readable FST still identify
+	if info.platform == core.PlatformGC {
+		info.Banner, _ = readBanner(r, size, info.Region)
+	}
+
+	return info, nil
+}
+
+// readBanner reads opening.bnr from the disc file system.
+func readBanner(r io.ReaderAt, size int64, region Region) (*Banner, error) {
+	fsys, err := NewFS(r, size)
+	if err != nil {
+		return nil, err
+	}
+	bnr, bnrSize, err := fsys.OpenFile(BannerFileName)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBanner(bnr, bnrSize, region)
 }
 
 func parseGCMBytes(header []byte) (*Info, error) {
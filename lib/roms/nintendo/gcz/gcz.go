@@ -0,0 +1,249 @@
+package gcz
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"sync"
+
+	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/roms/nintendo/gcm"
+)
+
+// GCZ (Dolphin compressed GameCube/Wii disc image) format parsing.
+//
+// Format reference:
+// https://github.com/dolphin-emu/dolphin/blob/master/Source/Core/DiscIO/CompressedBlob.h
+//
+// GCZ header (little-endian, 32 bytes):
+//
+//	Offset  Size  Description
+//	0x00    4     Magic (0xB10BC001)
+//	0x04    4     Sub type (0 = GameCube, 1 = Wii)
+//	0x08    8     Compressed data size
+//	0x10    8     Uncompressed data size (original disc size)
+//	0x18    4     Block size
+//	0x1C    4     Number of blocks
+//
+// The header is followed by a table of 8-byte block offsets (relative to the
+// start of the data area) and a table of 4-byte Adler-32 block checksums.
+// The data area follows the tables. Blocks are zlib-compressed unless the top
+// bit of their offset is set, in which case they are stored uncompressed.
+
+const (
+	headerSize = 0x20
+	magic      = 0xB10BC001
+
+	magicOffset          = 0x00
+	subTypeOffset        = 0x04
+	compressedSizeOffset = 0x08
+	dataSizeOffset       = 0x10
+	blockSizeOffset      = 0x18
+	numBlocksOffset      = 0x1C
+
+	uncompressedFlag = uint64(1) << 63
+	maxBlockSize     = 64 * 1024 * 1024
+)
+
+// SubType indicates the disc type stored in a GCZ file.
+type SubType uint32
+
+// SubType values.
+const (
+	SubTypeGameCube SubType = 0
+	SubTypeWii      SubType = 1
+)
+
+// Reader provides random access to the original disc inside a GCZ file.
+type Reader struct {
+	r              io.ReaderAt
+	subType        SubType
+	dataSize       int64
+	compressedSize int64
+	blockSize      int64
+	blockOffsets   []uint64
+	dataOffset     int64
+
+	mu          sync.Mutex
+	cachedIndex int
+	cachedBlock []byte
+}
+
+// NewReader opens a GCZ file.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	if size < headerSize {
+		return nil, fmt.Errorf("file too small for GCZ header: %d bytes", size)
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read GCZ header: %w", err)
+	}
+
+	if m := binary.LittleEndian.Uint32(header[magicOffset:]); m != magic {
+		return nil, fmt.Errorf("not a valid GCZ file: invalid magic 0x%08X", m)
+	}
+
+	compressedSize := int64(binary.LittleEndian.Uint64(header[compressedSizeOffset:]))
+	dataSize := int64(binary.LittleEndian.Uint64(header[dataSizeOffset:]))
+	blockSize := int64(binary.LittleEndian.Uint32(header[blockSizeOffset:]))
+	numBlocks := int64(binary.LittleEndian.Uint32(header[numBlocksOffset:]))
+
+	if blockSize == 0 || blockSize > maxBlockSize {
+		return nil, fmt.Errorf("invalid GCZ block size: %d", blockSize)
+	}
+	if numBlocks != (dataSize+blockSize-1)/blockSize {
+		return nil, fmt.Errorf("GCZ block count %d does not match data size %d", numBlocks, dataSize)
+	}
+
+	dataOffset := headerSize + numBlocks*(8+4)
+	if dataOffset+compressedSize > size {
+		return nil, fmt.Errorf("GCZ data extends beyond file")
+	}
+
+	table := make([]byte, numBlocks*8)
+	if _, err := r.ReadAt(table, headerSize); err != nil {
+		return nil, fmt.Errorf("failed to read GCZ block table: %w", err)
+	}
+	offsets := make([]uint64, numBlocks)
+	for i := range offsets {
+		offsets[i] = binary.LittleEndian.Uint64(table[i*8:])
+	}
+
+	return &Reader{
+		r:              r,
+		subType:        SubType(binary.LittleEndian.Uint32(header[subTypeOffset:])),
+		dataSize:       dataSize,
+		compressedSize: compressedSize,
+		blockSize:      blockSize,
+		blockOffsets:   offsets,
+		dataOffset:     dataOffset,
+		cachedIndex:    -1,
+	}, nil
+}
+
+// Size returns the size of the original disc.
+func (g *Reader) Size() int64 {
+	return g.dataSize
+}
+
+// ReadAt implements io.ReaderAt over the original disc.
+func (g *Reader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset")
+	}
+
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= g.dataSize {
+			return n, io.EOF
+		}
+
+		index := int(pos / g.blockSize)
+		block, err := g.block(index)
+		if err != nil {
+			return n, err
+		}
+
+		offsetInBlock := int(pos % g.blockSize)
+		if offsetInBlock >= len(block) {
+			return n, fmt.Errorf("GCZ block %d is truncated: %d bytes, need offset %d", index, len(block), offsetInBlock)
+		}
+		available := min(int64(len(block)-offsetInBlock), g.dataSize-pos)
+		n += copy(p[n:], block[offsetInBlock:offsetInBlock+int(available)])
+	}
+	return n, nil
+}
+
+// block returns the decompressed data of a block, using a single-block cache.
+func (g *Reader) block(index int) ([]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if index == g.cachedIndex {
+		return g.cachedBlock, nil
+	}
+
+	start := g.blockOffsets[index] &^ uncompressedFlag
+	end := uint64(g.compressedSize)
+	if index+1 < len(g.blockOffsets) {
+		end = g.blockOffsets[index+1] &^ uncompressedFlag
+	}
+	if end < start || end > uint64(g.compressedSize) {
+		return nil, fmt.Errorf("invalid GCZ block %d range: 0x%X-0x%X", index, start, end)
+	}
+
+	raw := make([]byte, end-start)
+	if _, err := g.r.ReadAt(raw, g.dataOffset+int64(start)); err != nil {
+		return nil, fmt.Errorf("failed to read GCZ block %d: %w", index, err)
+	}
+
+	block := raw
+	if g.blockOffsets[index]&uncompressedFlag == 0 {
+		zr, err := zlib.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress GCZ block %d: %w", index, err)
+		}
+		block = make([]byte, g.blockSize)
+		read, err := io.ReadFull(zr, block)
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("failed to decompress GCZ block %d: %w", index, err)
+		}
+		block = block[:read]
+	}
+
+	g.cachedIndex = index
+	g.cachedBlock = block
+	return block, nil
+}
+
+// Info contains metadata extracted from a GCZ file.
+type Info struct {
+	// GCM contains the game identification info parsed from the disc.
+	GCM *gcm.Info `json:"gcm,omitempty"`
+	// SubType indicates the disc type (GameCube or Wii).
+	SubType SubType `json:"sub_type"`
+	// BlockSize is the size of each compressed block.
+	BlockSize int64 `json:"block_size"`
+	// DiscSize is the size of the original disc.
+	DiscSize int64 `json:"disc_size"`
+}
+
+// GamePlatform implements core.GameInfo by delegating to GCM.
+func (i *Info) GamePlatform() core.Platform { return i.GCM.GamePlatform() }
+
+// GameTitle implements core.GameInfo by delegating to GCM.
+func (i *Info) GameTitle() string { return i.GCM.GameTitle() }
+
+// GameSerial implements core.GameInfo by delegating to GCM.
+func (i *Info) GameSerial() string { return i.GCM.GameSerial() }
+
+// GameRegions implements core.GameInfo by delegating to GCM.
+func (i *Info) GameRegions() []core.Region { return i.GCM.GameRegions() }
+
+// GameIcon implements core.IconProvider by delegating to GCM.
+func (i *Info) GameIcon() image.Image { return i.GCM.GameIcon() }
+
+// Parse opens a GCZ file and parses the disc header of the original disc.
+func Parse(r io.ReaderAt, size int64) (*Info, error) {
+	reader, err := NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	gcmInfo, err := gcm.Parse(reader, reader.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse disc header from GCZ: %w", err)
+	}
+
+	return &Info{
+		GCM:       gcmInfo,
+		SubType:   reader.subType,
+		BlockSize: reader.blockSize,
+		DiscSize:  reader.Size(),
+	}, nil
+}
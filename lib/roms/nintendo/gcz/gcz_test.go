@@ -0,0 +1,170 @@
+package gcz
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/roms/nintendo/gcm"
+)
+
+const testBlockSize = 0x4000
+
+// makeSyntheticDisc creates a small synthetic GameCube/Wii disc for testing.
+func makeSyntheticDisc(gameID string, title string, isWii bool, size int) []byte {
+	disc := make([]byte, size)
+	copy(disc, gameID)
+	copy(disc[0x04:], "01")
+	if isWii {
+		binary.BigEndian.PutUint32(disc[0x18:], 0x5D1C9EA3)
+	} else {
+		binary.BigEndian.PutUint32(disc[0x1C:], 0xC2339F3D)
+	}
+	copy(disc[0x20:], title)
+
+	// Non-zero pattern so reads from every block can be checked
+	for i := 0x400; i < size; i++ {
+		disc[i] = byte(i / testBlockSize)
+	}
+	return disc
+}
+
+// makeSyntheticGCZ compresses a disc into a GCZ file. Odd blocks are stored
+// uncompressed to exercise both block types.
+func makeSyntheticGCZ(disc []byte, subType SubType) []byte {
+	numBlocks := (len(disc) + testBlockSize - 1) / testBlockSize
+
+	var data bytes.Buffer
+	offsets := make([]uint64, numBlocks)
+	for i := range numBlocks {
+		block := disc[i*testBlockSize : min(len(disc), (i+1)*testBlockSize)]
+		offsets[i] = uint64(data.Len())
+		if i%2 == 1 {
+			offsets[i] |= uncompressedFlag
+			data.Write(block)
+			continue
+		}
+		zw := zlib.NewWriter(&data)
+		zw.Write(block)
+		zw.Close()
+	}
+
+	file := make([]byte, headerSize+numBlocks*12)
+	binary.LittleEndian.PutUint32(file[magicOffset:], magic)
+	binary.LittleEndian.PutUint32(file[subTypeOffset:], uint32(subType))
+	binary.LittleEndian.PutUint64(file[compressedSizeOffset:], uint64(data.Len()))
+	binary.LittleEndian.PutUint64(file[dataSizeOffset:], uint64(len(disc)))
+	binary.LittleEndian.PutUint32(file[blockSizeOffset:], testBlockSize)
+	binary.LittleEndian.PutUint32(file[numBlocksOffset:], uint32(numBlocks))
+	for i, offset := range offsets {
+		binary.LittleEndian.PutUint64(file[headerSize+i*8:], offset)
+	}
+	return append(file, data.Bytes()...)
+}
+
+func TestParse_GameCube(t *testing.T) {
+	disc := makeSyntheticDisc("GALE", "Test GameCube Game", false, 5*testBlockSize+0x100)
+	file := makeSyntheticGCZ(disc, SubTypeGameCube)
+
+	info, err := Parse(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.GamePlatform() != core.PlatformGC {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformGC)
+	}
+	if info.GameTitle() != "Test GameCube Game" {
+		t.Errorf("GameTitle() = %q, want %q", info.GameTitle(), "Test GameCube Game")
+	}
+	if info.GameSerial() != "GALE" {
+		t.Errorf("GameSerial() = %q, want %q", info.GameSerial(), "GALE")
+	}
+	if info.SubType != SubTypeGameCube {
+		t.Errorf("SubType = %v, want %v", info.SubType, SubTypeGameCube)
+	}
+	if info.DiscSize != int64(len(disc)) {
+		t.Errorf("DiscSize = %d, want %d", info.DiscSize, len(disc))
+	}
+}
+
+func TestParse_Wii(t *testing.T) {
+	disc := makeSyntheticDisc("RSBE", "Test Wii Game", true, 2*testBlockSize)
+	file := makeSyntheticGCZ(disc, SubTypeWii)
+
+	info, err := Parse(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.GamePlatform() != core.PlatformWii {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformWii)
+	}
+	if info.GCM.SystemCode != gcm.SystemCodeWii {
+		t.Errorf("SystemCode = %v, want %v", info.GCM.SystemCode, gcm.SystemCodeWii)
+	}
+}
+
+func TestReader_ReadAt(t *testing.T) {
+	disc := makeSyntheticDisc("GALE", "Test", false, 5*testBlockSize+0x100)
+	file := makeSyntheticGCZ(disc, SubTypeGameCube)
+
+	reader, err := NewReader(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	// Whole disc, spanning compressed and uncompressed blocks
+	got := make([]byte, len(disc))
+	if _, err := reader.ReadAt(got, 0); err != nil {
+		t.Fatalf("ReadAt() error = %v", err)
+	}
+	if !bytes.Equal(got, disc) {
+		t.Error("ReadAt() data does not match original disc")
+	}
+
+	// Unaligned read across a block boundary
+	got = make([]byte, 0x200)
+	if _, err := reader.ReadAt(got, 2*testBlockSize-0x100); err != nil {
+		t.Fatalf("ReadAt() error = %v", err)
+	}
+	if want := disc[2*testBlockSize-0x100 : 2*testBlockSize+0x100]; !bytes.Equal(got, want) {
+		t.Error("ReadAt() across block boundary does not match original disc")
+	}
+
+	// Read past the end
+	n, err := reader.ReadAt(make([]byte, 0x200), int64(len(disc))-0x100)
+	if n != 0x100 || err == nil {
+		t.Errorf("ReadAt() past end = %d, %v; want 256, EOF", n, err)
+	}
+}
+
+func TestReader_ReadAt_TruncatedBlock(t *testing.T) {
+	// A stored block shorter than the block size the header promises
+	file := makeSyntheticGCZ(make([]byte, 0x100), SubTypeGameCube)
+	binary.LittleEndian.PutUint64(file[dataSizeOffset:], testBlockSize)
+
+	reader, err := NewReader(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	if _, err := reader.ReadAt(make([]byte, 0x10), 0x200); err == nil {
+		t.Error("ReadAt() expected error past the end of a truncated block")
+	}
+}
+
+func TestParse_InvalidMagic(t *testing.T) {
+	file := make([]byte, headerSize)
+	if _, err := Parse(bytes.NewReader(file), int64(len(file))); err == nil {
+		t.Error("Parse() expected error for invalid magic")
+	}
+}
+
+func TestParse_TooSmall(t *testing.T) {
+	file := make([]byte, headerSize-1)
+	if _, err := Parse(bytes.NewReader(file), int64(len(file))); err == nil {
+		t.Error("Parse() expected error for file too small")
+	}
+}
@@ -0,0 +1,76 @@
+package n3ds
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// 3DSX (homebrew executable) format parsing.
+//
+// 3DSX specification:
+// https://www.3dbrew.org/wiki/3DSX_Format
+//
+// 3DSX header layout (little-endian):
+//
+//	Offset  Size  Description
+//	0x00    4     Magic "3DSX"
+//	0x04    2     Header size
+//	0x06    2     Relocation header size
+//	0x08    4     Format version
+//	0x0C    4     Flags
+//	0x10    16    Code, rodata, data and BSS segment sizes
+//
+// Extended header (present when header size > 0x20):
+//
+//	Offset  Size  Description
+//	0x20    4     SMDH offset
+//	0x24    4     SMDH size
+//	0x28    4     RomFS offset
+
+const (
+	threeDSXMagic              = "3DSX"
+	threeDSXHeaderSizeOffset   = 0x04
+	threeDSXBaseHeaderSize     = 0x20
+	threeDSXExtendedHeaderSize = 0x2C
+	threeDSXSMDHOffset         = 0x20
+	threeDSXSMDHSizeOffset     = 0x24
+)
+
+// Parse3DSX extracts game information from a 3DSX homebrew executable.
+// Titles are only available when the file embeds an SMDH in its extended header.
+func Parse3DSX(r io.ReaderAt, size int64) (*Info, error) {
+	if size < threeDSXBaseHeaderSize {
+		return nil, fmt.Errorf("file too small for 3DSX header: %d bytes", size)
+	}
+
+	header := make([]byte, threeDSXExtendedHeaderSize)
+	n, err := r.ReadAt(header, 0)
+	if n < threeDSXBaseHeaderSize {
+		return nil, fmt.Errorf("failed to read 3DSX header: %w", err)
+	}
+
+	if string(header[:4]) != threeDSXMagic {
+		return nil, fmt.Errorf("not a valid 3DSX file: invalid magic %q", string(header[:4]))
+	}
+
+	info := &Info{
+		Format:    Format3DSX,
+		ImageSize: size,
+		platform:  core.Platform3DS,
+	}
+
+	headerSize := binary.LittleEndian.Uint16(header[threeDSXHeaderSizeOffset:])
+	if headerSize >= threeDSXExtendedHeaderSize && n >= threeDSXExtendedHeaderSize {
+		smdhOffset := int64(binary.LittleEndian.Uint32(header[threeDSXSMDHOffset:]))
+		smdhLen := int64(binary.LittleEndian.Uint32(header[threeDSXSMDHSizeOffset:]))
+		if smdhLen > 0 && smdhOffset+smdhLen <= size {
+			// A malformed SMDH just means no titles
+			info.SMDH, _ = ParseSMDH(io.NewSectionReader(r, smdhOffset, smdhLen), smdhLen)
+		}
+	}
+
+	return info, nil
+}
@@ -0,0 +1,347 @@
+package n3ds
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/sargunv/rom-tools/internal/util"
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// CIA (CTR Importable Archive) format parsing.
+//
+// CIA specification:
+// https://www.3dbrew.org/wiki/CIA
+//
+// CIA header layout (0x2020 bytes, little-endian):
+//
+//	Offset  Size    Description
+//	0x00    4       Header size
+//	0x04    2       Type
+//	0x06    2       Version
+//	0x08    4       Certificate chain size
+//	0x0C    4       Ticket size
+//	0x10    4       TMD size
+//	0x14    4       Meta size
+//	0x18    8       Content size
+//	0x20    0x2000  Content index (bitmap of present content indices, MSB first)
+//
+// The certificate chain, ticket, TMD, content and meta sections follow the
+// header in that order, each aligned to 64 bytes.
+//
+// Certificates, tickets and TMDs are big-endian and start with a signature:
+// https://www.3dbrew.org/wiki/Certificates
+// https://www.3dbrew.org/wiki/Ticket
+// https://www.3dbrew.org/wiki/Title_metadata
+//
+// Ticket body (after signature, relevant fields):
+//
+//	Offset  Size  Description
+//	0x00    64    Issuer
+//	0x90    8     Ticket ID
+//	0x9C    8     Title ID
+//	0xA6    2     Ticket title version
+//
+// TMD body (after signature, relevant fields):
+//
+//	Offset  Size   Description
+//	0x00    64     Issuer
+//	0x4C    8      Title ID
+//	0x9C    2      Title version
+//	0x9E    2      Content count
+//	0xC4    0x900  Content info records
+//	0x9C4   48*N   Content chunk records: ID (4), index (2), type (2), size (8), SHA-256 (32)
+//
+// Meta section (relevant fields):
+//
+//	Offset  Size    Description
+//	0x400   0x36C0  SMDH icon data
+
+const (
+	ciaHeaderSize        = 0x2020
+	ciaTypeOffset        = 0x04
+	ciaVersionOffset     = 0x06
+	ciaCertSizeOffset    = 0x08
+	ciaTicketSizeOffset  = 0x0C
+	ciaTMDSizeOffset     = 0x10
+	ciaMetaSizeOffset    = 0x14
+	ciaContentSizeOffset = 0x18
+	ciaContentIdxOffset  = 0x20
+	ciaContentIdxSize    = 0x2000
+	ciaAlignment         = 64
+	ciaMetaSMDHOffset    = 0x400
+
+	ticketIssuerLen   = 0x40
+	ticketIDOffset    = 0x90
+	ticketTitleID     = 0x9C
+	ticketTitleVerOff = 0xA6
+	ticketBodySize    = 0xA8
+
+	tmdTitleIDOffset      = 0x4C
+	tmdTitleVersionOffset = 0x9C
+	tmdContentCountOffset = 0x9E
+	tmdChunkRecordsOffset = 0x9C4
+	tmdChunkRecordSize    = 0x30
+	tmdMaxContents        = 0x100
+
+	certIssuerLen  = 0x40
+	certNameOffset = 0x44
+	certNameLen    = 0x40
+	certKeyOffset  = 0x88
+
+	contentTypeEncrypted = 0x0001
+)
+
+// CIAContent is a content chunk record from a CIA's TMD.
+type CIAContent struct {
+	// ID is the content ID.
+	ID uint32 `json:"id"`
+	// Index is the content index (0 = main NCCH, 1 = manual, 2 = download play child).
+	Index uint16 `json:"index"`
+	// Type is the content type flags.
+	Type uint16 `json:"type"`
+	// Size is the content size in bytes.
+	Size uint64 `json:"size"`
+	// Encrypted indicates whether the content is encrypted with the title key.
+	Encrypted bool `json:"encrypted"`
+}
+
+// CIAInfo contains container details from a CIA file.
+type CIAInfo struct {
+	// Type is the CIA type field.
+	Type uint16 `json:"type"`
+	// Version is the CIA format version.
+	Version uint16 `json:"version"`
+	// Certificates lists the certificates in the chain as "issuer-name".
+	Certificates []string `json:"certificates,omitempty"`
+	// TicketIssuer is the signature issuer of the ticket.
+	TicketIssuer string `json:"ticket_issuer,omitempty"`
+	// TicketID is the ticket ID.
+	TicketID uint64 `json:"ticket_id"`
+	// TicketTitleVersion is the title version recorded in the ticket.
+	TicketTitleVersion uint16 `json:"ticket_title_version"`
+	// TitleID is the title ID from the TMD.
+	TitleID uint64 `json:"title_id"`
+	// TitleVersion is the title version from the TMD.
+	TitleVersion uint16 `json:"title_version"`
+	// Contents lists the content chunk records from the TMD.
+	Contents []CIAContent `json:"contents,omitempty"`
+	// ContentIndex lists the content indices present in the CIA.
+	ContentIndex []uint16 `json:"content_index,omitempty"`
+}
+
+// ParseCIA extracts game information from a CIA file.
+// Product code and other NCCH fields are only available when the main content is not
+// encrypted with the title key. The SMDH is read from the NCCH ExeFS when possible,
+// or otherwise from the CIA meta section.
+func ParseCIA(r io.ReaderAt, size int64) (*Info, error) {
+	if size < ciaHeaderSize {
+		return nil, fmt.Errorf("file too small for CIA header: %d bytes", size)
+	}
+
+	header := make([]byte, ciaHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read CIA header: %w", err)
+	}
+
+	headerSize := binary.LittleEndian.Uint32(header)
+	if headerSize != ciaHeaderSize {
+		return nil, fmt.Errorf("not a valid CIA: unexpected header size 0x%X", headerSize)
+	}
+
+	certSize := int64(binary.LittleEndian.Uint32(header[ciaCertSizeOffset:]))
+	ticketSize := int64(binary.LittleEndian.Uint32(header[ciaTicketSizeOffset:]))
+	tmdSize := int64(binary.LittleEndian.Uint32(header[ciaTMDSizeOffset:]))
+	metaSize := int64(binary.LittleEndian.Uint32(header[ciaMetaSizeOffset:]))
+	contentSize := int64(binary.LittleEndian.Uint64(header[ciaContentSizeOffset:]))
+
+	certOffset := alignCIA(ciaHeaderSize)
+	ticketOffset := certOffset + alignCIA(certSize)
+	tmdOffset := ticketOffset + alignCIA(ticketSize)
+	contentOffset := tmdOffset + alignCIA(tmdSize)
+	metaOffset := contentOffset + alignCIA(contentSize)
+
+	if tmdOffset+tmdSize > size {
+		return nil, fmt.Errorf("CIA TMD extends beyond file: offset %d, file size %d", tmdOffset, size)
+	}
+
+	cia := &CIAInfo{
+		Type:         binary.LittleEndian.Uint16(header[ciaTypeOffset:]),
+		Version:      binary.LittleEndian.Uint16(header[ciaVersionOffset:]),
+		ContentIndex: parseContentIndex(header[ciaContentIdxOffset : ciaContentIdxOffset+ciaContentIdxSize]),
+	}
+
+	cia.Certificates = parseCertificateChain(r, certOffset, certSize)
+
+	if err := parseTicket(r, ticketOffset, ticketSize, cia); err != nil {
+		return nil, err
+	}
+	if err := parseTMD(r, tmdOffset, tmdSize, cia); err != nil {
+		return nil, err
+	}
+
+	// The main NCCH is the first content; it is only readable if not title-key encrypted
+	var info *Info
+	if len(cia.Contents) > 0 && !cia.Contents[0].Encrypted {
+		info, _ = parseNCCH(r, contentOffset, size)
+	}
+	if info == nil {
+		info = &Info{
+			TitleID:  cia.TitleID,
+			Version:  cia.TitleVersion,
+			platform: core.Platform3DS,
+		}
+	}
+
+	// Fall back to the SMDH in the meta section
+	if info.SMDH == nil && metaSize >= ciaMetaSMDHOffset+smdhSize && metaOffset+metaSize <= size {
+		info.SMDH, _ = ParseSMDH(io.NewSectionReader(r, metaOffset+ciaMetaSMDHOffset, smdhSize), smdhSize)
+	}
+
+	info.Format = FormatCIA
+	info.ImageSize = size
+	info.PartitionCount = len(cia.Contents)
+	info.CIA = cia
+	return info, nil
+}
+
+// alignCIA rounds n up to the CIA section alignment.
+func alignCIA(n int64) int64 {
+	return (n + ciaAlignment - 1) &^ (ciaAlignment - 1)
+}
+
+// parseContentIndex converts the content index bitmap to a list of indices.
+func parseContentIndex(bitmap []byte) []uint16 {
+	var indices []uint16
+	for i, b := range bitmap {
+		for bit := 0; bit < 8; bit++ {
+			if b&(0x80>>bit) != 0 {
+				indices = append(indices, uint16(i*8+bit))
+			}
+		}
+	}
+	return indices
+}
+
+// signatureSize returns the total size of a signature block (type, signature and padding).
+func signatureSize(sigType uint32) (int64, bool) {
+	switch sigType {
+	case 0x010000, 0x010003: // RSA-4096
+		return 4 + 0x200 + 0x3C, true
+	case 0x010001, 0x010004: // RSA-2048
+		return 4 + 0x100 + 0x3C, true
+	case 0x010002, 0x010005: // ECDSA
+		return 4 + 0x3C + 0x40, true
+	default:
+		return 0, false
+	}
+}
+
+// publicKeySize returns the size of a certificate's public key block.
+func publicKeySize(keyType uint32) (int64, bool) {
+	switch keyType {
+	case 0: // RSA-4096
+		return 0x200 + 4 + 0x34, true
+	case 1: // RSA-2048
+		return 0x100 + 4 + 0x34, true
+	case 2: // ECC
+		return 0x3C + 0x3C, true
+	default:
+		return 0, false
+	}
+}
+
+// readSigned reads a signed structure and returns its body (after the signature).
+func readSigned(r io.ReaderAt, offset, size, minBody int64) ([]byte, error) {
+	if size < 4 {
+		return nil, fmt.Errorf("signed data too small: %d bytes", size)
+	}
+	data := make([]byte, size)
+	if _, err := r.ReadAt(data, offset); err != nil {
+		return nil, err
+	}
+	sigSize, ok := signatureSize(binary.BigEndian.Uint32(data))
+	if !ok {
+		return nil, fmt.Errorf("unknown signature type 0x%08X", binary.BigEndian.Uint32(data))
+	}
+	if sigSize+minBody > size {
+		return nil, fmt.Errorf("signed data too small for body: %d bytes", size)
+	}
+	return data[sigSize:], nil
+}
+
+// parseCertificateChain returns the "issuer-name" of each certificate in the chain.
+// Parsing stops at the first malformed certificate.
+func parseCertificateChain(r io.ReaderAt, offset, size int64) []string {
+	if size <= 0 {
+		return nil
+	}
+	data := make([]byte, size)
+	if _, err := r.ReadAt(data, offset); err != nil {
+		return nil
+	}
+
+	var certs []string
+	pos := int64(0)
+	for pos+4 <= size {
+		sigSize, ok := signatureSize(binary.BigEndian.Uint32(data[pos:]))
+		if !ok || pos+sigSize+certKeyOffset > size {
+			break
+		}
+		body := data[pos+sigSize:]
+		keySize, ok := publicKeySize(binary.BigEndian.Uint32(body[certIssuerLen:]))
+		if !ok {
+			break
+		}
+		issuer := util.ExtractASCII(body[:certIssuerLen])
+		name := util.ExtractASCII(body[certNameOffset : certNameOffset+certNameLen])
+		certs = append(certs, issuer+"-"+name)
+		pos += sigSize + certKeyOffset + keySize
+	}
+	return certs
+}
+
+// parseTicket reads the ticket fields into cia.
+func parseTicket(r io.ReaderAt, offset, size int64, cia *CIAInfo) error {
+	body, err := readSigned(r, offset, size, ticketBodySize)
+	if err != nil {
+		return fmt.Errorf("failed to read CIA ticket: %w", err)
+	}
+	cia.TicketIssuer = util.ExtractASCII(body[:ticketIssuerLen])
+	cia.TicketID = binary.BigEndian.Uint64(body[ticketIDOffset:])
+	cia.TicketTitleVersion = binary.BigEndian.Uint16(body[ticketTitleVerOff:])
+	return nil
+}
+
+// parseTMD reads the title metadata fields and content chunk records into cia.
+func parseTMD(r io.ReaderAt, offset, size int64, cia *CIAInfo) error {
+	body, err := readSigned(r, offset, size, tmdChunkRecordsOffset)
+	if err != nil {
+		return fmt.Errorf("failed to read CIA TMD: %w", err)
+	}
+
+	cia.TitleID = binary.BigEndian.Uint64(body[tmdTitleIDOffset:])
+	cia.TitleVersion = binary.BigEndian.Uint16(body[tmdTitleVersionOffset:])
+	count := int(binary.BigEndian.Uint16(body[tmdContentCountOffset:]))
+	if count > tmdMaxContents {
+		return fmt.Errorf("invalid CIA TMD content count: %d", count)
+	}
+
+	for i := 0; i < count; i++ {
+		off := tmdChunkRecordsOffset + i*tmdChunkRecordSize
+		if off+tmdChunkRecordSize > len(body) {
+			break
+		}
+		rec := body[off:]
+		contentType := binary.BigEndian.Uint16(rec[6:])
+		cia.Contents = append(cia.Contents, CIAContent{
+			ID:        binary.BigEndian.Uint32(rec[0:]),
+			Index:     binary.BigEndian.Uint16(rec[4:]),
+			Type:      contentType,
+			Size:      binary.BigEndian.Uint64(rec[8:]),
+			Encrypted: contentType&contentTypeEncrypted != 0,
+		})
+	}
+	return nil
+}
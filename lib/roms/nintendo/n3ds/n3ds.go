@@ -3,9 +3,9 @@ package n3ds
 import (
 	"encoding/binary"
 	"fmt"
+	"image"
 	"io"
 
-	"github.com/sargunv/rom-tools/internal/util"
 	"github.com/sargunv/rom-tools/lib/core"
 )
 
@@ -13,6 +13,8 @@ import (
 //
 // 3DS CCI (CTR Cart Image) files use the NCSD container format.
 // NCSD (Nintendo CTR System Data) contains one or more NCCH partitions.
+// CIA, standalone NCCH (CXI) and 3DSX files are handled in cia.go, ncch.go
+// and 3dsx.go respectively.
 // https://www.3dbrew.org/wiki/NCSD
 // https://www.3dbrew.org/wiki/NCCH
 //
@@ -32,43 +34,6 @@ import (
 //	0x188   8     Partition flags
 //	0x190   64    Partition ID table
 //	0x1D0   48    Reserved
-//
-// NCCH Header layout (0x200 bytes at partition 0 offset):
-//
-//	Offset  Size  Description
-//	0x000   256   RSA-2048 SHA-256 signature
-//	0x100   4     Magic "NCCH" (0x4843434E little-endian)
-//	0x104   4     Content size in media units
-//	0x108   8     Partition/Title ID
-//	0x110   2     Maker code (ASCII)
-//	0x112   2     Version
-//	0x114   4     Seed hash verification
-//	0x118   8     Program ID / Title ID
-//	0x120   16    Reserved
-//	0x130   32    Logo region hash
-//	0x150   16    Product code (ASCII, e.g., "CTR-P-ALGE")
-//	0x160   32    Extended header hash
-//	0x180   4     Extended header size
-//	0x184   4     Reserved
-//	0x188   8     Flags (content type, platform, crypto)
-//	0x190   112   Region offsets/sizes (plain, logo, exefs, romfs)
-//
-// Product code format (16 bytes at NCCH+0x150):
-//
-//	Format: XXX-Y-ZZZZ
-//	  - CTR = 3DS, KTR = New 3DS exclusive
-//	  - P = retail game, N = demo/special
-//	  - ZZZZ = 4-char game code, last char is region (J/E/P/U/C/K/T)
-//
-// NCCH Flags (8 bytes at NCCH+0x188):
-//
-//	Index  Description
-//	0-2    Reserved
-//	3      Crypto method (0=fixed key)
-//	4      Content platform (bit 1 = New 3DS exclusive)
-//	5      Content type (bits 0-2: 0=App, 1=SysUpdate, 2=Manual, 3=DLP, 4=Trial)
-//	6      Content unit size (log2)
-//	7      Fixed crypto key flag
 
 const (
 	ncsdHeaderSize       = 0x200 // 512 bytes
@@ -80,20 +45,19 @@ const (
 	ncsdPartTableEntries = 8
 	ncsdPartEntrySize    = 8 // 4 bytes offset + 4 bytes size
 
-	ncchMagicOffset       = 0x100
-	ncchMagic             = "NCCH"
-	ncchMakerCodeOffset   = 0x110
-	ncchMakerCodeLen      = 2
-	ncchVersionOffset     = 0x112
-	ncchTitleIDOffset     = 0x118
-	ncchProductCodeOffset = 0x150
-	ncchProductCodeLen    = 16
-	ncchFlagsOffset       = 0x188
-	ncchMinHeaderSize     = 0x200 // Minimum NCCH header to read
-
 	mediaUnitSize = 0x200 // 512 bytes per media unit
 )
 
+// Format identifies the container format a 3DS title was parsed from.
+type Format string
+
+const (
+	FormatCCI  Format = "cci"  // Cartridge image (NCSD)
+	FormatCIA  Format = "cia"  // CTR Importable Archive
+	FormatNCCH Format = "ncch" // Standalone NCCH (CXI/CFA)
+	Format3DSX Format = "3dsx" // Homebrew executable
+)
+
 // ContentType represents the type of NCCH content.
 type ContentType byte
 
@@ -120,8 +84,10 @@ const (
 	RegionTaiwan    Region = 'T'
 )
 
-// Info contains metadata extracted from a 3DS CCI/NCSD file.
+// Info contains metadata extracted from a 3DS CCI, CIA, NCCH or 3DSX file.
 type Info struct {
+	// Format is the container format the title was parsed from.
+	Format Format `json:"format"`
 	// MediaID is the unique media identifier from the NCSD header (0x108).
 	MediaID uint64 `json:"media_id"`
 	// ImageSize is the total image size in bytes.
@@ -145,6 +111,11 @@ type Info struct {
 	// Region is the target region from the product code.
 	Region Region `json:"region"`
 
+	// CIA contains the CIA container details (CIA only).
+	CIA *CIAInfo `json:"cia,omitempty"`
+	// SMDH contains the localized titles and icons, if available unencrypted.
+	SMDH *SMDH `json:"smdh,omitempty"`
+
 	// platform is the target platform (internal, used by GamePlatform).
 	platform core.Platform
 }
@@ -153,14 +124,29 @@ type Info struct {
 func (i *Info) GamePlatform() core.Platform { return i.platform }
 
 // GameTitle implements core.GameInfo.
-// 3DS CCI format does not contain a title field in the header.
-func (i *Info) GameTitle() string { return "" }
+// Returns the SMDH short title, or "" when no unencrypted SMDH is available.
+func (i *Info) GameTitle() string {
+	if i.SMDH == nil {
+		return ""
+	}
+	return i.SMDH.Title()
+}
+
+// GameIcon implements core.IconProvider.
+// Returns the SMDH large icon, or nil when no unencrypted SMDH is available.
+func (i *Info) GameIcon() image.Image {
+	if i.SMDH == nil {
+		return nil
+	}
+	return i.SMDH.LargeIcon
+}
 
 // GameSerial implements core.GameInfo.
 // Returns the product code (e.g., "CTR-P-ALGE").
 func (i *Info) GameSerial() string { return i.ProductCode }
 
 // GameRegions implements core.GameInfo.
+// Uses the product code region, falling back to the SMDH region lockout.
 func (i *Info) GameRegions() []core.Region {
 	switch i.Region {
 	case RegionJapan:
@@ -178,6 +164,11 @@ func (i *Info) GameRegions() []core.Region {
 	case RegionTaiwan:
 		return []core.Region{core.RegionTaiwan}
 	default:
+		if i.SMDH != nil {
+			if regions := i.SMDH.RegionLockout.Regions(); regions != nil {
+				return regions
+			}
+		}
 		return []core.Region{}
 	}
 }
@@ -229,61 +220,16 @@ func Parse(r io.ReaderAt, size int64) (*Info, error) {
 
 	// Calculate NCCH offset in bytes
 	ncchOffset := int64(partOffset) * mediaUnitSize
-	if ncchOffset+ncchMinHeaderSize > size {
-		return nil, fmt.Errorf("NCCH partition extends beyond file: offset %d, file size %d", ncchOffset, size)
-	}
-
-	// Read NCCH header
-	ncchHeader := make([]byte, ncchMinHeaderSize)
-	if _, err := r.ReadAt(ncchHeader, ncchOffset); err != nil {
-		return nil, fmt.Errorf("failed to read NCCH header at offset %d: %w", ncchOffset, err)
-	}
-
-	// Validate NCCH magic
-	ncchMagicVal := string(ncchHeader[ncchMagicOffset : ncchMagicOffset+4])
-	if ncchMagicVal != ncchMagic {
-		return nil, fmt.Errorf("not a valid NCCH partition: expected magic %q, got %q", ncchMagic, ncchMagicVal)
-	}
-
-	// Parse NCCH fields
-	titleID := binary.LittleEndian.Uint64(ncchHeader[ncchTitleIDOffset:])
-	makerCode := util.ExtractASCII(ncchHeader[ncchMakerCodeOffset : ncchMakerCodeOffset+ncchMakerCodeLen])
-	version := binary.LittleEndian.Uint16(ncchHeader[ncchVersionOffset:])
-	productCode := util.ExtractASCII(ncchHeader[ncchProductCodeOffset : ncchProductCodeOffset+ncchProductCodeLen])
-
-	// Parse flags
-	flags := ncchHeader[ncchFlagsOffset : ncchFlagsOffset+8]
-	contentType := ContentType(flags[5] & 0x07) // Lower 3 bits
-	isNew3DSExclusive := (flags[4] & 0x02) != 0 // Bit 1 of flags[4]
-
-	// Determine region from product code (last character of game code)
-	// Format: CTR-P-XXXR where R is region
-	var region Region
-	if len(productCode) >= 10 {
-		region = Region(productCode[9])
-	}
-
-	// Determine platform
-	var platform core.Platform
-	if isNew3DSExclusive {
-		platform = core.PlatformNew3DS
-	} else {
-		platform = core.Platform3DS
+	info, err := parseNCCH(r, ncchOffset, size)
+	if err != nil {
+		return nil, err
 	}
 
-	return &Info{
-		MediaID:           mediaID,
-		ImageSize:         imageSize,
-		PartitionCount:    partCount,
-		TitleID:           titleID,
-		ProductCode:       productCode,
-		MakerCode:         makerCode,
-		Version:           version,
-		ContentType:       contentType,
-		IsNew3DSExclusive: isNew3DSExclusive,
-		Region:            region,
-		platform:          platform,
-	}, nil
+	info.Format = FormatCCI
+	info.MediaID = mediaID
+	info.ImageSize = imageSize
+	info.PartitionCount = partCount
+	return info, nil
 }
 
 // parsePartitionEntry extracts offset and size for a partition from the NCSD table.
@@ -3,7 +3,9 @@ package n3ds
 import (
 	"bytes"
 	"encoding/binary"
+	"slices"
 	"testing"
+	"unicode/utf16"
 
 	"github.com/sargunv/rom-tools/lib/core"
 )
@@ -257,7 +259,7 @@ func TestInfo_GameInfo(t *testing.T) {
 		platform:    core.Platform3DS,
 	}
 
-	// GameTitle should return empty string (3DS headers don't have title)
+	// GameTitle should return empty string without an SMDH
 	if info.GameTitle() != "" {
 		t.Errorf("GameTitle() = %q, want empty string", info.GameTitle())
 	}
@@ -339,3 +341,242 @@ func TestParse_OutOfBoundsPartitionsExcluded(t *testing.T) {
 		t.Errorf("PartitionCount = %d, want 1 (out-of-bounds partitions should be excluded)", info.PartitionCount)
 	}
 }
+
+// putUTF16LE writes s as UTF-16LE into data.
+func putUTF16LE(data []byte, s string) {
+	for i, v := range utf16.Encode([]rune(s)) {
+		binary.LittleEndian.PutUint16(data[i*2:], v)
+	}
+}
+
+// makeSyntheticSMDH creates an SMDH with English and Japanese titles.
+func makeSyntheticSMDH() []byte {
+	data := make([]byte, smdhSize)
+	copy(data, smdhMagic)
+
+	ja := data[smdhTitlesOffset:]
+	putUTF16LE(ja, "テスト")
+	en := data[smdhTitlesOffset+smdhTitleEntrySize:]
+	putUTF16LE(en, "Test Game")
+	putUTF16LE(en[smdhShortDescLen:], "Test Game: Full Title")
+	putUTF16LE(en[smdhShortDescLen+smdhLongDescLen:], "Test Publisher")
+
+	binary.LittleEndian.PutUint32(data[smdhRegionLockOffset:], uint32(RegionLockoutNorthAmerica|RegionLockoutEurope))
+
+	// Large icon pixel 2 in Morton order is (0, 1); make it pure red
+	binary.LittleEndian.PutUint16(data[smdhLargeIconOffset+2*2:], 0xF800)
+	return data
+}
+
+// makeSyntheticNCCH creates a decrypted NCCH whose ExeFS contains the given icon.
+func makeSyntheticNCCH(productCode string, icon []byte) []byte {
+	const exefsUnits = 1
+	data := make([]byte, ncchMinHeaderSize+exefsHeaderSize+len(icon))
+	copy(data[ncchMagicOffset:], ncchMagic)
+	copy(data[ncchProductCodeOffset:], productCode)
+	data[ncchFlagsOffset+7] = ncchFlagNoCrypto
+	binary.LittleEndian.PutUint32(data[ncchExeFSOffset:], exefsUnits)
+	binary.LittleEndian.PutUint32(data[ncchExeFSSizeOffset:], uint32((exefsHeaderSize+len(icon)+mediaUnitSize-1)/mediaUnitSize))
+
+	exefs := data[exefsUnits*mediaUnitSize:]
+	copy(exefs, exefsIconName)
+	binary.LittleEndian.PutUint32(exefs[8:], 0)
+	binary.LittleEndian.PutUint32(exefs[12:], uint32(len(icon)))
+	copy(exefs[exefsHeaderSize:], icon)
+	return data
+}
+
+func TestParseSMDH(t *testing.T) {
+	data := makeSyntheticSMDH()
+
+	smdh, err := ParseSMDH(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ParseSMDH() error = %v", err)
+	}
+
+	if smdh.Title() != "Test Game" {
+		t.Errorf("Title() = %q, want %q", smdh.Title(), "Test Game")
+	}
+	if smdh.LongTitle() != "Test Game: Full Title" {
+		t.Errorf("LongTitle() = %q, want %q", smdh.LongTitle(), "Test Game: Full Title")
+	}
+	if smdh.Publisher() != "Test Publisher" {
+		t.Errorf("Publisher() = %q, want %q", smdh.Publisher(), "Test Publisher")
+	}
+	if got := smdh.Titles[LanguageJapanese].ShortDescription; got != "テスト" {
+		t.Errorf("Japanese title = %q, want %q", got, "テスト")
+	}
+	if len(smdh.Titles) != 2 {
+		t.Errorf("len(Titles) = %d, want 2", len(smdh.Titles))
+	}
+
+	wantRegions := []core.Region{core.RegionUSA, core.RegionEurope}
+	if !slices.Equal(smdh.RegionLockout.Regions(), wantRegions) {
+		t.Errorf("Regions() = %v, want %v", smdh.RegionLockout.Regions(), wantRegions)
+	}
+
+	if smdh.LargeIcon.Bounds().Dx() != smdhLargeIconSize || smdh.SmallIcon.Bounds().Dx() != smdhSmallIconSize {
+		t.Errorf("icon sizes = %v / %v, want 48x48 / 24x24", smdh.LargeIcon.Bounds(), smdh.SmallIcon.Bounds())
+	}
+	r, g, b, _ := smdh.LargeIcon.At(0, 1).RGBA()
+	if r>>8 != 0xFF || g != 0 || b != 0 {
+		t.Errorf("LargeIcon.At(0, 1) = (%d, %d, %d), want red", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestParseNCCH_DecryptedIcon(t *testing.T) {
+	data := makeSyntheticNCCH("CTR-P-TEST", makeSyntheticSMDH())
+
+	info, err := ParseNCCH(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ParseNCCH() error = %v", err)
+	}
+
+	if info.Format != FormatNCCH {
+		t.Errorf("Format = %q, want %q", info.Format, FormatNCCH)
+	}
+	if info.GameTitle() != "Test Game" {
+		t.Errorf("GameTitle() = %q, want %q", info.GameTitle(), "Test Game")
+	}
+	// Product code region 'T' wins over the SMDH region lockout
+	if !slices.Equal(info.GameRegions(), []core.Region{core.RegionTaiwan}) {
+		t.Errorf("GameRegions() = %v, want [Taiwan]", info.GameRegions())
+	}
+}
+
+func TestParseNCCH_Encrypted(t *testing.T) {
+	data := makeSyntheticNCCH("CTR-P-TEST", makeSyntheticSMDH())
+	data[ncchFlagsOffset+7] = 0
+
+	info, err := ParseNCCH(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ParseNCCH() error = %v", err)
+	}
+	if info.SMDH != nil {
+		t.Error("SMDH != nil, want nil for encrypted NCCH")
+	}
+}
+
+// makeSignedBlock creates an RSA-2048 SHA-256 signed block with the given body.
+func makeSignedBlock(body []byte) []byte {
+	sigSize, _ := signatureSize(0x010004)
+	data := make([]byte, sigSize+int64(len(body)))
+	binary.BigEndian.PutUint32(data, 0x010004)
+	copy(data[sigSize:], body)
+	return data
+}
+
+func TestParseCIA(t *testing.T) {
+	const titleID = 0x0004000000123400
+
+	// Certificate chain with a single RSA-2048 certificate
+	certBody := make([]byte, certKeyOffset+0x100+4+0x34)
+	copy(certBody, "Root-CA00000003")
+	binary.BigEndian.PutUint32(certBody[certIssuerLen:], 1)
+	copy(certBody[certNameOffset:], "XS0000000c")
+	cert := makeSignedBlock(certBody)
+
+	ticketBody := make([]byte, ticketBodySize)
+	copy(ticketBody, "Root-CA00000003-XS0000000c")
+	binary.BigEndian.PutUint64(ticketBody[ticketIDOffset:], 0x0123456789ABCDEF)
+	binary.BigEndian.PutUint64(ticketBody[ticketTitleID:], titleID)
+	ticket := makeSignedBlock(ticketBody)
+
+	ncch := makeSyntheticNCCH("CTR-P-TESE", makeSyntheticSMDH())
+
+	tmdBody := make([]byte, tmdChunkRecordsOffset+2*tmdChunkRecordSize)
+	binary.BigEndian.PutUint64(tmdBody[tmdTitleIDOffset:], titleID)
+	binary.BigEndian.PutUint16(tmdBody[tmdTitleVersionOffset:], 1040)
+	binary.BigEndian.PutUint16(tmdBody[tmdContentCountOffset:], 2)
+	rec := tmdBody[tmdChunkRecordsOffset:]
+	binary.BigEndian.PutUint64(rec[8:], uint64(len(ncch)))
+	rec = tmdBody[tmdChunkRecordsOffset+tmdChunkRecordSize:]
+	binary.BigEndian.PutUint32(rec[0:], 1)
+	binary.BigEndian.PutUint16(rec[4:], 1)
+	binary.BigEndian.PutUint16(rec[6:], contentTypeEncrypted)
+	binary.BigEndian.PutUint64(rec[8:], 0x200)
+	tmd := makeSignedBlock(tmdBody)
+
+	contentSize := int64(len(ncch)) + 0x200
+
+	header := make([]byte, ciaHeaderSize)
+	binary.LittleEndian.PutUint32(header, ciaHeaderSize)
+	binary.LittleEndian.PutUint32(header[ciaCertSizeOffset:], uint32(len(cert)))
+	binary.LittleEndian.PutUint32(header[ciaTicketSizeOffset:], uint32(len(ticket)))
+	binary.LittleEndian.PutUint32(header[ciaTMDSizeOffset:], uint32(len(tmd)))
+	binary.LittleEndian.PutUint64(header[ciaContentSizeOffset:], uint64(contentSize))
+	header[ciaContentIdxOffset] = 0xC0 // Contents 0 and 1
+
+	var data []byte
+	appendAligned := func(b []byte) {
+		data = append(data, b...)
+		data = append(data, make([]byte, alignCIA(int64(len(data)))-int64(len(data)))...)
+	}
+	appendAligned(header)
+	appendAligned(cert)
+	appendAligned(ticket)
+	appendAligned(tmd)
+	appendAligned(append(ncch, make([]byte, 0x200)...))
+
+	info, err := ParseCIA(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ParseCIA() error = %v", err)
+	}
+
+	if info.Format != FormatCIA {
+		t.Errorf("Format = %q, want %q", info.Format, FormatCIA)
+	}
+	if info.GameSerial() != "CTR-P-TESE" {
+		t.Errorf("GameSerial() = %q, want %q", info.GameSerial(), "CTR-P-TESE")
+	}
+	if info.GameTitle() != "Test Game" {
+		t.Errorf("GameTitle() = %q, want %q", info.GameTitle(), "Test Game")
+	}
+	if info.CIA == nil {
+		t.Fatal("CIA = nil, want CIA info")
+	}
+	if info.CIA.TitleID != titleID {
+		t.Errorf("CIA.TitleID = %016X, want %016X", info.CIA.TitleID, uint64(titleID))
+	}
+	if info.CIA.TitleVersion != 1040 {
+		t.Errorf("CIA.TitleVersion = %d, want 1040", info.CIA.TitleVersion)
+	}
+	if info.CIA.TicketID != 0x0123456789ABCDEF {
+		t.Errorf("CIA.TicketID = %016X, want 0123456789ABCDEF", info.CIA.TicketID)
+	}
+	if !slices.Equal(info.CIA.Certificates, []string{"Root-CA00000003-XS0000000c"}) {
+		t.Errorf("CIA.Certificates = %v, want [Root-CA00000003-XS0000000c]", info.CIA.Certificates)
+	}
+	if !slices.Equal(info.CIA.ContentIndex, []uint16{0, 1}) {
+		t.Errorf("CIA.ContentIndex = %v, want [0 1]", info.CIA.ContentIndex)
+	}
+	if len(info.CIA.Contents) != 2 || info.CIA.Contents[0].Encrypted || !info.CIA.Contents[1].Encrypted {
+		t.Errorf("CIA.Contents = %+v, want unencrypted content 0 and encrypted content 1", info.CIA.Contents)
+	}
+}
+
+func TestParse3DSX(t *testing.T) {
+	smdh := makeSyntheticSMDH()
+	data := make([]byte, threeDSXExtendedHeaderSize+len(smdh))
+	copy(data, threeDSXMagic)
+	binary.LittleEndian.PutUint16(data[threeDSXHeaderSizeOffset:], threeDSXExtendedHeaderSize)
+	binary.LittleEndian.PutUint32(data[threeDSXSMDHOffset:], threeDSXExtendedHeaderSize)
+	binary.LittleEndian.PutUint32(data[threeDSXSMDHSizeOffset:], uint32(len(smdh)))
+	copy(data[threeDSXExtendedHeaderSize:], smdh)
+
+	info, err := Parse3DSX(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Parse3DSX() error = %v", err)
+	}
+
+	if info.Format != Format3DSX {
+		t.Errorf("Format = %q, want %q", info.Format, Format3DSX)
+	}
+	if info.GameTitle() != "Test Game" {
+		t.Errorf("GameTitle() = %q, want %q", info.GameTitle(), "Test Game")
+	}
+	// No product code, so regions come from the SMDH
+	if !slices.Equal(info.GameRegions(), []core.Region{core.RegionUSA, core.RegionEurope}) {
+		t.Errorf("GameRegions() = %v, want [USA Europe]", info.GameRegions())
+	}
+}
@@ -0,0 +1,193 @@
+package n3ds
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/sargunv/rom-tools/internal/util"
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// NCCH Header layout (0x200 bytes at partition 0 offset):
+//
+//	Offset  Size  Description
+//	0x000   256   RSA-2048 SHA-256 signature
+//	0x100   4     Magic "NCCH" (0x4843434E little-endian)
+//	0x104   4     Content size in media units
+//	0x108   8     Partition/Title ID
+//	0x110   2     Maker code (ASCII)
+//	0x112   2     Version
+//	0x114   4     Seed hash verification
+//	0x118   8     Program ID / Title ID
+//	0x120   16    Reserved
+//	0x130   32    Logo region hash
+//	0x150   16    Product code (ASCII, e.g., "CTR-P-ALGE")
+//	0x160   32    Extended header hash
+//	0x180   4     Extended header size
+//	0x184   4     Reserved
+//	0x188   8     Flags (content type, platform, crypto)
+//	0x190   4     Plain region offset (media units)
+//	0x194   4     Plain region size (media units)
+//	0x198   4     Logo region offset (media units)
+//	0x19C   4     Logo region size (media units)
+//	0x1A0   4     ExeFS offset (media units)
+//	0x1A4   4     ExeFS size (media units)
+//
+// Product code format (16 bytes at NCCH+0x150):
+//
+//	Format: XXX-Y-ZZZZ
+//	  - CTR = 3DS, KTR = New 3DS exclusive
+//	  - P = retail game, N = demo/special
+//	  - ZZZZ = 4-char game code, last char is region (J/E/P/U/C/K/T)
+//
+// NCCH Flags (8 bytes at NCCH+0x188):
+//
+//	Index  Description
+//	0-2    Reserved
+//	3      Crypto method (0=fixed key)
+//	4      Content platform (bit 1 = New 3DS exclusive)
+//	5      Content type (bits 0-2: 0=App, 1=SysUpdate, 2=Manual, 3=DLP, 4=Trial)
+//	6      Content unit size (log2)
+//	7      Crypto flags (bit 0 = fixed key, bit 2 = no crypto)
+//
+// ExeFS header (0x200 bytes at NCCH+ExeFS offset):
+//
+//	Offset  Size  Description
+//	0x000   160   10 file entries: name (8, ASCII), offset (4), size (4)
+//	0x0A0   32    Reserved
+//	0x0C0   320   SHA-256 hashes of the files (in reverse order)
+//
+// File data starts directly after the ExeFS header. The "icon" file holds the
+// SMDH. ExeFS is only readable here when the NCCH "no crypto" flag is set
+// (i.e. the title has been decrypted).
+
+const (
+	ncchMagicOffset       = 0x100
+	ncchMagic             = "NCCH"
+	ncchMakerCodeOffset   = 0x110
+	ncchMakerCodeLen      = 2
+	ncchVersionOffset     = 0x112
+	ncchTitleIDOffset     = 0x118
+	ncchProductCodeOffset = 0x150
+	ncchProductCodeLen    = 16
+	ncchFlagsOffset       = 0x188
+	ncchExeFSOffset       = 0x1A0
+	ncchExeFSSizeOffset   = 0x1A4
+	ncchMinHeaderSize     = 0x200 // Minimum NCCH header to read
+
+	ncchFlagNoCrypto = 0x04 // flags[7]
+
+	exefsHeaderSize  = 0x200
+	exefsFileEntries = 10
+	exefsEntrySize   = 16
+	exefsIconName    = "icon"
+)
+
+// ParseNCCH extracts game information from a standalone NCCH file (CXI/CFA).
+func ParseNCCH(r io.ReaderAt, size int64) (*Info, error) {
+	info, err := parseNCCH(r, 0, size)
+	if err != nil {
+		return nil, err
+	}
+	info.Format = FormatNCCH
+	info.ImageSize = size
+	info.PartitionCount = 1
+	return info, nil
+}
+
+// parseNCCH parses an NCCH header at the given offset.
+// The SMDH is read from ExeFS when the NCCH is decrypted.
+func parseNCCH(r io.ReaderAt, ncchOffset, size int64) (*Info, error) {
+	if ncchOffset+ncchMinHeaderSize > size {
+		return nil, fmt.Errorf("NCCH partition extends beyond file: offset %d, file size %d", ncchOffset, size)
+	}
+
+	// Read NCCH header
+	ncchHeader := make([]byte, ncchMinHeaderSize)
+	if _, err := r.ReadAt(ncchHeader, ncchOffset); err != nil {
+		return nil, fmt.Errorf("failed to read NCCH header at offset %d: %w", ncchOffset, err)
+	}
+
+	// Validate NCCH magic
+	ncchMagicVal := string(ncchHeader[ncchMagicOffset : ncchMagicOffset+4])
+	if ncchMagicVal != ncchMagic {
+		return nil, fmt.Errorf("not a valid NCCH partition: expected magic %q, got %q", ncchMagic, ncchMagicVal)
+	}
+
+	// Parse NCCH fields
+	titleID := binary.LittleEndian.Uint64(ncchHeader[ncchTitleIDOffset:])
+	makerCode := util.ExtractASCII(ncchHeader[ncchMakerCodeOffset : ncchMakerCodeOffset+ncchMakerCodeLen])
+	version := binary.LittleEndian.Uint16(ncchHeader[ncchVersionOffset:])
+	productCode := util.ExtractASCII(ncchHeader[ncchProductCodeOffset : ncchProductCodeOffset+ncchProductCodeLen])
+
+	// Parse flags
+	flags := ncchHeader[ncchFlagsOffset : ncchFlagsOffset+8]
+	contentType := ContentType(flags[5] & 0x07) // Lower 3 bits
+	isNew3DSExclusive := (flags[4] & 0x02) != 0 // Bit 1 of flags[4]
+
+	// Determine region from product code (last character of game code)
+	// Format: CTR-P-XXXR where R is region
+	var region Region
+	if len(productCode) >= 10 {
+		region = Region(productCode[9])
+	}
+
+	// Determine platform
+	var platform core.Platform
+	if isNew3DSExclusive {
+		platform = core.PlatformNew3DS
+	} else {
+		platform = core.Platform3DS
+	}
+
+	info := &Info{
+		TitleID:           titleID,
+		ProductCode:       productCode,
+		MakerCode:         makerCode,
+		Version:           version,
+		ContentType:       contentType,
+		IsNew3DSExclusive: isNew3DSExclusive,
+		Region:            region,
+		platform:          platform,
+	}
+
+	// ExeFS is only readable without keys when the content is decrypted
+	if flags[7]&ncchFlagNoCrypto != 0 {
+		exefsOffset := ncchOffset + int64(binary.LittleEndian.Uint32(ncchHeader[ncchExeFSOffset:]))*mediaUnitSize
+		exefsSize := int64(binary.LittleEndian.Uint32(ncchHeader[ncchExeFSSizeOffset:])) * mediaUnitSize
+		if exefsSize > 0 {
+			// A missing or malformed icon just means no localized titles
+			info.SMDH, _ = readExeFSIcon(r, exefsOffset, size)
+		}
+	}
+
+	return info, nil
+}
+
+// readExeFSIcon locates the "icon" file in an ExeFS and parses it as SMDH.
+func readExeFSIcon(r io.ReaderAt, exefsOffset, size int64) (*SMDH, error) {
+	if exefsOffset+exefsHeaderSize > size {
+		return nil, fmt.Errorf("ExeFS header extends beyond file")
+	}
+
+	header := make([]byte, exefsHeaderSize)
+	if _, err := r.ReadAt(header, exefsOffset); err != nil {
+		return nil, fmt.Errorf("failed to read ExeFS header: %w", err)
+	}
+
+	for i := 0; i < exefsFileEntries; i++ {
+		entry := header[i*exefsEntrySize : (i+1)*exefsEntrySize]
+		if util.ExtractASCII(entry[:8]) != exefsIconName {
+			continue
+		}
+		fileOffset := exefsOffset + exefsHeaderSize + int64(binary.LittleEndian.Uint32(entry[8:]))
+		fileSize := int64(binary.LittleEndian.Uint32(entry[12:]))
+		if fileOffset+fileSize > size {
+			return nil, fmt.Errorf("ExeFS icon extends beyond file")
+		}
+		return ParseSMDH(io.NewSectionReader(r, fileOffset, fileSize), fileSize)
+	}
+
+	return nil, fmt.Errorf("ExeFS has no icon file")
+}
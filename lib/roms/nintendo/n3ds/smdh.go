@@ -0,0 +1,280 @@
+package n3ds
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"unicode/utf16"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// SMDH (icon and application title) format parsing.
+//
+// SMDH specification:
+// https://www.3dbrew.org/wiki/SMDH
+//
+// SMDH layout (0x36C0 bytes, little-endian):
+//
+//	Offset  Size    Description
+//	0x0000  4       Magic "SMDH"
+//	0x0004  2       Version
+//	0x0006  2       Reserved
+//	0x0008  0x2000  Application titles (16 x 0x200 bytes, see below)
+//	0x2008  0x10    Age ratings
+//	0x2018  4       Region lockout flags
+//	0x201C  12      Match maker IDs
+//	0x2028  4       Flags
+//	0x2040  0x480   Small icon (24x24, RGB565, tiled)
+//	0x24C0  0x1200  Large icon (48x48, RGB565, tiled)
+//
+// Application title entry (0x200 bytes, UTF-16LE):
+//
+//	Offset  Size   Description
+//	0x000   0x80   Short description (title)
+//	0x080   0x100  Long description (full title)
+//	0x180   0x80   Publisher
+//
+// Icons are stored as 8x8 tiles in row-major order, with the pixels of each
+// tile in Morton (Z-order) order.
+
+const (
+	smdhMagic            = "SMDH"
+	smdhSize             = 0x36C0
+	smdhVersionOffset    = 0x0004
+	smdhTitlesOffset     = 0x0008
+	smdhTitleEntrySize   = 0x200
+	smdhTitleCount       = 16
+	smdhShortDescLen     = 0x80
+	smdhLongDescLen      = 0x100
+	smdhPublisherLen     = 0x80
+	smdhRegionLockOffset = 0x2018
+	smdhSmallIconOffset  = 0x2040
+	smdhSmallIconSize    = 24
+	smdhLargeIconOffset  = 0x24C0
+	smdhLargeIconSize    = 48
+)
+
+// Language identifies an SMDH application title slot.
+type Language string
+
+// Language values in SMDH title slot order.
+const (
+	LanguageJapanese           Language = "ja"
+	LanguageEnglish            Language = "en"
+	LanguageFrench             Language = "fr"
+	LanguageGerman             Language = "de"
+	LanguageItalian            Language = "it"
+	LanguageSpanish            Language = "es"
+	LanguageSimplifiedChinese  Language = "zh-Hans"
+	LanguageKorean             Language = "ko"
+	LanguageDutch              Language = "nl"
+	LanguagePortuguese         Language = "pt"
+	LanguageRussian            Language = "ru"
+	LanguageTraditionalChinese Language = "zh-Hant"
+)
+
+// smdhLanguages lists the languages of the SMDH title slots in order.
+// Slots 12-15 are unused.
+var smdhLanguages = []Language{
+	LanguageJapanese,
+	LanguageEnglish,
+	LanguageFrench,
+	LanguageGerman,
+	LanguageItalian,
+	LanguageSpanish,
+	LanguageSimplifiedChinese,
+	LanguageKorean,
+	LanguageDutch,
+	LanguagePortuguese,
+	LanguageRussian,
+	LanguageTraditionalChinese,
+}
+
+// RegionLockout is the SMDH region lockout bitmask.
+type RegionLockout uint32
+
+// RegionLockout flags per 3dbrew.
+const (
+	RegionLockoutJapan        RegionLockout = 0x01
+	RegionLockoutNorthAmerica RegionLockout = 0x02
+	RegionLockoutEurope       RegionLockout = 0x04
+	RegionLockoutAustralia    RegionLockout = 0x08
+	RegionLockoutChina        RegionLockout = 0x10
+	RegionLockoutKorea        RegionLockout = 0x20
+	RegionLockoutTaiwan       RegionLockout = 0x40
+	RegionLockoutFree         RegionLockout = 0x7FFFFFFF
+)
+
+// Regions converts the region lockout flags to core regions.
+func (l RegionLockout) Regions() []core.Region {
+	if l == RegionLockoutFree {
+		return []core.Region{core.RegionWorld}
+	}
+	var regions []core.Region
+	if l&RegionLockoutJapan != 0 {
+		regions = append(regions, core.RegionJapan)
+	}
+	if l&RegionLockoutNorthAmerica != 0 {
+		regions = append(regions, core.RegionUSA)
+	}
+	if l&RegionLockoutEurope != 0 {
+		regions = append(regions, core.RegionEurope)
+	}
+	if l&RegionLockoutAustralia != 0 {
+		regions = append(regions, core.RegionAustralia)
+	}
+	if l&RegionLockoutChina != 0 {
+		regions = append(regions, core.RegionChina)
+	}
+	if l&RegionLockoutKorea != 0 {
+		regions = append(regions, core.RegionKorea)
+	}
+	if l&RegionLockoutTaiwan != 0 {
+		regions = append(regions, core.RegionTaiwan)
+	}
+	return regions
+}
+
+// ApplicationTitle is a localized title entry from an SMDH.
+type ApplicationTitle struct {
+	// ShortDescription is the short title shown on the HOME Menu.
+	ShortDescription string `json:"short_description,omitempty"`
+	// LongDescription is the full title.
+	LongDescription string `json:"long_description,omitempty"`
+	// Publisher is the publisher name.
+	Publisher string `json:"publisher,omitempty"`
+}
+
+// SMDH contains the localized titles and icons of a 3DS title.
+type SMDH struct {
+	// Version is the SMDH version.
+	Version uint16 `json:"version"`
+	// Titles maps languages to their localized titles. Empty slots are omitted.
+	Titles map[Language]ApplicationTitle `json:"titles,omitempty"`
+	// RegionLockout is the region lockout bitmask.
+	RegionLockout RegionLockout `json:"region_lockout"`
+	// SmallIcon is the 24x24 icon.
+	SmallIcon image.Image `json:"-"`
+	// LargeIcon is the 48x48 icon.
+	LargeIcon image.Image `json:"-"`
+}
+
+// Title returns the short title, preferring English, then Japanese, then any other language.
+func (s *SMDH) Title() string {
+	return s.localized(func(t ApplicationTitle) string { return t.ShortDescription })
+}
+
+// LongTitle returns the long title, preferring English, then Japanese, then any other language.
+func (s *SMDH) LongTitle() string {
+	return s.localized(func(t ApplicationTitle) string { return t.LongDescription })
+}
+
+// Publisher returns the publisher, preferring English, then Japanese, then any other language.
+func (s *SMDH) Publisher() string {
+	return s.localized(func(t ApplicationTitle) string { return t.Publisher })
+}
+
+func (s *SMDH) localized(field func(ApplicationTitle) string) string {
+	if v := field(s.Titles[LanguageEnglish]); v != "" {
+		return v
+	}
+	if v := field(s.Titles[LanguageJapanese]); v != "" {
+		return v
+	}
+	for _, lang := range smdhLanguages {
+		if v := field(s.Titles[lang]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ParseSMDH parses an SMDH icon file.
+func ParseSMDH(r io.ReaderAt, size int64) (*SMDH, error) {
+	if size < smdhSize {
+		return nil, fmt.Errorf("file too small for SMDH: %d bytes (need %d)", size, smdhSize)
+	}
+
+	data := make([]byte, smdhSize)
+	if _, err := r.ReadAt(data, 0); err != nil {
+		return nil, fmt.Errorf("failed to read SMDH: %w", err)
+	}
+
+	if string(data[:4]) != smdhMagic {
+		return nil, fmt.Errorf("not a valid SMDH: invalid magic %q", string(data[:4]))
+	}
+
+	titles := make(map[Language]ApplicationTitle)
+	for i, lang := range smdhLanguages {
+		entry := data[smdhTitlesOffset+i*smdhTitleEntrySize:]
+		title := ApplicationTitle{
+			ShortDescription: decodeUTF16LE(entry[:smdhShortDescLen]),
+			LongDescription:  decodeUTF16LE(entry[smdhShortDescLen : smdhShortDescLen+smdhLongDescLen]),
+			Publisher:        decodeUTF16LE(entry[smdhShortDescLen+smdhLongDescLen : smdhTitleEntrySize]),
+		}
+		if title != (ApplicationTitle{}) {
+			titles[lang] = title
+		}
+	}
+
+	return &SMDH{
+		Version:       binary.LittleEndian.Uint16(data[smdhVersionOffset:]),
+		Titles:        titles,
+		RegionLockout: RegionLockout(binary.LittleEndian.Uint32(data[smdhRegionLockOffset:])),
+		SmallIcon:     decodeTiledRGB565(data[smdhSmallIconOffset:], smdhSmallIconSize),
+		LargeIcon:     decodeTiledRGB565(data[smdhLargeIconOffset:], smdhLargeIconSize),
+	}, nil
+}
+
+// decodeTiledRGB565 decodes a square icon of 8x8 Morton-ordered RGB565 tiles.
+func decodeTiledRGB565(data []byte, dim int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, dim, dim))
+	i := 0
+	for tileY := 0; tileY < dim; tileY += 8 {
+		for tileX := 0; tileX < dim; tileX += 8 {
+			for p := 0; p < 64; p++ {
+				x, y := mortonDecode(p)
+				px := binary.LittleEndian.Uint16(data[i*2:])
+				i++
+				r := byte(px>>11) & 0x1F
+				g := byte(px>>5) & 0x3F
+				b := byte(px) & 0x1F
+				img.SetNRGBA(tileX+x, tileY+y, color.NRGBA{
+					R: r<<3 | r>>2,
+					G: g<<2 | g>>4,
+					B: b<<3 | b>>2,
+					A: 0xFF,
+				})
+			}
+		}
+	}
+	return img
+}
+
+// mortonDecode converts a Z-order index within an 8x8 tile to x/y coordinates.
+// Even bits of the index form x, odd bits form y.
+func mortonDecode(i int) (x, y int) {
+	x = (i & 1) | (i>>1)&2 | (i>>2)&4
+	y = (i>>1)&1 | (i>>2)&2 | (i>>3)&4
+	return x, y
+}
+
+// decodeUTF16LE decodes a null-terminated UTF-16LE string.
+func decodeUTF16LE(data []byte) string {
+	u16s := make([]uint16, len(data)/2)
+	for i := range u16s {
+		u16s[i] = binary.LittleEndian.Uint16(data[i*2:])
+	}
+
+	for i, v := range u16s {
+		if v == 0 {
+			u16s = u16s[:i]
+			break
+		}
+	}
+
+	return string(utf16.Decode(u16s))
+}
@@ -0,0 +1,299 @@
+package n64
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sargunv/rom-tools/internal/util"
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// Nintendo 64DD disk image parsing (.ndd and .d64).
+//
+// 64DD disk format specification:
+// https://n64brew.dev/wiki/64DD_Disk_Format
+//
+// The first 24 logical blocks (LBAs) of a disk form the system area. All of
+// them are in zone 0, where each block is 85 sectors of 232 bytes (19720
+// bytes). The system data is stored in LBAs 0, 1, 8 and 9 on retail disks and
+// in LBAs 2, 3, 10 and 11 on development disks. The disk ID is stored in LBAs
+// 14 and 15. Each copy occupies the first 232 bytes of its block.
+//
+// NDD images are full raw dumps of the disk. D64 images (a homebrew-friendly
+// format supported by MAME and Ares) store the system data at 0x000 and the
+// disk ID at 0x100, followed by the ROM and RAM area data at 0x200.
+//
+// System data layout (232 bytes, big-endian):
+//
+//	Offset  Size  Description
+//	0x00    4     Country code (0xE848D316 = Japan, 0x2263EE56 = USA, 0 = development)
+//	0x04    1     Format type (always 0x10)
+//	0x05    1     Disk type (0x10 + type 0-6)
+//	0x06    2     IPL load size (blocks)
+//	0x08    16    Defect track offsets (per zone)
+//	0x18    4     Reserved
+//	0x1C    4     IPL load address
+//	0x20    192   Defect track list
+//	0xE0    2     ROM area end LBA
+//	0xE2    2     RAM area start LBA (0xFFFF = no RAM area)
+//	0xE4    2     RAM area end LBA (0xFFFF = no RAM area)
+//	0xE6    2     Reserved
+//
+// Disk ID layout (232 bytes):
+//
+//	Offset  Size  Description
+//	0x00    4     Game code (category, unique code, destination, e.g., "DMPJ")
+//	0x04    1     Game version
+//	0x05    1     Disk number
+//	0x06    1     RAM area usage
+//	0x07    1     Disk use
+//	0x08    8     Factory line number
+//	0x10    8     Production date/time (BCD: year (2), month, day, hour, minute, second, unused)
+//	0x18    2     Company code (ASCII, e.g., "01" for Nintendo)
+//	0x1A    6     Free area
+
+const (
+	ddSectorSize    = 0xE8
+	ddZone0Block    = 85 * ddSectorSize // 19720 bytes
+	ddSystemAreaEnd = 24 * ddZone0Block
+
+	ddD64DiskIDOffset = 0x100
+	ddD64HeaderSize   = 0x200
+
+	ddCountryOffset     = 0x00
+	ddFormatTypeOffset  = 0x04
+	ddDiskTypeOffset    = 0x05
+	ddIPLSizeOffset     = 0x06
+	ddIPLAddressOffset  = 0x1C
+	ddROMEndLBAOffset   = 0xE0
+	ddRAMStartLBAOffset = 0xE2
+	ddRAMEndLBAOffset   = 0xE4
+
+	ddFormatType   = 0x10
+	ddDiskTypeBase = 0x10
+	ddMaxDiskType  = 6
+
+	ddGameCodeOffset    = 0x00
+	ddGameCodeLen       = 4
+	ddGameVersionOffset = 0x04
+	ddDiskNumberOffset  = 0x05
+	ddRAMUsageOffset    = 0x06
+	ddDiskUseOffset     = 0x07
+	ddDateOffset        = 0x10
+	ddCompanyCodeOffset = 0x18
+	ddCompanyCodeLen    = 2
+
+	// DDNoRAMArea marks the RAM area LBAs of a disk without a RAM area.
+	DDNoRAMArea = 0xFFFF
+)
+
+// Block locations of the system area copies.
+var (
+	ddRetailSystemLBAs = []int64{0, 1, 8, 9}
+	ddDevSystemLBAs    = []int64{2, 3, 10, 11}
+	ddDiskIDLBAs       = []int64{14, 15}
+)
+
+// DDCountry is the country code from the 64DD system data.
+type DDCountry uint32
+
+// DDCountry values.
+const (
+	DDCountryDevelopment DDCountry = 0x00000000
+	DDCountryJapan       DDCountry = 0xE848D316
+	DDCountryUSA         DDCountry = 0x2263EE56
+)
+
+// DDInfo contains metadata extracted from a 64DD disk image.
+type DDInfo struct {
+	// Country is the country code from the system data.
+	Country DDCountry `json:"country"`
+	// Development is true if the system data was found in the development disk blocks.
+	Development bool `json:"development,omitempty"`
+	// DiskType is the disk type (0-6), which determines the ROM/RAM zone layout.
+	DiskType int `json:"disk_type"`
+	// IPLLoadSize is the number of blocks loaded by the IPL at boot.
+	IPLLoadSize int `json:"ipl_load_size"`
+	// IPLLoadAddress is the RDRAM address the boot code is loaded to.
+	IPLLoadAddress uint32 `json:"ipl_load_address"`
+	// ROMEndLBA is the last LBA of the read-only area.
+	ROMEndLBA int `json:"rom_end_lba"`
+	// RAMStartLBA is the first LBA of the writable area (DDNoRAMArea if none).
+	RAMStartLBA int `json:"ram_start_lba"`
+	// RAMEndLBA is the last LBA of the writable area (DDNoRAMArea if none).
+	RAMEndLBA int `json:"ram_end_lba"`
+
+	// GameCode is the 4-character game code from the disk ID (e.g., "DMPJ").
+	GameCode string `json:"game_code,omitempty"`
+	// CategoryCode is the media type from the game code.
+	CategoryCode CategoryCode `json:"category_code"`
+	// Destination is the target region from the game code.
+	Destination Destination `json:"destination"`
+	// Version is the game version.
+	Version int `json:"version"`
+	// DiskNumber is the disk number within a multi-disk game.
+	DiskNumber int `json:"disk_number"`
+	// CompanyCode is the 2-character publisher code.
+	CompanyCode string `json:"company_code,omitempty"`
+	// ProductionDate is the disk production date and time.
+	ProductionDate time.Time `json:"production_date,omitempty"`
+}
+
+// GamePlatform implements core.GameInfo.
+func (i *DDInfo) GamePlatform() core.Platform { return core.PlatformN64DD }
+
+// GameTitle implements core.GameInfo.
+// 64DD disks have no title in the system area.
+func (i *DDInfo) GameTitle() string { return "" }
+
+// GameSerial implements core.GameInfo.
+func (i *DDInfo) GameSerial() string { return i.GameCode }
+
+// GameRegions implements core.GameInfo.
+func (i *DDInfo) GameRegions() []core.Region {
+	if regions := destinationRegions(i.Destination); len(regions) > 0 {
+		return regions
+	}
+	switch i.Country {
+	case DDCountryJapan:
+		return []core.Region{core.RegionJapan}
+	case DDCountryUSA:
+		return []core.Region{core.RegionUSA}
+	default:
+		return []core.Region{}
+	}
+}
+
+// ParseNDD extracts disk information from a raw 64DD disk image (.ndd).
+func ParseNDD(r io.ReaderAt, size int64) (*DDInfo, error) {
+	if size < ddSystemAreaEnd {
+		return nil, fmt.Errorf("file too small for 64DD system area: %d bytes", size)
+	}
+
+	readBlock := func(lba int64) ([]byte, error) {
+		data := make([]byte, ddSectorSize)
+		if _, err := r.ReadAt(data, lba*ddZone0Block); err != nil {
+			return nil, fmt.Errorf("failed to read 64DD LBA %d: %w", lba, err)
+		}
+		return data, nil
+	}
+
+	info, err := findDDSystemData(readBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, lba := range ddDiskIDLBAs {
+		diskID, err := readBlock(lba)
+		if err != nil {
+			return nil, err
+		}
+		if parseDDDiskID(info, diskID) {
+			break
+		}
+	}
+
+	return info, nil
+}
+
+// ParseD64 extracts disk information from a D64 64DD disk image.
+func ParseD64(r io.ReaderAt, size int64) (*DDInfo, error) {
+	if size < ddD64HeaderSize {
+		return nil, fmt.Errorf("file too small for D64 header: %d bytes", size)
+	}
+
+	header := make([]byte, ddD64HeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read D64 header: %w", err)
+	}
+
+	info, ok := parseDDSystemData(header[:ddSectorSize])
+	if !ok {
+		return nil, fmt.Errorf("not a valid D64 image: invalid system data")
+	}
+	parseDDDiskID(info, header[ddD64DiskIDOffset:ddD64DiskIDOffset+ddSectorSize])
+
+	return info, nil
+}
+
+// findDDSystemData reads the retail, then development system data copies
+// until a valid one is found.
+func findDDSystemData(readBlock func(lba int64) ([]byte, error)) (*DDInfo, error) {
+	for _, dev := range []bool{false, true} {
+		lbas := ddRetailSystemLBAs
+		if dev {
+			lbas = ddDevSystemLBAs
+		}
+		for _, lba := range lbas {
+			data, err := readBlock(lba)
+			if err != nil {
+				return nil, err
+			}
+			if info, ok := parseDDSystemData(data); ok {
+				info.Development = dev
+				return info, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("not a valid 64DD disk: no valid system data found")
+}
+
+// parseDDSystemData parses a system data block. Returns false if the block is not valid.
+func parseDDSystemData(data []byte) (*DDInfo, bool) {
+	if data[ddFormatTypeOffset] != ddFormatType {
+		return nil, false
+	}
+	diskType := int(data[ddDiskTypeOffset]) - ddDiskTypeBase
+	if diskType < 0 || diskType > ddMaxDiskType {
+		return nil, false
+	}
+
+	return &DDInfo{
+		Country:        DDCountry(binary.BigEndian.Uint32(data[ddCountryOffset:])),
+		DiskType:       diskType,
+		IPLLoadSize:    int(binary.BigEndian.Uint16(data[ddIPLSizeOffset:])),
+		IPLLoadAddress: binary.BigEndian.Uint32(data[ddIPLAddressOffset:]),
+		ROMEndLBA:      int(binary.BigEndian.Uint16(data[ddROMEndLBAOffset:])),
+		RAMStartLBA:    int(binary.BigEndian.Uint16(data[ddRAMStartLBAOffset:])),
+		RAMEndLBA:      int(binary.BigEndian.Uint16(data[ddRAMEndLBAOffset:])),
+	}, true
+}
+
+// parseDDDiskID fills in the disk ID fields. Returns false if the block is blank.
+func parseDDDiskID(info *DDInfo, data []byte) bool {
+	gameCode := util.ExtractASCII(data[ddGameCodeOffset : ddGameCodeOffset+ddGameCodeLen])
+	if gameCode == "" {
+		return false
+	}
+
+	info.GameCode = gameCode
+	if len(gameCode) == ddGameCodeLen {
+		info.CategoryCode = CategoryCode(gameCode[0])
+		info.Destination = Destination(gameCode[3])
+	}
+	info.Version = int(data[ddGameVersionOffset])
+	info.DiskNumber = int(data[ddDiskNumberOffset])
+	info.CompanyCode = util.ExtractASCII(data[ddCompanyCodeOffset : ddCompanyCodeOffset+ddCompanyCodeLen])
+	info.ProductionDate = parseDDDate(data[ddDateOffset : ddDateOffset+7])
+	return true
+}
+
+// parseDDDate converts a BCD production date (year (2), month, day, hour,
+// minute, second) to a time. Returns the zero time if the date is invalid.
+func parseDDDate(b []byte) time.Time {
+	var v [7]int
+	for i, x := range b {
+		hi, lo := int(x>>4), int(x&0x0F)
+		if hi > 9 || lo > 9 {
+			return time.Time{}
+		}
+		v[i] = hi*10 + lo
+	}
+	year := v[0]*100 + v[1]
+	if year == 0 || v[2] < 1 || v[2] > 12 || v[3] < 1 || v[3] > 31 {
+		return time.Time{}
+	}
+	return time.Date(year, time.Month(v[2]), v[3], v[4], v[5], v[6], 0, time.UTC)
+}
@@ -0,0 +1,158 @@
+package n64
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// makeDDSystemData creates a system data block.
+func makeDDSystemData(country DDCountry, diskType byte) []byte {
+	data := make([]byte, ddSectorSize)
+	binary.BigEndian.PutUint32(data[ddCountryOffset:], uint32(country))
+	data[ddFormatTypeOffset] = ddFormatType
+	data[ddDiskTypeOffset] = ddDiskTypeBase + diskType
+	binary.BigEndian.PutUint16(data[ddIPLSizeOffset:], 0x0010)
+	binary.BigEndian.PutUint32(data[ddIPLAddressOffset:], 0x80000400)
+	binary.BigEndian.PutUint16(data[ddROMEndLBAOffset:], 0x05A5)
+	binary.BigEndian.PutUint16(data[ddRAMStartLBAOffset:], 0x05A6)
+	binary.BigEndian.PutUint16(data[ddRAMEndLBAOffset:], 0x10DB)
+	return data
+}
+
+// makeDDDiskID creates a disk ID block.
+func makeDDDiskID(gameCode string, version byte, company string) []byte {
+	data := make([]byte, ddSectorSize)
+	copy(data[ddGameCodeOffset:], gameCode)
+	data[ddGameVersionOffset] = version
+	copy(data[ddDateOffset:], []byte{0x19, 0x99, 0x12, 0x01, 0x10, 0x30, 0x00})
+	copy(data[ddCompanyCodeOffset:], company)
+	return data
+}
+
+// makeTestNDD creates a raw disk image with system data in the given LBAs.
+func makeTestNDD(systemData []byte, systemLBAs []int64, diskID []byte) []byte {
+	image := make([]byte, ddSystemAreaEnd)
+	for _, lba := range systemLBAs {
+		copy(image[lba*ddZone0Block:], systemData)
+	}
+	for _, lba := range ddDiskIDLBAs {
+		copy(image[lba*ddZone0Block:], diskID)
+	}
+	return image
+}
+
+func TestParseNDD(t *testing.T) {
+	image := makeTestNDD(
+		makeDDSystemData(DDCountryJapan, 0),
+		ddRetailSystemLBAs,
+		makeDDDiskID("DMPJ", 0x01, "01"),
+	)
+
+	info, err := ParseNDD(bytes.NewReader(image), int64(len(image)))
+	if err != nil {
+		t.Fatalf("ParseNDD() error = %v", err)
+	}
+
+	if info.GamePlatform() != core.PlatformN64DD {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformN64DD)
+	}
+	if info.GameSerial() != "DMPJ" {
+		t.Errorf("GameSerial() = %q, want %q", info.GameSerial(), "DMPJ")
+	}
+	if info.Country != DDCountryJapan || info.Development {
+		t.Errorf("Country, Development = 0x%08X, %v, want 0x%08X, false", uint32(info.Country), info.Development, uint32(DDCountryJapan))
+	}
+	if info.DiskType != 0 {
+		t.Errorf("DiskType = %d, want 0", info.DiskType)
+	}
+	if info.IPLLoadSize != 0x10 || info.IPLLoadAddress != 0x80000400 {
+		t.Errorf("IPLLoadSize, IPLLoadAddress = %d, 0x%08X, want 16, 0x80000400", info.IPLLoadSize, info.IPLLoadAddress)
+	}
+	if info.ROMEndLBA != 0x05A5 || info.RAMStartLBA != 0x05A6 || info.RAMEndLBA != 0x10DB {
+		t.Errorf("LBAs = %d, %d, %d, want 1445, 1446, 4315", info.ROMEndLBA, info.RAMStartLBA, info.RAMEndLBA)
+	}
+	if info.CategoryCode != Category64DD || info.Destination != DestinationJapan {
+		t.Errorf("CategoryCode, Destination = %c, %c, want D, J", info.CategoryCode, info.Destination)
+	}
+	if info.Version != 1 || info.CompanyCode != "01" {
+		t.Errorf("Version, CompanyCode = %d, %q, want 1, %q", info.Version, info.CompanyCode, "01")
+	}
+	wantDate := time.Date(1999, time.December, 1, 10, 30, 0, 0, time.UTC)
+	if !info.ProductionDate.Equal(wantDate) {
+		t.Errorf("ProductionDate = %v, want %v", info.ProductionDate, wantDate)
+	}
+	if regions := info.GameRegions(); len(regions) != 1 || regions[0] != core.RegionJapan {
+		t.Errorf("GameRegions() = %v, want [%v]", regions, core.RegionJapan)
+	}
+}
+
+func TestParseNDD_Development(t *testing.T) {
+	image := makeTestNDD(
+		makeDDSystemData(DDCountryDevelopment, 3),
+		ddDevSystemLBAs[2:],
+		make([]byte, ddSectorSize),
+	)
+
+	info, err := ParseNDD(bytes.NewReader(image), int64(len(image)))
+	if err != nil {
+		t.Fatalf("ParseNDD() error = %v", err)
+	}
+
+	if !info.Development {
+		t.Error("Development = false, want true")
+	}
+	if info.DiskType != 3 {
+		t.Errorf("DiskType = %d, want 3", info.DiskType)
+	}
+	if info.GameSerial() != "" {
+		t.Errorf("GameSerial() = %q, want empty", info.GameSerial())
+	}
+	if regions := info.GameRegions(); len(regions) != 0 {
+		t.Errorf("GameRegions() = %v, want empty", regions)
+	}
+}
+
+func TestParseNDD_Invalid(t *testing.T) {
+	image := make([]byte, ddSystemAreaEnd)
+
+	if _, err := ParseNDD(bytes.NewReader(image), int64(len(image))); err == nil {
+		t.Error("ParseNDD() expected error for blank disk")
+	}
+	if _, err := ParseNDD(bytes.NewReader(image[:1000]), 1000); err == nil {
+		t.Error("ParseNDD() expected error for small file")
+	}
+}
+
+func TestParseD64(t *testing.T) {
+	image := make([]byte, ddD64HeaderSize+0x1000)
+	copy(image, makeDDSystemData(DDCountryUSA, 1))
+	copy(image[ddD64DiskIDOffset:], makeDDDiskID("DZSE", 0x00, "01"))
+
+	info, err := ParseD64(bytes.NewReader(image), int64(len(image)))
+	if err != nil {
+		t.Fatalf("ParseD64() error = %v", err)
+	}
+
+	if info.GameSerial() != "DZSE" {
+		t.Errorf("GameSerial() = %q, want %q", info.GameSerial(), "DZSE")
+	}
+	if info.DiskType != 1 {
+		t.Errorf("DiskType = %d, want 1", info.DiskType)
+	}
+	if regions := info.GameRegions(); len(regions) != 1 || regions[0] != core.RegionUSA {
+		t.Errorf("GameRegions() = %v, want [%v]", regions, core.RegionUSA)
+	}
+}
+
+func TestParseD64_RejectsC64Disk(t *testing.T) {
+	// Commodore 64 .d64 images share the extension but have no 64DD system data
+	image := make([]byte, 174848)
+
+	if _, err := ParseD64(bytes.NewReader(image), int64(len(image))); err == nil {
+		t.Error("ParseD64() expected error for non-64DD image")
+	}
+}
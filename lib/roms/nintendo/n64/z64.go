@@ -155,7 +155,12 @@ func (i *Info) GameSerial() string { return i.GameCode }
 
 // GameRegions implements core.GameInfo.
 func (i *Info) GameRegions() []core.Region {
-	switch i.Destination {
+	return destinationRegions(i.Destination)
+}
+
+// destinationRegions maps a game code destination to regions.
+func destinationRegions(d Destination) []core.Region {
+	switch d {
 	case DestinationJapan:
 		return []core.Region{core.RegionJapan}
 	case DestinationNorthAmerica:
@@ -0,0 +1,340 @@
+package nds
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// NDS/DSi banner (icon and title) parsing.
+//
+// Banner specification:
+// https://problemkaputt.de/gbatek-ds-cartridge-icon-title.htm
+//
+// The banner is located at the offset stored in the cartridge header (0x068).
+//
+// Banner layout (little-endian):
+//
+//	Offset  Size    Description
+//	0x0000  2       Version (0x0001, 0x0002 adds Chinese, 0x0003 adds Korean, 0x0103 adds DSi animated icon)
+//	0x0002  8       CRC-16 checksums
+//	0x000A  22      Reserved
+//	0x0020  0x200   Icon bitmap (32x32, 4bpp, 8x8 tiles)
+//	0x0220  0x20    Icon palette (16 colors, BGR555, color 0 is transparent)
+//	0x0240  0x100   Japanese title (UTF-16LE)
+//	0x0340  0x100   English title
+//	0x0440  0x100   French title
+//	0x0540  0x100   German title
+//	0x0640  0x100   Italian title
+//	0x0740  0x100   Spanish title
+//	0x0840  0x100   Chinese title (version 0x0002+)
+//	0x0940  0x100   Korean title (version 0x0003+)
+//	0x0A40  0x800   Reserved
+//	0x1240  0x1000  DSi animated icon bitmaps (8 x 0x200)
+//	0x2240  0x100   DSi animated icon palettes (8 x 0x20)
+//	0x2340  0x80    DSi animation sequence (64 x 2 bytes, 0x0000 terminates)
+//
+// Titles are up to three lines separated by newlines: the game name, an
+// optional subtitle, and the publisher.
+//
+// Animation sequence entry (16 bits):
+//
+//	Bit    Description
+//	15     Flip vertically
+//	14     Flip horizontally
+//	11-13  Palette index
+//	8-10   Bitmap index
+//	0-7    Frame duration (in 60 Hz frames)
+
+const (
+	bannerVersionOffset   = 0x0000
+	bannerIconOffset      = 0x0020
+	bannerPaletteOffset   = 0x0220
+	bannerTitlesOffset    = 0x0240
+	bannerTitleLen        = 0x100
+	bannerDSiBitmapOffset = 0x1240
+	bannerDSiPaletteOff   = 0x2240
+	bannerDSiSequenceOff  = 0x2340
+	bannerDSiSequenceLen  = 64
+
+	bannerSizeV1  = 0x0840
+	bannerSizeV2  = 0x0940
+	bannerSizeV3  = 0x0A40
+	bannerSizeDSi = 0x23C0
+
+	iconDim         = 32
+	iconBitmapSize  = 0x200
+	iconPaletteSize = 0x20
+)
+
+// BannerVersion values per GBATEK.
+const (
+	BannerVersionOriginal = 0x0001
+	BannerVersionChinese  = 0x0002
+	BannerVersionKorean   = 0x0003
+	BannerVersionDSi      = 0x0103
+)
+
+// Language identifies a banner title slot.
+type Language string
+
+// Language values in banner title slot order.
+const (
+	LanguageJapanese Language = "ja"
+	LanguageEnglish  Language = "en"
+	LanguageFrench   Language = "fr"
+	LanguageGerman   Language = "de"
+	LanguageItalian  Language = "it"
+	LanguageSpanish  Language = "es"
+	LanguageChinese  Language = "zh"
+	LanguageKorean   Language = "ko"
+)
+
+// bannerLanguages lists the languages of the banner title slots in order.
+var bannerLanguages = []Language{
+	LanguageJapanese,
+	LanguageEnglish,
+	LanguageFrench,
+	LanguageGerman,
+	LanguageItalian,
+	LanguageSpanish,
+	LanguageChinese,
+	LanguageKorean,
+}
+
+// BannerTitle is a localized title entry from the banner.
+type BannerTitle struct {
+	// Title is the first line, the game name.
+	Title string `json:"title,omitempty"`
+	// Subtitle is the middle line of a three-line title.
+	Subtitle string `json:"subtitle,omitempty"`
+	// Publisher is the last line of a multi-line title.
+	Publisher string `json:"publisher,omitempty"`
+}
+
+// Name returns the full game name, joining the title and subtitle.
+func (t BannerTitle) Name() string {
+	if t.Subtitle == "" {
+		return t.Title
+	}
+	if strings.HasSuffix(t.Title, ":") || strings.HasSuffix(t.Title, "-") {
+		return t.Title + " " + t.Subtitle
+	}
+	return t.Title + ": " + t.Subtitle
+}
+
+// AnimationFrame is a single frame of a DSi animated icon.
+type AnimationFrame struct {
+	// Image is the 32x32 frame image.
+	Image image.Image
+	// Duration is how long the frame is displayed.
+	Duration time.Duration
+}
+
+// Banner contains the localized titles and icons of an NDS/DSi title.
+type Banner struct {
+	// Version is the banner version.
+	Version uint16 `json:"version"`
+	// Titles maps languages to their localized titles. Empty slots are omitted.
+	Titles map[Language]BannerTitle `json:"titles,omitempty"`
+	// Icon is the 32x32 static icon.
+	Icon image.Image `json:"-"`
+	// AnimatedIcon is the DSi animated icon (nil if not present).
+	AnimatedIcon []AnimationFrame `json:"-"`
+}
+
+// Title returns the game name, preferring English, then Japanese, then any other language.
+func (b *Banner) Title() string {
+	return b.localized(func(t BannerTitle) string { return t.Name() })
+}
+
+// Publisher returns the publisher, preferring English, then Japanese, then any other language.
+func (b *Banner) Publisher() string {
+	return b.localized(func(t BannerTitle) string { return t.Publisher })
+}
+
+func (b *Banner) localized(field func(BannerTitle) string) string {
+	if v := field(b.Titles[LanguageEnglish]); v != "" {
+		return v
+	}
+	if v := field(b.Titles[LanguageJapanese]); v != "" {
+		return v
+	}
+	for _, lang := range bannerLanguages {
+		if v := field(b.Titles[lang]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ParseBanner parses an NDS/DSi banner.
+func ParseBanner(r io.ReaderAt, size int64) (*Banner, error) {
+	if size < bannerSizeV1 {
+		return nil, fmt.Errorf("file too small for NDS banner: %d bytes (need %d)", size, bannerSizeV1)
+	}
+
+	versionBuf := make([]byte, 2)
+	if _, err := r.ReadAt(versionBuf, bannerVersionOffset); err != nil {
+		return nil, fmt.Errorf("failed to read NDS banner version: %w", err)
+	}
+	version := binary.LittleEndian.Uint16(versionBuf)
+
+	// Some homebrew leaves the version at zero; treat it as the original layout
+	bannerSize := int64(bannerSizeV1)
+	titleCount := 6
+	switch version {
+	case BannerVersionChinese:
+		bannerSize, titleCount = bannerSizeV2, 7
+	case BannerVersionKorean:
+		bannerSize, titleCount = bannerSizeV3, 8
+	case BannerVersionDSi:
+		bannerSize, titleCount = bannerSizeDSi, 8
+	}
+	if bannerSize > size {
+		return nil, fmt.Errorf("NDS banner version 0x%04X extends beyond file", version)
+	}
+
+	data := make([]byte, bannerSize)
+	if _, err := r.ReadAt(data, 0); err != nil {
+		return nil, fmt.Errorf("failed to read NDS banner: %w", err)
+	}
+
+	titles := make(map[Language]BannerTitle)
+	for i, lang := range bannerLanguages[:titleCount] {
+		off := bannerTitlesOffset + i*bannerTitleLen
+		title := parseBannerTitle(decodeUTF16LE(data[off : off+bannerTitleLen]))
+		if title != (BannerTitle{}) {
+			titles[lang] = title
+		}
+	}
+
+	banner := &Banner{
+		Version: version,
+		Titles:  titles,
+		Icon: decodeIcon(
+			data[bannerIconOffset:bannerIconOffset+iconBitmapSize],
+			data[bannerPaletteOffset:bannerPaletteOffset+iconPaletteSize],
+			false, false,
+		),
+	}
+
+	if version == BannerVersionDSi {
+		banner.AnimatedIcon = decodeAnimatedIcon(data)
+	}
+
+	return banner, nil
+}
+
+// parseBannerTitle splits a banner title into its title, subtitle and publisher lines.
+func parseBannerTitle(s string) BannerTitle {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	switch len(lines) {
+	case 0:
+		return BannerTitle{}
+	case 1:
+		return BannerTitle{Title: lines[0]}
+	case 2:
+		return BannerTitle{Title: lines[0], Publisher: lines[1]}
+	default:
+		return BannerTitle{
+			Title:     lines[0],
+			Subtitle:  strings.Join(lines[1:len(lines)-1], " "),
+			Publisher: lines[len(lines)-1],
+		}
+	}
+}
+
+// decodeAnimatedIcon decodes the DSi animation sequence into frames.
+func decodeAnimatedIcon(data []byte) []AnimationFrame {
+	var frames []AnimationFrame
+	for i := 0; i < bannerDSiSequenceLen; i++ {
+		entry := binary.LittleEndian.Uint16(data[bannerDSiSequenceOff+i*2:])
+		if entry == 0 {
+			break
+		}
+
+		flipV := entry&0x8000 != 0
+		flipH := entry&0x4000 != 0
+		paletteIndex := int(entry>>11) & 0x07
+		bitmapIndex := int(entry>>8) & 0x07
+		duration := time.Duration(entry&0xFF) * time.Second / 60
+
+		bitmapOff := bannerDSiBitmapOffset + bitmapIndex*iconBitmapSize
+		paletteOff := bannerDSiPaletteOff + paletteIndex*iconPaletteSize
+		frames = append(frames, AnimationFrame{
+			Image: decodeIcon(
+				data[bitmapOff:bitmapOff+iconBitmapSize],
+				data[paletteOff:paletteOff+iconPaletteSize],
+				flipH, flipV,
+			),
+			Duration: duration,
+		})
+	}
+	return frames
+}
+
+// decodeIcon decodes a 32x32 4bpp tiled icon with a BGR555 palette.
+// Palette index 0 is transparent.
+func decodeIcon(bitmap, palette []byte, flipH, flipV bool) *image.Paletted {
+	pal := make(color.Palette, 16)
+	pal[0] = color.NRGBA{}
+	for i := 1; i < 16; i++ {
+		c := binary.LittleEndian.Uint16(palette[i*2:])
+		r := byte(c) & 0x1F
+		g := byte(c>>5) & 0x1F
+		b := byte(c>>10) & 0x1F
+		pal[i] = color.NRGBA{R: r<<3 | r>>2, G: g<<3 | g>>2, B: b<<3 | b>>2, A: 0xFF}
+	}
+
+	img := image.NewPaletted(image.Rect(0, 0, iconDim, iconDim), pal)
+	for tile := 0; tile < 16; tile++ {
+		tileX := (tile % 4) * 8
+		tileY := (tile / 4) * 8
+		for p := 0; p < 64; p++ {
+			b := bitmap[tile*32+p/2]
+			index := b & 0x0F // Low nibble is the left pixel
+			if p%2 == 1 {
+				index = b >> 4
+			}
+			x := tileX + p%8
+			y := tileY + p/8
+			if flipH {
+				x = iconDim - 1 - x
+			}
+			if flipV {
+				y = iconDim - 1 - y
+			}
+			img.SetColorIndex(x, y, index)
+		}
+	}
+	return img
+}
+
+// decodeUTF16LE decodes a null-terminated UTF-16LE string.
+func decodeUTF16LE(data []byte) string {
+	u16s := make([]uint16, len(data)/2)
+	for i := range u16s {
+		u16s[i] = binary.LittleEndian.Uint16(data[i*2:])
+	}
+
+	for i, v := range u16s {
+		if v == 0 {
+			u16s = u16s[:i]
+			break
+		}
+	}
+
+	return string(utf16.Decode(u16s))
+}
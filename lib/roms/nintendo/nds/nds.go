@@ -3,6 +3,7 @@ package nds
 import (
 	"encoding/binary"
 	"fmt"
+	"image"
 	"io"
 
 	"github.com/sargunv/rom-tools/internal/util"
@@ -37,7 +38,8 @@ import (
 //	0x038   4     ARM7 RAM Address
 //	0x03C   4     ARM7 Size
 //	0x040-0x05F   File system info (FNT, FAT, overlays)
-//	0x060-0x06F   Port settings and icon offset
+//	0x060-0x067   Port settings
+//	0x068   4     Icon/Title (banner) Offset (0 = none, see banner.go)
 //	0x06C   2     Secure Area Checksum (CRC-16)
 //	0x06E   2     Secure Area Delay
 //	0x070-0x0BF   Auto load, secure area, ROM size info
@@ -63,6 +65,7 @@ const (
 	ndsDeviceCapacityOffset = 0x014
 	ndsRegionOffset         = 0x01D
 	ndsVersionOffset        = 0x01E
+	ndsBannerOffsetOffset   = 0x068
 	ndsHeaderChecksumOffset = 0x15E
 )
 
@@ -163,6 +166,8 @@ type Info struct {
 	// HeaderChecksum is the CRC-16 of header bytes 0x000-0x15D (0x15E).
 	// TODO: validate this checksum
 	HeaderChecksum uint16 `json:"header_checksum"`
+	// Banner contains the localized titles and icon (nil if the ROM has no banner).
+	Banner *Banner `json:"banner,omitempty"`
 	// platform is NDS or DSi based on unit code (internal, used by GamePlatform).
 	platform core.Platform
 }
@@ -171,7 +176,23 @@ type Info struct {
 func (i *Info) GamePlatform() core.Platform { return i.platform }
 
 // GameTitle implements core.GameInfo.
-func (i *Info) GameTitle() string { return i.Title }
+// Returns the banner title, falling back to the header title.
+func (i *Info) GameTitle() string {
+	if i.Banner != nil {
+		if title := i.Banner.Title(); title != "" {
+			return title
+		}
+	}
+	return i.Title
+}
+
+// GameIcon implements core.IconProvider.
+func (i *Info) GameIcon() image.Image {
+	if i.Banner == nil {
+		return nil
+	}
+	return i.Banner.Icon
+}
 
 // GameSerial implements core.GameInfo.
 func (i *Info) GameSerial() string { return i.GameCode }
@@ -280,6 +301,12 @@ func Parse(r io.ReaderAt, size int64) (*Info, error) {
 	// Extract header checksum (little-endian)
 	headerChecksum := binary.LittleEndian.Uint16(header[ndsHeaderChecksumOffset:])
 
+	// Parse banner; a missing or malformed banner just means no localized titles
+	var banner *Banner
+	if bannerOffset := int64(binary.LittleEndian.Uint32(header[ndsBannerOffsetOffset:])); bannerOffset >= ndsHeaderSize && bannerOffset < size {
+		banner, _ = ParseBanner(io.NewSectionReader(r, bannerOffset, size-bannerOffset), size-bannerOffset)
+	}
+
 	return &Info{
 		Title:          title,
 		GameCode:       gameCode,
@@ -293,6 +320,7 @@ func Parse(r io.ReaderAt, size int64) (*Info, error) {
 		Region:         region,
 		Version:        version,
 		HeaderChecksum: headerChecksum,
+		Banner:         banner,
 		platform:       platform,
 	}, nil
 }
@@ -2,8 +2,12 @@ package nds
 
 import (
 	"bytes"
+	"encoding/binary"
+	"image/color"
 	"os"
 	"testing"
+	"time"
+	"unicode/utf16"
 
 	"github.com/sargunv/rom-tools/lib/core"
 )
@@ -74,6 +78,21 @@ func TestParse(t *testing.T) {
 	if info.Version != 0 {
 		t.Errorf("Version = %d, want %d", info.Version, 0)
 	}
+
+	// Verify banner (three-line title in the Japanese slot only)
+	if info.Banner == nil {
+		t.Fatal("Banner = nil, want banner")
+	}
+	want := BannerTitle{Title: "ndslib example", Subtitle: "http://ndslib.sourceforge.net/", Publisher: "^_^"}
+	if got := info.Banner.Titles[LanguageJapanese]; got != want {
+		t.Errorf("Banner Japanese title = %+v, want %+v", got, want)
+	}
+	if info.GameTitle() != "ndslib example: http://ndslib.sourceforge.net/" {
+		t.Errorf("GameTitle() = %q, want banner title", info.GameTitle())
+	}
+	if info.GameIcon() == nil || info.GameIcon().Bounds().Dx() != 32 {
+		t.Errorf("GameIcon() = %v, want 32x32 icon", info.GameIcon())
+	}
 }
 
 func TestParse_TooSmall(t *testing.T) {
@@ -86,3 +105,55 @@ func TestParse_TooSmall(t *testing.T) {
 		t.Error("Parse() expected error for file too small, got nil")
 	}
 }
+
+func TestParseBanner_DSi(t *testing.T) {
+	data := make([]byte, bannerSizeDSi)
+	binary.LittleEndian.PutUint16(data[bannerVersionOffset:], BannerVersionDSi)
+
+	for i, v := range utf16.Encode([]rune("Game\nNintendo")) {
+		binary.LittleEndian.PutUint16(data[bannerTitlesOffset+bannerTitleLen*7+i*2:], v)
+	}
+
+	// Static icon: top-left pixel uses palette color 1 (pure red)
+	data[bannerIconOffset] = 0x01
+	binary.LittleEndian.PutUint16(data[bannerPaletteOffset+2:], 0x001F)
+
+	// Animated bitmap 1 with palette 2 (pure blue), top-left pixel set
+	data[bannerDSiBitmapOffset+iconBitmapSize] = 0x01
+	binary.LittleEndian.PutUint16(data[bannerDSiPaletteOff+2*iconPaletteSize+2:], 0x7C00)
+
+	// Sequence: bitmap 0 / palette 0 for 6 frames, then bitmap 1 / palette 2 flipped horizontally for 30
+	binary.LittleEndian.PutUint16(data[bannerDSiSequenceOff:], 0x0006)
+	binary.LittleEndian.PutUint16(data[bannerDSiSequenceOff+2:], 0x4000|2<<11|1<<8|30)
+
+	banner, err := ParseBanner(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ParseBanner() error = %v", err)
+	}
+
+	if banner.Title() != "Game" || banner.Publisher() != "Nintendo" {
+		t.Errorf("Title(), Publisher() = %q, %q, want %q, %q", banner.Title(), banner.Publisher(), "Game", "Nintendo")
+	}
+	if _, ok := banner.Titles[LanguageKorean]; !ok {
+		t.Error("Titles missing Korean entry")
+	}
+
+	red := color.NRGBAModel.Convert(banner.Icon.At(0, 0)).(color.NRGBA)
+	if red != (color.NRGBA{R: 0xFF, A: 0xFF}) {
+		t.Errorf("Icon.At(0, 0) = %v, want red", red)
+	}
+	if _, _, _, a := banner.Icon.At(1, 0).RGBA(); a != 0 {
+		t.Errorf("Icon.At(1, 0) alpha = %d, want transparent", a)
+	}
+
+	if len(banner.AnimatedIcon) != 2 {
+		t.Fatalf("len(AnimatedIcon) = %d, want 2", len(banner.AnimatedIcon))
+	}
+	if banner.AnimatedIcon[0].Duration != 100*time.Millisecond {
+		t.Errorf("Frame 0 Duration = %v, want 100ms", banner.AnimatedIcon[0].Duration)
+	}
+	blue := color.NRGBAModel.Convert(banner.AnimatedIcon[1].Image.At(31, 0)).(color.NRGBA)
+	if blue != (color.NRGBA{B: 0xFF, A: 0xFF}) {
+		t.Errorf("Frame 1 At(31, 0) = %v, want blue (flipped)", blue)
+	}
+}
@@ -0,0 +1,343 @@
+package nes
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sargunv/rom-tools/internal/util"
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// Famicom Disk System disk image parsing.
+//
+// FDS disk format specification:
+// https://www.nesdev.org/wiki/FDS_disk_format
+// https://www.nesdev.org/wiki/FDS_file_format
+//
+// .fds images hold one or more 65500-byte disk sides, optionally preceded by
+// a 16-byte fwNES header:
+//
+//	Offset  Size  Description
+//	0x00    4     Magic: "FDS" + 0x1A
+//	0x04    1     Number of disk sides
+//	0x05    11    Reserved (zero)
+//
+// .qd (Quick Disk) images hold 65536-byte sides and keep the 2-byte CRC that
+// follows each block on the physical disk. .fds images omit the CRCs.
+//
+// Each side is a sequence of blocks, each starting with a block code:
+//
+// Block 1, disk info (56 bytes):
+//
+//	Offset  Size  Description
+//	0x00    1     Block code (0x01)
+//	0x01    14    Verification "*NINTENDO-HVC*"
+//	0x0F    1     Manufacturer code (same as NES licensee codes)
+//	0x10    3     Game name code (ASCII)
+//	0x13    1     Game type (' ' = normal, 'E' = event, 'R' = reduction in price)
+//	0x14    1     Revision number
+//	0x15    1     Side number (0 = side A, 1 = side B)
+//	0x16    1     Disk number
+//	0x17    1     Disk type (0 = FMC normal card, 1 = FSC card with shutter)
+//	0x18    1     Unknown
+//	0x19    1     Boot read file code (file IDs up to this are loaded at boot)
+//	0x1A    5     Unknown (0xFF)
+//	0x1F    3     Manufacturing date (BCD year, month, day)
+//	0x22    1     Country code (0x49 = Japan)
+//	0x23    9     Unknown
+//	0x2C    3     Rewritten disk date (BCD year, month, day)
+//	0x2F    2     Unknown
+//	0x31    2     Disk Writer serial number
+//	0x33    1     Unknown
+//	0x34    1     Disk rewrite count (BCD)
+//	0x35    1     Actual disk side
+//	0x36    1     Unknown
+//	0x37    1     Price
+//
+// Block 2, file amount (2 bytes): block code (0x02), number of files.
+//
+// Block 3, file header (16 bytes):
+//
+//	Offset  Size  Description
+//	0x00    1     Block code (0x03)
+//	0x01    1     File number
+//	0x02    1     File ID (boot files have IDs <= boot read file code)
+//	0x03    8     File name (ASCII)
+//	0x0B    2     Load address
+//	0x0D    2     File size
+//	0x0F    1     File type (0 = PRG, 1 = CHR, 2 = VRAM nametable)
+//
+// Block 4, file data: block code (0x04) followed by file size bytes.
+//
+// BCD years use the Shōwa era (1925 + year) on most disks; later disks use the
+// Heisei era (1988 + year) and a few use Gregorian years (1900 + year).
+
+const (
+	fdsHeaderSize       = 16
+	fdsSideSize         = 65500
+	qdSideSize          = 65536
+	fdsDiskInfoSize     = 56
+	fdsFileAmountSize   = 2
+	fdsFileHeaderSize   = 16
+	qdBlockCRCSize      = 2
+	fdsMaxSides         = 32
+	fdsVerification     = "*NINTENDO-HVC*"
+	fdsBlockDiskInfo    = 0x01
+	fdsBlockFileAmount  = 0x02
+	fdsBlockFileHeader  = 0x03
+	fdsBlockFileData    = 0x04
+	fdsDiskTypeShutter  = 0x01
+	fdsSideNumberOffset = 0x15
+)
+
+// fdsMagic is the fwNES header magic: "FDS" + 0x1A
+var fdsMagic = []byte{0x46, 0x44, 0x53, 0x1A}
+
+// FDSFileType indicates where a disk file is loaded.
+type FDSFileType byte
+
+// FDSFileType values per nesdev wiki.
+const (
+	FDSFileTypePRG  FDSFileType = 0 // CPU memory
+	FDSFileTypeCHR  FDSFileType = 1 // Pattern tables
+	FDSFileTypeVRAM FDSFileType = 2 // Nametables
+)
+
+// FDSFile is an entry in a disk side's file table.
+type FDSFile struct {
+	// Number is the file number (position on the side).
+	Number byte `json:"number"`
+	// ID is the file identification code.
+	ID byte `json:"id"`
+	// Name is the 8-character file name.
+	Name string `json:"name"`
+	// Address is the load address.
+	Address uint16 `json:"address"`
+	// Size is the file size in bytes.
+	Size int `json:"size"`
+	// Type is the load destination.
+	Type FDSFileType `json:"type"`
+	// Boot indicates the file is loaded at boot (ID <= boot read file code).
+	Boot bool `json:"boot"`
+}
+
+// FDSSide contains the disk info block and file table of one disk side.
+type FDSSide struct {
+	// ManufacturerCode is the publisher code (same as NES licensee codes).
+	ManufacturerCode byte `json:"manufacturer_code"`
+	// GameName is the 3-character game name code (e.g., "ZEL").
+	GameName string `json:"game_name,omitempty"`
+	// GameType is ' ' for normal disks, 'E' for event and 'R' for reduced price.
+	GameType byte `json:"game_type"`
+	// Revision is the game revision number.
+	Revision int `json:"revision"`
+	// SideNumber is 0 for side A and 1 for side B.
+	SideNumber int `json:"side_number"`
+	// DiskNumber is the disk number for multi-disk games (0 = first disk).
+	DiskNumber int `json:"disk_number"`
+	// Shutter indicates an FSC (shutter) disk rather than an FMC (normal) disk.
+	Shutter bool `json:"shutter"`
+	// BootFileCode is the highest file ID loaded at boot.
+	BootFileCode byte `json:"boot_file_code"`
+	// ManufacturingDate is the manufacturing date. Zero if not set or invalid.
+	ManufacturingDate time.Time `json:"manufacturing_date,omitempty"`
+	// RewriteDate is the Disk Writer rewrite date. Zero if not set or invalid.
+	RewriteDate time.Time `json:"rewrite_date,omitempty"`
+	// RewriteCount is the number of Disk Writer rewrites.
+	RewriteCount int `json:"rewrite_count"`
+	// CountryCode is the country code (0x49 = Japan).
+	CountryCode byte `json:"country_code"`
+	// FileCount is the file count from the file amount block.
+	// Copy-protected disks may contain more files than declared.
+	FileCount int `json:"file_count"`
+	// Files is the file table.
+	Files []FDSFile `json:"files,omitempty"`
+}
+
+// FDSInfo contains metadata extracted from a Famicom Disk System image.
+type FDSInfo struct {
+	// HeaderSize is the size of the fwNES header (16), or 0 for headerless images.
+	// No-Intro hashes exclude the header.
+	HeaderSize int `json:"header_size"`
+	// QuickDisk indicates a .qd image (65536-byte sides with block CRCs).
+	QuickDisk bool `json:"quick_disk"`
+	// Sides contains the parsed disk sides in image order.
+	Sides []FDSSide `json:"sides"`
+}
+
+// GamePlatform implements core.GameInfo.
+func (i *FDSInfo) GamePlatform() core.Platform { return core.PlatformFDS }
+
+// GameTitle implements core.GameInfo.
+// FDS disks have no title, only a 3-character game name code.
+func (i *FDSInfo) GameTitle() string { return "" }
+
+// GameSerial implements core.GameInfo.
+// Returns the product code printed on the disk label (e.g., "FMC-ZEL").
+func (i *FDSInfo) GameSerial() string {
+	if len(i.Sides) == 0 || i.Sides[0].GameName == "" {
+		return ""
+	}
+	side := i.Sides[0]
+	if side.Shutter {
+		return "FSC-" + side.GameName
+	}
+	return "FMC-" + side.GameName
+}
+
+// GameRegions implements core.GameInfo.
+// The Famicom Disk System was only released in Japan.
+func (i *FDSInfo) GameRegions() []core.Region {
+	return []core.Region{core.RegionJapan}
+}
+
+// ParseFDS extracts game information from a Famicom Disk System image.
+// Supports fwNES-headered and headerless .fds images and .qd images.
+func ParseFDS(r io.ReaderAt, size int64) (*FDSInfo, error) {
+	if size < fdsHeaderSize {
+		return nil, fmt.Errorf("file too small for FDS image: %d bytes", size)
+	}
+
+	header := make([]byte, fdsHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read FDS header: %w", err)
+	}
+
+	info := &FDSInfo{}
+	dataOffset := int64(0)
+	if bytes.Equal(header[:4], fdsMagic) {
+		info.HeaderSize = fdsHeaderSize
+		dataOffset = fdsHeaderSize
+	}
+
+	// Detect the side size: .qd sides are 64 KiB, .fds sides are 65500 bytes
+	dataSize := size - dataOffset
+	sideSize := int64(fdsSideSize)
+	if info.HeaderSize == 0 && dataSize%qdSideSize == 0 && dataSize%fdsSideSize != 0 {
+		sideSize = qdSideSize
+		info.QuickDisk = true
+	}
+
+	sideCount := dataSize / sideSize
+	if sideCount == 0 {
+		// Some dumps are truncated after the last used block
+		sideCount = 1
+	}
+	if sideCount > fdsMaxSides {
+		return nil, fmt.Errorf("too many FDS disk sides: %d", sideCount)
+	}
+
+	for s := int64(0); s < sideCount; s++ {
+		sideOffset := dataOffset + s*sideSize
+		length := min(sideSize, size-sideOffset)
+		data := make([]byte, length)
+		if _, err := r.ReadAt(data, sideOffset); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read FDS side %d: %w", s, err)
+		}
+
+		side, err := parseFDSSide(data, info.QuickDisk)
+		if err != nil {
+			// Only the first side must be valid; trailing data is ignored
+			if s == 0 {
+				return nil, err
+			}
+			break
+		}
+		info.Sides = append(info.Sides, *side)
+	}
+
+	return info, nil
+}
+
+// parseFDSSide parses the disk info block and file table of one side.
+func parseFDSSide(data []byte, quickDisk bool) (*FDSSide, error) {
+	crcSize := 0
+	if quickDisk {
+		crcSize = qdBlockCRCSize
+	}
+
+	if len(data) < fdsDiskInfoSize || data[0] != fdsBlockDiskInfo {
+		return nil, fmt.Errorf("not a valid FDS disk side: missing disk info block")
+	}
+	if string(data[1:1+len(fdsVerification)]) != fdsVerification {
+		return nil, fmt.Errorf("not a valid FDS disk side: invalid verification %q", string(data[1:1+len(fdsVerification)]))
+	}
+
+	side := &FDSSide{
+		ManufacturerCode:  data[0x0F],
+		GameName:          util.ExtractASCII(data[0x10:0x13]),
+		GameType:          data[0x13],
+		Revision:          int(data[0x14]),
+		SideNumber:        int(data[fdsSideNumberOffset]),
+		DiskNumber:        int(data[0x16]),
+		Shutter:           data[0x17] == fdsDiskTypeShutter,
+		BootFileCode:      data[0x19],
+		ManufacturingDate: parseFDSDate(data[0x1F:0x22]),
+		CountryCode:       data[0x22],
+		RewriteDate:       parseFDSDate(data[0x2C:0x2F]),
+		RewriteCount:      bcdToInt(data[0x34]),
+	}
+
+	pos := fdsDiskInfoSize + crcSize
+	if pos+fdsFileAmountSize > len(data) || data[pos] != fdsBlockFileAmount {
+		// Disk info without a file table (e.g., truncated dump)
+		return side, nil
+	}
+	side.FileCount = int(data[pos+1])
+	pos += fdsFileAmountSize + crcSize
+
+	// Read file headers until the next block is not a file header.
+	// This also picks up hidden files beyond the declared count.
+	for pos+fdsFileHeaderSize <= len(data) && data[pos] == fdsBlockFileHeader {
+		h := data[pos : pos+fdsFileHeaderSize]
+		file := FDSFile{
+			Number:  h[0x01],
+			ID:      h[0x02],
+			Name:    util.ExtractASCII(h[0x03:0x0B]),
+			Address: binary.LittleEndian.Uint16(h[0x0B:]),
+			Size:    int(binary.LittleEndian.Uint16(h[0x0D:])),
+			Type:    FDSFileType(h[0x0F]),
+			Boot:    h[0x02] <= side.BootFileCode,
+		}
+		pos += fdsFileHeaderSize + crcSize
+
+		if pos >= len(data) || data[pos] != fdsBlockFileData {
+			break
+		}
+		side.Files = append(side.Files, file)
+		pos += 1 + file.Size + crcSize
+	}
+
+	return side, nil
+}
+
+// parseFDSDate converts a 3-byte BCD date (year, month, day) to a time.
+// Returns zero time if the date is unset or invalid.
+func parseFDSDate(b []byte) time.Time {
+	year, month, day := bcdToInt(b[0]), bcdToInt(b[1]), bcdToInt(b[2])
+	if year < 0 || month < 1 || month > 12 || day < 1 || day > 31 {
+		return time.Time{}
+	}
+
+	switch {
+	case year >= 80: // Gregorian (e.g., 86 = 1986)
+		year += 1900
+	case year >= 58: // Shōwa era (e.g., 61 = 1986)
+		year += 1925
+	default: // Heisei era (e.g., 2 = 1990)
+		year += 1988
+	}
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// bcdToInt converts a BCD byte to an integer. Returns -1 if the byte is not valid BCD.
+func bcdToInt(b byte) int {
+	hi, lo := b>>4, b&0x0F
+	if hi > 9 || lo > 9 {
+		return -1
+	}
+	return int(hi)*10 + int(lo)
+}
@@ -0,0 +1,153 @@
+package nes
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// makeFDSSide builds a disk side with a disk info block and the given files.
+// The file amount block declares one fewer file than present, like copy-protected disks.
+func makeFDSSide(sideSize int, crc bool, sideNumber byte, files []FDSFile) []byte {
+	crcSize := 0
+	if crc {
+		crcSize = qdBlockCRCSize
+	}
+
+	data := make([]byte, 0, sideSize)
+	info := make([]byte, fdsDiskInfoSize)
+	info[0] = fdsBlockDiskInfo
+	copy(info[1:], fdsVerification)
+	info[0x0F] = 0x01 // Nintendo
+	copy(info[0x10:], "ZEL")
+	info[0x13] = ' '
+	info[0x14] = 1
+	info[fdsSideNumberOffset] = sideNumber
+	info[0x19] = 0x0F
+	copy(info[0x1F:], []byte{0x61, 0x02, 0x21}) // Shōwa 61 = 1986-02-21
+	info[0x22] = 0x49
+	copy(info[0x2C:], []byte{0x02, 0x11, 0x30}) // Heisei 2 = 1990-11-30
+	info[0x34] = 0x12
+	data = append(data, info...)
+	data = append(data, make([]byte, crcSize)...)
+
+	data = append(data, fdsBlockFileAmount, byte(len(files)-1))
+	data = append(data, make([]byte, crcSize)...)
+
+	for _, f := range files {
+		h := make([]byte, fdsFileHeaderSize)
+		h[0] = fdsBlockFileHeader
+		h[1] = f.Number
+		h[2] = f.ID
+		copy(h[3:], f.Name)
+		binary.LittleEndian.PutUint16(h[0x0B:], f.Address)
+		binary.LittleEndian.PutUint16(h[0x0D:], uint16(f.Size))
+		h[0x0F] = byte(f.Type)
+		data = append(data, h...)
+		data = append(data, make([]byte, crcSize)...)
+		data = append(data, fdsBlockFileData)
+		data = append(data, make([]byte, f.Size+crcSize)...)
+	}
+
+	return append(data, make([]byte, sideSize-len(data))...)
+}
+
+var testFDSFiles = []FDSFile{
+	{Number: 0, ID: 0x00, Name: "KYODAKU-", Address: 0x2800, Size: 0xE0, Type: FDSFileTypeVRAM},
+	{Number: 1, ID: 0x0F, Name: "ZELDA", Address: 0x6000, Size: 0x100, Type: FDSFileTypePRG},
+	{Number: 2, ID: 0x20, Name: "HIDDEN", Address: 0x0000, Size: 0x10, Type: FDSFileTypeCHR},
+}
+
+func TestParseFDS_Headered(t *testing.T) {
+	header := make([]byte, fdsHeaderSize)
+	copy(header, fdsMagic)
+	header[4] = 2
+
+	data := append(header, makeFDSSide(fdsSideSize, false, 0, testFDSFiles)...)
+	data = append(data, makeFDSSide(fdsSideSize, false, 1, testFDSFiles[:2])...)
+
+	info, err := ParseFDS(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ParseFDS() error = %v", err)
+	}
+
+	if info.GamePlatform() != core.PlatformFDS {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformFDS)
+	}
+	if info.HeaderSize != fdsHeaderSize {
+		t.Errorf("HeaderSize = %d, want %d", info.HeaderSize, fdsHeaderSize)
+	}
+	if info.GameSerial() != "FMC-ZEL" {
+		t.Errorf("GameSerial() = %q, want %q", info.GameSerial(), "FMC-ZEL")
+	}
+	if len(info.Sides) != 2 {
+		t.Fatalf("len(Sides) = %d, want 2", len(info.Sides))
+	}
+
+	side := info.Sides[0]
+	if side.ManufacturerCode != 0x01 || side.Revision != 1 || side.SideNumber != 0 || side.RewriteCount != 12 {
+		t.Errorf("side 0 = %+v, want manufacturer 0x01, revision 1, side 0, rewrite count 12", side)
+	}
+	if want := time.Date(1986, 2, 21, 0, 0, 0, 0, time.UTC); !side.ManufacturingDate.Equal(want) {
+		t.Errorf("ManufacturingDate = %v, want %v", side.ManufacturingDate, want)
+	}
+	if want := time.Date(1990, 11, 30, 0, 0, 0, 0, time.UTC); !side.RewriteDate.Equal(want) {
+		t.Errorf("RewriteDate = %v, want %v", side.RewriteDate, want)
+	}
+
+	// Hidden files beyond the declared count are included
+	if side.FileCount != 2 || len(side.Files) != 3 {
+		t.Fatalf("FileCount = %d, len(Files) = %d, want 2 and 3", side.FileCount, len(side.Files))
+	}
+	if f := side.Files[1]; f.Name != "ZELDA" || f.Address != 0x6000 || f.Size != 0x100 || !f.Boot {
+		t.Errorf("Files[1] = %+v, want boot file ZELDA at $6000", f)
+	}
+	if side.Files[2].Boot {
+		t.Error("Files[2].Boot = true, want false")
+	}
+	if info.Sides[1].SideNumber != 1 {
+		t.Errorf("Sides[1].SideNumber = %d, want 1", info.Sides[1].SideNumber)
+	}
+}
+
+func TestParseFDS_Headerless(t *testing.T) {
+	data := makeFDSSide(fdsSideSize, false, 0, testFDSFiles)
+
+	info, err := ParseFDS(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ParseFDS() error = %v", err)
+	}
+	if info.HeaderSize != 0 || info.QuickDisk {
+		t.Errorf("HeaderSize = %d, QuickDisk = %v, want 0 and false", info.HeaderSize, info.QuickDisk)
+	}
+	if len(info.Sides) != 1 || len(info.Sides[0].Files) != 3 {
+		t.Errorf("Sides = %+v, want 1 side with 3 files", info.Sides)
+	}
+}
+
+func TestParseFDS_QuickDisk(t *testing.T) {
+	data := makeFDSSide(qdSideSize, true, 0, testFDSFiles)
+
+	info, err := ParseFDS(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ParseFDS() error = %v", err)
+	}
+	if !info.QuickDisk {
+		t.Error("QuickDisk = false, want true")
+	}
+	if len(info.Sides) != 1 || len(info.Sides[0].Files) != 3 {
+		t.Errorf("Sides = %+v, want 1 side with 3 files", info.Sides)
+	}
+}
+
+func TestParseFDS_Invalid(t *testing.T) {
+	data := make([]byte, fdsSideSize)
+
+	_, err := ParseFDS(bytes.NewReader(data), int64(len(data)))
+	if err == nil {
+		t.Error("ParseFDS() expected error for blank disk, got nil")
+	}
+}
@@ -0,0 +1,548 @@
+package nsw
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// Nintendo Switch NSP and XCI container parsing.
+//
+// NSP files are PFS0 partitions containing NCAs, tickets and certificates.
+// XCI files are gamecard images with a card header and a root HFS0 partition
+// whose entries are themselves HFS0 partitions (update, normal, secure, logo).
+// https://switchbrew.org/wiki/XCI
+//
+// NCA contents are encrypted with console keys, so this package only reads
+// data that is available without them: partition file tables, the XCI card
+// header, tickets, and unencrypted content metadata (.cnmt and .cnmt.xml).
+//
+// XCI card header layout (at 0x100, little-endian):
+//
+//	Offset  Size  Description
+//	0x100   4     Magic ("HEAD")
+//	0x104   4     Secure area start (media units)
+//	0x108   4     Backup area start (media units)
+//	0x10C   1     Title key decryption index / KEK index
+//	0x10D   1     Cart size
+//	0x10E   1     Header version
+//	0x10F   1     Flags
+//	0x110   8     Package ID
+//	0x118   4     Valid data end address (media units)
+//	0x130   8     Root HFS0 partition offset
+//	0x138   8     Root HFS0 header size
+//
+// Some dumps include a 0x1000-byte key area before the card header.
+//
+// Ticket layout (after signature, relevant fields):
+//
+//	Offset  Size  Description
+//	0x000   64    Issuer
+//	0x160   16    Rights ID (title ID + key generation)
+//
+// Packaged content meta (.cnmt) header (little-endian):
+//
+//	Offset  Size  Description
+//	0x00    8     Title ID
+//	0x08    4     Version
+//	0x0C    1     Content meta type
+
+const (
+	xciMagicOffset      = 0x100
+	xciMagic            = "HEAD"
+	xciKeyAreaSize      = 0x1000
+	xciHeaderSize       = 0x200
+	xciSecureAreaOff    = 0x104
+	xciCartSizeOff      = 0x10D
+	xciHeaderVersionOff = 0x10E
+	xciFlagsOff         = 0x10F
+	xciPackageIDOff     = 0x110
+	xciValidDataEndOff  = 0x118
+	xciRootHFS0Off      = 0x130
+
+	mediaUnitSize = 0x200
+
+	ticketRightsIDOff = 0x160
+	ticketMaxSize     = 0x1000
+
+	cnmtHeaderSize = 0x20
+	cnmtMaxXMLSize = 0x100000
+)
+
+// Format identifies the container format.
+type Format string
+
+const (
+	FormatNSP Format = "nsp"
+	FormatXCI Format = "xci"
+)
+
+// CartSize is the gamecard capacity code from the XCI header.
+type CartSize byte
+
+// CartSize values per switchbrew.
+const (
+	CartSize1GB  CartSize = 0xFA
+	CartSize2GB  CartSize = 0xF8
+	CartSize4GB  CartSize = 0xF0
+	CartSize8GB  CartSize = 0xE0
+	CartSize16GB CartSize = 0xE1
+	CartSize32GB CartSize = 0xE2
+)
+
+// Bytes returns the capacity in bytes, or 0 for unknown codes.
+func (c CartSize) Bytes() int64 {
+	const gb = 1024 * 1024 * 1024
+	switch c {
+	case CartSize1GB:
+		return 1 * gb
+	case CartSize2GB:
+		return 2 * gb
+	case CartSize4GB:
+		return 4 * gb
+	case CartSize8GB:
+		return 8 * gb
+	case CartSize16GB:
+		return 16 * gb
+	case CartSize32GB:
+		return 32 * gb
+	default:
+		return 0
+	}
+}
+
+// ContentMetaType is the type of a title's content metadata.
+type ContentMetaType byte
+
+// ContentMetaType values per switchbrew.
+const (
+	ContentMetaSystemProgram ContentMetaType = 0x01
+	ContentMetaApplication   ContentMetaType = 0x80
+	ContentMetaPatch         ContentMetaType = 0x81
+	ContentMetaAddOnContent  ContentMetaType = 0x82
+	ContentMetaDelta         ContentMetaType = 0x83
+	ContentMetaDataPatch     ContentMetaType = 0x84
+)
+
+// contentMetaTypeNames maps .cnmt.xml type names to ContentMetaType values.
+var contentMetaTypeNames = map[string]ContentMetaType{
+	"SystemProgram": ContentMetaSystemProgram,
+	"Application":   ContentMetaApplication,
+	"Patch":         ContentMetaPatch,
+	"AddOnContent":  ContentMetaAddOnContent,
+	"Delta":         ContentMetaDelta,
+	"DataPatch":     ContentMetaDataPatch,
+}
+
+// CardHeader contains the XCI gamecard header fields.
+type CardHeader struct {
+	// SecureAreaStart is the byte offset of the secure area.
+	SecureAreaStart int64 `json:"secure_area_start"`
+	// CartSize is the gamecard capacity code.
+	CartSize CartSize `json:"cart_size"`
+	// CartSizeBytes is the gamecard capacity in bytes.
+	CartSizeBytes int64 `json:"cart_size_bytes"`
+	// HeaderVersion is the card header version.
+	HeaderVersion byte `json:"header_version"`
+	// Flags is the card header flags byte.
+	Flags byte `json:"flags"`
+	// PackageID is the gamecard package ID.
+	PackageID uint64 `json:"package_id"`
+	// ValidDataEnd is the size in bytes of the used data area (the trimmed image size).
+	ValidDataEnd int64 `json:"valid_data_end"`
+}
+
+// Ticket is a title key ticket found in the container.
+type Ticket struct {
+	// Name is the ticket file name.
+	Name string `json:"name"`
+	// RightsID is the 32-character hex rights ID.
+	RightsID string `json:"rights_id"`
+	// TitleID is the 16-character hex title ID from the rights ID.
+	TitleID string `json:"title_id"`
+	// KeyGeneration is the master key generation from the rights ID.
+	KeyGeneration byte `json:"key_generation"`
+}
+
+// Title is a title ID found in the container, with its version when known.
+type Title struct {
+	// ID is the 64-bit title ID.
+	ID uint64 `json:"id"`
+	// IDHex is the title ID as a 16-character hex string.
+	IDHex string `json:"id_hex"`
+	// Type is the content meta type.
+	Type ContentMetaType `json:"type"`
+	// Version is the title version, if known from content metadata.
+	Version uint32 `json:"version"`
+	// HasVersion indicates whether Version is known, from content metadata or the file name.
+	HasVersion bool `json:"has_version"`
+}
+
+// ApplicationID returns the base application title ID for this title.
+// Patches and add-on content are mapped back to the application they belong to.
+func (t Title) ApplicationID() uint64 {
+	switch t.Type {
+	case ContentMetaPatch:
+		return t.ID &^ 0x800
+	case ContentMetaAddOnContent:
+		return (t.ID - 0x1000) &^ 0xFFF
+	default:
+		return t.ID
+	}
+}
+
+// Info contains metadata extracted from a Switch NSP or XCI file.
+type Info struct {
+	// Format is the container format.
+	Format Format `json:"format"`
+	// Card is the gamecard header (XCI only).
+	Card *CardHeader `json:"card,omitempty"`
+	// Files lists all files in the container.
+	Files []Entry `json:"files,omitempty"`
+	// NCAs lists the NCA (or compressed NCZ) content archives.
+	NCAs []Entry `json:"ncas,omitempty"`
+	// Tickets lists the tickets in the container.
+	Tickets []Ticket `json:"tickets,omitempty"`
+	// Titles lists the title IDs found in tickets and content metadata.
+	Titles []Title `json:"titles,omitempty"`
+}
+
+// GamePlatform implements core.GameInfo.
+func (i *Info) GamePlatform() core.Platform { return core.PlatformSwitch }
+
+// GameTitle implements core.GameInfo.
+// Titles are stored in the encrypted control NCA and are not available without keys.
+func (i *Info) GameTitle() string { return "" }
+
+// GameSerial implements core.GameInfo. Returns the base application title ID.
+func (i *Info) GameSerial() string {
+	if id, ok := i.ApplicationID(); ok {
+		return fmt.Sprintf("%016X", id)
+	}
+	return ""
+}
+
+// GameRegions implements core.GameInfo.
+// Switch titles are region-free.
+func (i *Info) GameRegions() []core.Region { return []core.Region{} }
+
+// ApplicationID returns the base application title ID of the container's content.
+// Application titles take priority over patches and add-on content.
+func (i *Info) ApplicationID() (uint64, bool) {
+	for _, t := range i.Titles {
+		if t.Type == ContentMetaApplication {
+			return t.ID, true
+		}
+	}
+	if len(i.Titles) > 0 {
+		return i.Titles[0].ApplicationID(), true
+	}
+	return 0, false
+}
+
+// Parse extracts metadata from a Switch NSP or XCI file.
+func Parse(r io.ReaderAt, size int64) (*Info, error) {
+	if size < 4 {
+		return nil, fmt.Errorf("file too small for NSP or XCI header: %d bytes", size)
+	}
+
+	magic := make([]byte, 4)
+	if _, err := r.ReadAt(magic, 0); err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	if string(magic) == "PFS0" {
+		return ParseNSP(r, size)
+	}
+	return ParseXCI(r, size)
+}
+
+// ParseNSP extracts metadata from a Switch NSP (PFS0) file.
+func ParseNSP(r io.ReaderAt, size int64) (*Info, error) {
+	entries, err := readPartition(r, 0, size, "PFS0")
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{Format: FormatNSP, Files: entries}
+	info.addContents(r, size, entries)
+	return info, nil
+}
+
+// ParseXCI extracts metadata from a Switch XCI gamecard image.
+func ParseXCI(r io.ReaderAt, size int64) (*Info, error) {
+	base, err := findCardHeader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, xciHeaderSize)
+	if _, err := r.ReadAt(header, base); err != nil {
+		return nil, fmt.Errorf("failed to read XCI header: %w", err)
+	}
+
+	cartSize := CartSize(header[xciCartSizeOff])
+	card := &CardHeader{
+		SecureAreaStart: int64(binary.LittleEndian.Uint32(header[xciSecureAreaOff:])) * mediaUnitSize,
+		CartSize:        cartSize,
+		CartSizeBytes:   cartSize.Bytes(),
+		HeaderVersion:   header[xciHeaderVersionOff],
+		Flags:           header[xciFlagsOff],
+		PackageID:       binary.LittleEndian.Uint64(header[xciPackageIDOff:]),
+		ValidDataEnd:    (int64(binary.LittleEndian.Uint32(header[xciValidDataEndOff:])) + 1) * mediaUnitSize,
+	}
+
+	rootOffset := base + int64(binary.LittleEndian.Uint64(header[xciRootHFS0Off:]))
+	partitions, err := readPartition(r, rootOffset, size, "HFS0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read root partition: %w", err)
+	}
+
+	info := &Info{Format: FormatXCI, Card: card}
+	for _, part := range partitions {
+		entries, err := readPartition(r, part.Offset, size, "HFS0")
+		if err != nil {
+			// Trimmed or scene dumps may omit partitions; skip what we can't read
+			continue
+		}
+		for i := range entries {
+			entries[i].Partition = part.Name
+		}
+		info.Files = append(info.Files, entries...)
+
+		// Only the secure partition holds the game; update holds system firmware
+		if part.Name == "secure" {
+			info.addContents(r, size, entries)
+		}
+	}
+
+	return info, nil
+}
+
+// File name tags used by dump tools, e.g. "Game [0100000000010000][v196608].nsp".
+var (
+	fileNameTitleIDPattern = regexp.MustCompile(`\[(01[0-9a-fA-F]{14})\]`)
+	fileNameVersionPattern = regexp.MustCompile(`\[v(\d+)\]`)
+)
+
+// ParseFileName reads the title ID and version from the standard
+// [0100XXXXXXXXXXXX][vNNN] tags in an NSP or XCI file name.
+// Returns false if the name has no title ID tag.
+func ParseFileName(name string) (Title, bool) {
+	m := fileNameTitleIDPattern.FindStringSubmatch(name)
+	if m == nil {
+		return Title{}, false
+	}
+	t := Title{ID: titleIDFromHex(m[1])}
+	if v := fileNameVersionPattern.FindStringSubmatch(name); v != nil {
+		if version, err := strconv.ParseUint(v[1], 10, 32); err == nil {
+			t.Version, t.HasVersion = uint32(version), true
+		}
+	}
+	return t, t.ID != 0
+}
+
+// ApplyFileName fills in the title ID and version from the file name tags
+// when the container doesn't provide them. Most dumps carry only an encrypted
+// content meta NCA, so the file name is often the only source.
+func (i *Info) ApplyFileName(name string) {
+	t, ok := ParseFileName(name)
+	if !ok {
+		return
+	}
+	if len(i.Titles) == 0 {
+		i.addTitle(t)
+		return
+	}
+	if !t.HasVersion {
+		return
+	}
+	for idx, existing := range i.Titles {
+		if existing.ID == t.ID && !existing.HasVersion {
+			i.Titles[idx].Version, i.Titles[idx].HasVersion = t.Version, true
+		}
+	}
+}
+
+// findCardHeader returns the offset of the XCI image start, accounting for an optional key area.
+func findCardHeader(r io.ReaderAt, size int64) (int64, error) {
+	magic := make([]byte, 4)
+	for _, base := range []int64{0, xciKeyAreaSize} {
+		if base+xciHeaderSize > size {
+			continue
+		}
+		if _, err := r.ReadAt(magic, base+xciMagicOffset); err != nil {
+			continue
+		}
+		if string(magic) == xciMagic {
+			return base, nil
+		}
+	}
+	return 0, fmt.Errorf("not a valid XCI or NSP: no PFS0 or HEAD magic found")
+}
+
+// addContents classifies partition entries as NCAs, tickets and content metadata.
+func (i *Info) addContents(r io.ReaderAt, size int64, entries []Entry) {
+	for _, e := range entries {
+		name := strings.ToLower(e.Name)
+		switch {
+		case strings.HasSuffix(name, ".nca"), strings.HasSuffix(name, ".ncz"):
+			i.NCAs = append(i.NCAs, e)
+		case strings.HasSuffix(name, ".tik"):
+			if t, ok := parseTicket(r, size, e); ok {
+				i.Tickets = append(i.Tickets, t)
+				i.addTitle(Title{ID: titleIDFromHex(t.TitleID)})
+			}
+		case strings.HasSuffix(name, ".cnmt.xml"):
+			if t, ok := parseCNMTXML(r, size, e); ok {
+				i.addTitle(t)
+			}
+		case strings.HasSuffix(name, ".cnmt"):
+			if t, ok := parseCNMT(r, size, e); ok {
+				i.addTitle(t)
+			}
+		}
+	}
+}
+
+// addTitle adds a title, merging with an existing entry for the same ID.
+// Content metadata versions take priority over ticket-derived entries.
+func (i *Info) addTitle(t Title) {
+	if t.ID == 0 {
+		return
+	}
+	t.IDHex = fmt.Sprintf("%016X", t.ID)
+	if t.Type == 0 {
+		t.Type = guessContentMetaType(t.ID)
+	}
+	for idx, existing := range i.Titles {
+		if existing.ID == t.ID {
+			if t.HasVersion && !existing.HasVersion {
+				i.Titles[idx] = t
+			}
+			return
+		}
+	}
+	i.Titles = append(i.Titles, t)
+}
+
+// guessContentMetaType infers the content meta type from a title ID's low bits.
+// Applications end in 000, patches in 800, and add-on content in anything else.
+func guessContentMetaType(id uint64) ContentMetaType {
+	switch id & 0xFFF {
+	case 0x000:
+		return ContentMetaApplication
+	case 0x800:
+		return ContentMetaPatch
+	default:
+		return ContentMetaAddOnContent
+	}
+}
+
+var rightsIDPattern = regexp.MustCompile(`^([0-9a-fA-F]{32})\.tik$`)
+
+// parseTicket reads the rights ID from a ticket, falling back to its file name.
+func parseTicket(r io.ReaderAt, size int64, e Entry) (Ticket, bool) {
+	rightsID := ""
+	if m := rightsIDPattern.FindStringSubmatch(e.Name); m != nil {
+		rightsID = strings.ToUpper(m[1])
+	}
+
+	if e.Size > 0 && e.Size <= ticketMaxSize && e.Offset+e.Size <= size {
+		data := make([]byte, e.Size)
+		if _, err := r.ReadAt(data, e.Offset); err == nil {
+			if start, ok := ticketDataOffset(binary.LittleEndian.Uint32(data)); ok && start+ticketRightsIDOff+16 <= len(data) {
+				rightsID = strings.ToUpper(hex.EncodeToString(data[start+ticketRightsIDOff : start+ticketRightsIDOff+16]))
+			}
+		}
+	}
+
+	if rightsID == "" {
+		return Ticket{}, false
+	}
+
+	keyGen, _ := strconv.ParseUint(rightsID[30:], 16, 8)
+	return Ticket{
+		Name:          e.Name,
+		RightsID:      rightsID,
+		TitleID:       rightsID[:16],
+		KeyGeneration: byte(keyGen),
+	}, true
+}
+
+// ticketDataOffset returns the offset of the ticket data following the signature.
+// Signature types are stored little-endian in tickets.
+func ticketDataOffset(sigType uint32) (int, bool) {
+	switch sigType {
+	case 0x010000, 0x010003: // RSA-4096
+		return 4 + 0x200 + 0x3C, true
+	case 0x010001, 0x010004: // RSA-2048
+		return 4 + 0x100 + 0x3C, true
+	case 0x010002, 0x010005: // ECDSA
+		return 4 + 0x3C + 0x40, true
+	default:
+		return 0, false
+	}
+}
+
+// parseCNMT reads a decrypted packaged content meta file.
+func parseCNMT(r io.ReaderAt, size int64, e Entry) (Title, bool) {
+	if e.Size < cnmtHeaderSize || e.Offset+cnmtHeaderSize > size {
+		return Title{}, false
+	}
+	data := make([]byte, cnmtHeaderSize)
+	if _, err := r.ReadAt(data, e.Offset); err != nil {
+		return Title{}, false
+	}
+	return Title{
+		ID:         binary.LittleEndian.Uint64(data[0x00:]),
+		Version:    binary.LittleEndian.Uint32(data[0x08:]),
+		Type:       ContentMetaType(data[0x0C]),
+		HasVersion: true,
+	}, true
+}
+
+// cnmtXML is the subset of the .cnmt.xml format written by authoring tools.
+type cnmtXML struct {
+	Type    string `xml:"Type"`
+	ID      string `xml:"Id"`
+	Version uint32 `xml:"Version"`
+}
+
+// parseCNMTXML reads a .cnmt.xml content meta description.
+func parseCNMTXML(r io.ReaderAt, size int64, e Entry) (Title, bool) {
+	if e.Size <= 0 || e.Size > cnmtMaxXMLSize || e.Offset+e.Size > size {
+		return Title{}, false
+	}
+	var meta cnmtXML
+	if err := xml.NewDecoder(io.NewSectionReader(r, e.Offset, e.Size)).Decode(&meta); err != nil {
+		return Title{}, false
+	}
+	id := titleIDFromHex(meta.ID)
+	if id == 0 {
+		return Title{}, false
+	}
+	return Title{
+		ID:         id,
+		Version:    meta.Version,
+		Type:       contentMetaTypeNames[meta.Type],
+		HasVersion: true,
+	}, true
+}
+
+// titleIDFromHex parses a hex title ID, with or without a 0x prefix.
+// Returns 0 if the string is not a valid title ID.
+func titleIDFromHex(s string) uint64 {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "0x"), "0X")
+	id, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
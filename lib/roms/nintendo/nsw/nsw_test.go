@@ -0,0 +1,261 @@
+package nsw
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+type testFile struct {
+	name string
+	data []byte
+}
+
+// makePartition builds a PFS0 or HFS0 partition containing the given files.
+func makePartition(magic string, files []testFile) []byte {
+	entrySize := pfs0EntrySize
+	if magic == "HFS0" {
+		entrySize = hfs0EntrySize
+	}
+
+	var stringTable []byte
+	nameOffsets := make([]int, len(files))
+	for i, f := range files {
+		nameOffsets[i] = len(stringTable)
+		stringTable = append(stringTable, f.name...)
+		stringTable = append(stringTable, 0)
+	}
+
+	header := make([]byte, pfsHeaderSize+len(files)*entrySize)
+	copy(header, magic)
+	binary.LittleEndian.PutUint32(header[0x04:], uint32(len(files)))
+	binary.LittleEndian.PutUint32(header[0x08:], uint32(len(stringTable)))
+
+	var data []byte
+	for i, f := range files {
+		e := header[pfsHeaderSize+i*entrySize:]
+		binary.LittleEndian.PutUint64(e[0x00:], uint64(len(data)))
+		binary.LittleEndian.PutUint64(e[0x08:], uint64(len(f.data)))
+		binary.LittleEndian.PutUint32(e[0x10:], uint32(nameOffsets[i]))
+		data = append(data, f.data...)
+	}
+
+	out := append(header, stringTable...)
+	return append(out, data...)
+}
+
+// makeTicket builds an RSA-2048 signed ticket with the given rights ID.
+func makeTicket(rightsID string) []byte {
+	start, _ := ticketDataOffset(0x010004)
+	data := make([]byte, start+0x180)
+	binary.LittleEndian.PutUint32(data, 0x010004)
+	id, _ := hex.DecodeString(rightsID)
+	copy(data[start+ticketRightsIDOff:], id)
+	return data
+}
+
+func TestParseNSP(t *testing.T) {
+	cnmt := `<?xml version="1.0" encoding="utf-8"?>
+<ContentMeta>
+  <Type>Patch</Type>
+  <Id>0x0100000000010800</Id>
+  <Version>196608</Version>
+</ContentMeta>`
+
+	nsp := makePartition("PFS0", []testFile{
+		{"0123456789abcdef0123456789abcdef.nca", make([]byte, 64)},
+		{"fedcba9876543210fedcba9876543210.cnmt.nca", make([]byte, 32)},
+		{"fedcba9876543210fedcba9876543210.cnmt.xml", []byte(cnmt)},
+		{"01000000000108000000000000000005.tik", makeTicket("01000000000108000000000000000005")},
+		{"01000000000108000000000000000005.cert", make([]byte, 16)},
+	})
+
+	info, err := Parse(bytes.NewReader(nsp), int64(len(nsp)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.Format != FormatNSP {
+		t.Errorf("Format = %q, want %q", info.Format, FormatNSP)
+	}
+	if info.GamePlatform() != core.PlatformSwitch {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformSwitch)
+	}
+	if len(info.Files) != 5 {
+		t.Errorf("len(Files) = %d, want 5", len(info.Files))
+	}
+	if len(info.NCAs) != 2 {
+		t.Errorf("len(NCAs) = %d, want 2", len(info.NCAs))
+	}
+	if len(info.Tickets) != 1 {
+		t.Fatalf("len(Tickets) = %d, want 1", len(info.Tickets))
+	}
+	if info.Tickets[0].TitleID != "0100000000010800" {
+		t.Errorf("Ticket TitleID = %q, want %q", info.Tickets[0].TitleID, "0100000000010800")
+	}
+	if info.Tickets[0].KeyGeneration != 5 {
+		t.Errorf("Ticket KeyGeneration = %d, want 5", info.Tickets[0].KeyGeneration)
+	}
+
+	// The ticket and cnmt.xml describe the same patch title; the versioned entry wins
+	if len(info.Titles) != 1 {
+		t.Fatalf("len(Titles) = %d, want 1", len(info.Titles))
+	}
+	title := info.Titles[0]
+	if title.Type != ContentMetaPatch {
+		t.Errorf("Title Type = 0x%02X, want 0x%02X", title.Type, ContentMetaPatch)
+	}
+	if !title.HasVersion || title.Version != 196608 {
+		t.Errorf("Title Version = %d (has %v), want 196608", title.Version, title.HasVersion)
+	}
+
+	// Patches map back to their base application
+	if info.GameSerial() != "0100000000010000" {
+		t.Errorf("GameSerial() = %q, want %q", info.GameSerial(), "0100000000010000")
+	}
+
+	// Entry offsets must point at the file data
+	buf := make([]byte, 5)
+	if _, err := bytes.NewReader(nsp).ReadAt(buf, info.Files[2].Offset); err != nil {
+		t.Fatalf("ReadAt() error = %v", err)
+	}
+	if string(buf) != "<?xml" {
+		t.Errorf("Files[2] data = %q, want %q", buf, "<?xml")
+	}
+}
+
+func TestParseNSP_RawCNMT(t *testing.T) {
+	cnmt := make([]byte, cnmtHeaderSize)
+	binary.LittleEndian.PutUint64(cnmt[0x00:], 0x0100000000020000)
+	binary.LittleEndian.PutUint32(cnmt[0x08:], 0)
+	cnmt[0x0C] = byte(ContentMetaApplication)
+
+	nsp := makePartition("PFS0", []testFile{
+		{"Application_0100000000020000.cnmt", cnmt},
+	})
+
+	info, err := Parse(bytes.NewReader(nsp), int64(len(nsp)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if info.GameSerial() != "0100000000020000" {
+		t.Errorf("GameSerial() = %q, want %q", info.GameSerial(), "0100000000020000")
+	}
+}
+
+func TestParseXCI(t *testing.T) {
+	secure := makePartition("HFS0", []testFile{
+		{"0123456789abcdef0123456789abcdef.nca", make([]byte, 64)},
+	})
+	update := makePartition("HFS0", []testFile{
+		{"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa.nca", make([]byte, 16)},
+	})
+	root := makePartition("HFS0", []testFile{
+		{"update", update},
+		{"secure", secure},
+	})
+
+	const rootOffset = 0xF000
+	xci := make([]byte, rootOffset+len(root))
+	copy(xci[xciMagicOffset:], xciMagic)
+	binary.LittleEndian.PutUint32(xci[xciSecureAreaOff:], 0x80)
+	xci[xciCartSizeOff] = byte(CartSize4GB)
+	binary.LittleEndian.PutUint64(xci[xciPackageIDOff:], 0x1122334455667788)
+	binary.LittleEndian.PutUint32(xci[xciValidDataEndOff:], 0xFF)
+	binary.LittleEndian.PutUint64(xci[xciRootHFS0Off:], rootOffset)
+	copy(xci[rootOffset:], root)
+
+	info, err := Parse(bytes.NewReader(xci), int64(len(xci)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.Format != FormatXCI {
+		t.Errorf("Format = %q, want %q", info.Format, FormatXCI)
+	}
+	if info.Card == nil {
+		t.Fatal("Card = nil, want card header")
+	}
+	if info.Card.CartSizeBytes != 4*1024*1024*1024 {
+		t.Errorf("CartSizeBytes = %d, want 4 GiB", info.Card.CartSizeBytes)
+	}
+	if info.Card.PackageID != 0x1122334455667788 {
+		t.Errorf("PackageID = 0x%X, want 0x1122334455667788", info.Card.PackageID)
+	}
+	if info.Card.SecureAreaStart != 0x80*mediaUnitSize {
+		t.Errorf("SecureAreaStart = 0x%X, want 0x%X", info.Card.SecureAreaStart, 0x80*mediaUnitSize)
+	}
+	if info.Card.ValidDataEnd != 0x100*mediaUnitSize {
+		t.Errorf("ValidDataEnd = 0x%X, want 0x%X", info.Card.ValidDataEnd, 0x100*mediaUnitSize)
+	}
+	if len(info.Files) != 2 {
+		t.Errorf("len(Files) = %d, want 2", len(info.Files))
+	}
+	// Only secure partition NCAs belong to the game
+	if len(info.NCAs) != 1 || info.NCAs[0].Partition != "secure" {
+		t.Errorf("NCAs = %+v, want one NCA from secure partition", info.NCAs)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	data := make([]byte, 0x400)
+
+	_, err := Parse(bytes.NewReader(data), int64(len(data)))
+	if err == nil {
+		t.Error("Parse() expected error for invalid file, got nil")
+	}
+}
+
+func TestParseFileName(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   Title
+		wantOK bool
+	}{
+		{"Game [0100000000010000][v0].nsp", Title{ID: 0x0100000000010000, HasVersion: true}, true},
+		{"Game (USA) [0100000000010800][v196608] (1G+1U).xci", Title{ID: 0x0100000000010800, Version: 196608, HasVersion: true}, true},
+		{"Game [0100ABCDEF012000].nsp", Title{ID: 0x0100ABCDEF012000}, true},
+		{"Game [v65536].nsp", Title{}, false},
+		{"Game.nsp", Title{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseFileName(tt.name)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("ParseFileName(%q) = %+v, %v, want %+v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestApplyFileName(t *testing.T) {
+	// Only an encrypted content meta NCA, no ticket: the file name is the only source
+	nsp := makePartition("PFS0", []testFile{
+		{"fedcba9876543210fedcba9876543210.cnmt.nca", make([]byte, 32)},
+	})
+	info, err := ParseNSP(bytes.NewReader(nsp), int64(len(nsp)))
+	if err != nil {
+		t.Fatalf("ParseNSP() error = %v", err)
+	}
+	if info.GameSerial() != "" {
+		t.Fatalf("GameSerial() = %q before ApplyFileName, want empty", info.GameSerial())
+	}
+
+	info.ApplyFileName("Game [0100000000010800][v196608].nsp")
+	if info.GameSerial() != "0100000000010000" {
+		t.Errorf("GameSerial() = %q, want %q", info.GameSerial(), "0100000000010000")
+	}
+	if len(info.Titles) != 1 || info.Titles[0].Type != ContentMetaPatch || info.Titles[0].Version != 196608 {
+		t.Errorf("Titles = %+v, want patch v196608", info.Titles)
+	}
+
+	// A ticket-derived title keeps its ID and gains the version
+	info = &Info{}
+	info.addTitle(Title{ID: 0x0100000000010000})
+	info.ApplyFileName("Game [0100000000010000][v0].nsp")
+	info.ApplyFileName("Other [0100000000020000][v65536].nsp")
+	if len(info.Titles) != 1 || !info.Titles[0].HasVersion || info.Titles[0].Version != 0 {
+		t.Errorf("Titles = %+v, want one title with version 0", info.Titles)
+	}
+}
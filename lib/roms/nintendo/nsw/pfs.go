@@ -0,0 +1,121 @@
+package nsw
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/sargunv/rom-tools/internal/util"
+)
+
+// PFS0 and HFS0 partition filesystem parsing.
+//
+// https://switchbrew.org/wiki/NCA#PFS0
+// https://switchbrew.org/wiki/XCI#HFS0
+//
+// Both formats share the same layout: a header, a file entry table, a string
+// table of file names, and then the file data.
+//
+// Header layout (0x10 bytes, little-endian):
+//
+//	Offset  Size  Description
+//	0x00    4     Magic ("PFS0" or "HFS0")
+//	0x04    4     Number of files
+//	0x08    4     String table size
+//	0x0C    4     Reserved
+//
+// PFS0 file entry (0x18 bytes):
+//
+//	Offset  Size  Description
+//	0x00    8     Data offset (relative to data start)
+//	0x08    8     Data size
+//	0x10    4     String table offset
+//	0x14    4     Reserved
+//
+// HFS0 file entry (0x40 bytes):
+//
+//	Offset  Size  Description
+//	0x00    8     Data offset (relative to data start)
+//	0x08    8     Data size
+//	0x10    4     String table offset
+//	0x14    4     Hashed region size
+//	0x18    8     Reserved
+//	0x20    32    SHA-256 of hashed region
+
+const (
+	pfsHeaderSize     = 0x10
+	pfs0EntrySize     = 0x18
+	hfs0EntrySize     = 0x40
+	pfsMaxFiles       = 0x10000
+	pfsMaxStringTable = 0x100000
+)
+
+// Entry is a file within a PFS0 or HFS0 partition.
+type Entry struct {
+	// Partition is the name of the XCI partition containing the file (empty for NSP).
+	Partition string `json:"partition,omitempty"`
+	// Name is the file name.
+	Name string `json:"name"`
+	// Offset is the absolute offset of the file data within the image.
+	Offset int64 `json:"offset"`
+	// Size is the file size in bytes.
+	Size int64 `json:"size"`
+}
+
+// readPartition reads a PFS0 or HFS0 partition at offset and returns its entries.
+// Entry offsets are converted to absolute offsets within r.
+func readPartition(r io.ReaderAt, offset, size int64, magic string) ([]Entry, error) {
+	if offset+pfsHeaderSize > size {
+		return nil, fmt.Errorf("file too small for %s header", magic)
+	}
+
+	header := make([]byte, pfsHeaderSize)
+	if _, err := r.ReadAt(header, offset); err != nil {
+		return nil, fmt.Errorf("failed to read %s header: %w", magic, err)
+	}
+
+	if string(header[:4]) != magic {
+		return nil, fmt.Errorf("not a valid %s partition: invalid magic %q", magic, string(header[:4]))
+	}
+
+	numFiles := int64(binary.LittleEndian.Uint32(header[0x04:]))
+	stringTableSize := int64(binary.LittleEndian.Uint32(header[0x08:]))
+	if numFiles > pfsMaxFiles || stringTableSize > pfsMaxStringTable {
+		return nil, fmt.Errorf("invalid %s header: %d files, string table size %d", magic, numFiles, stringTableSize)
+	}
+
+	entrySize := int64(pfs0EntrySize)
+	if magic == "HFS0" {
+		entrySize = hfs0EntrySize
+	}
+
+	tableSize := numFiles*entrySize + stringTableSize
+	if offset+pfsHeaderSize+tableSize > size {
+		return nil, fmt.Errorf("%s file table extends beyond file", magic)
+	}
+
+	table := make([]byte, tableSize)
+	if _, err := r.ReadAt(table, offset+pfsHeaderSize); err != nil {
+		return nil, fmt.Errorf("failed to read %s file table: %w", magic, err)
+	}
+	stringTable := table[numFiles*entrySize:]
+	dataStart := offset + pfsHeaderSize + tableSize
+
+	entries := make([]Entry, 0, numFiles)
+	for i := range numFiles {
+		e := table[i*entrySize:]
+		dataOffset := int64(binary.LittleEndian.Uint64(e[0x00:]))
+		dataSize := int64(binary.LittleEndian.Uint64(e[0x08:]))
+		nameOffset := int64(binary.LittleEndian.Uint32(e[0x10:]))
+		if nameOffset >= stringTableSize {
+			return nil, fmt.Errorf("%s entry %d has invalid name offset %d", magic, i, nameOffset)
+		}
+		entries = append(entries, Entry{
+			Name:   util.ExtractASCII(stringTable[nameOffset:]),
+			Offset: dataStart + dataOffset,
+			Size:   dataSize,
+		})
+	}
+
+	return entries, nil
+}
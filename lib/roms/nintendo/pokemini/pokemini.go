@@ -0,0 +1,121 @@
+package pokemini
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sargunv/rom-tools/internal/util"
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// Nintendo Pokémon Mini ROM format parsing.
+//
+// Header specification:
+// https://www.pokemon-mini.net/documentation/cartridge/
+//
+// Header layout (at offset 0x2100, after the 8 KiB BIOS area):
+//
+//	Offset  Size  Description
+//	0x2100  2     Magic "MN"
+//	0x2102  162   Reset and interrupt vectors
+//	0x21A4  8     "NINTENDO"
+//	0x21AC  4     Product code (e.g., "MPTE")
+//	0x21B0  12    Title (Shift-JIS, null/space-padded)
+//	0x21BC  2     "2P"
+//	0x21BE  18    Reserved
+//
+// Product code breakdown (4 bytes at 0x21AC):
+//   - Byte 0: Always 'M'
+//   - Bytes 1-2: Unique game identifier
+//   - Byte 3: Destination (J=Japan, E=USA, P=Europe)
+
+const (
+	pmMagicOffset       = 0x2100
+	pmMagic             = "MN"
+	pmNintendoOffset    = 0x21A4
+	pmNintendo          = "NINTENDO"
+	pmProductCodeOffset = 0x21AC
+	pmProductCodeLen    = 4
+	pmTitleOffset       = 0x21B0
+	pmTitleLen          = 12
+	pmHeaderEnd         = 0x21D0
+	pmDestinationIndex  = 3
+)
+
+// Destination represents the target region from the fourth byte of the product code.
+type Destination byte
+
+// Destination values.
+const (
+	DestinationJapan  Destination = 'J'
+	DestinationUSA    Destination = 'E'
+	DestinationEurope Destination = 'P'
+)
+
+// Info contains metadata extracted from a Pokémon Mini ROM header.
+type Info struct {
+	// ProductCode is the 4-character product code (e.g., "MPTE").
+	ProductCode string `json:"product_code,omitempty"`
+	// Title is the game title (up to 12 characters, Shift-JIS decoded).
+	Title string `json:"title,omitempty"`
+	// Destination is the target region from byte 3 of ProductCode.
+	Destination Destination `json:"destination"`
+	// Licensed indicates the "NINTENDO" string is present (absent in most homebrew).
+	Licensed bool `json:"licensed"`
+}
+
+// GamePlatform implements core.GameInfo.
+func (i *Info) GamePlatform() core.Platform { return core.PlatformPokemonMini }
+
+// GameTitle implements core.GameInfo.
+func (i *Info) GameTitle() string { return i.Title }
+
+// GameSerial implements core.GameInfo.
+func (i *Info) GameSerial() string { return i.ProductCode }
+
+// GameRegions implements core.GameInfo.
+func (i *Info) GameRegions() []core.Region {
+	switch i.Destination {
+	case DestinationJapan:
+		return []core.Region{core.RegionJapan}
+	case DestinationUSA:
+		return []core.Region{core.RegionUSA}
+	case DestinationEurope:
+		return []core.Region{core.RegionEurope}
+	default:
+		return []core.Region{}
+	}
+}
+
+// Parse extracts game information from a Pokémon Mini ROM file.
+func Parse(r io.ReaderAt, size int64) (*Info, error) {
+	if size < pmHeaderEnd {
+		return nil, fmt.Errorf("file too small for Pokémon Mini header: %d bytes", size)
+	}
+
+	header := make([]byte, pmHeaderEnd-pmMagicOffset)
+	if _, err := r.ReadAt(header, pmMagicOffset); err != nil {
+		return nil, fmt.Errorf("failed to read Pokémon Mini header: %w", err)
+	}
+
+	if magic := string(header[:len(pmMagic)]); magic != pmMagic {
+		return nil, fmt.Errorf("not a valid Pokémon Mini ROM: invalid magic %q", magic)
+	}
+
+	field := func(offset, length int) []byte {
+		return header[offset-pmMagicOffset : offset-pmMagicOffset+length]
+	}
+
+	productCode := util.ExtractASCII(field(pmProductCodeOffset, pmProductCodeLen))
+	var destination Destination
+	if len(productCode) == pmProductCodeLen {
+		destination = Destination(productCode[pmDestinationIndex])
+	}
+
+	return &Info{
+		ProductCode: productCode,
+		Title:       util.ExtractShiftJIS(field(pmTitleOffset, pmTitleLen)),
+		Destination: destination,
+		Licensed:    string(field(pmNintendoOffset, len(pmNintendo))) == pmNintendo,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package pokemini
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// makeTestROM creates a minimal ROM with the given header fields.
+func makeTestROM(magic, productCode, title string, licensed bool) []byte {
+	rom := make([]byte, 0x4000)
+	copy(rom[pmMagicOffset:], magic)
+	if licensed {
+		copy(rom[pmNintendoOffset:], pmNintendo)
+	}
+	copy(rom[pmProductCodeOffset:], productCode)
+	copy(rom[pmTitleOffset:], title)
+	copy(rom[pmTitleOffset+pmTitleLen:], "2P")
+	return rom
+}
+
+func TestParse(t *testing.T) {
+	rom := makeTestROM(pmMagic, "MPTE", "Pinball", true)
+
+	info, err := Parse(bytes.NewReader(rom), int64(len(rom)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.GamePlatform() != core.PlatformPokemonMini {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformPokemonMini)
+	}
+	if info.GameTitle() != "Pinball" {
+		t.Errorf("GameTitle() = %q, want %q", info.GameTitle(), "Pinball")
+	}
+	if info.GameSerial() != "MPTE" {
+		t.Errorf("GameSerial() = %q, want %q", info.GameSerial(), "MPTE")
+	}
+	if !info.Licensed {
+		t.Error("Licensed = false, want true")
+	}
+	if regions := info.GameRegions(); len(regions) != 1 || regions[0] != core.RegionUSA {
+		t.Errorf("GameRegions() = %v, want [%v]", regions, core.RegionUSA)
+	}
+}
+
+func TestParse_Homebrew(t *testing.T) {
+	rom := makeTestROM(pmMagic, "", "", false)
+
+	info, err := Parse(bytes.NewReader(rom), int64(len(rom)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.Licensed {
+		t.Error("Licensed = true, want false")
+	}
+	if info.GameSerial() != "" {
+		t.Errorf("GameSerial() = %q, want empty", info.GameSerial())
+	}
+	if regions := info.GameRegions(); len(regions) != 0 {
+		t.Errorf("GameRegions() = %v, want empty", regions)
+	}
+}
+
+func TestParse_InvalidMagic(t *testing.T) {
+	rom := makeTestROM("XX", "MPTE", "Pinball", true)
+
+	if _, err := Parse(bytes.NewReader(rom), int64(len(rom))); err == nil {
+		t.Error("Parse() expected error for invalid magic")
+	}
+}
+
+func TestParse_TooSmall(t *testing.T) {
+	rom := make([]byte, 0x1000)
+
+	if _, err := Parse(bytes.NewReader(rom), int64(len(rom))); err == nil {
+		t.Error("Parse() expected error for small file")
+	}
+}
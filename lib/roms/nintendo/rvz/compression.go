@@ -0,0 +1,108 @@
+package rvz
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz/lzma"
+)
+
+// Compressor-specific data stored in wia_disc_t compr_data:
+//
+//	LZMA:  5 bytes - LZMA properties byte and dictionary size (little-endian)
+//	LZMA2: 1 byte  - LZMA2 dictionary size property
+//
+// LZMA and LZMA2 data is stored as a raw stream without the .lzma/.xz headers.
+
+const (
+	lzmaPropsSize  = 5
+	lzma2PropsSize = 1
+
+	lzmaHeaderSize  = 13
+	lzmaUnknownSize = ^uint64(0)
+)
+
+var zstdDecoder *zstd.Decoder
+
+func init() {
+	var err error
+	zstdDecoder, err = zstd.NewReader(nil)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create zstd decoder: %v", err))
+	}
+}
+
+// decompress decompresses data compressed with the given method.
+// props is the compressor-specific data from the disc struct.
+func decompress(method Compression, props []byte, data []byte) ([]byte, error) {
+	switch method {
+	case CompressionNone:
+		return data, nil
+	case CompressionBZIP2:
+		return io.ReadAll(bzip2.NewReader(bytes.NewReader(data)))
+	case CompressionLZMA:
+		return decompressLZMA(props, data)
+	case CompressionLZMA2:
+		return decompressLZMA2(props, data)
+	case CompressionZstandard:
+		return zstdDecoder.DecodeAll(data, nil)
+	default:
+		return nil, fmt.Errorf("unsupported compression method: %d", method)
+	}
+}
+
+// decompressLZMA decompresses a raw LZMA stream by prepending a .lzma header
+// built from the stored properties.
+func decompressLZMA(props []byte, data []byte) ([]byte, error) {
+	if len(props) < lzmaPropsSize {
+		return nil, fmt.Errorf("LZMA properties too short: %d bytes", len(props))
+	}
+
+	header := make([]byte, lzmaHeaderSize)
+	copy(header, props[:lzmaPropsSize])
+	binary.LittleEndian.PutUint64(header[lzmaPropsSize:], lzmaUnknownSize)
+
+	dictSize := binary.LittleEndian.Uint32(props[1:])
+	r, err := lzma.ReaderConfig{DictCap: int(max(dictSize, lzma.MinDictCap))}.NewReader(
+		io.MultiReader(bytes.NewReader(header), bytes.NewReader(data)))
+	if err != nil {
+		return nil, err
+	}
+	return readStream(r)
+}
+
+// decompressLZMA2 decompresses a raw LZMA2 stream.
+func decompressLZMA2(props []byte, data []byte) ([]byte, error) {
+	if len(props) < lzma2PropsSize {
+		return nil, fmt.Errorf("LZMA2 properties too short: %d bytes", len(props))
+	}
+
+	r, err := lzma.Reader2Config{DictCap: lzma2DictSize(props[0])}.NewReader2(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return readStream(r)
+}
+
+// lzma2DictSize decodes the LZMA2 dictionary size property byte.
+func lzma2DictSize(p byte) int {
+	if p >= 40 {
+		return lzma.MaxDictCap
+	}
+	return max((2|int(p&1))<<(p/2+11), lzma.MinDictCap)
+}
+
+// readStream reads a decompressed stream to the end. Streams without an end
+// marker stop at the end of the compressed data, which is not an error here.
+func readStream(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,134 @@
+package rvz
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// RVZ packing and junk data regeneration.
+//
+// Unused areas of GameCube and Wii discs are filled with pseudo-random junk
+// data from a lagged Fibonacci generator that is reseeded every 0x8000 bytes.
+// RVZ stores such runs as the generator seed instead of the data. Packed
+// group data is a sequence of records:
+//
+//	Offset  Size  Description
+//	0x00    4     Size (big-endian; top bit set = junk data)
+//	0x04    ...   Size bytes of data, or a 68-byte seed for junk data
+//
+// Reference implementation:
+// https://github.com/dolphin-emu/dolphin/blob/master/Source/Core/DiscIO/LaggedFibonacciGenerator.cpp
+
+const (
+	lfgK        = 521
+	lfgJ        = 32
+	lfgSeedSize = 17
+
+	junkSeedBytes = lfgSeedSize * 4
+	junkFlag      = 0x80000000
+	junkBlockSize = 0x8000
+
+	lfgBufferBytes = lfgK * 4
+)
+
+// lfg is the lagged Fibonacci generator used for GameCube and Wii junk data.
+type lfg struct {
+	buffer   [lfgK]uint32
+	position int // Byte position within buffer
+}
+
+// setSeed initializes the generator from a big-endian seed.
+func (g *lfg) setSeed(seed []byte) {
+	g.position = 0
+	for i := range lfgSeedSize {
+		g.buffer[i] = binary.BigEndian.Uint32(seed[i*4:])
+	}
+	for i := lfgSeedSize; i < lfgK; i++ {
+		g.buffer[i] = (g.buffer[i-17] << 23) ^ (g.buffer[i-16] >> 9) ^ g.buffer[i-1]
+	}
+
+	// Output words use bits 18-25 in place of bits 16-23
+	for i, x := range g.buffer {
+		g.buffer[i] = (x & 0xFF00FFFF) | ((x >> 2) & 0x00FF0000)
+	}
+	for range 4 {
+		g.advance()
+	}
+}
+
+// advance generates the next lfgK words.
+func (g *lfg) advance() {
+	for i := range lfgJ {
+		g.buffer[i] ^= g.buffer[i+lfgK-lfgJ]
+	}
+	for i := lfgJ; i < lfgK; i++ {
+		g.buffer[i] ^= g.buffer[i-lfgJ]
+	}
+}
+
+// skip discards count bytes of output.
+func (g *lfg) skip(count int) {
+	g.position += count
+	for g.position >= lfgBufferBytes {
+		g.advance()
+		g.position -= lfgBufferBytes
+	}
+}
+
+// read fills p with generator output. Words are output big-endian.
+func (g *lfg) read(p []byte) {
+	var word [4]byte
+	for len(p) > 0 {
+		binary.BigEndian.PutUint32(word[:], g.buffer[g.position/4])
+		n := copy(p, word[g.position%4:])
+		p = p[n:]
+		g.position += n
+		if g.position == lfgBufferBytes {
+			g.advance()
+			g.position = 0
+		}
+	}
+}
+
+// unpack expands RVZ-packed data into size bytes. dataOffset is the offset of
+// the data within the disc (or the partition data for Wii partitions), which
+// positions the regenerated junk within its 0x8000-byte block.
+func unpack(packed []byte, size int, dataOffset int64) ([]byte, error) {
+	out := make([]byte, 0, size)
+	var g lfg
+
+	for len(out) < size {
+		if len(packed) < 4 {
+			return nil, fmt.Errorf("RVZ packed data truncated at 0x%X", len(out))
+		}
+		recordSize := binary.BigEndian.Uint32(packed)
+		packed = packed[4:]
+
+		junk := recordSize&junkFlag != 0
+		n := int(recordSize &^ junkFlag)
+		if n > size-len(out) {
+			return nil, fmt.Errorf("RVZ packed record of %d bytes exceeds group size", n)
+		}
+
+		if junk {
+			if len(packed) < junkSeedBytes {
+				return nil, fmt.Errorf("RVZ junk seed truncated at 0x%X", len(out))
+			}
+			g.setSeed(packed)
+			packed = packed[junkSeedBytes:]
+			g.skip(int((dataOffset + int64(len(out))) % junkBlockSize))
+
+			start := len(out)
+			out = out[:start+n]
+			g.read(out[start:])
+		} else {
+			if len(packed) < n {
+				return nil, fmt.Errorf("RVZ packed data truncated at 0x%X", len(out))
+			}
+			out = append(out, packed[:n]...)
+			packed = packed[n:]
+		}
+	}
+
+	return out, nil
+}
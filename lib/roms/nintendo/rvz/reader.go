@@ -0,0 +1,538 @@
+package rvz
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// wia_disc_t fields after dhead (offsets relative to discStructBase):
+//
+//	Offset  Size  Description
+//	0x90    4     Number of partition entries
+//	0x94    4     Size of each partition entry
+//	0x98    8     File offset of partition entries
+//	0xA0    20    SHA-1 hash of partition entries
+//	0xB4    4     Number of raw data entries
+//	0xB8    8     File offset of raw data entries
+//	0xC0    4     Compressed size of raw data entries
+//	0xC4    4     Number of group entries
+//	0xC8    8     File offset of group entries
+//	0xD0    4     Compressed size of group entries
+//	0xD4    1     Length of compressor data
+//	0xD5    7     Compressor data (see compression.go)
+//
+// wia_part_t (0x30 bytes): 16-byte decrypted title key, followed by two
+// wia_part_data_t. Partition data is stored decrypted and without hashes.
+//
+//	Offset  Size  Description
+//	0x00    4     First sector (0x8000 bytes each, disc offset / 0x8000)
+//	0x04    4     Number of sectors
+//	0x08    4     First group entry index
+//	0x0C    4     Number of group entries
+//
+// wia_raw_data_t (0x18 bytes) covers all data outside of Wii partitions:
+//
+//	Offset  Size  Description
+//	0x00    8     Disc offset
+//	0x08    8     Size
+//	0x10    4     First group entry index
+//	0x14    4     Number of group entries
+//
+// wia_group_t (8 bytes, WIA) / rvz_group_t (12 bytes, RVZ):
+//
+//	Offset  Size  Description
+//	0x00    4     File offset / 4
+//	0x04    4     Data size (RVZ: top bit set = compressed; 0 = all zeros)
+//	0x08    4     RVZ packed size (RVZ only; 0 = not packed)
+//
+// Each group holds one chunk of a data entry. Wii partition groups start with
+// hash exception lists, which are skipped since hashes are not reconstructed.
+
+const (
+	// wia_disc_t offsets (relative to discStructBase)
+	numPartitionsOffset   = 0x90
+	partitionSizeOffset   = 0x94
+	partitionsOffset      = 0x98
+	numRawDataOffset      = 0xB4
+	rawDataOffset         = 0xB8
+	rawDataSizeOffset     = 0xC0
+	numGroupsOffset       = 0xC4
+	groupsOffset          = 0xC8
+	groupsSizeOffset      = 0xD0
+	comprDataLenOffset    = 0xD4
+	comprDataOffset       = 0xD5
+	comprDataMaxSize      = 7
+	discStructSize        = 0xDC
+	fullHeaderSize        = discStructBase + discStructSize
+	partitionEntrySize    = 0x30
+	partitionKeySize      = 16
+	partitionDataSize     = 0x10
+	rawDataEntrySize      = 0x18
+	wiaGroupEntrySize     = 8
+	rvzGroupEntrySize     = 12
+	rvzCompressedFlag     = 0x80000000
+	exceptionSize         = 2 + 20 // Offset and SHA-1 hash
+	exceptionListInterval = 0x200000
+
+	sectorSize     = 0x8000
+	sectorDataSize = 0x7C00 // Wii sector without the 0x400-byte hash area
+)
+
+// Reader provides random access to the original disc inside an RVZ/WIA file.
+//
+// For GameCube discs the reader covers the whole disc. For Wii discs it covers
+// the data outside of partitions; partition contents are read decrypted
+// through Partitions, since encrypting them again is not supported.
+type Reader struct {
+	// Partitions lists the Wii partitions of the disc (empty for GameCube discs).
+	Partitions []*Partition
+
+	file        io.ReaderAt
+	fileSize    int64
+	isRVZ       bool
+	discType    DiscType
+	compression Compression
+	props       []byte
+	chunkSize   int64
+	size        int64
+	dhead       []byte
+	rawData     []rawDataEntry
+	groups      []groupEntry
+
+	mu          sync.Mutex
+	cachedIndex int
+	cachedGroup []byte
+}
+
+// Partition is a Wii partition stored in an RVZ/WIA file.
+type Partition struct {
+	// Key is the decrypted title key of the partition.
+	Key [partitionKeySize]byte
+	// Offset is the disc offset of the partition data.
+	Offset int64
+
+	reader *Reader
+	data   [2]partitionDataEntry
+}
+
+type partitionDataEntry struct {
+	firstSector uint32
+	numSectors  uint32
+	firstGroup  uint32
+	numGroups   uint32
+}
+
+type rawDataEntry struct {
+	offset     int64
+	size       int64
+	firstGroup uint32
+	numGroups  uint32
+}
+
+type groupEntry struct {
+	offset     int64
+	size       uint32
+	compressed bool
+	packedSize uint32
+}
+
+// NewReader opens an RVZ/WIA file and reads its partition, raw data and group tables.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	if size < fullHeaderSize {
+		return nil, fmt.Errorf("file too small for RVZ disc struct: need %d bytes, got %d", fullHeaderSize, size)
+	}
+
+	header := make([]byte, fullHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read RVZ header: %w", err)
+	}
+
+	magic := string(header[magicOffset : magicOffset+4])
+	if magic != "WIA\x01" && magic != "RVZ\x01" {
+		return nil, fmt.Errorf("not a valid RVZ/WIA file: invalid magic (got %q)", magic)
+	}
+
+	disc := header[discStructBase:]
+	reader := &Reader{
+		file:        r,
+		fileSize:    size,
+		isRVZ:       magic == "RVZ\x01",
+		discType:    DiscType(binary.BigEndian.Uint32(disc[discTypeOffset:])),
+		compression: Compression(binary.BigEndian.Uint32(disc[compressionOffset:])),
+		chunkSize:   int64(binary.BigEndian.Uint32(disc[chunkSizeOffset:])),
+		size:        int64(binary.BigEndian.Uint64(header[isoFileSizeOffset:])),
+		dhead:       disc[dheadOffset : dheadOffset+dheadSize],
+		cachedIndex: -1,
+	}
+
+	if reader.compression == CompressionPurge {
+		return nil, fmt.Errorf("unsupported compression method: purge")
+	}
+	if reader.chunkSize == 0 || reader.chunkSize%sectorSize != 0 {
+		return nil, fmt.Errorf("invalid RVZ chunk size: %d", reader.chunkSize)
+	}
+	comprDataLen := int(disc[comprDataLenOffset])
+	if comprDataLen > comprDataMaxSize {
+		return nil, fmt.Errorf("invalid RVZ compressor data length: %d", comprDataLen)
+	}
+	reader.props = disc[comprDataOffset : comprDataOffset+comprDataLen]
+
+	var err error
+	if reader.groups, err = reader.readGroups(disc); err != nil {
+		return nil, err
+	}
+	if reader.rawData, err = reader.readRawData(disc); err != nil {
+		return nil, err
+	}
+	if reader.Partitions, err = reader.readPartitions(disc); err != nil {
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+// readTable reads a table of entries, decompressing it if compressed is set.
+func (r *Reader) readTable(name string, offset int64, storedSize int64, entrySize int, count uint32, compressed bool) ([]byte, error) {
+	data := make([]byte, storedSize)
+	if _, err := r.file.ReadAt(data, offset); err != nil {
+		return nil, fmt.Errorf("failed to read RVZ %s: %w", name, err)
+	}
+	if compressed {
+		var err error
+		if data, err = decompress(r.compression, r.props, data); err != nil {
+			return nil, fmt.Errorf("failed to decompress RVZ %s: %w", name, err)
+		}
+	}
+	if int64(len(data)) < int64(count)*int64(entrySize) {
+		return nil, fmt.Errorf("RVZ %s truncated: %d entries in %d bytes", name, count, len(data))
+	}
+	return data, nil
+}
+
+func (r *Reader) readGroups(disc []byte) ([]groupEntry, error) {
+	count := binary.BigEndian.Uint32(disc[numGroupsOffset:])
+	entrySize := wiaGroupEntrySize
+	if r.isRVZ {
+		entrySize = rvzGroupEntrySize
+	}
+
+	data, err := r.readTable("group entries",
+		int64(binary.BigEndian.Uint64(disc[groupsOffset:])),
+		int64(binary.BigEndian.Uint32(disc[groupsSizeOffset:])),
+		entrySize, count, true)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]groupEntry, count)
+	for i := range groups {
+		entry := data[i*entrySize:]
+		groups[i] = groupEntry{
+			offset: int64(binary.BigEndian.Uint32(entry)) << 2,
+			size:   binary.BigEndian.Uint32(entry[4:]),
+		}
+		if r.isRVZ {
+			groups[i].compressed = groups[i].size&rvzCompressedFlag != 0
+			groups[i].size &^= rvzCompressedFlag
+			groups[i].packedSize = binary.BigEndian.Uint32(entry[8:])
+		}
+	}
+	return groups, nil
+}
+
+func (r *Reader) readRawData(disc []byte) ([]rawDataEntry, error) {
+	count := binary.BigEndian.Uint32(disc[numRawDataOffset:])
+	data, err := r.readTable("raw data entries",
+		int64(binary.BigEndian.Uint64(disc[rawDataOffset:])),
+		int64(binary.BigEndian.Uint32(disc[rawDataSizeOffset:])),
+		rawDataEntrySize, count, true)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]rawDataEntry, count)
+	for i := range entries {
+		entry := data[i*rawDataEntrySize:]
+		entries[i] = rawDataEntry{
+			offset:     int64(binary.BigEndian.Uint64(entry)),
+			size:       int64(binary.BigEndian.Uint64(entry[8:])),
+			firstGroup: binary.BigEndian.Uint32(entry[16:]),
+			numGroups:  binary.BigEndian.Uint32(entry[20:]),
+		}
+		if err := r.checkGroups(entries[i].firstGroup, entries[i].numGroups); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].offset < entries[j].offset })
+	return entries, nil
+}
+
+func (r *Reader) readPartitions(disc []byte) ([]*Partition, error) {
+	count := binary.BigEndian.Uint32(disc[numPartitionsOffset:])
+	if count == 0 {
+		return nil, nil
+	}
+	entrySize := int(binary.BigEndian.Uint32(disc[partitionSizeOffset:]))
+	if entrySize < partitionEntrySize {
+		return nil, fmt.Errorf("invalid RVZ partition entry size: %d", entrySize)
+	}
+
+	// Partition entries are never compressed
+	data, err := r.readTable("partition entries",
+		int64(binary.BigEndian.Uint64(disc[partitionsOffset:])),
+		int64(count)*int64(entrySize),
+		entrySize, count, false)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := make([]*Partition, count)
+	for i := range partitions {
+		entry := data[i*entrySize:]
+		p := &Partition{reader: r}
+		copy(p.Key[:], entry)
+		for j := range p.data {
+			d := entry[partitionKeySize+j*partitionDataSize:]
+			p.data[j] = partitionDataEntry{
+				firstSector: binary.BigEndian.Uint32(d),
+				numSectors:  binary.BigEndian.Uint32(d[4:]),
+				firstGroup:  binary.BigEndian.Uint32(d[8:]),
+				numGroups:   binary.BigEndian.Uint32(d[12:]),
+			}
+			if err := r.checkGroups(p.data[j].firstGroup, p.data[j].numGroups); err != nil {
+				return nil, err
+			}
+		}
+		p.Offset = int64(p.data[0].firstSector) * sectorSize
+		partitions[i] = p
+	}
+	return partitions, nil
+}
+
+func (r *Reader) checkGroups(first, count uint32) error {
+	if uint64(first)+uint64(count) > uint64(len(r.groups)) {
+		return fmt.Errorf("RVZ group range %d+%d out of range (total: %d)", first, count, len(r.groups))
+	}
+	return nil
+}
+
+// Size returns the size of the original disc.
+func (r *Reader) Size() int64 {
+	return r.size
+}
+
+// Info parses the file header, reading the disc header from the reconstructed disc.
+func (r *Reader) Info() (*Info, error) {
+	return parseInfo(r.file, r.fileSize, r)
+}
+
+// DiscType returns the disc type (GameCube or Wii).
+func (r *Reader) DiscType() DiscType {
+	return r.discType
+}
+
+// ReadAt implements io.ReaderAt over the original disc.
+// Wii partition data cannot be read this way; use Partitions instead.
+func (r *Reader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset")
+	}
+
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= r.size {
+			return n, io.EOF
+		}
+		chunk := limit(p[n:], r.size-pos)
+
+		// The disc header is stored uncompressed in the disc struct
+		if pos < dheadSize {
+			n += copy(chunk, r.dhead[pos:])
+			continue
+		}
+
+		i := sort.Search(len(r.rawData), func(i int) bool {
+			return r.rawData[i].offset+r.rawData[i].size > pos
+		})
+		if i == len(r.rawData) || r.rawData[i].offset > pos {
+			for _, partition := range r.Partitions {
+				if pos >= partition.Offset && pos < partition.Offset+partition.encryptedSize() {
+					return n, fmt.Errorf("disc offset 0x%X is in an encrypted Wii partition", pos)
+				}
+			}
+			return n, fmt.Errorf("no RVZ data at disc offset 0x%X", pos)
+		}
+		entry := r.rawData[i]
+
+		// Raw data groups start at the sector containing the entry
+		start := entry.offset - entry.offset%sectorSize
+		entryEnd := entry.offset + entry.size
+		read, err := r.readFromGroups(limit(chunk, entryEnd-pos), pos,
+			start, entryEnd-start, r.chunkSize, entry.firstGroup, entry.numGroups, 0)
+		n += read
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// limit truncates p to at most n bytes.
+func limit(p []byte, n int64) []byte {
+	if int64(len(p)) > n {
+		return p[:n]
+	}
+	return p
+}
+
+// readFromGroups copies data from the group holding pos within a data entry.
+// dataOffset and dataSize describe the entry, which is split into chunkSize groups.
+func (r *Reader) readFromGroups(p []byte, pos, dataOffset, dataSize, chunkSize int64, firstGroup, numGroups uint32, exceptionLists int) (int, error) {
+	i := (pos - dataOffset) / chunkSize
+	if i >= int64(numGroups) {
+		return 0, fmt.Errorf("RVZ data at 0x%X is beyond the last group", pos)
+	}
+
+	groupStart := i * chunkSize
+	groupSize := min(chunkSize, dataSize-groupStart)
+	data, err := r.readGroup(int(firstGroup)+int(i), int(groupSize), exceptionLists, dataOffset+groupStart)
+	if err != nil {
+		return 0, err
+	}
+	return copy(p, data[pos-dataOffset-groupStart:]), nil
+}
+
+// readGroup returns the decompressed data of a group, using a single-group cache.
+// dataOffset is the offset of the group data, used to regenerate junk data.
+func (r *Reader) readGroup(index int, size int, exceptionLists int, dataOffset int64) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if index == r.cachedIndex {
+		return r.cachedGroup, nil
+	}
+
+	group := r.groups[index]
+	if group.size == 0 {
+		r.cachedIndex = index
+		r.cachedGroup = make([]byte, size)
+		return r.cachedGroup, nil
+	}
+
+	data := make([]byte, group.size)
+	if _, err := r.file.ReadAt(data, group.offset); err != nil {
+		return nil, fmt.Errorf("failed to read RVZ group %d: %w", index, err)
+	}
+
+	// RVZ groups that did not shrink when compressed are stored uncompressed
+	compressed := r.compression != CompressionNone && (!r.isRVZ || group.compressed)
+	if compressed {
+		var err error
+		if data, err = decompress(r.compression, r.props, data); err != nil {
+			return nil, fmt.Errorf("failed to decompress RVZ group %d: %w", index, err)
+		}
+	}
+
+	// Skip hash exception lists; uncompressed lists are padded to 4 bytes
+	pos := 0
+	for range exceptionLists {
+		if pos+2 > len(data) {
+			return nil, fmt.Errorf("RVZ group %d exception list truncated", index)
+		}
+		pos += 2 + int(binary.BigEndian.Uint16(data[pos:]))*exceptionSize
+	}
+	if !compressed {
+		pos = (pos + 3) &^ 3
+	}
+	if pos > len(data) {
+		return nil, fmt.Errorf("RVZ group %d exception list truncated", index)
+	}
+	data = data[pos:]
+
+	if group.packedSize != 0 {
+		var err error
+		if data, err = unpack(data, size, dataOffset); err != nil {
+			return nil, fmt.Errorf("failed to unpack RVZ group %d: %w", index, err)
+		}
+	}
+	if len(data) < size {
+		return nil, fmt.Errorf("RVZ group %d too short: %d bytes, want %d", index, len(data), size)
+	}
+
+	r.cachedIndex = index
+	r.cachedGroup = data[:size]
+	return r.cachedGroup, nil
+}
+
+// Open returns a reader for the decrypted partition data, without hashes.
+func (p *Partition) Open() io.ReaderAt {
+	return &partitionReader{partition: p}
+}
+
+// Size returns the size of the decrypted partition data.
+func (p *Partition) Size() int64 {
+	last := p.data[1]
+	if last.numSectors == 0 {
+		last = p.data[0]
+	}
+	return int64(last.firstSector+last.numSectors-p.data[0].firstSector) * sectorDataSize
+}
+
+// encryptedSize returns the size of the partition data on disc.
+func (p *Partition) encryptedSize() int64 {
+	return p.Size() / sectorDataSize * sectorSize
+}
+
+// partitionReader provides access to decrypted Wii partition data.
+type partitionReader struct {
+	partition *Partition
+}
+
+// ReadAt implements io.ReaderAt for decrypted partition data.
+func (pr *partitionReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset")
+	}
+
+	r := pr.partition.reader
+	size := pr.partition.Size()
+	chunkSize := r.chunkSize / sectorSize * sectorDataSize
+	exceptionLists := max(1, int(r.chunkSize/exceptionListInterval))
+	firstSector := pr.partition.data[0].firstSector
+
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= size {
+			return n, io.EOF
+		}
+
+		var entry *partitionDataEntry
+		var start, end int64
+		for i := range pr.partition.data {
+			d := &pr.partition.data[i]
+			start = int64(d.firstSector-firstSector) * sectorDataSize
+			end = start + int64(d.numSectors)*sectorDataSize
+			if pos >= start && pos < end {
+				entry = d
+				break
+			}
+		}
+		if entry == nil {
+			return n, fmt.Errorf("no RVZ data at partition offset 0x%X", pos)
+		}
+
+		read, err := r.readFromGroups(limit(p[n:], end-pos), pos,
+			start, end-start, chunkSize, entry.firstGroup, entry.numGroups, exceptionLists)
+		n += read
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
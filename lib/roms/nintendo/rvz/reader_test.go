@@ -0,0 +1,454 @@
+package rvz
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/sargunv/rom-tools/lib/roms/nintendo/gcm"
+	"github.com/ulikunitz/xz/lzma"
+)
+
+const testChunkSize = sectorSize
+
+// testGroup is a group as stored in a synthetic RVZ/WIA file.
+type testGroup struct {
+	data       []byte // Stored data (nil = all zeros)
+	compressed bool
+	packedSize uint32
+}
+
+// testPartition describes the two data entries of a synthetic Wii partition.
+type testPartition struct {
+	key  [partitionKeySize]byte
+	data [2]partitionDataEntry
+}
+
+// testImage describes a synthetic RVZ/WIA file.
+type testImage struct {
+	magic       string
+	discType    DiscType
+	compression Compression
+	props       []byte
+	isoSize     int64
+	dhead       []byte
+	partitions  []testPartition
+	rawData     []rawDataEntry
+	groups      []testGroup
+}
+
+// compressTest compresses data with the given method for test files.
+func compressTest(t *testing.T, method Compression, data []byte) (compressed []byte, props []byte) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	switch method {
+	case CompressionNone:
+		return data, nil
+	case CompressionZstandard:
+		enc, err := zstd.NewWriter(nil)
+		if err != nil {
+			t.Fatalf("zstd.NewWriter() error = %v", err)
+		}
+		return enc.EncodeAll(data, nil), nil
+	case CompressionLZMA:
+		w, err := lzma.WriterConfig{DictCap: 1 << 16}.NewWriter(&buf)
+		if err != nil {
+			t.Fatalf("lzma.NewWriter() error = %v", err)
+		}
+		w.Write(data)
+		w.Close()
+		// Strip the .lzma header, keeping the properties and dictionary size
+		return buf.Bytes()[lzmaHeaderSize:], buf.Bytes()[:lzmaPropsSize]
+	case CompressionLZMA2:
+		w, err := lzma.Writer2Config{DictCap: 2 << 20}.NewWriter2(&buf)
+		if err != nil {
+			t.Fatalf("lzma.NewWriter2() error = %v", err)
+		}
+		w.Write(data)
+		w.Close()
+		return buf.Bytes(), []byte{18} // 2 MiB dictionary
+	default:
+		t.Fatalf("unsupported test compression: %d", method)
+		return nil, nil
+	}
+}
+
+// build serializes the image. Tables and groups marked compressed are
+// compressed with the image's compression method.
+func (img testImage) build(t *testing.T) []byte {
+	t.Helper()
+
+	header := make([]byte, fullHeaderSize)
+	if img.magic == "" {
+		img.magic = "RVZ\x01"
+	}
+	isRVZ := img.magic == "RVZ\x01"
+	copy(header[magicOffset:], img.magic)
+	binary.BigEndian.PutUint64(header[isoFileSizeOffset:], uint64(img.isoSize))
+
+	disc := header[discStructBase:]
+	binary.BigEndian.PutUint32(disc[discTypeOffset:], uint32(img.discType))
+	binary.BigEndian.PutUint32(disc[compressionOffset:], uint32(img.compression))
+	binary.BigEndian.PutUint32(disc[chunkSizeOffset:], testChunkSize)
+	copy(disc[dheadOffset:], img.dhead)
+
+	// Tables and group data follow the header
+	var body []byte
+	offset := func() int64 { return int64(fullHeaderSize + len(body)) }
+
+	// Partition entries (never compressed)
+	binary.BigEndian.PutUint32(disc[numPartitionsOffset:], uint32(len(img.partitions)))
+	binary.BigEndian.PutUint32(disc[partitionSizeOffset:], partitionEntrySize)
+	binary.BigEndian.PutUint64(disc[partitionsOffset:], uint64(offset()))
+	for _, p := range img.partitions {
+		entry := make([]byte, partitionEntrySize)
+		copy(entry, p.key[:])
+		for j, d := range p.data {
+			e := entry[partitionKeySize+j*partitionDataSize:]
+			binary.BigEndian.PutUint32(e, d.firstSector)
+			binary.BigEndian.PutUint32(e[4:], d.numSectors)
+			binary.BigEndian.PutUint32(e[8:], d.firstGroup)
+			binary.BigEndian.PutUint32(e[12:], d.numGroups)
+		}
+		body = append(body, entry...)
+	}
+
+	// Raw data entries
+	raw := make([]byte, len(img.rawData)*rawDataEntrySize)
+	for i, entry := range img.rawData {
+		e := raw[i*rawDataEntrySize:]
+		binary.BigEndian.PutUint64(e, uint64(entry.offset))
+		binary.BigEndian.PutUint64(e[8:], uint64(entry.size))
+		binary.BigEndian.PutUint32(e[16:], entry.firstGroup)
+		binary.BigEndian.PutUint32(e[20:], entry.numGroups)
+	}
+	raw, props := compressTest(t, img.compression, raw)
+	binary.BigEndian.PutUint32(disc[numRawDataOffset:], uint32(len(img.rawData)))
+	binary.BigEndian.PutUint64(disc[rawDataOffset:], uint64(offset()))
+	binary.BigEndian.PutUint32(disc[rawDataSizeOffset:], uint32(len(raw)))
+	body = append(body, raw...)
+
+	// Group data, stored after the group table
+	entrySize := wiaGroupEntrySize
+	if isRVZ {
+		entrySize = rvzGroupEntrySize
+	}
+	table := make([]byte, len(img.groups)*entrySize)
+	var data []byte
+	for i, g := range img.groups {
+		stored := g.data
+		if g.data != nil && g.compressed {
+			stored, _ = compressTest(t, img.compression, g.data)
+		}
+		size := uint32(len(stored))
+		if isRVZ && g.compressed && g.data != nil {
+			size |= rvzCompressedFlag
+		}
+		e := table[i*entrySize:]
+		binary.BigEndian.PutUint32(e[4:], size)
+		if isRVZ {
+			binary.BigEndian.PutUint32(e[8:], g.packedSize)
+		}
+		// Offsets are filled in once the table size is known
+		binary.BigEndian.PutUint32(e, uint32(len(data)))
+		data = append(data, stored...)
+		for len(data)%4 != 0 {
+			data = append(data, 0)
+		}
+	}
+
+	// The compressed table size depends on the offsets, so reserve space for
+	// the uncompressed table plus compression overhead
+	tableOffset := offset()
+	dataOffset := (tableOffset + int64(len(table)) + 0x1000) &^ 3
+	for i := range img.groups {
+		e := table[i*entrySize:]
+		binary.BigEndian.PutUint32(e, uint32((dataOffset+int64(binary.BigEndian.Uint32(e)))>>2))
+	}
+	table, _ = compressTest(t, img.compression, table)
+	if int64(len(table)) > dataOffset-tableOffset {
+		t.Fatalf("group table too large: %d bytes", len(table))
+	}
+	binary.BigEndian.PutUint32(disc[numGroupsOffset:], uint32(len(img.groups)))
+	binary.BigEndian.PutUint64(disc[groupsOffset:], uint64(tableOffset))
+	binary.BigEndian.PutUint32(disc[groupsSizeOffset:], uint32(len(table)))
+	body = append(body, table...)
+	body = append(body, make([]byte, dataOffset-offset())...)
+	body = append(body, data...)
+
+	if img.props == nil {
+		img.props = props
+	}
+	disc[comprDataLenOffset] = byte(len(img.props))
+	copy(disc[comprDataOffset:], img.props)
+
+	return append(header, body...)
+}
+
+// makeTestData returns size bytes of a repeating pattern starting at seed.
+func makeTestData(size int, seed byte) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = seed + byte(i*7)
+	}
+	return data
+}
+
+// makeGameCubeImage builds a GameCube image of three groups: compressed,
+// stored uncompressed, and all zeros.
+func makeGameCubeImage(t *testing.T, magic string, compression Compression) ([]byte, []byte) {
+	t.Helper()
+
+	disc := makeTestData(3*testChunkSize, 1)
+	copy(disc, makeSyntheticGCMData(gcm.SystemCodeGameCube, "MK", gcm.RegionNorthAmerica, "Test Game", false))
+	clear(disc[2*testChunkSize:])
+
+	img := testImage{
+		magic:       magic,
+		discType:    DiscTypeGameCube,
+		compression: compression,
+		isoSize:     int64(len(disc)),
+		dhead:       disc[:dheadSize],
+		rawData: []rawDataEntry{
+			{offset: dheadSize, size: int64(len(disc)) - dheadSize, firstGroup: 0, numGroups: 3},
+		},
+		groups: []testGroup{
+			{data: disc[:testChunkSize], compressed: true},
+			{data: disc[testChunkSize : 2*testChunkSize], compressed: magic == "WIA\x01"},
+			{},
+		},
+	}
+	return img.build(t), disc
+}
+
+func TestReader_GameCube(t *testing.T) {
+	tests := []struct {
+		name        string
+		magic       string
+		compression Compression
+	}{
+		{"RVZ none", "RVZ\x01", CompressionNone},
+		{"RVZ zstd", "RVZ\x01", CompressionZstandard},
+		{"RVZ lzma", "RVZ\x01", CompressionLZMA},
+		{"RVZ lzma2", "RVZ\x01", CompressionLZMA2},
+		{"WIA lzma2", "WIA\x01", CompressionLZMA2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, disc := makeGameCubeImage(t, tt.magic, tt.compression)
+
+			reader, err := NewReader(bytes.NewReader(file), int64(len(file)))
+			if err != nil {
+				t.Fatalf("NewReader() error = %v", err)
+			}
+			if reader.Size() != int64(len(disc)) {
+				t.Errorf("Size() = %d, want %d", reader.Size(), len(disc))
+			}
+			if reader.DiscType() != DiscTypeGameCube {
+				t.Errorf("DiscType() = %v, want %v", reader.DiscType(), DiscTypeGameCube)
+			}
+
+			got := make([]byte, len(disc))
+			if _, err := reader.ReadAt(got, 0); err != nil {
+				t.Fatalf("ReadAt() error = %v", err)
+			}
+			if !bytes.Equal(got, disc) {
+				t.Error("ReadAt() data does not match original disc")
+			}
+
+			// Unaligned read across a group boundary
+			got = make([]byte, 0x100)
+			if _, err := reader.ReadAt(got, testChunkSize-0x80); err != nil {
+				t.Fatalf("ReadAt() error = %v", err)
+			}
+			if !bytes.Equal(got, disc[testChunkSize-0x80:testChunkSize+0x80]) {
+				t.Error("ReadAt() across group boundary does not match original disc")
+			}
+		})
+	}
+}
+
+func TestReader_Packed(t *testing.T) {
+	const plainSize = 0x120
+
+	seed := makeTestData(junkSeedBytes, 0x35)
+	packed := binary.BigEndian.AppendUint32(nil, plainSize)
+	packed = append(packed, makeTestData(plainSize, 9)...)
+	packed = binary.BigEndian.AppendUint32(packed, junkFlag|(testChunkSize-plainSize))
+	packed = append(packed, seed...)
+
+	// Junk is generated as if the run started at its offset within the block
+	var g lfg
+	g.setSeed(seed)
+	g.skip(plainSize)
+	want := make([]byte, testChunkSize)
+	copy(want, makeTestData(plainSize, 9))
+	g.read(want[plainSize:])
+
+	img := testImage{
+		discType:    DiscTypeGameCube,
+		compression: CompressionZstandard,
+		isoSize:     testChunkSize,
+		dhead:       want[:dheadSize],
+		rawData:     []rawDataEntry{{offset: dheadSize, size: testChunkSize - dheadSize, numGroups: 1}},
+		groups:      []testGroup{{data: packed, compressed: true, packedSize: uint32(len(packed))}},
+	}
+	file := img.build(t)
+
+	reader, err := NewReader(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	got := make([]byte, testChunkSize)
+	if _, err := reader.ReadAt(got, 0); err != nil {
+		t.Fatalf("ReadAt() error = %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("ReadAt() packed data does not match expected data")
+	}
+}
+
+func TestLFG_SkipMatchesRead(t *testing.T) {
+	seed := makeTestData(junkSeedBytes, 0x11)
+
+	var full lfg
+	full.setSeed(seed)
+	stream := make([]byte, 3*lfgBufferBytes)
+	full.read(stream)
+
+	for _, offset := range []int{0, 1, 0x7FF, lfgBufferBytes, lfgBufferBytes + 3} {
+		var g lfg
+		g.setSeed(seed)
+		g.skip(offset)
+		got := make([]byte, 0x100)
+		g.read(got)
+		if !bytes.Equal(got, stream[offset:offset+0x100]) {
+			t.Errorf("output after skip(%d) does not match stream", offset)
+		}
+	}
+}
+
+func TestReader_WiiPartition(t *testing.T) {
+	const partitionSector = 2
+
+	header := makeSyntheticGCMData(gcm.SystemCodeWii, "SM", gcm.RegionJapan, "Wii Game", true)
+	rawDisc := makeTestData(partitionSector*sectorSize, 3)
+	copy(rawDisc, header)
+
+	// Three partition groups of one sector each; the first has an exception
+	partitionData := makeTestData(3*sectorDataSize, 5)
+	exception := make([]byte, 2+exceptionSize)
+	binary.BigEndian.PutUint16(exception, 1)
+	noException := make([]byte, 4) // Count and padding
+
+	img := testImage{
+		magic:       "WIA\x01",
+		discType:    DiscTypeWii,
+		compression: CompressionNone,
+		isoSize:     (partitionSector + 3) * sectorSize,
+		dhead:       rawDisc[:dheadSize],
+		partitions: []testPartition{{
+			key: [partitionKeySize]byte{1, 2, 3},
+			data: [2]partitionDataEntry{
+				{firstSector: partitionSector, numSectors: 2, firstGroup: 2, numGroups: 2},
+				{firstSector: partitionSector + 2, numSectors: 1, firstGroup: 4, numGroups: 1},
+			},
+		}},
+		rawData: []rawDataEntry{
+			{offset: dheadSize, size: partitionSector*sectorSize - dheadSize, firstGroup: 0, numGroups: 2},
+		},
+		groups: []testGroup{
+			{data: rawDisc[:sectorSize]},
+			{data: rawDisc[sectorSize:]},
+			{data: append(exception, partitionData[:sectorDataSize]...)},
+			{data: append(noException, partitionData[sectorDataSize:2*sectorDataSize]...)},
+			{data: append(noException, partitionData[2*sectorDataSize:]...)},
+		},
+	}
+	file := img.build(t)
+
+	reader, err := NewReader(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	got := make([]byte, len(rawDisc))
+	if _, err := reader.ReadAt(got, 0); err != nil {
+		t.Fatalf("ReadAt() error = %v", err)
+	}
+	if !bytes.Equal(got, rawDisc) {
+		t.Error("ReadAt() raw data does not match original disc")
+	}
+	if _, err := reader.ReadAt(make([]byte, 0x10), partitionSector*sectorSize); err == nil {
+		t.Error("ReadAt() expected error for encrypted partition data")
+	}
+
+	if len(reader.Partitions) != 1 {
+		t.Fatalf("len(Partitions) = %d, want 1", len(reader.Partitions))
+	}
+	partition := reader.Partitions[0]
+	if partition.Offset != partitionSector*sectorSize {
+		t.Errorf("Partition.Offset = 0x%X, want 0x%X", partition.Offset, partitionSector*sectorSize)
+	}
+	if partition.Key[0] != 1 || partition.Key[2] != 3 {
+		t.Errorf("Partition.Key = %X, want 010203...", partition.Key)
+	}
+	if partition.Size() != int64(len(partitionData)) {
+		t.Errorf("Partition.Size() = %d, want %d", partition.Size(), len(partitionData))
+	}
+
+	got = make([]byte, len(partitionData))
+	if _, err := partition.Open().ReadAt(got, 0); err != nil {
+		t.Fatalf("Partition ReadAt() error = %v", err)
+	}
+	if !bytes.Equal(got, partitionData) {
+		t.Error("Partition ReadAt() data does not match original partition data")
+	}
+}
+
+func TestReader_Purge(t *testing.T) {
+	img := testImage{discType: DiscTypeGameCube, compression: CompressionNone, isoSize: testChunkSize}
+	file := img.build(t)
+	binary.BigEndian.PutUint32(file[discStructBase+compressionOffset:], uint32(CompressionPurge))
+
+	if _, err := NewReader(bytes.NewReader(file), int64(len(file))); err == nil {
+		t.Error("NewReader() expected error for purge compression")
+	}
+}
+
+func TestParse_FullDisc(t *testing.T) {
+	file, _ := makeGameCubeImage(t, "RVZ\x01", CompressionZstandard)
+
+	info, err := Parse(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if info.GameTitle() != "Test Game" {
+		t.Errorf("GameTitle() = %q, want %q", info.GameTitle(), "Test Game")
+	}
+	if info.ISOFileSize != 3*testChunkSize {
+		t.Errorf("ISOFileSize = %d, want %d", info.ISOFileSize, 3*testChunkSize)
+	}
+}
+
+func TestReader_Info(t *testing.T) {
+	file, _ := makeGameCubeImage(t, "RVZ\x01", CompressionZstandard)
+
+	reader, err := NewReader(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	info, err := reader.Info()
+	if err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if info.GameTitle() != "Test Game" {
+		t.Errorf("GameTitle() = %q, want %q", info.GameTitle(), "Test Game")
+	}
+	if info.DiscType != DiscTypeGameCube {
+		t.Errorf("DiscType = %v, want %v", info.DiscType, DiscTypeGameCube)
+	}
+}
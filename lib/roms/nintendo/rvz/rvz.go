@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"image"
 	"io"
 
 	"github.com/sargunv/rom-tools/lib/core"
@@ -36,6 +37,11 @@ import (
 //	0x08    4     Compression level (signed for Zstandard)
 //	0x0C    4     Chunk size
 //	0x10    128   dhead[0x80] - First 128 bytes of disc (UNCOMPRESSED!)
+//	...           Partition, raw data and group tables (see reader.go)
+//
+// Wii partitions are stored decrypted and without their hash areas, so Wii
+// RVZ/WIA files cannot be turned back into a disc image whose hashes match
+// Redump DATs. Only GameCube discs can be hashed through Reader.
 
 const (
 	fileHeadSize   = 0x48
@@ -124,8 +130,19 @@ func (i *Info) GameSerial() string { return i.GCM.GameSerial() }
 // GameRegions implements core.GameInfo by delegating to GCM.
 func (i *Info) GameRegions() []core.Region { return i.GCM.GameRegions() }
 
+// GameIcon implements core.IconProvider by delegating to GCM.
+func (i *Info) GameIcon() image.Image { return i.GCM.GameIcon() }
+
 // Parse reads and parses an RVZ/WIA file header.
 func Parse(r io.ReaderAt, size int64) (*Info, error) {
+	// Damaged images without readable tables still report the disc header
+	reader, _ := NewReader(r, size)
+	return parseInfo(r, size, reader)
+}
+
+// parseInfo parses the file header. The disc header is read from reader when
+// it is not nil, and from the copy embedded in the file header otherwise.
+func parseInfo(r io.ReaderAt, size int64, reader *Reader) (*Info, error) {
 	if size < totalHeaderSize {
 		return nil, fmt.Errorf("file too small for RVZ header: need %d bytes, got %d", totalHeaderSize, size)
 	}
@@ -159,10 +176,18 @@ func Parse(r io.ReaderAt, size int64) (*Info, error) {
 	dhead := make([]byte, dheadSize)
 	copy(dhead, header[discStructBase+dheadOffset:])
 
-	// Parse the embedded GCM header
-	gcmInfo, err := gcm.Parse(bytes.NewReader(dhead), int64(len(dhead)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse disc header from RVZ: %w", err)
+	// Parse the reconstructed disc when possible, which also finds GameCube
+	// banners. Otherwise fall back to the embedded disc header.
+	var gcmInfo *gcm.Info
+	if reader != nil {
+		gcmInfo, _ = gcm.Parse(reader, reader.Size())
+	}
+	if gcmInfo == nil {
+		var err error
+		gcmInfo, err = gcm.Parse(bytes.NewReader(dhead), int64(len(dhead)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse disc header from RVZ: %w", err)
+		}
 	}
 
 	return &Info{
@@ -0,0 +1,119 @@
+package vb
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sargunv/rom-tools/internal/util"
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// Nintendo Virtual Boy ROM format parsing.
+//
+// Virtual Boy ROMs store their header just before the interrupt vector table
+// at the end of the ROM (0x...FDE0 in the ROM address space, i.e. ROM size - 0x220).
+//
+// Header specification:
+// https://www.planetvb.com/content/downloads/documents/stsvb.html
+//
+// Header layout (32 bytes at ROM size - 0x220):
+//
+//	Offset  Size  Description
+//	0x00    20    Game title (Shift-JIS, space-padded)
+//	0x14    5     Reserved (zero)
+//	0x19    2     Maker code (ASCII, e.g., "01" for Nintendo)
+//	0x1B    4     Game code (ASCII, e.g., "VMTJ")
+//	0x1F    1     Version (1.x, e.g., 0x00 = 1.0)
+//
+// Game code breakdown (4 bytes at 0x1B):
+//   - Byte 0: Always 'V'
+//   - Bytes 1-2: Unique game identifier
+//   - Byte 3: Destination (J=Japan, E=USA)
+
+const (
+	vbHeaderFromEnd    = 0x220
+	vbHeaderSize       = 0x20
+	vbTitleOffset      = 0x00
+	vbTitleLen         = 20
+	vbMakerCodeOffset  = 0x19
+	vbMakerCodeLen     = 2
+	vbGameCodeOffset   = 0x1B
+	vbGameCodeLen      = 4
+	vbVersionOffset    = 0x1F
+	vbGameCodePrefix   = 'V'
+	vbMinROMSize       = 0x400
+	vbMaxROMSize       = 16 * 1024 * 1024
+	vbDestinationIndex = 3
+)
+
+// Destination represents the target region from the fourth byte of the game code.
+type Destination byte
+
+// Destination values.
+const (
+	DestinationJapan  Destination = 'J'
+	DestinationUSA    Destination = 'E'
+	DestinationEurope Destination = 'P'
+)
+
+// Info contains metadata extracted from a Virtual Boy ROM header.
+type Info struct {
+	// Title is the game title (up to 20 characters, Shift-JIS decoded).
+	Title string `json:"title,omitempty"`
+	// MakerCode is the 2-character publisher code.
+	MakerCode string `json:"maker_code,omitempty"`
+	// GameCode is the 4-character game code (e.g., "VMTJ").
+	GameCode string `json:"game_code,omitempty"`
+	// Destination is the target region from byte 3 of GameCode.
+	Destination Destination `json:"destination"`
+	// Version is the minor version number (1.Version).
+	Version int `json:"version"`
+}
+
+// GamePlatform implements core.GameInfo.
+func (i *Info) GamePlatform() core.Platform { return core.PlatformVirtualBoy }
+
+// GameTitle implements core.GameInfo.
+func (i *Info) GameTitle() string { return i.Title }
+
+// GameSerial implements core.GameInfo.
+func (i *Info) GameSerial() string { return i.GameCode }
+
+// GameRegions implements core.GameInfo.
+func (i *Info) GameRegions() []core.Region {
+	switch i.Destination {
+	case DestinationJapan:
+		return []core.Region{core.RegionJapan}
+	case DestinationUSA:
+		return []core.Region{core.RegionUSA}
+	case DestinationEurope:
+		return []core.Region{core.RegionEurope}
+	default:
+		return []core.Region{}
+	}
+}
+
+// Parse extracts game information from a Virtual Boy ROM file.
+func Parse(r io.ReaderAt, size int64) (*Info, error) {
+	if size < vbMinROMSize || size > vbMaxROMSize {
+		return nil, fmt.Errorf("invalid Virtual Boy ROM size: %d bytes", size)
+	}
+
+	header := make([]byte, vbHeaderSize)
+	if _, err := r.ReadAt(header, size-vbHeaderFromEnd); err != nil {
+		return nil, fmt.Errorf("failed to read Virtual Boy header: %w", err)
+	}
+
+	gameCode := util.ExtractASCII(header[vbGameCodeOffset : vbGameCodeOffset+vbGameCodeLen])
+	if len(gameCode) != vbGameCodeLen || gameCode[0] != vbGameCodePrefix {
+		return nil, fmt.Errorf("not a valid Virtual Boy ROM: invalid game code %q", gameCode)
+	}
+
+	return &Info{
+		Title:       util.ExtractShiftJIS(header[vbTitleOffset : vbTitleOffset+vbTitleLen]),
+		MakerCode:   util.ExtractASCII(header[vbMakerCodeOffset : vbMakerCodeOffset+vbMakerCodeLen]),
+		GameCode:    gameCode,
+		Destination: Destination(gameCode[vbDestinationIndex]),
+		Version:     int(header[vbVersionOffset]),
+	}, nil
+}
@@ -0,0 +1,79 @@
+package vb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// makeTestROM creates a minimal ROM with the given header fields.
+func makeTestROM(size int, title, makerCode, gameCode string, version byte) []byte {
+	rom := make([]byte, size)
+	header := rom[size-vbHeaderFromEnd:]
+	copy(header[vbTitleOffset:], title)
+	copy(header[vbMakerCodeOffset:], makerCode)
+	copy(header[vbGameCodeOffset:], gameCode)
+	header[vbVersionOffset] = version
+	return rom
+}
+
+func TestParse(t *testing.T) {
+	rom := makeTestROM(0x1000, "MARIO'S TENNIS      ", "01", "VMTJ", 0x00)
+
+	info, err := Parse(bytes.NewReader(rom), int64(len(rom)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.GamePlatform() != core.PlatformVirtualBoy {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformVirtualBoy)
+	}
+	if info.GameTitle() != "MARIO'S TENNIS" {
+		t.Errorf("GameTitle() = %q, want %q", info.GameTitle(), "MARIO'S TENNIS")
+	}
+	if info.GameSerial() != "VMTJ" {
+		t.Errorf("GameSerial() = %q, want %q", info.GameSerial(), "VMTJ")
+	}
+	if info.MakerCode != "01" {
+		t.Errorf("MakerCode = %q, want %q", info.MakerCode, "01")
+	}
+	if info.Version != 0 {
+		t.Errorf("Version = %d, want 0", info.Version)
+	}
+	if regions := info.GameRegions(); len(regions) != 1 || regions[0] != core.RegionJapan {
+		t.Errorf("GameRegions() = %v, want [%v]", regions, core.RegionJapan)
+	}
+}
+
+func TestParse_USA(t *testing.T) {
+	rom := makeTestROM(0x1000, "WARIO LAND", "01", "VWCE", 0x01)
+
+	info, err := Parse(bytes.NewReader(rom), int64(len(rom)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.Version != 1 {
+		t.Errorf("Version = %d, want 1", info.Version)
+	}
+	if regions := info.GameRegions(); len(regions) != 1 || regions[0] != core.RegionUSA {
+		t.Errorf("GameRegions() = %v, want [%v]", regions, core.RegionUSA)
+	}
+}
+
+func TestParse_InvalidGameCode(t *testing.T) {
+	rom := makeTestROM(0x1000, "NOT A VB ROM", "01", "ABCD", 0x00)
+
+	if _, err := Parse(bytes.NewReader(rom), int64(len(rom))); err == nil {
+		t.Error("Parse() expected error for invalid game code")
+	}
+}
+
+func TestParse_TooSmall(t *testing.T) {
+	rom := make([]byte, 0x100)
+
+	if _, err := Parse(bytes.NewReader(rom), int64(len(rom))); err == nil {
+		t.Error("Parse() expected error for small file")
+	}
+}
@@ -0,0 +1,193 @@
+package wbfs
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/roms/nintendo/gcm"
+)
+
+// WBFS (Wii Backup File System) disc image format parsing.
+//
+// Format reference:
+// https://github.com/dolphin-emu/dolphin/blob/master/Source/Core/DiscIO/WbfsBlob.cpp
+//
+// WBFS header (big-endian, first HD sector):
+//
+//	Offset  Size  Description
+//	0x00    4     Magic ("WBFS")
+//	0x04    4     Number of HD sectors
+//	0x08    1     HD sector size shift (log2)
+//	0x09    1     WBFS sector size shift (log2)
+//	0x0A    2     Padding
+//	0x0C    ...   Disc table (1 byte per disc slot)
+//
+// The disc info of the first disc starts at the second HD sector:
+//
+//	Offset  Size       Description
+//	0x000   0x100      Copy of the disc header
+//	0x100   2 * n      WBFS sector table (big-endian, 0 = sector unused)
+//
+// n is the number of WBFS sectors needed to cover a dual-layer Wii disc.
+// Each table entry gives the WBFS sector in the file holding that part of the
+// disc. Unused sectors read as zeros. Only the first disc of a file is read,
+// and split files (.wbf1, .wbf2, ...) are not supported.
+
+const (
+	magic            = "WBFS"
+	headerSize       = 0x0C
+	hdSecShiftOffset = 0x08
+	wbfsShiftOffset  = 0x09
+	discHeaderCopy   = 0x100
+
+	wiiSectorSize  = 0x8000
+	wiiSectorCount = 143432 * 2 // Dual-layer disc
+
+	minShift = 9  // 512-byte HD sectors
+	maxShift = 31 // Largest sector size that fits the table
+)
+
+// Reader provides random access to the original disc inside a WBFS file.
+type Reader struct {
+	r          io.ReaderAt
+	fileSize   int64
+	sectorSize int64
+	table      []uint16
+	size       int64
+}
+
+// NewReader opens a WBFS file.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	if size < headerSize {
+		return nil, fmt.Errorf("file too small for WBFS header: %d bytes", size)
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read WBFS header: %w", err)
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, fmt.Errorf("not a valid WBFS file: invalid magic %q", header[:len(magic)])
+	}
+
+	hdShift := uint(header[hdSecShiftOffset])
+	wbfsShift := uint(header[wbfsShiftOffset])
+	if hdShift < minShift || hdShift > maxShift || wbfsShift < hdShift || wbfsShift > maxShift {
+		return nil, fmt.Errorf("invalid WBFS sector sizes: HD 2^%d, WBFS 2^%d", hdShift, wbfsShift)
+	}
+	hdSectorSize := int64(1) << hdShift
+	sectorSize := int64(1) << wbfsShift
+
+	discSize := int64(wiiSectorCount) * wiiSectorSize
+	count := (discSize + sectorSize - 1) / sectorSize
+
+	tableData := make([]byte, count*2)
+	if _, err := r.ReadAt(tableData, hdSectorSize+discHeaderCopy); err != nil {
+		return nil, fmt.Errorf("failed to read WBFS sector table: %w", err)
+	}
+	table := make([]uint16, count)
+	lastUsed := -1
+	for i := range table {
+		table[i] = binary.BigEndian.Uint16(tableData[i*2:])
+		if table[i] != 0 {
+			if (int64(table[i])+1)*sectorSize > size {
+				return nil, fmt.Errorf("WBFS sector %d extends beyond file", table[i])
+			}
+			lastUsed = i
+		}
+	}
+	if lastUsed < 0 {
+		return nil, fmt.Errorf("WBFS file has no disc data")
+	}
+
+	reader := &Reader{
+		r:          r,
+		fileSize:   size,
+		sectorSize: sectorSize,
+		table:      table,
+		size:       discSize,
+	}
+
+	// Trailing unused sectors are not recorded; WBFS only holds Wii discs
+	reader.size = gcm.StandardDiscSize(true, int64(lastUsed+1)*sectorSize)
+	return reader, nil
+}
+
+// Size returns the size of the original disc.
+func (w *Reader) Size() int64 {
+	return w.size
+}
+
+// ReadAt implements io.ReaderAt over the original disc.
+func (w *Reader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset")
+	}
+
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= w.size {
+			return n, io.EOF
+		}
+
+		index := pos / w.sectorSize
+		offsetInSector := pos % w.sectorSize
+		chunk := p[n:min(len(p), n+int(w.sectorSize-offsetInSector), n+int(w.size-pos))]
+
+		if sector := w.table[index]; sector == 0 {
+			clear(chunk)
+		} else if _, err := w.r.ReadAt(chunk, int64(sector)*w.sectorSize+offsetInSector); err != nil {
+			return n, fmt.Errorf("failed to read WBFS sector %d: %w", sector, err)
+		}
+		n += len(chunk)
+	}
+	return n, nil
+}
+
+// Info contains metadata extracted from a WBFS file.
+type Info struct {
+	// GCM contains the game identification info parsed from the disc.
+	GCM *gcm.Info `json:"gcm,omitempty"`
+	// SectorSize is the WBFS sector size.
+	SectorSize int64 `json:"sector_size"`
+	// DiscSize is the size of the original disc.
+	DiscSize int64 `json:"disc_size"`
+}
+
+// GamePlatform implements core.GameInfo by delegating to GCM.
+func (i *Info) GamePlatform() core.Platform { return i.GCM.GamePlatform() }
+
+// GameTitle implements core.GameInfo by delegating to GCM.
+func (i *Info) GameTitle() string { return i.GCM.GameTitle() }
+
+// GameSerial implements core.GameInfo by delegating to GCM.
+func (i *Info) GameSerial() string { return i.GCM.GameSerial() }
+
+// GameRegions implements core.GameInfo by delegating to GCM.
+func (i *Info) GameRegions() []core.Region { return i.GCM.GameRegions() }
+
+// GameIcon implements core.IconProvider by delegating to GCM.
+func (i *Info) GameIcon() image.Image { return i.GCM.GameIcon() }
+
+// Parse opens a WBFS file and parses the disc header of the original disc.
+func Parse(r io.ReaderAt, size int64) (*Info, error) {
+	reader, err := NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	gcmInfo, err := gcm.Parse(reader, reader.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse disc header from WBFS: %w", err)
+	}
+
+	return &Info{
+		GCM:        gcmInfo,
+		SectorSize: reader.sectorSize,
+		DiscSize:   reader.Size(),
+	}, nil
+}
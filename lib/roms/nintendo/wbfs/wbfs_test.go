@@ -0,0 +1,138 @@
+package wbfs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/roms/nintendo/gcm"
+)
+
+const (
+	testHDShift   = 9  // 512-byte HD sectors
+	testWBFSShift = 15 // 32 KiB WBFS sectors
+)
+
+// makeSyntheticDiscHeader creates a synthetic Wii disc header for testing.
+func makeSyntheticDiscHeader(gameID string, title string) []byte {
+	header := make([]byte, 0x400)
+	copy(header, gameID)
+	copy(header[0x04:], "01")
+	binary.BigEndian.PutUint32(header[0x18:], 0x5D1C9EA3)
+	copy(header[0x20:], title)
+	return header
+}
+
+// makeSyntheticWBFS builds a WBFS file holding one disc. Each entry of
+// sectors is the content of a disc sector, or nil for an unused sector.
+// Used sectors are stored in reverse order to exercise the sector table.
+func makeSyntheticWBFS(sectors [][]byte) []byte {
+	sectorSize := 1 << testWBFSShift
+	count := wiiSectorCount * wiiSectorSize / sectorSize
+
+	// WBFS sector 0 holds the headers and the disc table
+	file := make([]byte, sectorSize)
+	copy(file, magic)
+	file[hdSecShiftOffset] = testHDShift
+	file[wbfsShiftOffset] = testWBFSShift
+
+	tableOffset := (1 << testHDShift) + discHeaderCopy
+	if len(sectors) > 0 {
+		copy(file[1<<testHDShift:], sectors[0])
+	}
+	file = append(file, make([]byte, (tableOffset+count*2+sectorSize-1)/sectorSize*sectorSize-len(file))...)
+
+	next := uint16(len(file) / sectorSize)
+	for i := len(sectors) - 1; i >= 0; i-- {
+		if sectors[i] == nil {
+			continue
+		}
+		binary.BigEndian.PutUint16(file[tableOffset+i*2:], next)
+		data := make([]byte, sectorSize)
+		copy(data, sectors[i])
+		file = append(file, data...)
+		next++
+	}
+	return file
+}
+
+func TestParse(t *testing.T) {
+	file := makeSyntheticWBFS([][]byte{makeSyntheticDiscHeader("RSBE", "Test Wii Game")})
+
+	info, err := Parse(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.GamePlatform() != core.PlatformWii {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformWii)
+	}
+	if info.GameTitle() != "Test Wii Game" {
+		t.Errorf("GameTitle() = %q, want %q", info.GameTitle(), "Test Wii Game")
+	}
+	if info.GameSerial() != "RSBE" {
+		t.Errorf("GameSerial() = %q, want %q", info.GameSerial(), "RSBE")
+	}
+	if info.SectorSize != 1<<testWBFSShift {
+		t.Errorf("SectorSize = %d, want %d", info.SectorSize, 1<<testWBFSShift)
+	}
+	if info.DiscSize != gcm.WiiSingleLayerDiscSize {
+		t.Errorf("DiscSize = %d, want %d", info.DiscSize, gcm.WiiSingleLayerDiscSize)
+	}
+}
+
+func TestReader_ReadAt(t *testing.T) {
+	sectorSize := int64(1) << testWBFSShift
+	header := makeSyntheticDiscHeader("RSBE", "Test")
+	third := bytes.Repeat([]byte{0xAA}, int(sectorSize))
+	file := makeSyntheticWBFS([][]byte{header, nil, third})
+
+	reader, err := NewReader(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	got := make([]byte, 0x60)
+	if _, err := reader.ReadAt(got, 0); err != nil {
+		t.Fatalf("ReadAt() error = %v", err)
+	}
+	if !bytes.Equal(got, header[:0x60]) {
+		t.Errorf("ReadAt() header = %X, want %X", got, header[:0x60])
+	}
+
+	// Unused sector reads as zeros, then remapped data follows
+	got = make([]byte, 0x20)
+	if _, err := reader.ReadAt(got, 2*sectorSize-0x10); err != nil {
+		t.Fatalf("ReadAt() error = %v", err)
+	}
+	want := append(make([]byte, 0x10), bytes.Repeat([]byte{0xAA}, 0x10)...)
+	if !bytes.Equal(got, want) {
+		t.Errorf("ReadAt() across sector boundary = %X, want %X", got, want)
+	}
+}
+
+func TestParse_InvalidMagic(t *testing.T) {
+	file := make([]byte, 0x1000)
+	if _, err := Parse(bytes.NewReader(file), int64(len(file))); err == nil {
+		t.Error("Parse() expected error for invalid magic")
+	}
+}
+
+func TestParse_NoDisc(t *testing.T) {
+	file := makeSyntheticWBFS(nil)
+	if _, err := Parse(bytes.NewReader(file), int64(len(file))); err == nil {
+		t.Error("Parse() expected error for WBFS without disc data")
+	}
+}
+
+func TestNewReader_SectorBeyondFile(t *testing.T) {
+	file := makeSyntheticWBFS([][]byte{makeSyntheticDiscHeader("RSPE", "Test")})
+
+	// The last possible sector must not wrap around when checked against the file size
+	tableOffset := (1 << testHDShift) + discHeaderCopy
+	binary.BigEndian.PutUint16(file[tableOffset:], 0xFFFF)
+	if _, err := NewReader(bytes.NewReader(file), int64(len(file))); err == nil {
+		t.Error("NewReader() expected error for sector beyond end of file")
+	}
+}
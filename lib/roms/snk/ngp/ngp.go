@@ -0,0 +1,113 @@
+package ngp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sargunv/rom-tools/internal/util"
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// SNK Neo Geo Pocket / Neo Geo Pocket Color ROM format parsing.
+//
+// Header specification:
+// https://www.devrs.com/ngp/files/ngpctech.txt
+//
+// Header layout (64 bytes at offset 0x00):
+//
+//	Offset  Size  Description
+//	0x00    28    Copyright string ("COPYRIGHT BY SNK CORPORATION" or " LICENSED BY SNK CORPORATION")
+//	0x1C    4     Entry point (little-endian)
+//	0x20    2     Game ID (little-endian)
+//	0x22    1     Version
+//	0x23    1     Color flag (0x00 = Neo Geo Pocket, 0x10 = Neo Geo Pocket Color)
+//	0x24    12    Title (ASCII, space/null-padded)
+//	0x30    16    Reserved
+
+const (
+	ngpHeaderSize      = 64
+	ngpCopyrightOffset = 0x00
+	ngpCopyrightLen    = 28
+	ngpEntryOffset     = 0x1C
+	ngpGameIDOffset    = 0x20
+	ngpVersionOffset   = 0x22
+	ngpColorOffset     = 0x23
+	ngpTitleOffset     = 0x24
+	ngpTitleLen        = 12
+
+	ngpColorFlag = 0x10
+)
+
+// Copyright strings identifying first-party and licensed games.
+const (
+	CopyrightSNK      = "COPYRIGHT BY SNK CORPORATION"
+	CopyrightLicensed = " LICENSED BY SNK CORPORATION"
+)
+
+// Info contains metadata extracted from a Neo Geo Pocket ROM header.
+type Info struct {
+	// Copyright is the copyright string (0x00).
+	Copyright string `json:"copyright,omitempty"`
+	// Licensed indicates a third-party game ("LICENSED BY SNK CORPORATION").
+	Licensed bool `json:"licensed"`
+	// EntryPoint is the program entry point address (0x1C).
+	EntryPoint uint32 `json:"entry_point"`
+	// GameID is the software ID (0x20).
+	GameID uint16 `json:"game_id"`
+	// Version is the software version (0x22).
+	Version int `json:"version"`
+	// Color indicates a Neo Geo Pocket Color game (0x23).
+	Color bool `json:"color"`
+	// Title is the game title (0x24, up to 12 ASCII characters).
+	Title string `json:"title,omitempty"`
+}
+
+// GamePlatform implements core.GameInfo.
+func (i *Info) GamePlatform() core.Platform {
+	if i.Color {
+		return core.PlatformNGPC
+	}
+	return core.PlatformNGP
+}
+
+// GameTitle implements core.GameInfo.
+func (i *Info) GameTitle() string { return i.Title }
+
+// GameSerial implements core.GameInfo.
+// The header only has a numeric game ID, not the printed serial.
+func (i *Info) GameSerial() string { return "" }
+
+// GameRegions implements core.GameInfo.
+// Neo Geo Pocket ROMs are region-free; the system language selects the region.
+func (i *Info) GameRegions() []core.Region {
+	return []core.Region{}
+}
+
+// Parse extracts game information from a Neo Geo Pocket or Neo Geo Pocket Color ROM file.
+func Parse(r io.ReaderAt, size int64) (*Info, error) {
+	if size < ngpHeaderSize {
+		return nil, fmt.Errorf("file too small for NGP header: %d bytes", size)
+	}
+
+	header := make([]byte, ngpHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read NGP header: %w", err)
+	}
+
+	copyright := string(header[ngpCopyrightOffset : ngpCopyrightOffset+ngpCopyrightLen])
+	if copyright != CopyrightSNK && copyright != CopyrightLicensed {
+		return nil, fmt.Errorf("not a valid NGP ROM: invalid copyright string %q", copyright)
+	}
+
+	return &Info{
+		Copyright:  strings.TrimSpace(copyright),
+		Licensed:   copyright == CopyrightLicensed,
+		EntryPoint: binary.LittleEndian.Uint32(header[ngpEntryOffset:]),
+		GameID:     binary.LittleEndian.Uint16(header[ngpGameIDOffset:]),
+		Version:    int(header[ngpVersionOffset]),
+		Color:      header[ngpColorOffset] == ngpColorFlag,
+		Title:      util.ExtractASCII(header[ngpTitleOffset : ngpTitleOffset+ngpTitleLen]),
+	}, nil
+}
@@ -0,0 +1,73 @@
+package ngp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+// makeTestROM creates a minimal ROM with the given header fields.
+func makeTestROM(copyright string, gameID uint16, version, color byte, title string) []byte {
+	rom := make([]byte, 0x100)
+	copy(rom[ngpCopyrightOffset:], copyright)
+	binary.LittleEndian.PutUint32(rom[ngpEntryOffset:], 0x00200040)
+	binary.LittleEndian.PutUint16(rom[ngpGameIDOffset:], gameID)
+	rom[ngpVersionOffset] = version
+	rom[ngpColorOffset] = color
+	copy(rom[ngpTitleOffset:], title)
+	return rom
+}
+
+func TestParse_Color(t *testing.T) {
+	rom := makeTestROM(CopyrightSNK, 0x0061, 0x01, ngpColorFlag, "SONIC")
+
+	info, err := Parse(bytes.NewReader(rom), int64(len(rom)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.GamePlatform() != core.PlatformNGPC {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformNGPC)
+	}
+	if info.GameTitle() != "SONIC" {
+		t.Errorf("GameTitle() = %q, want %q", info.GameTitle(), "SONIC")
+	}
+	if info.GameID != 0x0061 || info.Version != 1 {
+		t.Errorf("GameID, Version = 0x%04X, %d, want 0x0061, 1", info.GameID, info.Version)
+	}
+	if info.EntryPoint != 0x00200040 {
+		t.Errorf("EntryPoint = 0x%08X, want 0x00200040", info.EntryPoint)
+	}
+	if info.Licensed {
+		t.Error("Licensed = true, want false")
+	}
+}
+
+func TestParse_LicensedMono(t *testing.T) {
+	rom := makeTestROM(CopyrightLicensed, 0x0001, 0x00, 0x00, "MELONCHAN")
+
+	info, err := Parse(bytes.NewReader(rom), int64(len(rom)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.GamePlatform() != core.PlatformNGP {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformNGP)
+	}
+	if !info.Licensed {
+		t.Error("Licensed = false, want true")
+	}
+	if info.Copyright != "LICENSED BY SNK CORPORATION" {
+		t.Errorf("Copyright = %q, want %q", info.Copyright, "LICENSED BY SNK CORPORATION")
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	rom := makeTestROM("NOT A NEO GEO POCKET ROM....", 0, 0, 0, "")
+
+	if _, err := Parse(bytes.NewReader(rom), int64(len(rom))); err == nil {
+		t.Error("Parse() expected error for invalid copyright, got nil")
+	}
+}
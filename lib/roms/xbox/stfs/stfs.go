@@ -0,0 +1,244 @@
+package stfs
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"unicode/utf16"
+
+	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/roms/xbox/titleid"
+)
+
+// Xbox 360 STFS (Secure Transacted File System) package header parsing.
+//
+// STFS specification:
+// https://free60.org/System-Software/Formats/STFS/
+//
+// STFS packages are used for Xbox Live Arcade titles, Games on Demand (GOD),
+// title updates, DLC, and saves. GOD images use the same header with an SVOD
+// volume descriptor; the header file sits at <TitleID>/00007000/<ContentID>.
+//
+// Header layout (big-endian, relevant fields):
+//
+//	Offset  Size    Description
+//	0x0000  4       Magic ("CON ", "LIVE" or "PIRS")
+//	0x0344  4       Content type
+//	0x0348  4       Metadata version
+//	0x034C  8       Content size
+//	0x0354  4       Media ID
+//	0x0358  4       Version
+//	0x035C  4       Base version
+//	0x0360  4       Title ID
+//	0x0364  1       Platform
+//	0x0365  1       Executable type
+//	0x0366  1       Disc number
+//	0x0367  1       Disc in set
+//	0x0368  4       Save game ID
+//	0x03A9  4       Volume descriptor type (0=STFS, 1=SVOD)
+//	0x0411  0x900   Display names (18 locales x 0x80 bytes, UTF-16BE)
+//	0x0D11  0x900   Display descriptions (18 locales x 0x80 bytes, UTF-16BE)
+//	0x1611  0x80    Publisher name (UTF-16BE)
+//	0x1691  0x80    Title name (UTF-16BE)
+
+const (
+	stfsContentTypeOff    = 0x0344
+	stfsMetadataVerOff    = 0x0348
+	stfsContentSizeOff    = 0x034C
+	stfsMediaIDOff        = 0x0354
+	stfsVersionOff        = 0x0358
+	stfsBaseVersionOff    = 0x035C
+	stfsTitleIDOff        = 0x0360
+	stfsPlatformOff       = 0x0364
+	stfsExecutableTypeOff = 0x0365
+	stfsDiscNumberOff     = 0x0366
+	stfsDiscInSetOff      = 0x0367
+	stfsSaveGameIDOff     = 0x0368
+	stfsDescriptorTypeOff = 0x03A9
+	stfsDisplayNameOff    = 0x0411
+	stfsDescriptionOff    = 0x0D11
+	stfsPublisherOff      = 0x1611
+	stfsTitleNameOff      = 0x1691
+	stfsStringLen         = 0x80
+	stfsHeaderSize        = stfsTitleNameOff + stfsStringLen
+)
+
+// Magic identifies the signing type of an STFS package.
+type Magic string
+
+const (
+	MagicCON  Magic = "CON " // Console-signed (saves, GOD images)
+	MagicLIVE Magic = "LIVE" // Signed by Xbox Live (XBLA, DLC)
+	MagicPIRS Magic = "PIRS" // Signed by Microsoft, not from Xbox Live (title updates)
+)
+
+// ContentType indicates the kind of content in the package.
+type ContentType uint32
+
+// ContentType values per Free60.
+const (
+	ContentTypeSavedGame          ContentType = 0x00000001
+	ContentTypeMarketplaceContent ContentType = 0x00000002
+	ContentTypePublisher          ContentType = 0x00000003
+	ContentTypeXbox360Title       ContentType = 0x00001000
+	ContentTypeInstalledGame      ContentType = 0x00004000
+	ContentTypeXboxOriginalGame   ContentType = 0x00005000
+	ContentTypeGameOnDemand       ContentType = 0x00007000
+	ContentTypeAvatarItem         ContentType = 0x00009000
+	ContentTypeProfile            ContentType = 0x00010000
+	ContentTypeGamerPicture       ContentType = 0x00020000
+	ContentTypeTheme              ContentType = 0x00030000
+	ContentTypeStorageDownload    ContentType = 0x00050000
+	ContentTypeXboxSavedGame      ContentType = 0x00060000
+	ContentTypeXboxDownload       ContentType = 0x00070000
+	ContentTypeGameDemo           ContentType = 0x00080000
+	ContentTypeVideo              ContentType = 0x00090000
+	ContentTypeGameTitle          ContentType = 0x000A0000
+	ContentTypeInstaller          ContentType = 0x000B0000
+	ContentTypeGameTrailer        ContentType = 0x000C0000
+	ContentTypeArcadeTitle        ContentType = 0x000D0000
+	ContentTypeXNA                ContentType = 0x000E0000
+	ContentTypeLicenseStore       ContentType = 0x000F0000
+	ContentTypeMovie              ContentType = 0x00100000
+	ContentTypeCommunityGame      ContentType = 0x02000000
+)
+
+// DescriptorType indicates how the package data is stored.
+type DescriptorType uint32
+
+const (
+	DescriptorSTFS DescriptorType = 0 // Data stored in the package file itself
+	DescriptorSVOD DescriptorType = 1 // Data stored in a separate .data directory (GOD)
+)
+
+// Info contains metadata extracted from an STFS package header.
+type Info struct {
+	// Magic is the package signing type.
+	Magic Magic `json:"magic"`
+	// ContentType indicates the kind of content in the package.
+	ContentType ContentType `json:"content_type"`
+	// MetadataVersion is the header metadata version.
+	MetadataVersion uint32 `json:"metadata_version"`
+	// ContentSize is the size of the package content in bytes.
+	ContentSize uint64 `json:"content_size"`
+	// DescriptorType indicates whether the package uses STFS or SVOD storage.
+	DescriptorType DescriptorType `json:"descriptor_type"`
+	// TitleID is the numeric title ID.
+	TitleID uint32 `json:"title_id"`
+	// TitleIDHex is the title ID as an 8-character hex string.
+	TitleIDHex string `json:"title_id_hex,omitempty"`
+	// PublisherCode is the 2-character publisher code from title ID.
+	PublisherCode string `json:"publisher_code,omitempty"`
+	// GameNumber is the game number from title ID.
+	GameNumber uint16 `json:"game_number"`
+	// MediaID is the media ID of the content.
+	MediaID uint32 `json:"media_id"`
+	// Version is the raw content version.
+	Version uint32 `json:"version"`
+	// BaseVersion is the raw base version.
+	BaseVersion uint32 `json:"base_version"`
+	// Platform is the target platform byte.
+	Platform byte `json:"platform"`
+	// ExecutableType is the executable type byte.
+	ExecutableType byte `json:"executable_type"`
+	// DiscNumber is the disc number for multi-disc games.
+	DiscNumber int `json:"disc_number"`
+	// DiscInSet is the total number of discs.
+	DiscInSet int `json:"disc_in_set"`
+	// SaveGameID is the save game ID.
+	SaveGameID uint32 `json:"save_game_id"`
+	// DisplayName is the English display name of the content.
+	DisplayName string `json:"display_name,omitempty"`
+	// Description is the English display description of the content.
+	Description string `json:"description,omitempty"`
+	// Publisher is the publisher name.
+	Publisher string `json:"publisher,omitempty"`
+	// TitleName is the name of the title the content belongs to.
+	TitleName string `json:"title_name,omitempty"`
+}
+
+// GamePlatform implements core.GameInfo.
+func (i *Info) GamePlatform() core.Platform { return core.PlatformXbox360 }
+
+// GameTitle implements core.GameInfo.
+// Prefers the title name, falling back to the display name.
+func (i *Info) GameTitle() string {
+	if i.TitleName != "" {
+		return i.TitleName
+	}
+	return i.DisplayName
+}
+
+// GameSerial implements core.GameInfo. Returns serial in "XX-###" format,
+// like Xbox XBE serials.
+func (i *Info) GameSerial() string {
+	if i.TitleID == 0 {
+		return ""
+	}
+	return titleid.Serial(i.TitleID)
+}
+
+// GameRegions implements core.GameInfo.
+// STFS headers do not contain region information.
+func (i *Info) GameRegions() []core.Region { return []core.Region{} }
+
+// Parse extracts metadata from an STFS package header.
+func Parse(r io.ReaderAt, size int64) (*Info, error) {
+	if size < stfsHeaderSize {
+		return nil, fmt.Errorf("file too small for STFS header: %d bytes (need at least %d)", size, stfsHeaderSize)
+	}
+
+	header := make([]byte, stfsHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read STFS header: %w", err)
+	}
+
+	magic := Magic(header[:4])
+	if magic != MagicCON && magic != MagicLIVE && magic != MagicPIRS {
+		return nil, fmt.Errorf("not a valid STFS package: invalid magic %q", string(magic))
+	}
+
+	titleID := binary.BigEndian.Uint32(header[stfsTitleIDOff:])
+	publisherCode, gameNumber := titleid.Decode(titleID)
+
+	return &Info{
+		Magic:           magic,
+		ContentType:     ContentType(binary.BigEndian.Uint32(header[stfsContentTypeOff:])),
+		MetadataVersion: binary.BigEndian.Uint32(header[stfsMetadataVerOff:]),
+		ContentSize:     binary.BigEndian.Uint64(header[stfsContentSizeOff:]),
+		DescriptorType:  DescriptorType(binary.BigEndian.Uint32(header[stfsDescriptorTypeOff:])),
+		TitleID:         titleID,
+		TitleIDHex:      fmt.Sprintf("%08X", titleID),
+		PublisherCode:   publisherCode,
+		GameNumber:      gameNumber,
+		MediaID:         binary.BigEndian.Uint32(header[stfsMediaIDOff:]),
+		Version:         binary.BigEndian.Uint32(header[stfsVersionOff:]),
+		BaseVersion:     binary.BigEndian.Uint32(header[stfsBaseVersionOff:]),
+		Platform:        header[stfsPlatformOff],
+		ExecutableType:  header[stfsExecutableTypeOff],
+		DiscNumber:      int(header[stfsDiscNumberOff]),
+		DiscInSet:       int(header[stfsDiscInSetOff]),
+		SaveGameID:      binary.BigEndian.Uint32(header[stfsSaveGameIDOff:]),
+		DisplayName:     decodeUTF16BE(header[stfsDisplayNameOff : stfsDisplayNameOff+stfsStringLen]),
+		Description:     decodeUTF16BE(header[stfsDescriptionOff : stfsDescriptionOff+stfsStringLen]),
+		Publisher:       decodeUTF16BE(header[stfsPublisherOff : stfsPublisherOff+stfsStringLen]),
+		TitleName:       decodeUTF16BE(header[stfsTitleNameOff : stfsTitleNameOff+stfsStringLen]),
+	}, nil
+}
+
+// decodeUTF16BE decodes a null-terminated UTF-16BE string.
+func decodeUTF16BE(data []byte) string {
+	u16s := make([]uint16, len(data)/2)
+	for i := range u16s {
+		u16s[i] = binary.BigEndian.Uint16(data[i*2:])
+	}
+
+	for i, v := range u16s {
+		if v == 0 {
+			u16s = u16s[:i]
+			break
+		}
+	}
+
+	return string(utf16.Decode(u16s))
+}
@@ -0,0 +1,99 @@
+package stfs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/sargunv/rom-tools/lib/core"
+)
+
+func putUTF16BE(buf []byte, s string) {
+	for i, u := range utf16.Encode([]rune(s)) {
+		binary.BigEndian.PutUint16(buf[i*2:], u)
+	}
+}
+
+// makeTestSTFS creates a minimal STFS header.
+func makeTestSTFS(magic Magic, contentType ContentType, titleID uint32, displayName, titleName string) []byte {
+	buf := make([]byte, stfsHeaderSize)
+
+	copy(buf[0:], magic)
+	binary.BigEndian.PutUint32(buf[stfsContentTypeOff:], uint32(contentType))
+	binary.BigEndian.PutUint32(buf[stfsTitleIDOff:], titleID)
+	binary.BigEndian.PutUint32(buf[stfsMediaIDOff:], 0xCAFEBABE)
+	binary.BigEndian.PutUint32(buf[stfsDescriptorTypeOff:], uint32(DescriptorSVOD))
+	buf[stfsDiscNumberOff] = 1
+	buf[stfsDiscInSetOff] = 1
+	putUTF16BE(buf[stfsDisplayNameOff:], displayName)
+	putUTF16BE(buf[stfsPublisherOff:], "Microsoft")
+	putUTF16BE(buf[stfsTitleNameOff:], titleName)
+
+	return buf
+}
+
+func TestParse_GameOnDemand(t *testing.T) {
+	data := makeTestSTFS(MagicCON, ContentTypeGameOnDemand, 0x4D5307E6, "Halo 3", "Halo 3")
+
+	info, err := Parse(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.GamePlatform() != core.PlatformXbox360 {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformXbox360)
+	}
+	if info.ContentType != ContentTypeGameOnDemand {
+		t.Errorf("ContentType = 0x%08X, want 0x%08X", info.ContentType, ContentTypeGameOnDemand)
+	}
+	if info.DescriptorType != DescriptorSVOD {
+		t.Errorf("DescriptorType = %d, want %d", info.DescriptorType, DescriptorSVOD)
+	}
+	if info.GameTitle() != "Halo 3" {
+		t.Errorf("GameTitle() = %q, want %q", info.GameTitle(), "Halo 3")
+	}
+	if info.GameSerial() != "MS-2022" {
+		t.Errorf("GameSerial() = %q, want %q", info.GameSerial(), "MS-2022")
+	}
+	if info.Publisher != "Microsoft" {
+		t.Errorf("Publisher = %q, want %q", info.Publisher, "Microsoft")
+	}
+	if info.MediaID != 0xCAFEBABE {
+		t.Errorf("MediaID = 0x%08X, want 0xCAFEBABE", info.MediaID)
+	}
+}
+
+func TestParse_ArcadeFallsBackToDisplayName(t *testing.T) {
+	data := makeTestSTFS(MagicLIVE, ContentTypeArcadeTitle, 0x58410A6D, "Braid", "")
+
+	info, err := Parse(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.Magic != MagicLIVE {
+		t.Errorf("Magic = %q, want %q", info.Magic, MagicLIVE)
+	}
+	if info.GameTitle() != "Braid" {
+		t.Errorf("GameTitle() = %q, want %q", info.GameTitle(), "Braid")
+	}
+}
+
+func TestParse_InvalidMagic(t *testing.T) {
+	data := makeTestSTFS("NOPE", ContentTypeArcadeTitle, 0, "", "")
+
+	_, err := Parse(bytes.NewReader(data), int64(len(data)))
+	if err == nil {
+		t.Error("Parse() expected error for invalid magic, got nil")
+	}
+}
+
+func TestParse_TooSmall(t *testing.T) {
+	data := []byte("LIVE")
+
+	_, err := Parse(bytes.NewReader(data), int64(len(data)))
+	if err == nil {
+		t.Error("Parse() expected error for small file, got nil")
+	}
+}
@@ -0,0 +1,20 @@
+// Package titleid decodes Xbox and Xbox 360 title IDs.
+//
+// A title ID's high 16 bits are a 2-character ASCII publisher code and its
+// low 16 bits the game number, e.g. 0x4D530802 is publisher "MS", game 2050.
+// Serials are written as "<publisher code>-<game number>", with the game
+// number padded to at least 3 digits.
+package titleid
+
+import "fmt"
+
+// Decode extracts the publisher code and game number from a title ID.
+func Decode(titleID uint32) (string, uint16) {
+	return string([]byte{byte(titleID >> 24), byte(titleID >> 16)}), uint16(titleID & 0xFFFF)
+}
+
+// Serial returns the serial of a title ID in "XX-###" format.
+func Serial(titleID uint32) string {
+	publisherCode, gameNumber := Decode(titleID)
+	return fmt.Sprintf("%s-%03d", publisherCode, gameNumber)
+}
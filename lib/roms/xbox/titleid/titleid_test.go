@@ -0,0 +1,45 @@
+package titleid
+
+import "testing"
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		titleID       uint32
+		publisherCode string
+		gameNumber    uint16
+	}{
+		{0x4D530001, "MS", 1},   // Microsoft, game 1
+		{0x45410010, "EA", 16},  // EA, game 16
+		{0x53450100, "SE", 256}, // Square Enix, game 256
+		{0x00000000, "\x00\x00", 0},
+		{0xFFFFFFFF, "\xFF\xFF", 65535},
+	}
+
+	for _, tt := range tests {
+		publisherCode, gameNumber := Decode(tt.titleID)
+		if publisherCode != tt.publisherCode {
+			t.Errorf("Decode(0x%08X) publisherCode = %q, want %q",
+				tt.titleID, publisherCode, tt.publisherCode)
+		}
+		if gameNumber != tt.gameNumber {
+			t.Errorf("Decode(0x%08X) gameNumber = %d, want %d",
+				tt.titleID, gameNumber, tt.gameNumber)
+		}
+	}
+}
+
+func TestSerial(t *testing.T) {
+	tests := []struct {
+		titleID uint32
+		want    string
+	}{
+		{0x4D530802, "MS-2050"},
+		{0x4D530064, "MS-100"},
+		{0x54510005, "TQ-005"},
+	}
+	for _, tt := range tests {
+		if got := Serial(tt.titleID); got != tt.want {
+			t.Errorf("Serial(0x%08X) = %q, want %q", tt.titleID, got, tt.want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"unicode/utf16"
 
 	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/roms/xbox/titleid"
 )
 
 // XBE (Xbox Executable) format parsing.
@@ -118,7 +119,7 @@ func (i *Info) GameTitle() string { return i.Title }
 
 // GameSerial implements core.GameInfo. Returns serial in "XX-###" format.
 func (i *Info) GameSerial() string {
-	return fmt.Sprintf("%s-%03d", i.PublisherCode, i.GameNumber)
+	return titleid.Serial(i.TitleID)
 }
 
 // GameRegions implements core.GameInfo.
@@ -197,7 +198,7 @@ func parseXBEAt(r io.ReaderAt, xbeOffset int64, size int64) (*Info, error) {
 	}
 
 	// Decode title ID into publisher code and game number
-	publisherCode, gameNumber := decodeTitleID(titleID)
+	publisherCode, gameNumber := titleid.Decode(titleID)
 
 	return &Info{
 		TitleID:           titleID,
@@ -215,19 +216,6 @@ func parseXBEAt(r io.ReaderAt, xbeOffset int64, size int64) (*Info, error) {
 	}, nil
 }
 
-// decodeTitleID extracts publisher code and game number from a title ID.
-// Title ID format: high 16 bits = publisher code (2 ASCII chars), low 16 bits = game number
-func decodeTitleID(titleID uint32) (string, uint16) {
-	gameNumber := uint16(titleID & 0xFFFF)
-	publisherCode := uint16((titleID >> 16) & 0xFFFF)
-
-	// Publisher code is 2 ASCII chars (big-endian in the high word)
-	char1 := byte((publisherCode >> 8) & 0xFF)
-	char2 := byte(publisherCode & 0xFF)
-
-	return string([]byte{char1, char2}), gameNumber
-}
-
 // decodeUTF16LE decodes a null-terminated UTF-16LE string.
 func decodeUTF16LE(data []byte) string {
 	// Convert bytes to uint16 slice
@@ -155,32 +155,6 @@ func TestParse_TooSmall(t *testing.T) {
 	}
 }
 
-func TestDecodeTitleID(t *testing.T) {
-	tests := []struct {
-		titleID       uint32
-		publisherCode string
-		gameNumber    uint16
-	}{
-		{0x4D530001, "MS", 1},   // Microsoft, game 1
-		{0x45410010, "EA", 16},  // EA, game 16
-		{0x53450100, "SE", 256}, // Square Enix, game 256
-		{0x00000000, "\x00\x00", 0},
-		{0xFFFFFFFF, "\xFF\xFF", 65535},
-	}
-
-	for _, tt := range tests {
-		publisherCode, gameNumber := decodeTitleID(tt.titleID)
-		if publisherCode != tt.publisherCode {
-			t.Errorf("decodeTitleID(0x%08X) publisherCode = %q, want %q",
-				tt.titleID, publisherCode, tt.publisherCode)
-		}
-		if gameNumber != tt.gameNumber {
-			t.Errorf("decodeTitleID(0x%08X) gameNumber = %d, want %d",
-				tt.titleID, gameNumber, tt.gameNumber)
-		}
-	}
-}
-
 func TestDecodeUTF16LE(t *testing.T) {
 	tests := []struct {
 		name     string
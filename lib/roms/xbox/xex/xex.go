@@ -0,0 +1,326 @@
+package xex
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/sargunv/rom-tools/internal/util"
+	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/roms/xbox/titleid"
+	"github.com/sargunv/rom-tools/lib/roms/xbox/xiso"
+)
+
+// XEX2 (Xbox 360 Executable) format parsing.
+//
+// XEX2 specification:
+// https://free60.org/System-Software/Formats/XEX/
+//
+// XEX2 header layout (big-endian):
+//
+//	Offset  Size  Description
+//	0x00    4     Magic ("XEX2")
+//	0x04    4     Module flags
+//	0x08    4     PE data offset
+//	0x0C    4     Reserved
+//	0x10    4     Security info offset
+//	0x14    4     Optional header count
+//	0x18    8*N   Optional headers (key + value)
+//
+// Optional header values are stored inline when the low byte of the key is
+// 0x00 or 0x01. Otherwise the value is a file offset to the header data.
+//
+// Execution info (optional header 0x00040006, 24 bytes):
+//
+//	Offset  Size  Description
+//	0x00    4     Media ID
+//	0x04    4     Version
+//	0x08    4     Base version
+//	0x0C    4     Title ID
+//	0x10    1     Platform
+//	0x11    1     Executable type
+//	0x12    1     Disc number
+//	0x13    1     Disc count
+//	0x14    4     Save game ID
+//
+// Security info (at security info offset, relevant fields):
+//
+//	Offset  Size  Description
+//	0x178   4     Game region flags
+//	0x17C   4     Allowed media types
+
+const (
+	xexMagic          = "XEX2"
+	xexHeaderSize     = 0x18
+	xexOptHeaderSize  = 8
+	xexMaxOptHeaders  = 0x100
+	xexModuleFlagsOff = 0x04
+	xexSecInfoOff     = 0x10
+	xexOptCountOff    = 0x14
+
+	optKeyExecutionInfo  = 0x00040006
+	optKeyOriginalPEName = 0x000183FF
+
+	execInfoSize          = 24
+	execMediaIDOff        = 0x00
+	execVersionOff        = 0x04
+	execBaseVersionOff    = 0x08
+	execTitleIDOff        = 0x0C
+	execPlatformOff       = 0x10
+	execExecutableTypeOff = 0x11
+	execDiscNumberOff     = 0x12
+	execDiscCountOff      = 0x13
+	execSaveGameIDOff     = 0x14
+
+	secInfoRegionOff     = 0x178
+	secInfoMediaTypesOff = 0x17C
+	secInfoMinSize       = 0x180
+)
+
+// Region represents Xbox 360 game region flags.
+type Region uint32
+
+const (
+	RegionNTSCU      Region = 0x000000FF
+	RegionNTSCJ      Region = 0x0000FF00
+	RegionNTSCJJapan Region = 0x00000100
+	RegionNTSCJChina Region = 0x00000200
+	RegionPAL        Region = 0x00FF0000
+	RegionPALAUNZ    Region = 0x00010000
+	RegionOther      Region = 0xFF000000
+	RegionAll        Region = 0xFFFFFFFF
+)
+
+// MediaType represents Xbox 360 allowed media type flags.
+type MediaType uint32
+
+const (
+	MediaHardDisk         MediaType = 0x00000001
+	MediaDVDX2            MediaType = 0x00000002
+	MediaDVDCD            MediaType = 0x00000004
+	MediaDVD5             MediaType = 0x00000008
+	MediaDVD9             MediaType = 0x00000010
+	MediaSystemFlash      MediaType = 0x00000020
+	MediaMemoryUnit       MediaType = 0x00000080
+	MediaUSBMassStorage   MediaType = 0x00000100
+	MediaNetwork          MediaType = 0x00000200
+	MediaDirectFromMemory MediaType = 0x00000400
+	MediaRAMDrive         MediaType = 0x00000800
+	MediaSVOD             MediaType = 0x00001000
+	MediaInsecurePackage  MediaType = 0x01000000
+	MediaSaveGamePackage  MediaType = 0x02000000
+	MediaLocallySigned    MediaType = 0x04000000
+	MediaLiveSigned       MediaType = 0x08000000
+	MediaXboxPackage      MediaType = 0x10000000
+)
+
+// Info contains metadata extracted from an Xbox 360 XEX2 file.
+type Info struct {
+	// TitleID is the numeric title ID.
+	TitleID uint32 `json:"title_id"`
+	// TitleIDHex is the title ID as an 8-character hex string.
+	TitleIDHex string `json:"title_id_hex,omitempty"`
+	// PublisherCode is the 2-character publisher code from title ID.
+	PublisherCode string `json:"publisher_code,omitempty"`
+	// GameNumber is the game number from title ID.
+	GameNumber uint16 `json:"game_number"`
+	// MediaID identifies the specific disc or package the executable was built for.
+	MediaID uint32 `json:"media_id"`
+	// MediaIDHex is the media ID as an 8-character hex string.
+	MediaIDHex string `json:"media_id_hex,omitempty"`
+	// Version is the executable version (e.g., "1.0.1234.0").
+	Version string `json:"version,omitempty"`
+	// BaseVersion is the version of the base executable this one updates.
+	BaseVersion string `json:"base_version,omitempty"`
+	// Platform is the target platform byte from the execution info.
+	Platform byte `json:"platform"`
+	// ExecutableType is the executable type byte from the execution info.
+	ExecutableType byte `json:"executable_type"`
+	// DiscNumber is the disc number for multi-disc games.
+	DiscNumber int `json:"disc_number"`
+	// DiscCount is the total number of discs.
+	DiscCount int `json:"disc_count"`
+	// SaveGameID is the save game ID.
+	SaveGameID uint32 `json:"save_game_id"`
+	// ModuleFlags is the module flags bitmask from the XEX header.
+	ModuleFlags uint32 `json:"module_flags"`
+	// OriginalPEName is the original file name of the executable, if present.
+	OriginalPEName string `json:"original_pe_name,omitempty"`
+	// RegionFlags is the bitmask of Region values.
+	RegionFlags Region `json:"region_flags"`
+	// AllowedMediaTypes is a bitmask of allowed media types.
+	AllowedMediaTypes MediaType `json:"allowed_media_types"`
+}
+
+// GamePlatform implements core.GameInfo.
+func (i *Info) GamePlatform() core.Platform { return core.PlatformXbox360 }
+
+// GameTitle implements core.GameInfo.
+// XEX headers do not contain a plaintext title.
+func (i *Info) GameTitle() string { return "" }
+
+// GameSerial implements core.GameInfo. Returns serial in "XX-###" format,
+// like Xbox XBE serials.
+func (i *Info) GameSerial() string {
+	if i.TitleID == 0 {
+		return ""
+	}
+	return titleid.Serial(i.TitleID)
+}
+
+// GameRegions implements core.GameInfo.
+func (i *Info) GameRegions() []core.Region {
+	return regionsFromFlags(i.RegionFlags)
+}
+
+// Parse extracts game information from an XEX2 file.
+func Parse(r io.ReaderAt, size int64) (*Info, error) {
+	if size < xexHeaderSize {
+		return nil, fmt.Errorf("file too small for XEX header: %d bytes (need at least %d)", size, xexHeaderSize)
+	}
+
+	header := make([]byte, xexHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read XEX header: %w", err)
+	}
+
+	if string(header[:4]) != xexMagic {
+		return nil, fmt.Errorf("not a valid XEX2: invalid magic")
+	}
+
+	moduleFlags := binary.BigEndian.Uint32(header[xexModuleFlagsOff:])
+	secInfoOffset := int64(binary.BigEndian.Uint32(header[xexSecInfoOff:]))
+	optCount := binary.BigEndian.Uint32(header[xexOptCountOff:])
+
+	if optCount > xexMaxOptHeaders || xexHeaderSize+int64(optCount)*xexOptHeaderSize > size {
+		return nil, fmt.Errorf("invalid XEX optional header count: %d", optCount)
+	}
+
+	optHeaders := make([]byte, optCount*xexOptHeaderSize)
+	if _, err := r.ReadAt(optHeaders, xexHeaderSize); err != nil {
+		return nil, fmt.Errorf("failed to read XEX optional headers: %w", err)
+	}
+
+	info := &Info{ModuleFlags: moduleFlags}
+
+	for i := 0; i < int(optCount); i++ {
+		key := binary.BigEndian.Uint32(optHeaders[i*xexOptHeaderSize:])
+		value := binary.BigEndian.Uint32(optHeaders[i*xexOptHeaderSize+4:])
+
+		switch key {
+		case optKeyExecutionInfo:
+			if err := parseExecutionInfo(r, size, int64(value), info); err != nil {
+				return nil, err
+			}
+		case optKeyOriginalPEName:
+			info.OriginalPEName = readSizedString(r, size, int64(value))
+		}
+	}
+
+	// Security info holds the region and media restrictions
+	if secInfoOffset > 0 && secInfoOffset+secInfoMinSize <= size {
+		secInfo := make([]byte, 8)
+		if _, err := r.ReadAt(secInfo, secInfoOffset+secInfoRegionOff); err != nil {
+			return nil, fmt.Errorf("failed to read XEX security info: %w", err)
+		}
+		info.RegionFlags = Region(binary.BigEndian.Uint32(secInfo[0:]))
+		info.AllowedMediaTypes = MediaType(binary.BigEndian.Uint32(secInfo[secInfoMediaTypesOff-secInfoRegionOff:]))
+	}
+
+	return info, nil
+}
+
+// ParseDisc extracts game information from default.xex on an Xbox 360 disc image.
+// The XDVDFS game partition is located using the XGD2/XGD3 offsets known to xiso.
+func ParseDisc(r io.ReaderAt, size int64) (*Info, error) {
+	xexReader, err := xiso.OpenFile(r, size, "default.xex")
+	if err != nil {
+		return nil, err
+	}
+	return Parse(xexReader, xexReader.Size())
+}
+
+// parseExecutionInfo reads the execution info optional header into info.
+func parseExecutionInfo(r io.ReaderAt, size, offset int64, info *Info) error {
+	if offset+execInfoSize > size {
+		return fmt.Errorf("XEX execution info extends beyond file: offset %d, file size %d", offset, size)
+	}
+
+	data := make([]byte, execInfoSize)
+	if _, err := r.ReadAt(data, offset); err != nil {
+		return fmt.Errorf("failed to read XEX execution info: %w", err)
+	}
+
+	info.MediaID = binary.BigEndian.Uint32(data[execMediaIDOff:])
+	info.MediaIDHex = fmt.Sprintf("%08X", info.MediaID)
+	info.Version = formatVersion(binary.BigEndian.Uint32(data[execVersionOff:]))
+	info.BaseVersion = formatVersion(binary.BigEndian.Uint32(data[execBaseVersionOff:]))
+	info.TitleID = binary.BigEndian.Uint32(data[execTitleIDOff:])
+	info.TitleIDHex = fmt.Sprintf("%08X", info.TitleID)
+	info.PublisherCode, info.GameNumber = titleid.Decode(info.TitleID)
+	info.Platform = data[execPlatformOff]
+	info.ExecutableType = data[execExecutableTypeOff]
+	info.DiscNumber = int(data[execDiscNumberOff])
+	info.DiscCount = int(data[execDiscCountOff])
+	info.SaveGameID = binary.BigEndian.Uint32(data[execSaveGameIDOff:])
+	return nil
+}
+
+// readSizedString reads a length-prefixed string optional header.
+// The first 4 bytes hold the total size of the header data, including the length itself.
+// Returns an empty string if the data is missing or malformed.
+func readSizedString(r io.ReaderAt, size, offset int64) string {
+	if offset+4 > size {
+		return ""
+	}
+	lenBuf := make([]byte, 4)
+	if _, err := r.ReadAt(lenBuf, offset); err != nil {
+		return ""
+	}
+	dataLen := int64(binary.BigEndian.Uint32(lenBuf))
+	if dataLen <= 4 || dataLen > 0x1000 || offset+dataLen > size {
+		return ""
+	}
+	data := make([]byte, dataLen-4)
+	if _, err := r.ReadAt(data, offset+4); err != nil {
+		return ""
+	}
+	return util.ExtractASCII(data)
+}
+
+// formatVersion formats an XEX version as "major.minor.build.qfe".
+// Version layout: major (4 bits), minor (4 bits), build (16 bits), qfe (8 bits).
+func formatVersion(v uint32) string {
+	return fmt.Sprintf("%d.%d.%d.%d", v>>28, (v>>24)&0xF, (v>>8)&0xFFFF, v&0xFF)
+}
+
+// regionsFromFlags converts Xbox 360 region flags to core regions.
+func regionsFromFlags(flags Region) []core.Region {
+	if flags == RegionAll {
+		return []core.Region{core.RegionWorld}
+	}
+
+	var regions []core.Region
+	if flags&RegionNTSCU != 0 {
+		regions = append(regions, core.RegionAmericas)
+	}
+	if flags&RegionNTSCJJapan != 0 {
+		regions = append(regions, core.RegionJapan)
+	}
+	if flags&RegionNTSCJChina != 0 {
+		regions = append(regions, core.RegionChina)
+	}
+	if flags&RegionNTSCJ&^(RegionNTSCJJapan|RegionNTSCJChina) != 0 {
+		regions = append(regions, core.RegionAsia)
+	}
+	if flags&RegionPAL&^RegionPALAUNZ != 0 {
+		regions = append(regions, core.RegionEurope)
+	}
+	if flags&RegionPALAUNZ != 0 {
+		regions = append(regions, core.RegionAustralia, core.RegionNewZealand)
+	}
+	if flags&RegionOther != 0 {
+		regions = append(regions, core.RegionWorld)
+	}
+	return regions
+}
@@ -0,0 +1,136 @@
+package xex
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"testing"
+
+	"github.com/sargunv/rom-tools/lib/core"
+	"github.com/sargunv/rom-tools/lib/roms/xbox/titleid"
+)
+
+// makeTestXEX creates a minimal XEX2 file with execution info, original PE name
+// and security info.
+func makeTestXEX(titleID, mediaID uint32, regionFlags Region) []byte {
+	const (
+		execOffset = 0x100
+		nameOffset = 0x140
+		secOffset  = 0x200
+	)
+	buf := make([]byte, secOffset+secInfoMinSize)
+
+	copy(buf[0:], xexMagic)
+	binary.BigEndian.PutUint32(buf[xexModuleFlagsOff:], 0x00000001)
+	binary.BigEndian.PutUint32(buf[xexSecInfoOff:], secOffset)
+	binary.BigEndian.PutUint32(buf[xexOptCountOff:], 2)
+
+	// Optional headers
+	binary.BigEndian.PutUint32(buf[xexHeaderSize:], optKeyExecutionInfo)
+	binary.BigEndian.PutUint32(buf[xexHeaderSize+4:], execOffset)
+	binary.BigEndian.PutUint32(buf[xexHeaderSize+8:], optKeyOriginalPEName)
+	binary.BigEndian.PutUint32(buf[xexHeaderSize+12:], nameOffset)
+
+	// Execution info
+	binary.BigEndian.PutUint32(buf[execOffset+execMediaIDOff:], mediaID)
+	binary.BigEndian.PutUint32(buf[execOffset+execVersionOff:], 0x20002A01) // 2.0.42.1
+	binary.BigEndian.PutUint32(buf[execOffset+execBaseVersionOff:], 0x20000000)
+	binary.BigEndian.PutUint32(buf[execOffset+execTitleIDOff:], titleID)
+	buf[execOffset+execDiscNumberOff] = 1
+	buf[execOffset+execDiscCountOff] = 2
+
+	// Original PE name (size includes the length field)
+	name := "default.pe"
+	binary.BigEndian.PutUint32(buf[nameOffset:], uint32(4+len(name)+2))
+	copy(buf[nameOffset+4:], name)
+
+	// Security info
+	binary.BigEndian.PutUint32(buf[secOffset+secInfoRegionOff:], uint32(regionFlags))
+	binary.BigEndian.PutUint32(buf[secOffset+secInfoMediaTypesOff:], uint32(MediaDVDX2))
+
+	return buf
+}
+
+func TestParse(t *testing.T) {
+	data := makeTestXEX(0x4D5307E6, 0x12345678, RegionNTSCU|RegionPAL)
+
+	info, err := Parse(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.GamePlatform() != core.PlatformXbox360 {
+		t.Errorf("GamePlatform() = %v, want %v", info.GamePlatform(), core.PlatformXbox360)
+	}
+	if info.TitleIDHex != "4D5307E6" {
+		t.Errorf("TitleIDHex = %q, want %q", info.TitleIDHex, "4D5307E6")
+	}
+	if info.GameSerial() != "MS-2022" {
+		t.Errorf("GameSerial() = %q, want %q", info.GameSerial(), "MS-2022")
+	}
+	if info.MediaIDHex != "12345678" {
+		t.Errorf("MediaIDHex = %q, want %q", info.MediaIDHex, "12345678")
+	}
+	if info.Version != "2.0.42.1" {
+		t.Errorf("Version = %q, want %q", info.Version, "2.0.42.1")
+	}
+	if info.BaseVersion != "2.0.0.0" {
+		t.Errorf("BaseVersion = %q, want %q", info.BaseVersion, "2.0.0.0")
+	}
+	if info.DiscNumber != 1 || info.DiscCount != 2 {
+		t.Errorf("Disc = %d/%d, want 1/2", info.DiscNumber, info.DiscCount)
+	}
+	if info.OriginalPEName != "default.pe" {
+		t.Errorf("OriginalPEName = %q, want %q", info.OriginalPEName, "default.pe")
+	}
+	if info.AllowedMediaTypes != MediaDVDX2 {
+		t.Errorf("AllowedMediaTypes = 0x%08X, want 0x%08X", info.AllowedMediaTypes, MediaDVDX2)
+	}
+
+	regions := info.GameRegions()
+	want := []core.Region{core.RegionAmericas, core.RegionEurope, core.RegionAustralia, core.RegionNewZealand}
+	if !slices.Equal(regions, want) {
+		t.Errorf("GameRegions() = %v, want %v", regions, want)
+	}
+}
+
+func TestParse_RegionFree(t *testing.T) {
+	data := makeTestXEX(0x4D5307E6, 0, RegionAll)
+
+	info, err := Parse(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	regions := info.GameRegions()
+	if len(regions) != 1 || regions[0] != core.RegionWorld {
+		t.Errorf("GameRegions() = %v, want [World]", regions)
+	}
+}
+
+func TestParse_InvalidMagic(t *testing.T) {
+	data := makeTestXEX(0x4D5307E6, 0, RegionAll)
+	copy(data, "XEX1")
+
+	_, err := Parse(bytes.NewReader(data), int64(len(data)))
+	if err == nil {
+		t.Error("Parse() expected error for invalid magic, got nil")
+	}
+}
+
+func TestParse_TooSmall(t *testing.T) {
+	data := []byte("XEX2")
+
+	_, err := Parse(bytes.NewReader(data), int64(len(data)))
+	if err == nil {
+		t.Error("Parse() expected error for small file, got nil")
+	}
+}
+
+func TestGameSerial_MatchesXBEFormat(t *testing.T) {
+	publisherCode, gameNumber := titleid.Decode(0x4D530064)
+	info := &Info{TitleID: 0x4D530064, PublisherCode: publisherCode, GameNumber: gameNumber}
+	if got := info.GameSerial(); got != "MS-100" {
+		t.Errorf("GameSerial() = %q, want %q", got, "MS-100")
+	}
+}
@@ -18,55 +18,111 @@ import (
 //   - Offset 0x10000: Volume descriptor with "MICROSOFT*XBOX*MEDIA" magic
 //   - Root directory entry follows, containing file entries in a binary tree
 //   - default.xbe in root contains game metadata in its certificate
+//
+// Full disc images (e.g., Redump dumps) place the XDVDFS game partition at a
+// fixed offset that depends on the disc generation:
+//
+//	Offset      Description
+//	0x00000000  Extracted XISO (game partition only)
+//	0x18300000  XGD1 (original Xbox)
+//	0x0FD90000  XGD2 (Xbox 360)
+//	0x02080000  XGD3 (Xbox 360)
+//
+// All sector numbers within the partition are relative to its start.
 
 const (
 	xisoVolumeDescOffset = 0x10000
 	xisoMagicSize        = 20
 	xisoRootDirOffset    = 0x14
 	xisoRootDirSizeOff   = 0x18
+
+	sectorSize = 2048
 )
 
+// partitionOffsets are the known XDVDFS game partition offsets, in the order they are tried.
+var partitionOffsets = []int64{
+	0x00000000, // Extracted XISO
+	0x18300000, // XGD1
+	0x0FD90000, // XGD2
+	0x02080000, // XGD3
+}
+
 // Parse extracts game information from an Xbox XISO image.
 func Parse(r io.ReaderAt, size int64) (*xbe.Info, error) {
-	// Read volume descriptor
+	xbeReader, err := OpenFile(r, size, "default.xbe")
+	if err != nil {
+		return nil, err
+	}
+	return xbe.Parse(xbeReader, xbeReader.Size())
+}
+
+// OpenFile locates a file in the root directory of an XDVDFS image and returns
+// a reader over its contents. Both extracted XISO images and full XGD1/XGD2/XGD3
+// disc images are supported.
+func OpenFile(r io.ReaderAt, size int64, name string) (*io.SectionReader, error) {
 	if size < xisoVolumeDescOffset+32 {
 		return nil, fmt.Errorf("file too small for XISO header")
 	}
 
-	volDesc := make([]byte, 32)
-	if _, err := r.ReadAt(volDesc, xisoVolumeDescOffset); err != nil {
-		return nil, fmt.Errorf("failed to read XISO volume descriptor: %w", err)
+	partOffset, err := findPartition(r, size)
+	if err != nil {
+		return nil, err
 	}
 
-	// Verify magic
-	if string(volDesc[:xisoMagicSize]) != "MICROSOFT*XBOX*MEDIA" {
-		return nil, fmt.Errorf("not a valid XISO: invalid magic")
+	volDesc := make([]byte, 32)
+	if _, err := r.ReadAt(volDesc, partOffset+xisoVolumeDescOffset); err != nil {
+		return nil, fmt.Errorf("failed to read XISO volume descriptor: %w", err)
 	}
 
 	// Get root directory location
 	rootDirSector := binary.LittleEndian.Uint32(volDesc[xisoRootDirOffset:])
 	rootDirSize := binary.LittleEndian.Uint32(volDesc[xisoRootDirSizeOff:])
 
-	// Find default.xbe in root directory
-	xbeOffset, err := findDefaultXBE(r, int64(rootDirSector)*2048, int64(rootDirSize))
+	entry, err := findFile(r, partOffset+int64(rootDirSector)*sectorSize, int64(rootDirSize), name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find default.xbe: %w", err)
+		return nil, fmt.Errorf("failed to find %s: %w", name, err)
+	}
+
+	fileOffset := partOffset + int64(entry.sector)*sectorSize
+	if fileOffset > size {
+		return nil, fmt.Errorf("%s starts beyond end of image: offset %d, size %d", name, fileOffset, size)
+	}
+	fileSize := min(int64(entry.size), size-fileOffset)
+	return io.NewSectionReader(r, fileOffset, fileSize), nil
+}
+
+// findPartition returns the offset of the first XDVDFS partition with a valid volume descriptor.
+func findPartition(r io.ReaderAt, size int64) (int64, error) {
+	magic := make([]byte, xisoMagicSize)
+	for _, off := range partitionOffsets {
+		if off+xisoVolumeDescOffset+32 > size {
+			continue
+		}
+		if _, err := r.ReadAt(magic, off+xisoVolumeDescOffset); err != nil {
+			continue
+		}
+		if string(magic) == "MICROSOFT*XBOX*MEDIA" {
+			return off, nil
+		}
 	}
+	return 0, fmt.Errorf("not a valid XISO: invalid magic")
+}
 
-	// Parse XBE using io.NewSectionReader to create a reader starting at the XBE offset
-	xbeReader := io.NewSectionReader(r, xbeOffset, size-xbeOffset)
-	return xbe.Parse(xbeReader, size-xbeOffset)
+// dirEntry is the location of a file found in an XDVDFS directory.
+type dirEntry struct {
+	sector uint32
+	size   uint32
 }
 
-// findDefaultXBE searches the XDVDFS directory tree for default.xbe.
+// findFile searches the XDVDFS directory tree for a file.
 // The directory uses a binary tree structure with left/right child offsets.
-func findDefaultXBE(r io.ReaderAt, dirOffset, dirSize int64) (int64, error) {
+func findFile(r io.ReaderAt, dirOffset, dirSize int64, name string) (dirEntry, error) {
 	dirData := make([]byte, dirSize)
 	if _, err := r.ReadAt(dirData, dirOffset); err != nil {
-		return 0, fmt.Errorf("failed to read directory at offset %d (size %d): %w", dirOffset, dirSize, err)
+		return dirEntry{}, fmt.Errorf("failed to read directory at offset %d (size %d): %w", dirOffset, dirSize, err)
 	}
 
-	return searchDirectory(dirData, "default.xbe")
+	return searchDirectory(dirData, name)
 }
 
 // searchDirectory searches a directory's binary tree for a file.
@@ -80,29 +136,30 @@ func findDefaultXBE(r io.ReaderAt, dirOffset, dirSize int64) (int64, error) {
 //	12      1     File attributes
 //	13      1     Filename length
 //	14      N     Filename (ASCII)
-func searchDirectory(dirData []byte, target string) (int64, error) {
+func searchDirectory(dirData []byte, target string) (dirEntry, error) {
 	target = strings.ToLower(target)
 	return searchDirectoryAt(dirData, 0, target)
 }
 
-func searchDirectoryAt(dirData []byte, offset int, target string) (int64, error) {
+func searchDirectoryAt(dirData []byte, offset int, target string) (dirEntry, error) {
 	if offset+14 > len(dirData) {
-		return 0, fmt.Errorf("directory entry offset out of bounds")
+		return dirEntry{}, fmt.Errorf("directory entry offset out of bounds")
 	}
 
 	leftOffset := binary.LittleEndian.Uint16(dirData[offset:]) * 4
 	rightOffset := binary.LittleEndian.Uint16(dirData[offset+2:]) * 4
 	fileSector := binary.LittleEndian.Uint32(dirData[offset+4:])
+	fileSize := binary.LittleEndian.Uint32(dirData[offset+8:])
 	nameLen := int(dirData[offset+13])
 
 	if nameLen == 0 || offset+14+nameLen > len(dirData) {
-		return 0, fmt.Errorf("invalid directory entry")
+		return dirEntry{}, fmt.Errorf("invalid directory entry")
 	}
 
 	name := strings.ToLower(string(dirData[offset+14 : offset+14+nameLen]))
 
 	if name == target {
-		return int64(fileSector) * 2048, nil
+		return dirEntry{sector: fileSector, size: fileSize}, nil
 	}
 
 	// Binary tree search
@@ -119,5 +176,5 @@ func searchDirectoryAt(dirData []byte, offset int, target string) (int64, error)
 		}
 	}
 
-	return 0, fmt.Errorf("file not found")
+	return dirEntry{}, fmt.Errorf("file not found")
 }
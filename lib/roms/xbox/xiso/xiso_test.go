@@ -2,6 +2,7 @@ package xiso
 
 import (
 	"bytes"
+	"encoding/binary"
 	"os"
 	"testing"
 )
@@ -101,3 +102,59 @@ func TestParse_MissingDefaultXBE(t *testing.T) {
 		t.Error("Parse() expected error for missing default.xbe, got nil")
 	}
 }
+
+// offsetImage is a sparse io.ReaderAt that places data at a fixed base offset
+// and reads as zeros everywhere else.
+type offsetImage struct {
+	base int64
+	data []byte
+}
+
+func (o offsetImage) ReadAt(p []byte, off int64) (int, error) {
+	clear(p)
+	rel := off - o.base
+	if rel+int64(len(p)) <= 0 || rel >= int64(len(o.data)) {
+		return len(p), nil
+	}
+	if rel < 0 {
+		copy(p[-rel:], o.data)
+	} else {
+		copy(p, o.data[rel:])
+	}
+	return len(p), nil
+}
+
+func TestOpenFile_XGD3(t *testing.T) {
+	// Minimal XDVDFS partition containing a single file "default.xex"
+	part := make([]byte, xisoVolumeDescOffset+3*sectorSize)
+	copy(part[xisoVolumeDescOffset:], "MICROSOFT*XBOX*MEDIA")
+	rootSector := uint32(xisoVolumeDescOffset/sectorSize + 1)
+	binary.LittleEndian.PutUint32(part[xisoVolumeDescOffset+xisoRootDirOffset:], rootSector)
+	binary.LittleEndian.PutUint32(part[xisoVolumeDescOffset+xisoRootDirSizeOff:], sectorSize)
+
+	dir := part[rootSector*sectorSize:]
+	binary.LittleEndian.PutUint32(dir[4:], rootSector+1) // file sector
+	binary.LittleEndian.PutUint32(dir[8:], 4)            // file size
+	dir[13] = byte(len("default.xex"))
+	copy(dir[14:], "default.xex")
+	copy(part[(rootSector+1)*sectorSize:], "XEX2")
+
+	const xgd3Offset = 0x02080000
+	img := offsetImage{base: xgd3Offset, data: part}
+	size := int64(xgd3Offset + len(part))
+
+	r, err := OpenFile(img, size, "DEFAULT.XEX")
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	if r.Size() != 4 {
+		t.Errorf("Size() = %d, want 4", r.Size())
+	}
+	buf := make([]byte, 4)
+	if _, err := r.ReadAt(buf, 0); err != nil {
+		t.Fatalf("ReadAt() error = %v", err)
+	}
+	if string(buf) != "XEX2" {
+		t.Errorf("content = %q, want %q", buf, "XEX2")
+	}
+}